@@ -46,6 +46,33 @@ const (
 	ORDER_FAIL
 )
 
+// the valid status transitions of the order, the same status is always valid, such as more deal in part_finish.
+var tradeStatusTransition = map[TradeStatus][]TradeStatus{
+	ORDER_UNFINISH: {
+		ORDER_PART_FINISH, ORDER_FINISH, ORDER_CANCEL, ORDER_REJECT, ORDER_CANCEL_ING, ORDER_FAIL,
+	},
+	ORDER_PART_FINISH: {ORDER_FINISH, ORDER_CANCEL, ORDER_CANCEL_ING},
+	ORDER_CANCEL_ING:  {ORDER_PART_FINISH, ORDER_FINISH, ORDER_CANCEL},
+}
+
+// IsFinal return true if the order will not change anymore.
+func (ts TradeStatus) IsFinal() bool {
+	return ts == ORDER_FINISH || ts == ORDER_CANCEL || ts == ORDER_REJECT || ts == ORDER_FAIL
+}
+
+// CanTransitTo check the status transition is valid or not.
+func (ts TradeStatus) CanTransitTo(next TradeStatus) bool {
+	if ts == next {
+		return true
+	}
+	for _, status := range tradeStatusTransition[ts] {
+		if status == next {
+			return true
+		}
+	}
+	return false
+}
+
 // k线周期
 const (
 	KLINE_PERIOD_1MIN = 1 + iota
@@ -147,6 +174,98 @@ func (ft FutureType) String() string {
 	return futureTypeSymbol[ft]
 }
 
+var positionSideSymbol = [...]string{"AUTO", "BOTH", "LONG", "SHORT"}
+
+// PositionSide is the position which the swap order works on.
+// POSITION_SIDE_AUTO is the hedge mode side of the FutureType, the orders without the PositionSide work as before.
+type PositionSide int
+
+const (
+	POSITION_SIDE_AUTO  PositionSide = iota // the long or the short side of the FutureType, default
+	POSITION_SIDE_BOTH                      // the net position in the one-way mode
+	POSITION_SIDE_LONG                      // the long position in the hedge mode
+	POSITION_SIDE_SHORT                     // the short position in the hedge mode
+)
+
+func (ps PositionSide) String() string {
+	return positionSideSymbol[ps]
+}
+
+var positionModeSymbol = [...]string{"ONE_WAY", "HEDGE"}
+
+// PositionMode is the position mode of the account, it is called the dual side mode in some exchanges.
+type PositionMode int
+
+const (
+	POSITION_MODE_ONE_WAY PositionMode = iota // one net position in a pair
+	POSITION_MODE_HEDGE                       // the long and the short positions in a pair at the same time
+)
+
+func (pm PositionMode) String() string {
+	return positionModeSymbol[pm]
+}
+
+var longShortTypeSymbol = [...]string{"TOP_ACCOUNT", "TOP_POSITION", "GLOBAL_ACCOUNT"}
+
+// LongShortType is the trader group and the measure of the long short ratio.
+type LongShortType int
+
+const (
+	LONG_SHORT_TOP_ACCOUNT    LongShortType = iota // the accounts of the top traders
+	LONG_SHORT_TOP_POSITION                        // the positions of the top traders
+	LONG_SHORT_GLOBAL_ACCOUNT                      // the accounts of all the traders
+)
+
+func (lst LongShortType) String() string {
+	return longShortTypeSymbol[lst]
+}
+
+var conditionTypeSymbol = [...]string{"NONE", "STOP", "TAKE_PROFIT", "TRAILING_STOP"}
+
+// ConditionType is the native conditional order type, the order is placed when the trigger price is reached.
+// The PlaceType MARKET means the stop market order, the others mean the stop limit order with the Price.
+type ConditionType int
+
+const (
+	CONDITION_NONE          ConditionType = iota // the normal order
+	CONDITION_STOP                               // stop loss, stop market or stop limit
+	CONDITION_TAKE_PROFIT                        // take profit, market or limit
+	CONDITION_TRAILING_STOP                      // trailing stop market, follow the price by the callback rate
+)
+
+func (ct ConditionType) String() string {
+	return conditionTypeSymbol[ct]
+}
+
+var triggerTypeSymbol = [...]string{"LAST", "MARK", "INDEX"}
+
+// TriggerType is the price which the conditional order is triggered by.
+type TriggerType int
+
+const (
+	TRIGGER_LAST  TriggerType = iota // the last trade price, default
+	TRIGGER_MARK                     // the mark price
+	TRIGGER_INDEX                    // the index price
+)
+
+func (tt TriggerType) String() string {
+	return triggerTypeSymbol[tt]
+}
+
+var optionTypeSymbol = [...]string{"CALL", "PUT"}
+
+// OptionType is the right of the option contract.
+type OptionType int
+
+const (
+	OPTION_CALL OptionType = iota // the right to buy the underlying at the strike price
+	OPTION_PUT                    // the right to sell the underlying at the strike price
+)
+
+func (ot OptionType) String() string {
+	return optionTypeSymbol[ot]
+}
+
 const (
 	CROSS    = "cross"
 	ISOLATED = "isolated"
@@ -157,6 +276,7 @@ const (
 	TRADE_TYPE_SPOT   = "spot"
 	TRADE_TYPE_SWAP   = "swap"
 	TRADE_TYPE_MARGIN = "margin"
+	TRADE_TYPE_OPTION = "option"
 )
 
 const (
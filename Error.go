@@ -19,3 +19,23 @@ type WSStopError struct {
 func (e *WSStopError) Error() string {
 	return fmt.Sprintf("websocket stop error: %s", e.Msg)
 }
+
+// OrderInvalidError is returned when the order is rejected locally by the normalizer, the order is not sent.
+type OrderInvalidError struct {
+	Field string // price, amount or notional
+	Msg   string
+}
+
+func (e *OrderInvalidError) Error() string {
+	return fmt.Sprintf("order invalid error: %s %s", e.Field, e.Msg)
+}
+
+// BatchError is returned by the batch apis when some of the orders fail, the errors of each order are returned too.
+type BatchError struct {
+	Failed int
+	Total  int
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("batch order error: %d of %d orders failed", e.Failed, e.Total)
+}
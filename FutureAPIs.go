@@ -11,6 +11,10 @@ type FutureRestAPI interface {
 	GetIndex(pair Pair) (float64, []byte, error)
 	GetMark(pair Pair, contractType string) (float64, []byte, error)
 	GetKlineRecords(contractType string, pair Pair, period, size, since int) ([]*FutureKline, []byte, error)
+	// the mark price, the index price and the premium index klines, there is no volume in them.
+	GetMarkKline(contractType string, pair Pair, period, size, since int) ([]*FutureKline, []byte, error)
+	GetIndexKline(contractType string, pair Pair, period, size, since int) ([]*FutureKline, []byte, error)
+	GetPremiumKline(contractType string, pair Pair, period, size, since int) ([]*FutureKline, []byte, error)
 	GetTrades(pair Pair, contractType string) ([]*Trade, []byte, error)
 
 	// private api
@@ -20,6 +24,11 @@ type FutureRestAPI interface {
 	GetOrders(pair Pair, contractType string) ([]*FutureOrder, []byte, error)
 	GetOrder(order *FutureOrder) ([]byte, error)
 	GetPairFlow(pair Pair) ([]*FutureAccountItem, []byte, error)
+	// the side is used in the okex isolated hedge mode only, the marginType is CROSS or ISOLATED.
+	SetLeverage(pair Pair, contractType string, lever int64, side PositionSide) ([]byte, error)
+	GetLeverage(pair Pair, contractType string, side PositionSide) (int64, []byte, error)
+	SetMarginMode(pair Pair, contractType string, marginType string) ([]byte, error)
+	GetLeverageBrackets(pair Pair, contractType string) ([]*LeverageBracket, []byte, error)
 
 	// util api
 	KeepAlive()
@@ -209,6 +209,11 @@ type FutureContract struct {
 	PricePrecision  int64   `json:"price_precision"`
 	AmountPrecision int64   `json:"amount_precision"`
 
+	LotSize         float64 `json:"lot_size"`          // the amount step, 0 means 10^-AmountPrecision
+	MinAmount       float64 `json:"min_amount"`        // 0 means no limit
+	MinNotional     float64 `json:"min_notional"`      // in the counter currency, 0 means no limit
+	MaxMarketAmount float64 `json:"max_market_amount"` // the max amount of the market order, 0 means no limit
+
 	MaxScalePriceLimit float64 `json:"max_scale_price_limit"`
 	MinScalePriceLimit float64 `json:"min_scale_price_limit"`
 
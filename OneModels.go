@@ -76,6 +76,15 @@ type OneOrder struct {
 	LeverRate      int64
 	Fee            float64
 	Exchange       string
+
+	ReduceOnly    bool         // the order only reduce the position
+	ClosePosition bool         // close the whole position, the Amount is ignored
+	PositionSide  PositionSide // POSITION_SIDE_BOTH in the one-way mode account
+}
+
+// ResolvePosition return the position side and whether the order reduce the position.
+func (order *OneOrder) ResolvePosition() (PositionSide, bool, error) {
+	return ResolvePosition(order.Type, order.PositionSide, order.ReduceOnly, order.ClosePosition)
 }
 
 type OneInfo struct {
@@ -93,4 +102,9 @@ type OneInfo struct {
 	TickSize        float64 `json:"tick_size"`
 	PricePrecision  int64   `json:"price_precision"`
 	AmountPrecision int64   `json:"amount_precision"`
+
+	LotSize         float64 `json:"lot_size"`          // the amount step, 0 means 10^-AmountPrecision
+	MinAmount       float64 `json:"min_amount"`        // 0 means no limit
+	MinNotional     float64 `json:"min_notional"`      // in the counter currency, 0 means no limit
+	MaxMarketAmount float64 `json:"max_market_amount"` // the max amount of the market order, 0 means no limit
 }
@@ -0,0 +1,26 @@
+package goghostex
+
+import "time"
+
+// OptionRestAPI is the api of the european option, the contract is found by the Symbol of the OptionContract.
+type OptionRestAPI interface {
+	// public api
+	GetExchangeName() string
+	// the contracts of the underlying pair, the zero expiry means all the expiries.
+	GetContracts(pair Pair, expiry time.Time) ([]*OptionContract, []byte, error)
+	GetContract(symbol string) *OptionContract
+	GetTicker(symbol string) (*OptionTicker, []byte, error)
+	// the tickers of the chain with the mark iv and the greeks, the zero expiry means all the expiries.
+	GetTickers(pair Pair, expiry time.Time) ([]*OptionTicker, []byte, error)
+	GetDepth(symbol string, size int) (*OptionDepth, []byte, error)
+
+	// private api
+	PlaceOrder(order *OptionOrder) ([]byte, error)
+	CancelOrder(order *OptionOrder) ([]byte, error)
+	GetOrder(order *OptionOrder) ([]byte, error)
+	GetUnFinishOrders(pair Pair) ([]*OptionOrder, []byte, error)
+	GetPositions(pair Pair) ([]*OptionPosition, []byte, error)
+
+	// util api
+	KeepAlive()
+}
@@ -0,0 +1,146 @@
+package goghostex
+
+import (
+	"errors"
+	"sort"
+	"time"
+)
+
+// OptionContract is the option instrument, the Pair is the underlying and the Symbol is the exchange instrument id.
+type OptionContract struct {
+	Pair       Pair       `json:"-"`
+	Symbol     string     `json:"symbol"`
+	Exchange   string     `json:"exchange"`
+	OptionType OptionType `json:"option_type"`
+	Strike     float64    `json:"strike"`
+
+	ExpiryTimestamp int64  `json:"expiry_timestamp"` // unit:ms
+	ExpiryDate      string `json:"expiry_date"`      // format yyyy-mm-dd, the timezone define in api config
+
+	SettleCurrency string  `json:"settle_currency"`
+	UnitAmount     float64 `json:"unit_amount"` // the underlying amount of one contract
+	TickSize       float64 `json:"tick_size"`
+	LotSize        float64 `json:"lot_size"`   // the contract step
+	MinAmount      float64 `json:"min_amount"` // the min contracts of the order
+}
+
+func (contract *OptionContract) GetNormalizer() *Normalizer {
+	return &Normalizer{
+		TickSize:   contract.TickSize,
+		LotSize:    contract.LotSize,
+		MinAmount:  contract.MinAmount,
+		UnitAmount: contract.UnitAmount,
+		SettleMode: SETTLE_MODE_COUNTER,
+	}
+}
+
+// OptionGreeks is the black scholes greeks of one contract, the Vega and the Theta are in the counter currency.
+type OptionGreeks struct {
+	Delta float64 `json:"delta"`
+	Gamma float64 `json:"gamma"`
+	Vega  float64 `json:"vega"`
+	Theta float64 `json:"theta"`
+}
+
+// OptionTicker is the price and the implied volatility of the contract, the IV is the ratio, eg: 0.5 means 50%.
+type OptionTicker struct {
+	Pair      Pair    `json:"-"`
+	Symbol    string  `json:"symbol"`
+	Exchange  string  `json:"exchange"`
+	Last      float64 `json:"last"`
+	Buy       float64 `json:"buy"`
+	Sell      float64 `json:"sell"`
+	BuyIV     float64 `json:"buy_iv"`
+	SellIV    float64 `json:"sell_iv"`
+	MarkPrice float64 `json:"mark_price"`
+	MarkIV    float64 `json:"mark_iv"`
+	// the forward price in okex, the index price in binance
+	UnderlyingPrice float64      `json:"underlying_price"`
+	Greeks          OptionGreeks `json:"greeks"`
+	Timestamp       int64        `json:"timestamp"` // unit:ms
+	Date            string       `json:"date"`      // date: format yyyy-mm-dd HH:MM:SS, the timezone define in api config
+}
+
+type OptionDepth struct {
+	Pair      Pair
+	Symbol    string
+	Exchange  string
+	Timestamp int64
+	Date      string
+	AskList   DepthRecords // Ascending order
+	BidList   DepthRecords // Descending order
+}
+
+// Verify the depth data is right, the option book is thin, so the empty side is allowed.
+func (depth *OptionDepth) Verify() error {
+	for i := 1; i < len(depth.AskList); i++ {
+		if depth.AskList[i-1].Price >= depth.AskList[i].Price {
+			return errors.New("The ask_list is not ascending ordered! ")
+		}
+	}
+	for i := 1; i < len(depth.BidList); i++ {
+		if depth.BidList[i-1].Price <= depth.BidList[i].Price {
+			return errors.New("The bid_list is not descending ordered! ")
+		}
+	}
+	return nil
+}
+
+type OptionOrder struct {
+	// cid is important, when the order api return wrong, you can find it in unfinished api
+	Cid            string
+	OrderId        string
+	Symbol         string
+	Side           TradeSide // BUY or SELL
+	Price          float64
+	Amount         float64 // unit: contract
+	AvgPrice       float64
+	DealAmount     float64
+	Fee            float64
+	PlaceTimestamp int64 // unit: ms
+	PlaceDatetime  string
+	DealTimestamp  int64 // unit: ms
+	DealDatetime   string
+	Status         TradeStatus
+	PlaceType      PlaceType // place_type 0：NORMAL 1：MAKER_ONLY 2：FOK 3：IOC
+	ReduceOnly     bool
+	Pair           Pair
+	Exchange       string
+}
+
+// OptionPosition is the position of the contract, the Amount is negative in the short position.
+type OptionPosition struct {
+	Pair             Pair
+	Symbol           string
+	Exchange         string
+	Amount           float64 // unit: contract
+	AvgPrice         float64
+	MarkPrice        float64
+	UnrealizedProfit float64
+	Greeks           OptionGreeks // the greeks of the whole position, the zero value means not provided
+}
+
+// FilterOptionContracts return the contracts expire in the day of the expiry, the zero expiry return all of them.
+// The result is sorted by the expiry, the strike and the call before the put.
+func FilterOptionContracts(contracts []*OptionContract, expiry time.Time) []*OptionContract {
+	var filtered = make([]*OptionContract, 0, len(contracts))
+	for _, contract := range contracts {
+		if !expiry.IsZero() {
+			var expiryTime = time.Unix(contract.ExpiryTimestamp/1000, 0).In(expiry.Location())
+			if expiryTime.Format("2006-01-02") != expiry.Format("2006-01-02") {
+				continue
+			}
+		}
+		filtered = append(filtered, contract)
+	}
+	sort.SliceStable(filtered, func(i, j int) bool {
+		if filtered[i].ExpiryTimestamp != filtered[j].ExpiryTimestamp {
+			return filtered[i].ExpiryTimestamp < filtered[j].ExpiryTimestamp
+		}
+		if filtered[i].Strike != filtered[j].Strike {
+			return filtered[i].Strike < filtered[j].Strike
+		}
+		return filtered[i].OptionType < filtered[j].OptionType
+	})
+	return filtered
+}
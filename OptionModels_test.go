@@ -0,0 +1,30 @@
+package goghostex
+
+import (
+	"testing"
+	"time"
+)
+
+// go test -v -count=1 -run=TestFilterOptionContracts
+func TestFilterOptionContracts(t *testing.T) {
+	var expiry = time.Date(2024, 12, 27, 8, 0, 0, 0, time.UTC)
+	var later = time.Date(2025, 3, 28, 8, 0, 0, 0, time.UTC)
+	var contracts = []*OptionContract{
+		{Symbol: "BTC-250328-60000-C", OptionType: OPTION_CALL, Strike: 60000, ExpiryTimestamp: later.UnixMilli()},
+		{Symbol: "BTC-241227-70000-P", OptionType: OPTION_PUT, Strike: 70000, ExpiryTimestamp: expiry.UnixMilli()},
+		{Symbol: "BTC-241227-60000-P", OptionType: OPTION_PUT, Strike: 60000, ExpiryTimestamp: expiry.UnixMilli()},
+		{Symbol: "BTC-241227-60000-C", OptionType: OPTION_CALL, Strike: 60000, ExpiryTimestamp: expiry.UnixMilli()},
+	}
+
+	var all = FilterOptionContracts(contracts, time.Time{})
+	if len(all) != 4 || all[0].Symbol != "BTC-241227-60000-C" || all[1].Symbol != "BTC-241227-60000-P" ||
+		all[3].Symbol != "BTC-250328-60000-C" {
+		t.Error("the sorted contracts are wrong: ", all[0].Symbol, all[1].Symbol, all[3].Symbol)
+		return
+	}
+
+	var chain = FilterOptionContracts(contracts, time.Date(2024, 12, 27, 0, 0, 0, 0, time.UTC))
+	if len(chain) != 3 || chain[2].Symbol != "BTC-241227-70000-P" {
+		t.Error("the chain of the expiry is wrong: ", len(chain))
+	}
+}
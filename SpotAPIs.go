@@ -15,6 +15,12 @@ type SpotRestAPI interface {
 	GetAccount() (*Account, []byte, error)
 	PlaceOrder(order *Order) ([]byte, error)
 	CancelOrder(order *Order) ([]byte, error)
+	// change the price and the amount of the open order in place, 0 means not change.
+	AmendOrder(order *Order, newPrice, newAmount float64) ([]byte, error)
+	// the orders are updated in place, the errors are in the same order of the orders, nil means success.
+	BatchPlaceOrders(orders []*Order) ([]error, []byte, error)
+	BatchCancelOrders(orders []*Order) ([]error, []byte, error)
+	CancelAllOrders(pair Pair) ([]byte, error)
 	GetOrder(order *Order) ([]byte, error)
 	GetOrders(pair Pair) ([]*Order, error) // dealed orders
 	GetUnFinishOrders(pair Pair) ([]*Order, []byte, error)
@@ -174,6 +174,8 @@ type APIConfig struct {
 	ApiPassphrase string //for okex.com v3 api
 	ClientId      string //for bitstamp.net , huobi.pro
 	Location      *time.Location
+	Logger        Logger  // the client logger, use the global logger if nil
+	Metrics       Metrics // the client metrics, use the global metrics if nil
 }
 
 type Rule struct {
@@ -184,6 +186,11 @@ type Rule struct {
 	BaseMinSize      float64
 	BasePrecision    int
 	CounterPrecision int
+
+	TickSize        float64 // the price step, 0 means 10^-CounterPrecision
+	LotSize         float64 // the amount step, 0 means 10^-BasePrecision
+	MinNotional     float64 // in the counter currency, 0 means no limit
+	MaxMarketAmount float64 // the max amount of the market order, 0 means no limit
 }
 
 //type Instrument struct {
@@ -1,5 +1,7 @@
 package goghostex
 
+import "time"
+
 type SwapRestAPI interface {
 	// public api
 	GetExchangeName() string
@@ -8,18 +10,47 @@ type SwapRestAPI interface {
 	GetContract(pair Pair) *SwapContract
 	GetLimit(pair Pair) (float64, float64, error)
 	GetKline(pair Pair, period, size, since int) ([]*SwapKline, []byte, error)
+	// the mark price, the index price and the premium index klines, there is no volume in them.
+	GetMarkKline(pair Pair, period, size, since int) ([]*SwapKline, []byte, error)
+	GetIndexKline(pair Pair, period, size, since int) ([]*SwapKline, []byte, error)
+	GetPremiumKline(pair Pair, period, size, since int) ([]*SwapKline, []byte, error)
 	GetOpenAmount(pair Pair) (float64, int64, []byte, error)
 	GetFundingFees(pair Pair) ([][]interface{}, []byte, error)
 	GetFundingFee(pair Pair) (float64, error)
+	// the realized funding rates in [start, end], the pages are requested inside, sorted by the funding time.
+	GetFundingRateHistory(pair Pair, start, end time.Time) ([]*FundingRate, []byte, error)
+	// the predicted funding rate of the next funding time.
+	GetNextFundingRate(pair Pair) (*FundingRate, []byte, error)
+	// the recent liquidations sorted by the timestamp, binance only provide the force orders of the account.
+	GetLiquidations(pair Pair, size int) ([]*Liquidation, []byte, error)
 
 	// private api
 	GetAccount() (*SwapAccount, []byte, error)
 	PlaceOrder(order *SwapOrder) ([]byte, error)
 	CancelOrder(order *SwapOrder) ([]byte, error)
+	// change the price and the amount of the open order in place, 0 means not change.
+	AmendOrder(order *SwapOrder, newPrice, newAmount float64) ([]byte, error)
+	// the orders are updated in place, the errors are in the same order of the orders, nil means success.
+	BatchPlaceOrders(orders []*SwapOrder) ([]error, []byte, error)
+	BatchCancelOrders(orders []*SwapOrder) ([]error, []byte, error)
+	CancelAllOrders(pair Pair) ([]byte, error)
+	// the dead man's switch, the exchange cancel all the open orders when it is not refreshed in the timeout.
+	// Call it again before the timeout to refresh, 0 turns it off. It is per pair in binance and gate, in the whole account in okex and kraken.
+	CancelAllAfter(pair Pair, timeout time.Duration) ([]byte, error)
 	GetOrder(order *SwapOrder) ([]byte, error)
 	GetOrders(pair Pair) ([]*SwapOrder, []byte, error)
 	GetUnFinishOrders(pair Pair) ([]*SwapOrder, []byte, error)
 	GetPosition(pair Pair, openType FutureType) (*SwapPosition, []byte, error)
+	// the one-way or the hedge position mode of the account, the orders set the PositionSide by it.
+	// It is per settle currency in binance and gate, in the whole account in okex, kraken only support the one-way mode.
+	GetPositionMode(pair Pair) (PositionMode, []byte, error)
+	SetPositionMode(pair Pair, mode PositionMode) ([]byte, error)
+	// the side is used in the okex isolated hedge mode only, the others set the leverage of the pair.
+	SetLeverage(pair Pair, lever int64, side PositionSide) ([]byte, error)
+	GetLeverage(pair Pair, side PositionSide) (int64, []byte, error)
+	// the marginType is CROSS or ISOLATED.
+	SetMarginMode(pair Pair, marginType string) ([]byte, error)
+	GetLeverageBrackets(pair Pair) ([]*LeverageBracket, []byte, error)
 	AddMargin(pair Pair, openType FutureType, marginAmount float64) ([]byte, error)
 	ReduceMargin(pair Pair, openType FutureType, marginAmount float64) ([]byte, error)
 	GetAccountFlow() ([]*SwapAccountItem, []byte, error)
@@ -28,3 +59,12 @@ type SwapRestAPI interface {
 	// util api
 	KeepAlive()
 }
+
+// SwapStatAPI is the derivatives statistics of the swap, the period, size and since work as GetKline.
+// The result is sorted by the timestamp ascending, the exchanges only keep the recent history.
+type SwapStatAPI interface {
+	GetOpenInterest(pair Pair) (*OpenInterest, []byte, error)
+	GetOpenInterestHistory(pair Pair, period, size, since int) ([]*OpenInterest, []byte, error)
+	GetLongShortRatio(pair Pair, ratioType LongShortType, period, size, since int) ([]*LongShortRatio, []byte, error)
+	GetTakerVolume(pair Pair, period, size, since int) ([]*TakerVolume, []byte, error)
+}
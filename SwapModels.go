@@ -2,6 +2,8 @@ package goghostex
 
 import (
 	"errors"
+	"fmt"
+	"sort"
 )
 
 type SwapTicker struct {
@@ -85,6 +87,66 @@ type SwapOrder struct {
 	Fee            float64
 	Pair           Pair
 	Exchange       string
+
+	// the conditional order, the ConditionType is CONDITION_NONE in the normal order
+	ConditionType ConditionType
+	TriggerPrice  float64     // the trigger price, it is the activation price in the trailing stop, 0 means none
+	TriggerType   TriggerType // the trigger price type, default TRIGGER_LAST
+	CallbackRate  float64     // the callback ratio of the trailing stop, eg: 0.01 means 1%
+
+	// the order only reduce the position, it is implied by the liquidate type in the one-way mode
+	ReduceOnly bool
+	// close the whole position, the Amount is ignored, the exchange which not support it return an error
+	ClosePosition bool
+	// the position side of the order, POSITION_SIDE_BOTH in the one-way mode account
+	PositionSide PositionSide
+}
+
+// ResolvePosition return the position side and whether the order reduce the position.
+func (order *SwapOrder) ResolvePosition() (PositionSide, bool, error) {
+	return ResolvePosition(order.Type, order.PositionSide, order.ReduceOnly, order.ClosePosition)
+}
+
+// IsReduce return true if the order only reduce the position, the liquidate type reduce it in both position modes.
+func IsReduce(futureType FutureType, reduceOnly bool) bool {
+	return reduceOnly || futureType == LIQUIDATE_LONG || futureType == LIQUIDATE_SHORT
+}
+
+// ResolvePosition check the position params of the order, the POSITION_SIDE_AUTO is resolved to the side of the type.
+// The liquidate type reduce the side in the hedge mode already, so the reduce only open order is invalid there.
+func ResolvePosition(
+	futureType FutureType, side PositionSide, reduceOnly, closePosition bool,
+) (PositionSide, bool, error) {
+	var typeSide = POSITION_SIDE_LONG
+	switch futureType {
+	case OPEN_LONG, LIQUIDATE_LONG:
+	case OPEN_SHORT, LIQUIDATE_SHORT:
+		typeSide = POSITION_SIDE_SHORT
+	default:
+		return side, false, &OrderInvalidError{Field: "type", Msg: fmt.Sprintf("%d is not the future type", futureType)}
+	}
+	var isLiquidate = futureType == LIQUIDATE_LONG || futureType == LIQUIDATE_SHORT
+
+	switch side {
+	case POSITION_SIDE_BOTH:
+		return side, reduceOnly || closePosition || isLiquidate, nil
+	case POSITION_SIDE_AUTO, POSITION_SIDE_LONG, POSITION_SIDE_SHORT:
+		if side != POSITION_SIDE_AUTO && side != typeSide {
+			return side, false, &OrderInvalidError{
+				Field: "position_side",
+				Msg:   fmt.Sprintf("%s is not the side of %s", side, futureType),
+			}
+		}
+		if !isLiquidate && (reduceOnly || closePosition) {
+			return typeSide, false, &OrderInvalidError{
+				Field: "reduce_only",
+				Msg:   fmt.Sprintf("the %s order can not reduce the position in the hedge mode", futureType),
+			}
+		}
+		return typeSide, isLiquidate, nil
+	default:
+		return side, false, &OrderInvalidError{Field: "position_side", Msg: fmt.Sprintf("%d is unknown", side)}
+	}
 }
 
 type SwapPosition struct {
@@ -99,6 +161,87 @@ type SwapPosition struct {
 	Leverage       int64
 }
 
+// FundingRate is the funding rate of the swap, the Rate is the ratio of the position value, eg: 0.0001 means 0.01%.
+type FundingRate struct {
+	Pair     Pair
+	Exchange string
+	Rate     float64
+	// false is the realized rate settled at the FundingTime, true is the next rate which may change before it.
+	IsPredicted bool
+	FundingTime int64   // unit: ms, the settle time of the rate
+	Interval    int64   // unit: ms, the time between two fundings
+	MarkPrice   float64 // the mark price at the funding time, 0 means not provided
+}
+
+// SortFundingRates sort the rates by the funding time, the zero interval is filled by the time to the previous rate,
+// and the defaultInterval when there is no previous one.
+func SortFundingRates(rates []*FundingRate, defaultInterval int64) {
+	sort.SliceStable(rates, func(i, j int) bool {
+		return rates[i].FundingTime < rates[j].FundingTime
+	})
+	for i, rate := range rates {
+		if rate.Interval > 0 {
+			continue
+		}
+		rate.Interval = defaultInterval
+		if i > 0 && rate.FundingTime > rates[i-1].FundingTime {
+			rate.Interval = rate.FundingTime - rates[i-1].FundingTime
+		}
+	}
+}
+
+// LeverageBracket is the leverage tier of the position, the bigger position has the lower max leverage.
+// The Floor and the Cap are in the unit of the exchange, they are the position value in binance um, gate and kraken,
+// the contract amount in binance cm and okex.
+type LeverageBracket struct {
+	Pair             Pair
+	Tier             int64
+	Floor            float64
+	Cap              float64
+	MaxLeverage      float64
+	MaintMarginRatio float64
+}
+
+// OpenInterest is the open interest of the swap at the Timestamp.
+type OpenInterest struct {
+	Pair      Pair
+	Exchange  string
+	Amount    float64 // in the basis currency
+	Value     float64 // in the counter currency, 0 means not provided
+	Timestamp int64   // unit: ms
+}
+
+// LongShortRatio is the long short ratio of the traders in the LongShortType at the Timestamp.
+type LongShortRatio struct {
+	Pair      Pair
+	Exchange  string
+	Type      LongShortType
+	Ratio     float64 // the long divided by the short
+	Long      float64 // the long share, 0 means not provided
+	Short     float64 // the short share, 0 means not provided
+	Timestamp int64   // unit: ms
+}
+
+// TakerVolume is the volume of the taker orders in the period start at the Timestamp.
+type TakerVolume struct {
+	Pair       Pair
+	Exchange   string
+	BuyVolume  float64 // in the basis currency
+	SellVolume float64 // in the basis currency
+	Timestamp  int64   // unit: ms
+}
+
+// Liquidation is the forced order of the liquidated position, the SELL side means a long position is liquidated.
+type Liquidation struct {
+	Pair      Pair
+	Exchange  string
+	Symbol    string    // the contract symbol in the exchange
+	Side      TradeSide // BUY or SELL, the side of the forced order
+	Price     float64
+	Amount    float64 // in the basis currency
+	Timestamp int64   // unit: ms
+}
+
 type SwapAccount struct {
 	Exchange string
 	// In swap, the usdt is default.
@@ -157,4 +300,9 @@ type SwapContract struct {
 	TickSize        float64 `json:"tick_size"`
 	PricePrecision  int64   `json:"price_precision"`
 	AmountPrecision int64   `json:"amount_precision"`
+
+	LotSize         float64 `json:"lot_size"`          // the amount step, 0 means 10^-AmountPrecision
+	MinAmount       float64 `json:"min_amount"`        // 0 means no limit
+	MinNotional     float64 `json:"min_notional"`      // in the counter currency, 0 means no limit
+	MaxMarketAmount float64 `json:"max_market_amount"` // the max amount of the market order, 0 means no limit
 }
@@ -0,0 +1,53 @@
+package goghostex
+
+import (
+	"testing"
+)
+
+// go test -v -count=1 -run=TestResolvePosition
+func TestResolvePosition(t *testing.T) {
+	var cases = []struct {
+		futureType FutureType
+		side       PositionSide
+		reduceOnly bool
+		wantSide   PositionSide
+		wantReduce bool
+		wantErr    bool
+	}{
+		{OPEN_SHORT, POSITION_SIDE_AUTO, false, POSITION_SIDE_SHORT, false, false},
+		{LIQUIDATE_LONG, POSITION_SIDE_AUTO, false, POSITION_SIDE_LONG, true, false},
+		{OPEN_LONG, POSITION_SIDE_BOTH, true, POSITION_SIDE_BOTH, true, false},
+		{LIQUIDATE_SHORT, POSITION_SIDE_BOTH, false, POSITION_SIDE_BOTH, true, false},
+		{OPEN_LONG, POSITION_SIDE_SHORT, false, POSITION_SIDE_SHORT, false, true},
+		{OPEN_LONG, POSITION_SIDE_LONG, true, POSITION_SIDE_LONG, false, true},
+	}
+	for i, c := range cases {
+		side, reduce, err := ResolvePosition(c.futureType, c.side, c.reduceOnly, false)
+		if (err != nil) != c.wantErr {
+			t.Error(i, "the error is wrong: ", err)
+			return
+		}
+		if err == nil && (side != c.wantSide || reduce != c.wantReduce) {
+			t.Error(i, "the position is wrong: ", side, reduce)
+			return
+		}
+	}
+}
+
+// go test -v -count=1 -run=TestSortFundingRates
+func TestSortFundingRates(t *testing.T) {
+	var hour = int64(60 * 60 * 1000)
+	var rates = []*FundingRate{
+		{Rate: 0.0002, FundingTime: 9 * hour},
+		{Rate: 0.0001, FundingTime: hour},
+		{Rate: 0.0003, FundingTime: 17 * hour, Interval: 4 * hour},
+	}
+	SortFundingRates(rates, 8*hour)
+	if rates[0].FundingTime != hour || rates[0].Interval != 8*hour {
+		t.Error("the first rate is wrong: ", *rates[0])
+		return
+	}
+	if rates[1].Interval != 8*hour || rates[2].Interval != 4*hour {
+		t.Error("the interval is wrong: ", rates[1].Interval, rates[2].Interval)
+	}
+}
@@ -0,0 +1,69 @@
+package goghostex
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// BatchSplit split the count to the ranges [start, end), the size of each one is not bigger than the limit.
+func BatchSplit(count, limit int) [][2]int {
+	var ranges = make([][2]int, 0)
+	if limit <= 0 {
+		limit = count
+	}
+	for start := 0; start < count; start += limit {
+		var end = start + limit
+		if end > count {
+			end = count
+		}
+		ranges = append(ranges, [2]int{start, end})
+	}
+	return ranges
+}
+
+// BatchSequential call the do function on the orders one by one, the i is the index of the order.
+// It is the fallback of the exchange without the batch api.
+func BatchSequential(count int, do func(i int) ([]byte, error)) ([]error, []byte, error) {
+	var errs = make([]error, count)
+	var responses = make([][]byte, 0, count)
+	for i := 0; i < count; i++ {
+		var resp, err = do(i)
+		errs[i] = err
+		if len(resp) > 0 {
+			responses = append(responses, resp)
+		}
+	}
+	return errs, BatchResponse(responses), BatchResult(errs)
+}
+
+// BatchResponse join the raw responses of the batch requests to one json array.
+func BatchResponse(responses [][]byte) []byte {
+	if len(responses) == 1 {
+		return responses[0]
+	}
+	var raws = make([]json.RawMessage, 0, len(responses))
+	for _, resp := range responses {
+		if json.Valid(resp) {
+			raws = append(raws, bytes.TrimSpace(resp))
+		} else {
+			var quoted, _ = json.Marshal(string(resp))
+			raws = append(raws, quoted)
+		}
+	}
+	var joined, _ = json.Marshal(raws)
+	return joined
+}
+
+// BatchResult return the BatchError when any of the orders fail, or nil.
+func BatchResult(errs []error) error {
+	var failed = 0
+	for _, err := range errs {
+		if err != nil {
+			failed++
+		}
+	}
+	if failed == 0 {
+		return nil
+	}
+	return &BatchError{Failed: failed, Total: len(errs)}
+}
@@ -0,0 +1,34 @@
+package goghostex
+
+import (
+	"errors"
+	"testing"
+)
+
+// go test -v -count=1 -run=TestBatch
+func TestBatch(t *testing.T) {
+	var ranges = BatchSplit(12, 5)
+	if len(ranges) != 3 || ranges[2] != [2]int{10, 12} {
+		t.Error("the batch split is wrong: ", ranges)
+		return
+	}
+
+	var errs, resp, err = BatchSequential(3, func(i int) ([]byte, error) {
+		if i == 1 {
+			return []byte("bad gateway"), errors.New("failed")
+		}
+		return []byte(`{"id":1}`), nil
+	})
+	if errs[0] != nil || errs[1] == nil || errs[2] != nil {
+		t.Error("the errors should be per order: ", errs)
+		return
+	}
+	var batchErr, ok = err.(*BatchError)
+	if !ok || batchErr.Failed != 1 || batchErr.Total != 3 {
+		t.Error("the batch error is wrong: ", err)
+		return
+	}
+	if string(resp) != `[{"id":1},"bad gateway",{"id":1}]` {
+		t.Error("the batch response is wrong: ", string(resp))
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"strings"
+	"time"
 )
 
 func NewHttpRequest(
@@ -15,6 +16,37 @@ func NewHttpRequest(
 	postData string,
 	reqHeaders map[string]string,
 ) ([]byte, error) {
+	return NewHttpRequestWithMetrics(nil, "", client, reqType, reqUrl, postData, reqHeaders)
+}
+
+// NewHttpRequestWithMetrics is the same as NewHttpRequest,
+// and it observes the latency, status code and rate-limit weight of the exchange into the metrics if not nil.
+// The path of the url is the endpoint label, so use the NewHttpRequestWithRoute if the path has the id or the symbol.
+func NewHttpRequestWithMetrics(
+	metrics Metrics,
+	exchange string,
+	client *http.Client,
+	reqType,
+	reqUrl,
+	postData string,
+	reqHeaders map[string]string,
+) ([]byte, error) {
+	return NewHttpRequestWithRoute(metrics, exchange, "", client, reqType, reqUrl, postData, reqHeaders)
+}
+
+// NewHttpRequestWithRoute is the same as NewHttpRequestWithMetrics, the route is the endpoint label of the metrics.
+// It should be the uri template like /api/v2/order_book/%s/, so the ids in the path do not create the new series.
+// The path of the url is used if the route is empty.
+func NewHttpRequestWithRoute(
+	metrics Metrics,
+	exchange string,
+	route string,
+	client *http.Client,
+	reqType,
+	reqUrl,
+	postData string,
+	reqHeaders map[string]string,
+) ([]byte, error) {
 
 	var req *http.Request
 	if strings.ToUpper(reqType) == http.MethodGet {
@@ -35,7 +67,19 @@ func NewHttpRequest(
 		}
 	}
 
+	var start = time.Now()
 	resp, err := client.Do(req)
+	if metrics != nil {
+		var statusCode = 0
+		if resp != nil {
+			statusCode = resp.StatusCode
+			observeRateLimitWeight(metrics, exchange, resp.Header)
+		}
+		if route == "" {
+			route = req.URL.Path
+		}
+		metrics.ObserveRest(exchange, strings.ToUpper(reqType), route, statusCode, time.Since(start))
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -66,3 +110,28 @@ func NewHttpRequest(
 
 	return bodyData, nil
 }
+
+// RouteOf return the template which the path of the uri match, the %s in the template match one segment of the path,
+// and the query of both is ignored. The path itself is returned when no template match.
+func RouteOf(uri string, templates []string) string {
+	var path = strings.SplitN(uri, "?", 2)[0]
+	var segments = strings.Split(path, "/")
+	for _, template := range templates {
+		var route = strings.SplitN(template, "?", 2)[0]
+		var parts = strings.Split(route, "/")
+		if len(parts) != len(segments) {
+			continue
+		}
+		var matched = true
+		for i, part := range parts {
+			if part != "%s" && part != segments[i] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return route
+		}
+	}
+	return path
+}
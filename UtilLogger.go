@@ -0,0 +1,143 @@
+package goghostex
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+)
+
+type LogLevel int
+
+const (
+	LOG_LEVEL_DEBUG LogLevel = iota
+	LOG_LEVEL_INFO
+	LOG_LEVEL_WARN
+	LOG_LEVEL_ERROR
+)
+
+var logLevelSymbol = [...]string{"debug", "info", "warn", "error"}
+
+func (ll LogLevel) String() string {
+	if ll < LOG_LEVEL_DEBUG || ll > LOG_LEVEL_ERROR {
+		return "unknown"
+	}
+	return logLevelSymbol[ll]
+}
+
+// the common field keys, the adapters use them to tag the logs.
+const (
+	LOG_FIELD_EXCHANGE   = "exchange"
+	LOG_FIELD_CONN_ID    = "conn_id"
+	LOG_FIELD_INSTRUMENT = "instrument"
+	LOG_FIELD_ERROR      = "error"
+)
+
+type LogField struct {
+	Key   string
+	Value interface{}
+}
+
+func LogF(key string, value interface{}) LogField {
+	return LogField{Key: key, Value: value}
+}
+
+// Logger is the leveled structured logger used in the library, route it to your own log pipeline.
+type Logger interface {
+	Debug(msg string, fields ...LogField)
+	Info(msg string, fields ...LogField)
+	Warn(msg string, fields ...LogField)
+	Error(msg string, fields ...LogField)
+	// With return a child logger which always carry the fields.
+	With(fields ...LogField) Logger
+}
+
+var (
+	globalLogger    Logger = &nopLogger{}
+	globalLoggerMux        = &sync.RWMutex{}
+)
+
+// SetLogger set the global logger, it is used when the client has no logger in APIConfig. nil means no-op.
+func SetLogger(logger Logger) {
+	globalLoggerMux.Lock()
+	defer globalLoggerMux.Unlock()
+	if logger == nil {
+		logger = &nopLogger{}
+	}
+	globalLogger = logger
+}
+
+func GetLogger() Logger {
+	globalLoggerMux.RLock()
+	defer globalLoggerMux.RUnlock()
+	return globalLogger
+}
+
+// GetLogger return the client logger, or the global logger if the client one is not set.
+func (config *APIConfig) GetLogger() Logger {
+	if config == nil || config.Logger == nil {
+		return GetLogger()
+	}
+	return config.Logger
+}
+
+type nopLogger struct{}
+
+func (this *nopLogger) Debug(msg string, fields ...LogField) {}
+func (this *nopLogger) Info(msg string, fields ...LogField)  {}
+func (this *nopLogger) Warn(msg string, fields ...LogField)  {}
+func (this *nopLogger) Error(msg string, fields ...LogField) {}
+func (this *nopLogger) With(fields ...LogField) Logger       { return this }
+
+// NewStdLogger write the logs which level >= the level to the standard logger, nil means log.Default().
+func NewStdLogger(out *log.Logger, level LogLevel) Logger {
+	if out == nil {
+		out = log.Default()
+	}
+	return &stdLogger{out: out, level: level}
+}
+
+type stdLogger struct {
+	out    *log.Logger
+	level  LogLevel
+	fields []LogField
+}
+
+func (this *stdLogger) Debug(msg string, fields ...LogField) {
+	this.write(LOG_LEVEL_DEBUG, msg, fields)
+}
+
+func (this *stdLogger) Info(msg string, fields ...LogField) {
+	this.write(LOG_LEVEL_INFO, msg, fields)
+}
+
+func (this *stdLogger) Warn(msg string, fields ...LogField) {
+	this.write(LOG_LEVEL_WARN, msg, fields)
+}
+
+func (this *stdLogger) Error(msg string, fields ...LogField) {
+	this.write(LOG_LEVEL_ERROR, msg, fields)
+}
+
+func (this *stdLogger) With(fields ...LogField) Logger {
+	var all = make([]LogField, 0, len(this.fields)+len(fields))
+	all = append(all, this.fields...)
+	all = append(all, fields...)
+	return &stdLogger{out: this.out, level: this.level, fields: all}
+}
+
+func (this *stdLogger) write(level LogLevel, msg string, fields []LogField) {
+	if level < this.level {
+		return
+	}
+
+	var builder = strings.Builder{}
+	builder.WriteString(fmt.Sprintf("[%s] %s", level, msg))
+	for _, f := range this.fields {
+		builder.WriteString(fmt.Sprintf(" %s=%v", f.Key, f.Value))
+	}
+	for _, f := range fields {
+		builder.WriteString(fmt.Sprintf(" %s=%v", f.Key, f.Value))
+	}
+	this.out.Println(builder.String())
+}
@@ -0,0 +1,33 @@
+package goghostex
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"testing"
+)
+
+func TestStdLogger(t *testing.T) {
+	var buf = &bytes.Buffer{}
+	var logger = NewStdLogger(log.New(buf, "", 0), LOG_LEVEL_INFO).With(LogF(LOG_FIELD_EXCHANGE, OKEX))
+
+	logger.Debug("debug message")
+	if buf.Len() != 0 {
+		t.Error("the debug message should be filtered")
+		return
+	}
+
+	logger.Warn("sequence gap", LogF(LOG_FIELD_INSTRUMENT, "BTC-USDT-SWAP"))
+	var line = buf.String()
+	if !strings.Contains(line, "[warn] sequence gap exchange=okex instrument=BTC-USDT-SWAP") {
+		t.Error("the log line is wrong: ", line)
+		return
+	}
+
+	var config = &APIConfig{}
+	SetLogger(logger)
+	defer SetLogger(nil)
+	if config.GetLogger() != logger {
+		t.Error("the config should use the global logger")
+	}
+}
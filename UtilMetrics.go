@@ -0,0 +1,91 @@
+package goghostex
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Metrics is the hook of the library measurements, implement it to send the data into your own monitor system,
+// or use the PrometheusMetrics directly.
+type Metrics interface {
+	// the rest request latency and status code, the statusCode is 0 if the request is not sent out.
+	ObserveRest(exchange, method, endpoint string, statusCode int, latency time.Duration)
+	// the rate-limit weight which the exchange return in the response header.
+	SetRateLimitWeight(exchange, name string, weight float64)
+
+	// the websocket client restart.
+	IncWSReconnect(exchange, client string)
+	// the websocket client stop, cause the restart times reach the limit.
+	IncWSRestartLimit(exchange, client string)
+	// the websocket client receive message.
+	IncWSMessage(exchange, client string)
+
+	// the local order book find the sequence gap, and resubscribe or get the snapshot again.
+	IncBookResync(exchange, instrument string)
+	// the duration between now and the last update of the local order book.
+	SetBookStaleness(exchange, instrument string, staleness time.Duration)
+}
+
+// the response headers which carry the rate-limit weight.
+var rateLimitWeightHeaders = []string{
+	"X-Mbx-Used-Weight",
+	"X-Mbx-Used-Weight-1m",
+	"X-Sapi-Used-Ip-Weight-1m",
+	"X-Mbx-Order-Count-10s",
+	"X-Mbx-Order-Count-1m",
+}
+
+var (
+	globalMetrics    Metrics = &nopMetrics{}
+	globalMetricsMux         = &sync.RWMutex{}
+)
+
+// SetMetrics set the global metrics, it is used when the client has no metrics in APIConfig. nil means no-op.
+func SetMetrics(metrics Metrics) {
+	globalMetricsMux.Lock()
+	defer globalMetricsMux.Unlock()
+	if metrics == nil {
+		metrics = &nopMetrics{}
+	}
+	globalMetrics = metrics
+}
+
+func GetMetrics() Metrics {
+	globalMetricsMux.RLock()
+	defer globalMetricsMux.RUnlock()
+	return globalMetrics
+}
+
+// GetMetrics return the client metrics, or the global metrics if the client one is not set.
+func (config *APIConfig) GetMetrics() Metrics {
+	if config == nil || config.Metrics == nil {
+		return GetMetrics()
+	}
+	return config.Metrics
+}
+
+func observeRateLimitWeight(metrics Metrics, exchange string, header http.Header) {
+	for _, name := range rateLimitWeightHeaders {
+		var value = header.Get(name)
+		if value == "" {
+			continue
+		}
+		if weight, err := strconv.ParseFloat(value, 64); err == nil {
+			metrics.SetRateLimitWeight(exchange, name, weight)
+		}
+	}
+}
+
+type nopMetrics struct{}
+
+func (this *nopMetrics) ObserveRest(exchange, method, endpoint string, statusCode int, latency time.Duration) {
+}
+func (this *nopMetrics) SetRateLimitWeight(exchange, name string, weight float64) {}
+func (this *nopMetrics) IncWSReconnect(exchange, client string)                   {}
+func (this *nopMetrics) IncWSRestartLimit(exchange, client string)                {}
+func (this *nopMetrics) IncWSMessage(exchange, client string)                     {}
+func (this *nopMetrics) IncBookResync(exchange, instrument string)                {}
+func (this *nopMetrics) SetBookStaleness(exchange, instrument string, staleness time.Duration) {
+}
@@ -0,0 +1,235 @@
+package goghostex
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	METRIC_REST_DURATION         = "goghostex_rest_request_duration_seconds"
+	METRIC_REST_REQUESTS         = "goghostex_rest_requests_total"
+	METRIC_REST_WEIGHT           = "goghostex_rest_rate_limit_weight"
+	METRIC_WS_RECONNECTS         = "goghostex_ws_reconnects_total"
+	METRIC_WS_RESTART_LIMITS     = "goghostex_ws_restart_limit_total"
+	METRIC_WS_MESSAGES           = "goghostex_ws_messages_total"
+	METRIC_BOOK_RESYNCS          = "goghostex_book_resyncs_total"
+	METRIC_BOOK_STALENESS        = "goghostex_book_staleness_seconds"
+	PROMETHEUS_TEXT_CONTENT_TYPE = "text/plain; version=0.0.4; charset=utf-8"
+)
+
+var DEFAULT_LATENCY_BUCKETS = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+var prometheusMetricMeta = map[string][2]string{
+	METRIC_REST_DURATION:     {"histogram", "The rest request latency in seconds."},
+	METRIC_REST_REQUESTS:     {"counter", "The rest request count by the status code."},
+	METRIC_REST_WEIGHT:       {"gauge", "The rate-limit weight which the exchange return."},
+	METRIC_WS_RECONNECTS:     {"counter", "The websocket restart count."},
+	METRIC_WS_RESTART_LIMITS: {"counter", "The websocket stop count cause the restart limit is reached."},
+	METRIC_WS_MESSAGES:       {"counter", "The websocket received message count."},
+	METRIC_BOOK_RESYNCS:      {"counter", "The local order book resync count cause the sequence gap."},
+	METRIC_BOOK_STALENESS:    {"gauge", "The seconds since the last update of the local order book."},
+}
+
+type promHistogram struct {
+	counts []uint64 // the count of each bucket, not cumulative
+	count  uint64
+	sum    float64
+}
+
+// PrometheusMetrics keep the metrics in memory, and export them in the prometheus text format by ServeHTTP.
+type PrometheusMetrics struct {
+	Buckets []float64 // the latency buckets in seconds, default DEFAULT_LATENCY_BUCKETS, don't change it after use
+
+	mux        sync.Mutex
+	values     map[string]map[string]float64 // counter and gauge, metric name => labels => value
+	histograms map[string]*promHistogram     // labels => histogram
+}
+
+func NewPrometheusMetrics() *PrometheusMetrics {
+	return &PrometheusMetrics{
+		Buckets:    DEFAULT_LATENCY_BUCKETS,
+		values:     make(map[string]map[string]float64),
+		histograms: make(map[string]*promHistogram),
+	}
+}
+
+func (this *PrometheusMetrics) ObserveRest(exchange, method, endpoint string, statusCode int, latency time.Duration) {
+	var labels = promLabels("exchange", exchange, "method", method, "endpoint", endpoint)
+	var seconds = latency.Seconds()
+
+	this.mux.Lock()
+	defer this.mux.Unlock()
+
+	if this.Buckets == nil {
+		this.Buckets = DEFAULT_LATENCY_BUCKETS
+	}
+	if this.histograms == nil {
+		this.histograms = make(map[string]*promHistogram)
+	}
+	var hist, exist = this.histograms[labels]
+	if !exist {
+		hist = &promHistogram{counts: make([]uint64, len(this.Buckets))}
+		this.histograms[labels] = hist
+	}
+	for i, bound := range this.Buckets {
+		if seconds <= bound {
+			hist.counts[i]++
+			break
+		}
+	}
+	hist.count++
+	hist.sum += seconds
+
+	this.add(
+		METRIC_REST_REQUESTS,
+		promLabels("exchange", exchange, "method", method, "endpoint", endpoint, "code", strconv.Itoa(statusCode)),
+		1,
+	)
+}
+
+func (this *PrometheusMetrics) SetRateLimitWeight(exchange, name string, weight float64) {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	this.set(METRIC_REST_WEIGHT, promLabels("exchange", exchange, "name", name), weight)
+}
+
+func (this *PrometheusMetrics) IncWSReconnect(exchange, client string) {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	this.add(METRIC_WS_RECONNECTS, promLabels("exchange", exchange, "client", client), 1)
+}
+
+func (this *PrometheusMetrics) IncWSRestartLimit(exchange, client string) {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	this.add(METRIC_WS_RESTART_LIMITS, promLabels("exchange", exchange, "client", client), 1)
+}
+
+func (this *PrometheusMetrics) IncWSMessage(exchange, client string) {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	this.add(METRIC_WS_MESSAGES, promLabels("exchange", exchange, "client", client), 1)
+}
+
+func (this *PrometheusMetrics) IncBookResync(exchange, instrument string) {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	this.add(METRIC_BOOK_RESYNCS, promLabels("exchange", exchange, "instrument", instrument), 1)
+}
+
+func (this *PrometheusMetrics) SetBookStaleness(exchange, instrument string, staleness time.Duration) {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	this.set(METRIC_BOOK_STALENESS, promLabels("exchange", exchange, "instrument", instrument), staleness.Seconds())
+}
+
+// ServeHTTP export the metrics in the prometheus text format, mount it on the /metrics path.
+func (this *PrometheusMetrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", PROMETHEUS_TEXT_CONTENT_TYPE)
+	_, _ = w.Write([]byte(this.Export()))
+}
+
+// Export return the metrics in the prometheus text format.
+func (this *PrometheusMetrics) Export() string {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+
+	var names = make([]string, 0, len(prometheusMetricMeta))
+	for name := range prometheusMetricMeta {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var builder = strings.Builder{}
+	for _, name := range names {
+		var meta = prometheusMetricMeta[name]
+		if name == METRIC_REST_DURATION {
+			if len(this.histograms) == 0 {
+				continue
+			}
+			builder.WriteString(fmt.Sprintf("# HELP %s %s\n# TYPE %s %s\n", name, meta[1], name, meta[0]))
+			this.writeHistograms(&builder, name)
+			continue
+		}
+
+		var series = this.values[name]
+		if len(series) == 0 {
+			continue
+		}
+		builder.WriteString(fmt.Sprintf("# HELP %s %s\n# TYPE %s %s\n", name, meta[1], name, meta[0]))
+		for _, labels := range sortedKeys(series) {
+			builder.WriteString(fmt.Sprintf("%s{%s} %s\n", name, labels, promFloat(series[labels])))
+		}
+	}
+	return builder.String()
+}
+
+func (this *PrometheusMetrics) writeHistograms(builder *strings.Builder, name string) {
+	var keys = make([]string, 0, len(this.histograms))
+	for labels := range this.histograms {
+		keys = append(keys, labels)
+	}
+	sort.Strings(keys)
+
+	for _, labels := range keys {
+		var hist = this.histograms[labels]
+		var cumulative = uint64(0)
+		for i, bound := range this.Buckets {
+			cumulative += hist.counts[i]
+			builder.WriteString(fmt.Sprintf(
+				"%s_bucket{%s,le=\"%s\"} %d\n", name, labels, promFloat(bound), cumulative,
+			))
+		}
+		builder.WriteString(fmt.Sprintf("%s_bucket{%s,le=\"+Inf\"} %d\n", name, labels, hist.count))
+		builder.WriteString(fmt.Sprintf("%s_sum{%s} %s\n", name, labels, promFloat(hist.sum)))
+		builder.WriteString(fmt.Sprintf("%s_count{%s} %d\n", name, labels, hist.count))
+	}
+}
+
+func (this *PrometheusMetrics) add(name, labels string, delta float64) {
+	if this.values == nil {
+		this.values = make(map[string]map[string]float64)
+	}
+	if this.values[name] == nil {
+		this.values[name] = make(map[string]float64)
+	}
+	this.values[name][labels] += delta
+}
+
+func (this *PrometheusMetrics) set(name, labels string, value float64) {
+	if this.values == nil {
+		this.values = make(map[string]map[string]float64)
+	}
+	if this.values[name] == nil {
+		this.values[name] = make(map[string]float64)
+	}
+	this.values[name][labels] = value
+}
+
+// promLabels render the key value pairs to the prometheus label text, keep the order of the pairs.
+func promLabels(kvs ...string) string {
+	var pairs = make([]string, 0, len(kvs)/2)
+	for i := 0; i+1 < len(kvs); i += 2 {
+		var value = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(kvs[i+1])
+		pairs = append(pairs, fmt.Sprintf("%s=\"%s\"", kvs[i], value))
+	}
+	return strings.Join(pairs, ",")
+}
+
+func promFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func sortedKeys(m map[string]float64) []string {
+	var keys = make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,82 @@
+package goghostex
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPrometheusMetrics(t *testing.T) {
+	var server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-MBX-USED-WEIGHT-1M", "42")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	var metrics = NewPrometheusMetrics()
+	var _, err = NewHttpRequestWithMetrics(
+		metrics, BINANCE, server.Client(), http.MethodGet, server.URL+"/fapi/v1/depth?symbol=BTCUSDT", "", nil,
+	)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	metrics.IncWSReconnect(OKEX, "WSMarketOKEx")
+	metrics.IncBookResync(OKEX, "BTC-USDT-SWAP")
+	metrics.SetBookStaleness(OKEX, "BTC-USDT-SWAP", 1500*time.Millisecond)
+
+	var recorder = httptest.NewRecorder()
+	metrics.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	var text = recorder.Body.String()
+	var expects = []string{
+		`goghostex_rest_requests_total{exchange="binance",method="GET",endpoint="/fapi/v1/depth",code="200"} 1`,
+		`goghostex_rest_request_duration_seconds_count{exchange="binance",method="GET",endpoint="/fapi/v1/depth"} 1`,
+		`goghostex_rest_rate_limit_weight{exchange="binance",name="X-Mbx-Used-Weight-1m"} 42`,
+		`goghostex_ws_reconnects_total{exchange="okex",client="WSMarketOKEx"} 1`,
+		`goghostex_book_resyncs_total{exchange="okex",instrument="BTC-USDT-SWAP"} 1`,
+		`goghostex_book_staleness_seconds{exchange="okex",instrument="BTC-USDT-SWAP"} 1.5`,
+	}
+	for _, expect := range expects {
+		if !strings.Contains(text, expect) {
+			t.Error("the metric is missing: ", expect, "\n", text)
+			return
+		}
+	}
+}
+
+func TestNewHttpRequestWithRoute(t *testing.T) {
+	var server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	var metrics = NewPrometheusMetrics()
+	var templates = []string{"/api/v2/order_book/%s/", "/api/v2/%s/market/%s/?time=%s"}
+	for _, uri := range []string{"/api/v2/order_book/btcusd/", "/api/v2/order_book/btceur/"} {
+		var _, err = NewHttpRequestWithRoute(
+			metrics, BITSTAMP, RouteOf(uri, templates), server.Client(), http.MethodGet, server.URL+uri, "", nil,
+		)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+	}
+
+	var recorder = httptest.NewRecorder()
+	metrics.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	var expect = `goghostex_rest_requests_total{exchange="bitstamp",method="GET",endpoint="/api/v2/order_book/%s/",code="200"} 2`
+	if !strings.Contains(recorder.Body.String(), expect) {
+		t.Error("the route label is wrong: ", recorder.Body.String())
+		return
+	}
+
+	if route := RouteOf("/api/v2/buy/market/btcusd/?time=hour", templates); route != "/api/v2/%s/market/%s/" {
+		t.Error("the template should match: ", route)
+		return
+	}
+	if route := RouteOf("/api/v2/account_balances/", templates); route != "/api/v2/account_balances/" {
+		t.Error("the static path should be the route: ", route)
+	}
+}
@@ -0,0 +1,233 @@
+package goghostex
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+type RoundMode int
+
+const (
+	ROUND_FLOOR RoundMode = iota // round toward zero, the default mode
+	ROUND_CEIL
+	ROUND_NEAREST
+)
+
+// the tolerance of the float division, 1.0000000001 ticks is 1 tick.
+const roundEpsilon = 1e-9
+
+// Normalizer round the order price and amount with the exchange rule, and reject the invalid orders
+// before they are sent. The adapters use the floor mode, if you want the other mode, normalize the
+// order yourself before place it, the rounded values will not be changed by the adapters.
+type Normalizer struct {
+	TickSize        float64 // the price step
+	LotSize         float64 // the amount step
+	PricePrecision  int64
+	AmountPrecision int64
+	MinAmount       float64
+	MinNotional     float64
+	MaxMarketAmount float64
+	UnitAmount      float64 // the contract value, 1 for the spot
+	SettleMode      int64   // the notional of the basis settle contract is amount*UnitAmount
+	// the order only reduce the position, the exchanges accept it under the min notional,
+	// otherwise the small position can not be closed.
+	ReduceOnly bool
+
+	PriceMode  RoundMode
+	AmountMode RoundMode
+}
+
+func (contract *SwapContract) GetNormalizer() *Normalizer {
+	return &Normalizer{
+		TickSize:        contract.TickSize,
+		LotSize:         contract.LotSize,
+		PricePrecision:  contract.PricePrecision,
+		AmountPrecision: contract.AmountPrecision,
+		MinAmount:       contract.MinAmount,
+		MinNotional:     contract.MinNotional,
+		MaxMarketAmount: contract.MaxMarketAmount,
+		UnitAmount:      contract.UnitAmount,
+		SettleMode:      contract.SettleMode,
+	}
+}
+
+func (contract *FutureContract) GetNormalizer() *Normalizer {
+	return &Normalizer{
+		TickSize:        contract.TickSize,
+		LotSize:         contract.LotSize,
+		PricePrecision:  contract.PricePrecision,
+		AmountPrecision: contract.AmountPrecision,
+		MinAmount:       contract.MinAmount,
+		MinNotional:     contract.MinNotional,
+		MaxMarketAmount: contract.MaxMarketAmount,
+		UnitAmount:      contract.UnitAmount,
+		SettleMode:      contract.SettleMode,
+	}
+}
+
+func (info *OneInfo) GetNormalizer() *Normalizer {
+	return &Normalizer{
+		TickSize:        info.TickSize,
+		LotSize:         info.LotSize,
+		PricePrecision:  info.PricePrecision,
+		AmountPrecision: info.AmountPrecision,
+		MinAmount:       info.MinAmount,
+		MinNotional:     info.MinNotional,
+		MaxMarketAmount: info.MaxMarketAmount,
+		UnitAmount:      info.ContractValue,
+		SettleMode:      int64(info.SettleMode),
+	}
+}
+
+func (rule *Rule) GetNormalizer() *Normalizer {
+	return &Normalizer{
+		TickSize:        rule.TickSize,
+		LotSize:         rule.LotSize,
+		PricePrecision:  int64(rule.CounterPrecision),
+		AmountPrecision: int64(rule.BasePrecision),
+		MinAmount:       rule.BaseMinSize,
+		MinNotional:     rule.MinNotional,
+		MaxMarketAmount: rule.MaxMarketAmount,
+		UnitAmount:      1,
+		SettleMode:      SETTLE_MODE_COUNTER,
+	}
+}
+
+// RoundToStep round the value to the multiple of the step, the value is not changed if step <= 0.
+func RoundToStep(v, step float64, mode RoundMode) float64 {
+	if step <= 0 {
+		return v
+	}
+	var steps = v / step
+	switch mode {
+	case ROUND_CEIL:
+		steps = math.Ceil(steps - roundEpsilon)
+	case ROUND_NEAREST:
+		steps = math.Round(steps)
+	default:
+		steps = math.Floor(steps + roundEpsilon)
+	}
+	return steps * step
+}
+
+// stepDecimals return the decimal places of the step, eg: 0.25 => 2, 5 => 0.
+func stepDecimals(step float64) int64 {
+	var text = strconv.FormatFloat(step, 'f', -1, 64)
+	if index := strings.IndexByte(text, '.'); index >= 0 {
+		return int64(len(text) - index - 1)
+	}
+	return 0
+}
+
+func (n *Normalizer) priceStep() float64 {
+	if n.TickSize > 0 {
+		return n.TickSize
+	}
+	return math.Pow10(-int(n.PricePrecision))
+}
+
+func (n *Normalizer) amountStep() float64 {
+	if n.LotSize > 0 {
+		return n.LotSize
+	}
+	return math.Pow10(-int(n.AmountPrecision))
+}
+
+func (n *Normalizer) pricePrecision() int64 {
+	var decimals = stepDecimals(n.priceStep())
+	if n.PricePrecision > decimals {
+		return n.PricePrecision
+	}
+	return decimals
+}
+
+func (n *Normalizer) amountPrecision() int64 {
+	var decimals = stepDecimals(n.amountStep())
+	if n.AmountPrecision > decimals {
+		return n.AmountPrecision
+	}
+	return decimals
+}
+
+func (n *Normalizer) RoundPrice(price float64) float64 {
+	return ToFloat64(FloatToString(RoundToStep(price, n.priceStep(), n.PriceMode), n.pricePrecision()))
+}
+
+func (n *Normalizer) RoundAmount(amount float64) float64 {
+	return ToFloat64(FloatToString(RoundToStep(amount, n.amountStep(), n.AmountMode), n.amountPrecision()))
+}
+
+// FormatPrice round the price and return the string for the request param.
+func (n *Normalizer) FormatPrice(price float64) string {
+	return FloatToString(n.RoundPrice(price), n.pricePrecision())
+}
+
+// FormatAmount round the amount and return the string for the request param.
+func (n *Normalizer) FormatAmount(amount float64) string {
+	return FloatToString(n.RoundAmount(amount), n.amountPrecision())
+}
+
+// Notional return the order value in the counter currency.
+func (n *Normalizer) Notional(price, amount float64) float64 {
+	var unitAmount = n.UnitAmount
+	if unitAmount <= 0 {
+		unitAmount = 1
+	}
+	if n.SettleMode == SETTLE_MODE_BASIS {
+		return amount * unitAmount
+	}
+	return amount * unitAmount * price
+}
+
+// Validate check the rounded price and amount, the price is ignored in the market order,
+// the min notional is ignored in the reduce only order.
+func (n *Normalizer) Validate(price, amount float64, isMarket bool) error {
+	if !isMarket && price <= 0 {
+		return &OrderInvalidError{Field: "price", Msg: fmt.Sprintf("%v is not positive", price)}
+	}
+	if amount <= 0 {
+		return &OrderInvalidError{Field: "amount", Msg: fmt.Sprintf("%v is not positive", amount)}
+	}
+	if n.MinAmount > 0 && amount < n.MinAmount-roundEpsilon {
+		return &OrderInvalidError{Field: "amount", Msg: fmt.Sprintf("%v is less than %v", amount, n.MinAmount)}
+	}
+	if isMarket && n.MaxMarketAmount > 0 && amount > n.MaxMarketAmount+roundEpsilon {
+		return &OrderInvalidError{
+			Field: "amount",
+			Msg:   fmt.Sprintf("%v is bigger than the market order limit %v", amount, n.MaxMarketAmount),
+		}
+	}
+	if !isMarket && !n.ReduceOnly && n.MinNotional > 0 {
+		if notional := n.Notional(price, amount); notional < n.MinNotional-roundEpsilon {
+			return &OrderInvalidError{
+				Field: "notional",
+				Msg:   fmt.Sprintf("%v is less than %v", notional, n.MinNotional),
+			}
+		}
+	}
+	return nil
+}
+
+// Normalize round the price and the amount, then validate them.
+func (n *Normalizer) Normalize(price, amount float64, isMarket bool) (float64, float64, error) {
+	var roundAmount = n.RoundAmount(amount)
+	if isMarket {
+		return price, roundAmount, n.Validate(price, roundAmount, true)
+	}
+	var roundPrice = n.RoundPrice(price)
+	return roundPrice, roundAmount, n.Validate(roundPrice, roundAmount, false)
+}
+
+// NormalizePrice round and check the price only, it is for the close position order which has no amount.
+func (n *Normalizer) NormalizePrice(price float64, isMarket bool) (float64, error) {
+	if isMarket {
+		return price, nil
+	}
+	var roundPrice = n.RoundPrice(price)
+	if roundPrice <= 0 {
+		return roundPrice, &OrderInvalidError{Field: "price", Msg: fmt.Sprintf("%v is not positive", roundPrice)}
+	}
+	return roundPrice, nil
+}
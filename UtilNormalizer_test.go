@@ -0,0 +1,75 @@
+package goghostex
+
+import (
+	"testing"
+)
+
+// go test -v -count=1 -run=TestNormalizer
+func TestNormalizer(t *testing.T) {
+	var contract = &SwapContract{
+		SettleMode:      SETTLE_MODE_COUNTER,
+		UnitAmount:      0.01,
+		TickSize:        0.05,
+		PricePrecision:  2,
+		AmountPrecision: 0,
+		MinAmount:       1,
+		MinNotional:     5,
+		MaxMarketAmount: 100,
+	}
+	var normalizer = contract.GetNormalizer()
+
+	var cases = []struct {
+		mode  RoundMode
+		price string
+	}{
+		{ROUND_FLOOR, "487.75"},
+		{ROUND_CEIL, "487.8"},
+		{ROUND_NEAREST, "487.8"},
+	}
+	for _, c := range cases {
+		normalizer.PriceMode = c.mode
+		if price := normalizer.FormatPrice(487.7777); price != c.price {
+			t.Error("the price is wrong: ", c.mode, price)
+			return
+		}
+	}
+	// the price on the tick is not changed by any mode.
+	normalizer.PriceMode = ROUND_CEIL
+	if price := normalizer.FormatPrice(0.3); price != "0.3" {
+		t.Error("the price on the tick is changed: ", price)
+		return
+	}
+
+	var _, amount, err = normalizer.Normalize(1000, 1.9, false)
+	if err != nil || amount != 1 {
+		t.Error("the amount is wrong: ", amount, err)
+		return
+	}
+
+	var invalids = []struct {
+		price, amount float64
+		isMarket      bool
+		field         string
+	}{
+		{100, 0.5, false, "amount"},
+		{100, 3, false, "notional"},
+		{0, 3, false, "price"},
+		{0, 101, true, "amount"},
+	}
+	for _, c := range invalids {
+		var _, _, err = normalizer.Normalize(c.price, c.amount, c.isMarket)
+		if invalid, ok := err.(*OrderInvalidError); !ok || invalid.Field != c.field {
+			t.Error("the order should be invalid: ", c, err)
+			return
+		}
+	}
+	// the reduce only order is not limited by the min notional.
+	normalizer.ReduceOnly = true
+	if _, _, err = normalizer.Normalize(100, 3, false); err != nil {
+		t.Error("the reduce only order should skip the min notional: ", err)
+		return
+	}
+	if !IsReduce(LIQUIDATE_LONG, false) || IsReduce(OPEN_SHORT, false) {
+		t.Error("the liquidate order should be the reduce order")
+	}
+}
@@ -2,10 +2,8 @@ package goghostex
 
 import (
 	"fmt"
-	"log"
 	"net/http"
 	"net/http/httputil"
-	"net/url"
 	"sync"
 	"time"
 
@@ -15,6 +13,7 @@ import (
 type WsConfig struct {
 	WsUrl                 string              // websocket server url, necessary
 	ProxyUrl              string              // proxy url, not necessary
+	DialerConfig          *WsDialerConfig     // the dialer setting of the connection, not necessary
 	ReqHeaders            map[string][]string // set the head info ,when connecting, not necessary
 	HeartbeatIntervalTime time.Duration       // the heartbeat interval, necessary
 	HeartbeatData         []byte              // the raw text of heartbeat data for example: ping, necessary if heartbeatfunc is nil
@@ -26,6 +25,7 @@ type WsConfig struct {
 	UnCompressFunc        func([]byte) ([]byte, error) // the uncompress func, not necessary
 	ErrorHandleFunc       func(err error)              // the error handle func, not necessary
 	IsDump                bool                         // is print the connect info, not necessary
+	Logger                Logger                       // the logger of the connection, use the global logger if nil
 }
 
 type WsConn struct {
@@ -42,6 +42,8 @@ type WsConn struct {
 	closeRecv      chan struct{}
 	closeCheck     chan struct{}
 	subs           []interface{}
+
+	dialer *websocket.Dialer // built once from the DialerConfig and the ProxyUrl in the Build
 }
 
 // websocket build config
@@ -63,6 +65,11 @@ func (b *WsBuilder) ProxyUrl(proxyUrl string) *WsBuilder {
 	return b
 }
 
+func (b *WsBuilder) DialerConfig(config *WsDialerConfig) *WsBuilder {
+	b.wsConfig.DialerConfig = config
+	return b
+}
+
 func (b *WsBuilder) ReqHeader(key, value string) *WsBuilder {
 	b.wsConfig.ReqHeaders[key] = append(b.wsConfig.ReqHeaders[key], value)
 	return b
@@ -106,21 +113,54 @@ func (b *WsBuilder) ErrorHandleFunc(f func(err error)) *WsBuilder {
 	return b
 }
 
+func (b *WsBuilder) Logger(logger Logger) *WsBuilder {
+	b.wsConfig.Logger = logger
+	return b
+}
+
+// Build connect the websocket, it panics on the wrong dialer config or the dial failure, BuildE return the error.
 func (b *WsBuilder) Build() *WsConn {
+	wsConn, err := b.BuildE()
+	if err != nil {
+		panic(err)
+	}
+	return wsConn
+}
+
+// BuildE validate the dialer config and connect the websocket, the wrong config or the dial failure return the error.
+func (b *WsBuilder) BuildE() (*WsConn, error) {
 	if b.wsConfig.ErrorHandleFunc == nil {
+		var logger = b.wsConfig.Logger
 		b.wsConfig.ErrorHandleFunc = func(err error) {
-			log.Println(err)
+			if logger == nil {
+				logger = GetLogger()
+			}
+			logger.Error("websocket error", LogF(LOG_FIELD_ERROR, err))
 		}
 	}
 	wsConn := &WsConn{WsConfig: *b.wsConfig}
-	return wsConn.NewWs()
+	if err := wsConn.initDialer(); err != nil {
+		return nil, err
+	}
+	return wsConn.NewWsE()
 }
 
+// NewWs connect the websocket and start the timers, it panics on the dial failure, NewWsE return the error.
 func (ws *WsConn) NewWs() *WsConn {
+	wsConn, err := ws.NewWsE()
+	if err != nil {
+		panic(err)
+	}
+	return wsConn
+}
+
+func (ws *WsConn) NewWsE() (*WsConn, error) {
 	ws.Lock()
 	defer ws.Unlock()
 
-	ws.connect()
+	if err := ws.connect(); err != nil {
+		return nil, err
+	}
 
 	ws.mu = make(chan struct{}, 1)
 	ws.closeHeartbeat = make(chan struct{}, 1)
@@ -132,35 +172,49 @@ func (ws *WsConn) NewWs() *WsConn {
 	ws.ReConnectTimer()
 	ws.checkStatusTimer()
 
-	return ws
+	return ws, nil
 }
 
-func (ws *WsConn) connect() {
-	dialer := websocket.DefaultDialer
+// initDialer build the dialer once, so the wrong proxy or local address is found before the connection.
+func (ws *WsConn) initDialer() error {
+	// copy the dialer config, the ProxyUrl is kept for the compatibility.
+	var dialerConfig = WsDialerConfig{}
+	if ws.DialerConfig != nil {
+		dialerConfig = *ws.DialerConfig
+	}
+	if dialerConfig.ProxyUrl == "" {
+		dialerConfig.ProxyUrl = ws.ProxyUrl
+	}
 
-	if ws.ProxyUrl != "" {
-		proxy, err := url.Parse(ws.ProxyUrl)
-		if err == nil {
-			log.Println("proxy url :", proxy)
-			dialer.Proxy = http.ProxyURL(proxy)
-		} else {
-			log.Println("proxy url error ? ", err)
+	dialer, err := NewWsDialer(&dialerConfig)
+	if err != nil {
+		return err
+	}
+	ws.dialer = dialer
+	return nil
+}
+
+func (ws *WsConn) connect() error {
+	if ws.dialer == nil {
+		if err := ws.initDialer(); err != nil {
+			return err
 		}
 	}
 
-	wsConn, resp, err := dialer.Dial(ws.WsUrl, http.Header(ws.ReqHeaders))
+	wsConn, resp, err := ws.dialer.Dial(ws.WsUrl, http.Header(ws.ReqHeaders))
 	if err != nil {
-		panic(err)
+		return err
 	}
 
 	ws.Conn = wsConn
 
 	if ws.IsDump {
 		dumpData, _ := httputil.DumpResponse(resp, true)
-		log.Println(string(dumpData))
+		ws.logger().Debug("websocket connected", LogF("response", string(dumpData)))
 	}
 
 	ws.UpdateActiveTime()
+	return nil
 }
 
 func (ws *WsConn) SendJsonMessage(v interface{}) error {
@@ -183,14 +237,20 @@ func (ws *WsConn) ReConnect() {
 	ws.Lock()
 	defer ws.Unlock()
 
-	log.Println("close ws  error :", ws.Close())
+	if err := ws.Close(); err != nil {
+		ws.logger().Warn("close websocket error", LogF(LOG_FIELD_ERROR, err))
+	}
 	time.Sleep(time.Second)
 
-	ws.connect()
+	// the reconnect run in the background, the error is handled and the next timer try again.
+	if err := ws.connect(); err != nil {
+		ws.ErrorHandleFunc(err)
+		return
+	}
 
 	//re subscribe
 	for _, sub := range ws.subs {
-		log.Println("subscribe:", sub)
+		ws.logger().Info("resubscribe", LogF("sub", sub))
 		_ = ws.SendJsonMessage(sub)
 	}
 }
@@ -207,12 +267,12 @@ func (ws *WsConn) ReConnectTimer() {
 		for {
 			select {
 			case <-timer.C:
-				log.Println("reconnect websocket")
+				ws.logger().Info("reconnect websocket")
 				ws.ReConnect()
 				timer.Reset(ws.ReconnectIntervalTime)
 			case <-ws.closeReconnect:
 				timer.Stop()
-				log.Println("close websocket connect ,  exiting reconnect timer goroutine.")
+				ws.logger().Debug("close websocket connect, exiting reconnect timer goroutine")
 				return
 			}
 		}
@@ -234,12 +294,12 @@ func (ws *WsConn) checkStatusTimer() {
 			case <-timer.C:
 				now := time.Now()
 				if now.Sub(ws.activeTime) >= 2*ws.HeartbeatIntervalTime {
-					log.Println("active time [ ", ws.activeTime, " ] has expired , begin reconnect ws.")
+					ws.logger().Warn("active time has expired, begin reconnect ws", LogF("active_time", ws.activeTime))
 					ws.ReConnect()
 				}
 				timer.Reset(ws.HeartbeatIntervalTime)
 			case <-ws.closeCheck:
-				log.Println("check status timer exiting")
+				ws.logger().Debug("check status timer exiting")
 				return
 			}
 		}
@@ -247,7 +307,7 @@ func (ws *WsConn) checkStatusTimer() {
 }
 
 func (ws *WsConn) HeartbeatTimer() {
-	log.Println("heartbeat interval time = ", ws.HeartbeatIntervalTime)
+	ws.logger().Debug("heartbeat timer", LogF("interval", ws.HeartbeatIntervalTime))
 	if ws.HeartbeatIntervalTime == 0 || (ws.HeartbeatDataType == 0 && ws.HeartbeatData == nil) {
 		return
 	}
@@ -261,12 +321,12 @@ func (ws *WsConn) HeartbeatTimer() {
 			case <-timer.C:
 				err := ws.WriteMessage(ws.HeartbeatDataType, ws.HeartbeatData)
 				if err != nil {
-					log.Println("heartbeat error , ", err)
+					ws.logger().Warn("heartbeat error", LogF(LOG_FIELD_ERROR, err))
 					time.Sleep(time.Second)
 				}
 			case <-ws.closeHeartbeat:
 				timer.Stop()
-				log.Println("close websocket connect , exiting heartbeat goroutine.")
+				ws.logger().Debug("close websocket connect, exiting heartbeat goroutine")
 				return
 			}
 		}
@@ -274,7 +334,7 @@ func (ws *WsConn) HeartbeatTimer() {
 }
 
 func (ws *WsConn) Subscribe(subEvent interface{}) error {
-	log.Println("Subscribe:", subEvent)
+	ws.logger().Info("subscribe", LogF("sub", subEvent))
 	err := ws.SendJsonMessage(subEvent)
 	if err != nil {
 		return err
@@ -291,7 +351,7 @@ func (ws *WsConn) ReceiveMessage() {
 
 			if len(ws.closeRecv) > 0 {
 				<-ws.closeRecv
-				log.Println("close websocket , exiting receive message goroutine.")
+				ws.logger().Debug("close websocket, exiting receive message goroutine")
 				return
 			}
 
@@ -323,7 +383,7 @@ func (ws *WsConn) ReceiveMessage() {
 				ws.CloseWs()
 				return
 			default:
-				log.Println("error websocket message type , content is :\n", string(msg))
+				ws.logger().Warn("error websocket message type", LogF("msg_type", t), LogF("content", string(msg)))
 			}
 		}
 	}()
@@ -349,8 +409,15 @@ func (ws *WsConn) CloseWs() {
 
 	err := ws.Close()
 	if err != nil {
-		log.Println("close websocket error , ", err)
+		ws.logger().Warn("close websocket error", LogF(LOG_FIELD_ERROR, err))
+	}
+}
+
+func (ws *WsConn) logger() Logger {
+	if ws.Logger != nil {
+		return ws.Logger
 	}
+	return GetLogger()
 }
 
 func (ws *WsConn) clearChannel(c chan struct{}) {
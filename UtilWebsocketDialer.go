@@ -0,0 +1,71 @@
+package goghostex
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	DEFAULT_WEBSOCKET_HANDSHAKE_TIMEOUT = 45 * time.Second
+)
+
+// WsDialerConfig is the dial setting of one websocket connection, every ws client keeps its own one,
+// so the proxy or the local address will not leak into the other connections in the process.
+type WsDialerConfig struct {
+	ProxyUrl          string        // http://, https:// or socks5:// proxy url, not necessary
+	TLSConfig         *tls.Config   // the tls client config, not necessary
+	LocalAddr         string        // the local ip which the connection bind to, for the multi nic host, not necessary
+	HandshakeTimeout  time.Duration // the handshake timeout, default 45 seconds, not necessary
+	EnableCompression bool          // negotiate the per message compression (RFC 7692), not necessary
+}
+
+// NewWsDialer build a new dialer by the config, the config can be nil, then the default dialer setting is used.
+func NewWsDialer(config *WsDialerConfig) (*websocket.Dialer, error) {
+	var dialer = &websocket.Dialer{
+		Proxy:            http.ProxyFromEnvironment,
+		HandshakeTimeout: DEFAULT_WEBSOCKET_HANDSHAKE_TIMEOUT,
+	}
+	if config == nil {
+		return dialer, nil
+	}
+
+	if config.ProxyUrl != "" {
+		var proxy, err = url.Parse(config.ProxyUrl)
+		if err != nil {
+			return nil, fmt.Errorf("the proxy url %s is wrong: %s", config.ProxyUrl, err.Error())
+		}
+		switch proxy.Scheme {
+		case "http", "https", "socks5":
+			dialer.Proxy = http.ProxyURL(proxy)
+		default:
+			return nil, fmt.Errorf("the proxy scheme %s is not supported, use http/https/socks5", proxy.Scheme)
+		}
+	}
+
+	if config.LocalAddr != "" {
+		var ip = net.ParseIP(config.LocalAddr)
+		if ip == nil {
+			return nil, fmt.Errorf("the local address %s is not a valid ip", config.LocalAddr)
+		}
+		var netDialer = &net.Dialer{
+			LocalAddr: &net.TCPAddr{IP: ip},
+		}
+		dialer.NetDialContext = netDialer.DialContext
+	}
+
+	if config.TLSConfig != nil {
+		dialer.TLSClientConfig = config.TLSConfig.Clone()
+	}
+	if config.HandshakeTimeout > 0 {
+		dialer.HandshakeTimeout = config.HandshakeTimeout
+	}
+	dialer.EnableCompression = config.EnableCompression
+
+	return dialer, nil
+}
@@ -6,6 +6,8 @@ import (
 	"io/ioutil"
 	"testing"
 	"time"
+
+	"github.com/gorilla/websocket"
 )
 
 func Test_time(t *testing.T) {
@@ -63,3 +65,54 @@ func TestNewWsConn(t *testing.T) {
 	time.Sleep(time.Second * 20)
 	ws.CloseWs()
 }
+
+func TestNewWsDialer(t *testing.T) {
+	var dialer, err = NewWsDialer(nil)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if dialer.HandshakeTimeout != DEFAULT_WEBSOCKET_HANDSHAKE_TIMEOUT {
+		t.Error("the default handshake timeout is wrong")
+		return
+	}
+
+	dialer, err = NewWsDialer(&WsDialerConfig{
+		ProxyUrl:          "socks5://127.0.0.1:1090",
+		LocalAddr:         "127.0.0.1",
+		HandshakeTimeout:  5 * time.Second,
+		EnableCompression: true,
+	})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if dialer.Proxy == nil || dialer.NetDialContext == nil || !dialer.EnableCompression {
+		t.Error("the dialer config is not applied")
+		return
+	}
+	if dialer == websocket.DefaultDialer {
+		t.Error("the dialer must not be the global default dialer")
+		return
+	}
+
+	if _, err = NewWsDialer(&WsDialerConfig{ProxyUrl: "ftp://127.0.0.1:21"}); err == nil {
+		t.Error("the ftp proxy should be rejected")
+		return
+	}
+	if _, err = NewWsDialer(&WsDialerConfig{LocalAddr: "not-an-ip"}); err == nil {
+		t.Error("the wrong local address should be rejected")
+	}
+}
+
+func TestWsBuilder_BuildE(t *testing.T) {
+	var ws, err = NewWsBuilder().WsUrl("wss://127.0.0.1:1").ProxyUrl("ftp://127.0.0.1:21").BuildE()
+	if err == nil || ws != nil {
+		t.Error("the wrong proxy should return the error, not panic")
+		return
+	}
+	ws, err = NewWsBuilder().WsUrl("wss://127.0.0.1:1").DialerConfig(&WsDialerConfig{LocalAddr: "not-an-ip"}).BuildE()
+	if err == nil || ws != nil {
+		t.Error("the wrong local address should return the error, not panic")
+	}
+}
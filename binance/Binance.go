@@ -96,6 +96,11 @@ func New(config *APIConfig) *Binance {
 		Locker:  new(sync.Mutex),
 		Infos:   make(map[string]*OneInfo),
 	}
+	binance.Option = &Option{
+		Binance:   binance,
+		Locker:    new(sync.Mutex),
+		contracts: make(map[string]*OptionContract),
+	}
 	return binance
 }
 
@@ -106,6 +111,7 @@ type Binance struct {
 	Swap   *Swap
 	Future *Future
 	One    *One
+	Option *Option
 }
 
 func (this *Binance) GetExchangeName() string {
@@ -123,7 +129,9 @@ func (this *Binance) buildParamsSigned(postForm *url.Values) error {
 }
 
 func (this *Binance) DoRequest(httpMethod, uri, reqBody string, response interface{}) ([]byte, error) {
-	resp, err := NewHttpRequest(
+	resp, err := NewHttpRequestWithMetrics(
+		this.config.GetMetrics(),
+		BINANCE,
 		this.config.HttpClient,
 		httpMethod,
 		this.config.Endpoint+uri,
@@ -159,3 +167,35 @@ func (this *Binance) ExchangeInfo() ([]byte, error) {
 		return body, nil
 	}
 }
+
+// binanceFilters is the trade rule in the filters of the exchange info.
+type binanceFilters struct {
+	TickSize        float64
+	LotSize         float64
+	MinAmount       float64
+	MinNotional     float64
+	MaxMarketAmount float64
+}
+
+func parseFilters(filters []map[string]interface{}) binanceFilters {
+	var result = binanceFilters{TickSize: -1}
+	for _, f := range filters {
+		switch f["filterType"] {
+		case "PRICE_FILTER":
+			result.TickSize = ToFloat64(f["tickSize"])
+		case "LOT_SIZE":
+			result.LotSize = ToFloat64(f["stepSize"])
+			result.MinAmount = ToFloat64(f["minQty"])
+		case "MARKET_LOT_SIZE":
+			result.MaxMarketAmount = ToFloat64(f["maxQty"])
+		case "MIN_NOTIONAL", "NOTIONAL":
+			// the spot use minNotional, the usdt margined future use notional.
+			if v, exist := f["minNotional"]; exist {
+				result.MinNotional = ToFloat64(v)
+			} else if v, exist := f["notional"]; exist {
+				result.MinNotional = ToFloat64(v)
+			}
+		}
+	}
+	return result
+}
@@ -131,3 +131,36 @@ func (future *Future) GetPairFlow(pair Pair) ([]*FutureAccountItem, []byte, erro
 
 	return items, resp, nil
 }
+
+// the delivery future share the leverage api with the swap, the symbol is the contract name.
+func (future *Future) SetLeverage(pair Pair, contractType string, lever int64, side PositionSide) ([]byte, error) {
+	contract, err := future.GetContract(pair, contractType)
+	if err != nil {
+		return nil, err
+	}
+	return future.Binance.Swap.setLeverage(contract.ContractName, contract.SettleMode, lever)
+}
+
+func (future *Future) GetLeverage(pair Pair, contractType string, side PositionSide) (int64, []byte, error) {
+	contract, err := future.GetContract(pair, contractType)
+	if err != nil {
+		return 0, nil, err
+	}
+	return future.Binance.Swap.getLeverage(contract.ContractName, contract.SettleMode)
+}
+
+func (future *Future) SetMarginMode(pair Pair, contractType string, marginType string) ([]byte, error) {
+	contract, err := future.GetContract(pair, contractType)
+	if err != nil {
+		return nil, err
+	}
+	return future.Binance.Swap.setMarginMode(contract.ContractName, contract.SettleMode, marginType)
+}
+
+func (future *Future) GetLeverageBrackets(pair Pair, contractType string) ([]*LeverageBracket, []byte, error) {
+	contract, err := future.GetContract(pair, contractType)
+	if err != nil {
+		return nil, nil, err
+	}
+	return future.Binance.Swap.getLeverageBrackets(pair, contract.ContractName, contract.SettleMode)
+}
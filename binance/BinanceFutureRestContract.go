@@ -357,17 +357,13 @@ func (future *Future) getCMContracts() ([]*FutureContract, []byte, error) {
 
 		var rawData, _ = json.Marshal(item)
 		var priceMaxScale, priceMinScale float64 = 1.2, 0.8
-		var tickSize float64 = -1
 		for _, filter := range item.Filters {
 			if value, ok := filter["filterType"].(string); ok && value == "PERCENT_PRICE" {
 				priceMaxScale = ToFloat64(filter["multiplierUp"])
 				priceMinScale = ToFloat64(filter["multiplierDown"])
 			}
-
-			if value, ok := filter["filterType"].(string); ok && value == "PRICE_FILTER" {
-				tickSize = ToFloat64(filter["tickSize"])
-			}
 		}
+		var filters = parseFilters(item.Filters)
 
 		var dueTime = time.Unix(item.DeliveryDate/1000, 0).In(future.config.Location)
 		var openTime = time.Unix(item.OnboardDate/1000, 0).In(future.config.Location)
@@ -402,9 +398,13 @@ func (future *Future) getCMContracts() ([]*FutureContract, []byte, error) {
 			DueDate:       dueTime.Format(GO_BIRTHDAY),
 
 			UnitAmount:      item.ContractSize,
-			TickSize:        tickSize,
+			TickSize:        filters.TickSize,
 			PricePrecision:  item.PricePrecision,
 			AmountPrecision: item.QuantityPrecision,
+			LotSize:         filters.LotSize,
+			MinAmount:       filters.MinAmount,
+			MinNotional:     filters.MinNotional,
+			MaxMarketAmount: filters.MaxMarketAmount,
 
 			MaxScalePriceLimit: priceMaxScale,
 			MinScalePriceLimit: priceMinScale,
@@ -462,17 +462,13 @@ func (future *Future) getUMContracts() ([]*FutureContract, []byte, error) {
 		var rawData, _ = json.Marshal(item)
 
 		var priceMaxScale, priceMinScale float64 = 1.2, 0.8
-		var tickSize float64 = -1
 		for _, filter := range item.Filters {
 			if value, ok := filter["filterType"].(string); ok && value == "PERCENT_PRICE" {
 				priceMaxScale = ToFloat64(filter["multiplierUp"])
 				priceMinScale = ToFloat64(filter["multiplierDown"])
 			}
-
-			if value, ok := filter["filterType"].(string); ok && value == "PRICE_FILTER" {
-				tickSize = ToFloat64(filter["tickSize"])
-			}
 		}
+		var filters = parseFilters(item.Filters)
 
 		var dueTime = time.Unix(item.DeliveryDate/1000, 0).In(future.config.Location)
 		var openTime = time.Unix(item.OnboardDate/1000, 0).In(future.config.Location)
@@ -513,9 +509,13 @@ func (future *Future) getUMContracts() ([]*FutureContract, []byte, error) {
 			DueDate:       dueTime.Format(GO_BIRTHDAY),
 
 			UnitAmount:      1,
-			TickSize:        tickSize,
+			TickSize:        filters.TickSize,
 			PricePrecision:  item.PricePrecision,
 			AmountPrecision: item.QuantityPrecision,
+			LotSize:         filters.LotSize,
+			MinAmount:       filters.MinAmount,
+			MinNotional:     filters.MinNotional,
+			MaxMarketAmount: filters.MaxMarketAmount,
 
 			MaxScalePriceLimit: priceMaxScale,
 			MinScalePriceLimit: priceMinScale,
@@ -293,6 +293,69 @@ func (future *Future) GetKlineRecords(
 	return GetAscFutureKline(list), resp, nil
 }
 
+func (future *Future) GetMarkKline(
+	contractType string,
+	pair Pair,
+	period, size, since int,
+) ([]*FutureKline, []byte, error) {
+	return future.getPriceKline(SWAP_MARK_KLINE_API, contractType, pair, period, size, since)
+}
+
+func (future *Future) GetIndexKline(
+	contractType string,
+	pair Pair,
+	period, size, since int,
+) ([]*FutureKline, []byte, error) {
+	return future.getPriceKline(SWAP_INDEX_KLINE_API, contractType, pair, period, size, since)
+}
+
+func (future *Future) GetPremiumKline(
+	contractType string,
+	pair Pair,
+	period, size, since int,
+) ([]*FutureKline, []byte, error) {
+	return future.getPriceKline(SWAP_PREMIUM_KLINE_API, contractType, pair, period, size, since)
+}
+
+// the delivery future share the price kline api with the swap, the symbol is the contract name.
+func (future *Future) getPriceKline(
+	api, contractType string,
+	pair Pair,
+	period, size, since int,
+) ([]*FutureKline, []byte, error) {
+	contract, err := future.GetContract(pair, contractType)
+	if err != nil {
+		return nil, nil, err
+	}
+	klines, resp, err := future.Binance.Swap.getPriceKline(
+		api, pair, contract.ContractName, contract.SettleMode, period, size, since,
+	)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	var list = make([]*FutureKline, 0, len(klines))
+	for _, k := range klines {
+		var timestamp = ToInt64(k[0])
+		list = append(list, &FutureKline{
+			Kline: Kline{
+				Pair:      pair,
+				Exchange:  BINANCE,
+				Timestamp: timestamp,
+				Date:      time.Unix(timestamp/1000, 0).In(future.config.Location).Format(GO_BIRTHDAY),
+				Open:      ToFloat64(k[1]),
+				High:      ToFloat64(k[2]),
+				Low:       ToFloat64(k[3]),
+				Close:     ToFloat64(k[4]),
+			},
+			ContractType: contractType,
+			DueTimestamp: contract.DueTimestamp,
+			DueDate:      contract.DueDate,
+		})
+	}
+	return GetAscFutureKline(list), resp, nil
+}
+
 func (future *Future) GetCandles(
 	dueTimestamp int64,
 	symbol string,
@@ -450,7 +513,9 @@ func (future *Future) KeepAlive() {
 }
 
 func (future *Future) DoRequest(httpMethod, endPoint, uri, reqBody string, response interface{}) ([]byte, error) {
-	resp, err := NewHttpRequest(
+	resp, err := NewHttpRequestWithMetrics(
+		future.config.GetMetrics(),
+		BINANCE,
 		future.config.HttpClient,
 		httpMethod,
 		endPoint+uri,
@@ -470,21 +535,6 @@ func (future *Future) DoRequest(httpMethod, endPoint, uri, reqBody string, respo
 	}
 }
 
-func (future *Future) getFutureType(side, sidePosition string) FutureType {
-	if side == "BUY" && sidePosition == "LONG" {
-		return OPEN_LONG
-	} else if side == "SELL" && sidePosition == "SHORT" {
-		return OPEN_SHORT
-	} else if side == "SELL" && sidePosition == "LONG" {
-		return LIQUIDATE_LONG
-	} else if side == "BUY" && sidePosition == "SHORT" {
-		return LIQUIDATE_SHORT
-	} else {
-		panic("input error, do not use BOTH. ")
-	}
-
-}
-
 //// return the binance style symbol
 //func (future *Future) getBNSymbol(contractName string) string {
 //	var infos = strings.Split(contractName, "-")
@@ -78,19 +78,28 @@ func (future *Future) PlaceOrder(order *FutureOrder) ([]byte, error) {
 	if side, exist = sideRelation[order.Type]; !exist {
 		return nil, errors.New("future type not found. ")
 	}
-	if positionSide, exist = positionSideRelation[order.Type]; !exist {
-		return nil, errors.New("future type not found. ")
+	if side, _, err := ResolvePosition(order.Type, POSITION_SIDE_AUTO, false, false); err != nil {
+		return nil, err
+	} else {
+		positionSide = positionSideRelation[side]
 	}
 	if placeType, exist = placeTypeRelation[order.PlaceType]; !exist {
 		return nil, errors.New("place type not found. ")
 	}
 
+	var normalizer = contract.GetNormalizer()
+	normalizer.ReduceOnly = IsReduce(order.Type, false)
+	price, _, err := normalizer.Normalize(order.Price, float64(order.Amount), false)
+	if err != nil {
+		return nil, err
+	}
+
 	param := url.Values{}
 	param.Set("symbol", contract.ContractName)
 	param.Set("side", side)
 	param.Set("positionSide", positionSide)
 	param.Set("type", "LIMIT")
-	param.Set("price", FloatToPrice(order.Price, contract.PricePrecision, contract.TickSize))
+	param.Set("price", normalizer.FormatPrice(price))
 	param.Set("quantity", fmt.Sprintf("%d", order.Amount))
 	// "GTC": 成交为止, 一直有效。
 	// "IOC": 无法立即成交(吃单)的部分就撤销。
@@ -226,6 +235,8 @@ func (future *Future) GetOrders(pair Pair, contractType string) ([]*FutureOrder,
 		Price         float64 `json:"price,string"`
 		Side          string  `json:"side"`
 		PositionSide  string  `json:"positionSide"`
+		ReduceOnly    bool    `json:"reduceOnly"`
+		ClosePosition bool    `json:"closePosition"`
 		Status        string  `json:"status"`
 		Symbol        string  `json:"symbol"`
 		Pair          string  `json:"pair"`
@@ -247,6 +258,10 @@ func (future *Future) GetOrders(pair Pair, contractType string) ([]*FutureOrder,
 
 	orders := make([]*FutureOrder, 0)
 	for _, item := range response {
+		futureType, err := getFutureType(item.Side, item.PositionSide, item.ReduceOnly || item.ClosePosition)
+		if err != nil {
+			return nil, resp, err
+		}
 		placeTime := time.Unix(item.Time/1000, item.Time%1000).In(future.config.Location)
 		updateTime := time.Unix(item.UpdateTime/1000, item.UpdateTime%1000).In(future.config.Location)
 
@@ -263,7 +278,7 @@ func (future *Future) GetOrders(pair Pair, contractType string) ([]*FutureOrder,
 			DealDatetime:   updateTime.Format(GO_BIRTHDAY),
 			Status:         _INTERNAL_ORDER_STATUS_REVERSE_CONVERTER[item.Status],
 			PlaceType:      _INTERNAL_PLACE_TYPE_REVERSE_CONVERTER[item.TimeInForce],
-			Type:           future.getFutureType(item.Side, item.PositionSide),
+			Type:           futureType,
 			//LeverRate: item.,
 			//Fee:item.,
 			Pair:         pair,
@@ -59,11 +59,21 @@ func (margin *Margin) PlaceOrder(order *Order) ([]byte, error) {
 		return nil, errors.New("Can not deal the order side. ")
 	}
 
+	rule, err := margin.Spot.getRule(order.Pair)
+	if err != nil {
+		return nil, err
+	}
+	var normalizer = rule.GetNormalizer()
+	price, amount, err := normalizer.Normalize(order.Price, order.Amount, orderType == "MARKET")
+	if err != nil {
+		return nil, err
+	}
+
 	params := url.Values{}
 	params.Set("symbol", order.Pair.ToSymbol("", true))
 	params.Set("side", orderSide)
 	params.Set("type", orderType)
-	params.Set("quantity", fmt.Sprintf("%f", order.Amount))
+	params.Set("quantity", normalizer.FormatAmount(amount))
 	params.Set("newClientOrderId", order.Cid)
 
 	switch order.OrderType {
@@ -79,7 +89,7 @@ func (margin *Margin) PlaceOrder(order *Order) ([]byte, error) {
 
 	switch orderType {
 	case "LIMIT":
-		params.Set("price", fmt.Sprintf("%f", order.Price))
+		params.Set("price", normalizer.FormatPrice(price))
 	}
 
 	if err := margin.buildParamsSigned(&params); err != nil {
@@ -99,6 +99,7 @@ func (o *One) GetCMInfos() ([]*OneInfo, []byte, error) {
 			status = "PENDING"
 		}
 
+		var filters = parseFilters(symbol.Filters)
 		infos = append(infos, &OneInfo{
 			Pair: Pair{
 				NewCurrency(symbol.BaseAsset, ""),
@@ -115,6 +116,11 @@ func (o *One) GetCMInfos() ([]*OneInfo, []byte, error) {
 
 			PricePrecision:  symbol.PricePrecision,
 			AmountPrecision: symbol.QuantityPrecision,
+			TickSize:        filters.TickSize,
+			LotSize:         filters.LotSize,
+			MinAmount:       filters.MinAmount,
+			MinNotional:     filters.MinNotional,
+			MaxMarketAmount: filters.MaxMarketAmount,
 		})
 	}
 
@@ -159,6 +165,7 @@ func (o *One) GetUMInfos() ([]*OneInfo, []byte, error) {
 		if status == "PENDING_TRADING" {
 			status = "PENDING"
 		}
+		var filters = parseFilters(symbol.Filters)
 		infos = append(infos, &OneInfo{
 			Pair: Pair{
 				NewCurrency(symbol.BaseAsset, ""),
@@ -176,6 +183,11 @@ func (o *One) GetUMInfos() ([]*OneInfo, []byte, error) {
 
 			PricePrecision:  symbol.PricePrecision,
 			AmountPrecision: symbol.QuantityPrecision,
+			TickSize:        filters.TickSize,
+			LotSize:         filters.LotSize,
+			MinAmount:       filters.MinAmount,
+			MinNotional:     filters.MinNotional,
+			MaxMarketAmount: filters.MaxMarketAmount,
 		})
 	}
 
@@ -220,34 +232,51 @@ func (o *One) PlaceOrder(order *OneOrder) ([]byte, error) {
 	}
 
 	// 获取下单类型和方向
-	var side, positionSide, placeType = "", "", ""
+	var side, placeType = "", ""
 	var exist = false
 
 	if side, exist = sideRelation[order.Type]; !exist {
 		return nil, errors.New("swap type not found")
 	}
-	if positionSide, exist = positionSideRelation[order.Type]; !exist {
-		return nil, errors.New("swap type not found")
-	}
 	if placeType, exist = placeTypeRelation[order.PlaceType]; !exist {
 		return nil, errors.New("place type not found")
 	}
+	positionSide, reduceOnly, err := order.ResolvePosition()
+	if err != nil {
+		return nil, err
+	}
+	// 统一账户的普通订单不支持 closePosition
+	if order.ClosePosition {
+		return nil, errors.New("binance portfolio margin not support close position, use reduce only")
+	}
 
 	// 设置订单参数
 	var param = url.Values{}
 	param.Set("symbol", order.ProductId)
 	param.Set("side", side)
-	param.Set("positionSide", positionSide)
+	param.Set("positionSide", positionSideRelation[positionSide])
+	// 双向持仓模式下不能传 reduceOnly
+	if reduceOnly && positionSide == POSITION_SIDE_BOTH {
+		param.Set("reduceOnly", "true")
+	}
+
+	// 按照合约信息处理价格和数量的精度
+	var normalizer = info.GetNormalizer()
+	normalizer.ReduceOnly = reduceOnly
+	price, amount, err := normalizer.Normalize(order.Price, order.Amount, order.PlaceType == MARKET)
+	if err != nil {
+		return nil, err
+	}
 
 	// 处理订单类型
 	if order.PlaceType == MARKET {
 		param.Set("type", "MARKET")
 		// 市价单以数量下单
-		param.Set("quantity", FloatToString(order.Amount, info.AmountPrecision)) // 假设精度为8，实际应该从合约信息获取
+		param.Set("quantity", normalizer.FormatAmount(amount))
 	} else {
 		param.Set("type", "LIMIT")
-		param.Set("price", FloatToPrice(order.Price, 8, 0.0001)) // 假设精度为8，tickSize为0.0001
-		param.Set("quantity", FloatToString(order.Amount, info.AmountPrecision))
+		param.Set("price", normalizer.FormatPrice(price))
+		param.Set("quantity", normalizer.FormatAmount(amount))
 		// 设置时效类型
 		param.Set("timeInForce", placeType)
 	}
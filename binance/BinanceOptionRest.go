@@ -0,0 +1,538 @@
+package binance
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	. "github.com/deforceHK/goghostex"
+)
+
+const (
+	OPTION_ENDPOINT = "https://eapi.binance.com"
+
+	OPTION_EXCHANGE_INFO_URI = "/eapi/v1/exchangeInfo"
+	OPTION_TICKER_URI        = "/eapi/v1/ticker?"
+	OPTION_MARK_URI          = "/eapi/v1/mark?"
+	OPTION_INDEX_URI         = "/eapi/v1/index?"
+	OPTION_DEPTH_URI         = "/eapi/v1/depth?"
+	OPTION_ORDER_URI         = "/eapi/v1/order?"
+	OPTION_OPEN_ORDERS_URI   = "/eapi/v1/openOrders?"
+	OPTION_POSITION_URI      = "/eapi/v1/position?"
+)
+
+var optionStatusRelation = map[string]TradeStatus{
+	"ACCEPTED":         ORDER_UNFINISH,
+	"PARTIALLY_FILLED": ORDER_PART_FINISH,
+	"FILLED":           ORDER_FINISH,
+	"CANCELLED":        ORDER_CANCEL,
+	"REJECTED":         ORDER_FAIL,
+}
+
+var optionTimeInForceRelation = map[PlaceType]string{
+	NORMAL:     "GTC",
+	ONLY_MAKER: "GTC",
+	FOK:        "FOK",
+	IOC:        "IOC",
+}
+
+// Option is the european option in the eapi, the symbol is like BTC-241227-60000-C, the price is in usdt.
+type Option struct {
+	*Binance
+	sync.Locker
+	contracts map[string]*OptionContract // the symbol to the contract
+
+	LastKeepLiveTime time.Time
+}
+
+func (option *Option) GetExchangeName() string {
+	return BINANCE
+}
+
+// symbolPair return the underlying pair of the symbol, all the options are settled in usdt.
+func (option *Option) symbolPair(symbol string) Pair {
+	return Pair{Basis: NewCurrency(strings.Split(symbol, "-")[0], ""), Counter: USDT}
+}
+
+func (option *Option) GetContracts(pair Pair, expiry time.Time) ([]*OptionContract, []byte, error) {
+	var response = struct {
+		OptionSymbols []struct {
+			Symbol      string                   `json:"symbol"`
+			Side        string                   `json:"side"`
+			StrikePrice float64                  `json:"strikePrice,string"`
+			Underlying  string                   `json:"underlying"`
+			Unit        float64                  `json:"unit"`
+			ExpiryDate  int64                    `json:"expiryDate"`
+			QuoteAsset  string                   `json:"quoteAsset"`
+			Filters     []map[string]interface{} `json:"filters"`
+		} `json:"optionSymbols"`
+	}{}
+	resp, err := option.DoRequest(http.MethodGet, OPTION_EXCHANGE_INFO_URI, "", &response)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	var contracts = make([]*OptionContract, 0, len(response.OptionSymbols))
+	option.Lock()
+	if option.contracts == nil {
+		option.contracts = make(map[string]*OptionContract)
+	}
+	for _, item := range response.OptionSymbols {
+		var filters = parseFilters(item.Filters)
+		var contract = &OptionContract{
+			Pair:       option.symbolPair(item.Symbol),
+			Symbol:     item.Symbol,
+			Exchange:   BINANCE,
+			OptionType: OPTION_CALL,
+			Strike:     item.StrikePrice,
+
+			ExpiryTimestamp: item.ExpiryDate,
+			ExpiryDate:      time.Unix(item.ExpiryDate/1000, 0).In(option.config.Location).Format("2006-01-02"),
+
+			SettleCurrency: item.QuoteAsset,
+			UnitAmount:     item.Unit,
+			TickSize:       filters.TickSize,
+			LotSize:        filters.LotSize,
+			MinAmount:      filters.MinAmount,
+		}
+		if item.Side == "PUT" {
+			contract.OptionType = OPTION_PUT
+		}
+		option.contracts[contract.Symbol] = contract
+		if item.Underlying == pair.ToSymbol("", true) {
+			contracts = append(contracts, contract)
+		}
+	}
+	option.Unlock()
+
+	return FilterOptionContracts(contracts, expiry), resp, nil
+}
+
+// GetContract return the contract of the symbol, the exchange info is requested when it is not found.
+func (option *Option) GetContract(symbol string) *OptionContract {
+	option.Lock()
+	var contract, exist = option.contracts[symbol]
+	option.Unlock()
+	if exist {
+		return contract
+	}
+
+	if _, _, err := option.GetContracts(option.symbolPair(symbol), time.Time{}); err != nil {
+		return nil
+	}
+	option.Lock()
+	defer option.Unlock()
+	return option.contracts[symbol]
+}
+
+// optionTickerBN is the item of the ticker api.
+type optionTickerBN struct {
+	Symbol    string  `json:"symbol"`
+	LastPrice float64 `json:"lastPrice,string"`
+	BidPrice  float64 `json:"bidPrice,string"`
+	AskPrice  float64 `json:"askPrice,string"`
+	CloseTime int64   `json:"closeTime"`
+}
+
+// optionMarkBN is the item of the mark api, the greeks are in usdt.
+type optionMarkBN struct {
+	Symbol    string  `json:"symbol"`
+	MarkPrice float64 `json:"markPrice,string"`
+	BidIV     float64 `json:"bidIV,string"`
+	AskIV     float64 `json:"askIV,string"`
+	MarkIV    float64 `json:"markIV,string"`
+	Delta     float64 `json:"delta,string"`
+	Theta     float64 `json:"theta,string"`
+	Gamma     float64 `json:"gamma,string"`
+	Vega      float64 `json:"vega,string"`
+}
+
+// getTickers merge the ticker, the mark and the index of the underlying, the empty symbol means all the symbols.
+func (option *Option) getTickers(pair Pair, symbol string) ([]*OptionTicker, []byte, error) {
+	var params = url.Values{}
+	if symbol != "" {
+		params.Set("symbol", symbol)
+	}
+
+	var tickers = make([]*optionTickerBN, 0)
+	resp, err := option.DoRequest(http.MethodGet, OPTION_TICKER_URI+params.Encode(), "", &tickers)
+	if err != nil {
+		return nil, resp, err
+	}
+	var marks = make([]*optionMarkBN, 0)
+	resp, err = option.DoRequest(http.MethodGet, OPTION_MARK_URI+params.Encode(), "", &marks)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	var indexParams = url.Values{}
+	indexParams.Set("underlying", pair.ToSymbol("", true))
+	var index = struct {
+		Time       int64   `json:"time"`
+		IndexPrice float64 `json:"indexPrice,string"`
+	}{}
+	resp, err = option.DoRequest(http.MethodGet, OPTION_INDEX_URI+indexParams.Encode(), "", &index)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	var tickerKV = make(map[string]*optionTickerBN, len(tickers))
+	for _, ticker := range tickers {
+		tickerKV[ticker.Symbol] = ticker
+	}
+	var result = make([]*OptionTicker, 0, len(marks))
+	for _, mark := range marks {
+		var ticker = &OptionTicker{
+			Pair:            pair,
+			Symbol:          mark.Symbol,
+			Exchange:        BINANCE,
+			BuyIV:           mark.BidIV,
+			SellIV:          mark.AskIV,
+			MarkPrice:       mark.MarkPrice,
+			MarkIV:          mark.MarkIV,
+			UnderlyingPrice: index.IndexPrice,
+			Greeks: OptionGreeks{
+				Delta: mark.Delta,
+				Gamma: mark.Gamma,
+				Vega:  mark.Vega,
+				Theta: mark.Theta,
+			},
+			Timestamp: index.Time,
+			Date:      time.Unix(index.Time/1000, 0).In(option.config.Location).Format(GO_BIRTHDAY),
+		}
+		if item, exist := tickerKV[mark.Symbol]; exist {
+			ticker.Last, ticker.Buy, ticker.Sell = item.LastPrice, item.BidPrice, item.AskPrice
+		}
+		result = append(result, ticker)
+	}
+	return result, resp, nil
+}
+
+func (option *Option) GetTicker(symbol string) (*OptionTicker, []byte, error) {
+	var tickers, resp, err = option.getTickers(option.symbolPair(symbol), symbol)
+	if err != nil {
+		return nil, resp, err
+	}
+	if len(tickers) == 0 {
+		return nil, resp, errors.New("lack response data. ")
+	}
+	return tickers[0], resp, nil
+}
+
+func (option *Option) GetTickers(pair Pair, expiry time.Time) ([]*OptionTicker, []byte, error) {
+	var contracts, resp, err = option.GetContracts(pair, expiry)
+	if err != nil {
+		return nil, resp, err
+	}
+	var symbols = make(map[string]bool, len(contracts))
+	for _, contract := range contracts {
+		symbols[contract.Symbol] = true
+	}
+
+	tickers, resp, err := option.getTickers(pair, "")
+	if err != nil {
+		return nil, resp, err
+	}
+	var filtered = make([]*OptionTicker, 0, len(contracts))
+	for _, ticker := range tickers {
+		if symbols[ticker.Symbol] {
+			filtered = append(filtered, ticker)
+		}
+	}
+	return filtered, resp, nil
+}
+
+// GetDepth return the depth of the symbol, the size is one of 10, 20, 50, 100, 500 and 1000.
+func (option *Option) GetDepth(symbol string, size int) (*OptionDepth, []byte, error) {
+	var params = url.Values{}
+	params.Set("symbol", symbol)
+	params.Set("limit", fmt.Sprintf("%d", size))
+
+	var response = struct {
+		Bids [][]string `json:"bids"`
+		Asks [][]string `json:"asks"`
+		T    int64      `json:"T"`
+	}{}
+	resp, err := option.DoRequest(http.MethodGet, OPTION_DEPTH_URI+params.Encode(), "", &response)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	var depth = &OptionDepth{
+		Pair:      option.symbolPair(symbol),
+		Symbol:    symbol,
+		Exchange:  BINANCE,
+		Timestamp: response.T,
+		Date:      time.Unix(response.T/1000, 0).In(option.config.Location).Format(GO_BIRTHDAY),
+	}
+	for _, bid := range response.Bids {
+		depth.BidList = append(depth.BidList, DepthRecord{Price: ToFloat64(bid[0]), Amount: ToFloat64(bid[1])})
+	}
+	for _, ask := range response.Asks {
+		depth.AskList = append(depth.AskList, DepthRecord{Price: ToFloat64(ask[0]), Amount: ToFloat64(ask[1])})
+	}
+	return depth, resp, nil
+}
+
+// optionOrderBN is the order of the eapi, the quantity is in contracts.
+type optionOrderBN struct {
+	OrderId       int64   `json:"orderId"`
+	ClientOrderId string  `json:"clientOrderId"`
+	Symbol        string  `json:"symbol"`
+	Price         float64 `json:"price,string"`
+	Quantity      float64 `json:"quantity,string"`
+	ExecutedQty   float64 `json:"executedQty,string"`
+	Fee           float64 `json:"fee,string"`
+	Side          string  `json:"side"`
+	Type          string  `json:"type"`
+	TimeInForce   string  `json:"timeInForce"`
+	PostOnly      bool    `json:"postOnly"`
+	ReduceOnly    bool    `json:"reduceOnly"`
+	CreateTime    int64   `json:"createTime"`
+	UpdateTime    int64   `json:"updateTime"`
+	Status        string  `json:"status"`
+	AvgPrice      float64 `json:"avgPrice,string"`
+}
+
+func (response *optionOrderBN) merge(order *OptionOrder, loc *time.Location) {
+	order.OrderId = fmt.Sprintf("%d", response.OrderId)
+	order.Cid = response.ClientOrderId
+	order.Symbol = response.Symbol
+	order.Side = SELL
+	if response.Side == "BUY" {
+		order.Side = BUY
+	}
+	switch {
+	case response.Type == "MARKET":
+		order.PlaceType = MARKET
+	case response.PostOnly:
+		order.PlaceType = ONLY_MAKER
+	default:
+		order.PlaceType = _INTERNAL_PLACE_TYPE_REVERSE_CONVERTER[response.TimeInForce]
+	}
+	order.Status = optionStatusRelation[response.Status]
+	order.Price = response.Price
+	order.Amount = response.Quantity
+	order.AvgPrice = response.AvgPrice
+	order.DealAmount = response.ExecutedQty
+	order.Fee = response.Fee
+	order.ReduceOnly = response.ReduceOnly
+	order.Exchange = BINANCE
+
+	order.PlaceTimestamp = response.CreateTime
+	order.PlaceDatetime = time.Unix(response.CreateTime/1000, 0).In(loc).Format(GO_BIRTHDAY)
+	order.DealTimestamp = response.UpdateTime
+	order.DealDatetime = time.Unix(response.UpdateTime/1000, 0).In(loc).Format(GO_BIRTHDAY)
+}
+
+func (option *Option) PlaceOrder(order *OptionOrder) ([]byte, error) {
+	if order == nil {
+		return nil, errors.New("order param is nil")
+	}
+	var contract = option.GetContract(order.Symbol)
+	if contract == nil {
+		return nil, fmt.Errorf("the option %s is not found. ", order.Symbol)
+	}
+	if order.Side != BUY && order.Side != SELL {
+		return nil, fmt.Errorf("the trade side %s is not supported in the option. ", order.Side)
+	}
+
+	var isMarket = order.PlaceType == MARKET
+	var normalizer = contract.GetNormalizer()
+	price, amount, err := normalizer.Normalize(order.Price, order.Amount, isMarket)
+	if err != nil {
+		return nil, err
+	}
+
+	var params = url.Values{}
+	params.Set("symbol", order.Symbol)
+	params.Set("side", "SELL")
+	if order.Side == BUY {
+		params.Set("side", "BUY")
+	}
+	params.Set("quantity", normalizer.FormatAmount(amount))
+	if isMarket {
+		params.Set("type", "MARKET")
+	} else {
+		var timeInForce, exist = optionTimeInForceRelation[order.PlaceType]
+		if !exist {
+			return nil, errors.New("place type not found. ")
+		}
+		params.Set("type", "LIMIT")
+		params.Set("price", normalizer.FormatPrice(price))
+		params.Set("timeInForce", timeInForce)
+		if order.PlaceType == ONLY_MAKER {
+			params.Set("postOnly", "true")
+		}
+	}
+	if order.ReduceOnly {
+		params.Set("reduceOnly", "true")
+	}
+	if order.Cid != "" {
+		params.Set("clientOrderId", order.Cid)
+	}
+	if err := option.buildParamsSigned(&params); err != nil {
+		return nil, err
+	}
+
+	var response optionOrderBN
+	resp, err := option.DoRequest(http.MethodPost, OPTION_ORDER_URI+params.Encode(), "", &response)
+	if err != nil {
+		return resp, err
+	}
+	response.merge(order, option.config.Location)
+	order.Pair = contract.Pair
+	return resp, nil
+}
+
+func (option *Option) orderParams(order *OptionOrder) url.Values {
+	var params = url.Values{}
+	params.Set("symbol", order.Symbol)
+	if order.OrderId != "" {
+		params.Set("orderId", order.OrderId)
+	} else {
+		params.Set("clientOrderId", order.Cid)
+	}
+	return params
+}
+
+func (option *Option) CancelOrder(order *OptionOrder) ([]byte, error) {
+	var params = option.orderParams(order)
+	if err := option.buildParamsSigned(&params); err != nil {
+		return nil, err
+	}
+
+	var response optionOrderBN
+	resp, err := option.DoRequest(http.MethodDelete, OPTION_ORDER_URI+params.Encode(), "", &response)
+	if err != nil {
+		return resp, err
+	}
+	response.merge(order, option.config.Location)
+	return resp, nil
+}
+
+func (option *Option) GetOrder(order *OptionOrder) ([]byte, error) {
+	var params = option.orderParams(order)
+	if err := option.buildParamsSigned(&params); err != nil {
+		return nil, err
+	}
+
+	var response optionOrderBN
+	resp, err := option.DoRequest(http.MethodGet, OPTION_ORDER_URI+params.Encode(), "", &response)
+	if err != nil {
+		return resp, err
+	}
+	response.merge(order, option.config.Location)
+	order.Pair = option.symbolPair(order.Symbol)
+	return resp, nil
+}
+
+// GetUnFinishOrders return the open orders of the underlying, the eapi has no underlying filter so it is done here.
+func (option *Option) GetUnFinishOrders(pair Pair) ([]*OptionOrder, []byte, error) {
+	var params = url.Values{}
+	if err := option.buildParamsSigned(&params); err != nil {
+		return nil, nil, err
+	}
+
+	var response = make([]*optionOrderBN, 0)
+	resp, err := option.DoRequest(http.MethodGet, OPTION_OPEN_ORDERS_URI+params.Encode(), "", &response)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	var prefix = pair.Basis.Symbol + "-"
+	var orders = make([]*OptionOrder, 0, len(response))
+	for _, item := range response {
+		if !strings.HasPrefix(item.Symbol, prefix) {
+			continue
+		}
+		var order = &OptionOrder{Pair: pair}
+		item.merge(order, option.config.Location)
+		orders = append(orders, order)
+	}
+	return orders, resp, nil
+}
+
+// GetPositions return the positions of the underlying, the greeks of the position are not provided by the eapi.
+func (option *Option) GetPositions(pair Pair) ([]*OptionPosition, []byte, error) {
+	var params = url.Values{}
+	if err := option.buildParamsSigned(&params); err != nil {
+		return nil, nil, err
+	}
+
+	var response = make([]struct {
+		Symbol        string  `json:"symbol"`
+		Side          string  `json:"side"`
+		Quantity      float64 `json:"quantity,string"`
+		EntryPrice    float64 `json:"entryPrice,string"`
+		MarkPrice     float64 `json:"markPrice,string"`
+		UnrealizedPNL float64 `json:"unrealizedPNL,string"`
+	}, 0)
+	resp, err := option.DoRequest(http.MethodGet, OPTION_POSITION_URI+params.Encode(), "", &response)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	var prefix = pair.Basis.Symbol + "-"
+	var positions = make([]*OptionPosition, 0, len(response))
+	for _, item := range response {
+		if !strings.HasPrefix(item.Symbol, prefix) || item.Quantity == 0 {
+			continue
+		}
+		var amount = item.Quantity
+		if item.Side == "SHORT" && amount > 0 {
+			amount = -amount
+		}
+		positions = append(positions, &OptionPosition{
+			Pair:             pair,
+			Symbol:           item.Symbol,
+			Exchange:         BINANCE,
+			Amount:           amount,
+			AvgPrice:         item.EntryPrice,
+			MarkPrice:        item.MarkPrice,
+			UnrealizedProfit: item.UnrealizedPNL,
+		})
+	}
+	return positions, resp, nil
+}
+
+func (option *Option) DoRequest(httpMethod, uri, reqBody string, response interface{}) ([]byte, error) {
+	var header = map[string]string{
+		"X-MBX-APIKEY": option.config.ApiKey,
+	}
+	if httpMethod == http.MethodPost {
+		header["Content-Type"] = "application/x-www-form-urlencoded"
+	}
+	resp, err := NewHttpRequestWithMetrics(
+		option.config.GetMetrics(),
+		BINANCE,
+		option.config.HttpClient,
+		httpMethod,
+		OPTION_ENDPOINT+uri,
+		reqBody,
+		header,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if option.LastKeepLiveTime.Before(now) {
+		option.LastKeepLiveTime = now
+	}
+	return resp, json.Unmarshal(resp, &response)
+}
+
+func (option *Option) KeepAlive() {
+	// last timestamp in 5s, no need to keep alive
+	if (time.Now().Unix() - option.LastKeepLiveTime.Unix()) < 5 {
+		return
+	}
+	var response interface{}
+	_, _ = option.DoRequest(http.MethodGet, "/eapi/v1/time", "", &response)
+}
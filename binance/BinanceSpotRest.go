@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 
 	. "github.com/deforceHK/goghostex"
@@ -14,6 +15,8 @@ import (
 
 type Spot struct {
 	*Binance
+
+	rules sync.Map // pair.String() => *Rule, the cache of GetExchangeRule for PlaceOrder
 }
 
 // the common resp struct of order/info/cancel
@@ -242,11 +245,21 @@ func (spot *Spot) PlaceOrder(order *Order) ([]byte, error) {
 		return nil, errors.New("Can not deal the order side. ")
 	}
 
+	rule, err := spot.getRule(order.Pair)
+	if err != nil {
+		return nil, err
+	}
+	var normalizer = rule.GetNormalizer()
+	price, amount, err := normalizer.Normalize(order.Price, order.Amount, orderType == "MARKET")
+	if err != nil {
+		return nil, err
+	}
+
 	params := url.Values{}
 	params.Set("symbol", order.Pair.ToSymbol("", true))
 	params.Set("side", orderSide)
 	params.Set("type", orderType)
-	params.Set("quantity", fmt.Sprintf("%f", order.Amount))
+	params.Set("quantity", normalizer.FormatAmount(amount))
 	params.Set("newClientOrderId", order.Cid)
 
 	switch order.OrderType {
@@ -262,7 +275,7 @@ func (spot *Spot) PlaceOrder(order *Order) ([]byte, error) {
 
 	switch orderType {
 	case "LIMIT":
-		params.Set("price", fmt.Sprintf("%f", order.Price))
+		params.Set("price", normalizer.FormatPrice(price))
 	case "MARKET":
 		params.Del("timeInForce")
 	}
@@ -316,6 +329,118 @@ func (spot *Spot) CancelOrder(order *Order) ([]byte, error) {
 	return resp, nil
 }
 
+// AmendOrder reduce the amount by the keepPriority api when the price is not changed,
+// otherwise it is the cancelReplace, the new order has the new OrderId and lose the queue position.
+func (spot *Spot) AmendOrder(order *Order, newPrice, newAmount float64) ([]byte, error) {
+	if order.OrderId == "" {
+		return nil, errors.New("You must get the order_id. ")
+	}
+	if order.Side != BUY && order.Side != SELL {
+		return nil, errors.New("Only the limit order can be amended. ")
+	}
+	if newPrice <= 0 {
+		newPrice = order.Price
+	}
+	if newAmount <= 0 {
+		newAmount = order.Amount
+	}
+
+	rule, err := spot.getRule(order.Pair)
+	if err != nil {
+		return nil, err
+	}
+	var normalizer = rule.GetNormalizer()
+	price, amount, err := normalizer.Normalize(newPrice, newAmount, false)
+	if err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	params.Set("symbol", order.Pair.ToSymbol("", true))
+	if price == order.Price && amount < order.Amount {
+		params.Set("orderId", order.OrderId)
+		params.Set("newQty", normalizer.FormatAmount(amount))
+		if err := spot.buildParamsSigned(&params); err != nil {
+			return nil, err
+		}
+
+		response := struct {
+			AmendedOrder remoteOrder `json:"amendedOrder"`
+		}{}
+		resp, err := spot.DoRequest(
+			http.MethodPut,
+			API_V3+"order/amend/keepPriority?",
+			params.Encode(),
+			&response,
+		)
+		if err != nil {
+			return resp, err
+		}
+		response.AmendedOrder.merge(order, spot.config.Location)
+		return resp, nil
+	}
+
+	params.Set("cancelReplaceMode", "STOP_ON_FAILURE")
+	params.Set("cancelOrderId", order.OrderId)
+	params.Set("side", strings.ToUpper(order.Side.String()))
+	params.Set("type", "LIMIT")
+	params.Set("timeInForce", "GTC")
+	params.Set("quantity", normalizer.FormatAmount(amount))
+	params.Set("price", normalizer.FormatPrice(price))
+	if err := spot.buildParamsSigned(&params); err != nil {
+		return nil, err
+	}
+
+	response := struct {
+		CancelResult     string      `json:"cancelResult"`
+		NewOrderResult   string      `json:"newOrderResult"`
+		NewOrderResponse remoteOrder `json:"newOrderResponse"`
+	}{}
+	resp, err := spot.DoRequest(
+		http.MethodPost,
+		API_V3+"order/cancelReplace?",
+		params.Encode(),
+		&response,
+	)
+	if err != nil {
+		return resp, err
+	}
+	if response.NewOrderResult != "SUCCESS" {
+		return resp, errors.New(string(resp))
+	}
+	response.NewOrderResponse.merge(order, spot.config.Location)
+	return resp, nil
+}
+
+func (spot *Spot) CancelAllOrders(pair Pair) ([]byte, error) {
+	params := url.Values{}
+	params.Set("symbol", pair.ToSymbol("", true))
+	if err := spot.buildParamsSigned(&params); err != nil {
+		return nil, err
+	}
+
+	response := make([]remoteOrder, 0)
+	return spot.DoRequest(
+		"DELETE",
+		API_V3+"openOrders?",
+		params.Encode(),
+		&response,
+	)
+}
+
+// BatchPlaceOrders binance spot has no batch order api, the orders are placed one by one.
+func (spot *Spot) BatchPlaceOrders(orders []*Order) ([]error, []byte, error) {
+	return BatchSequential(len(orders), func(i int) ([]byte, error) {
+		return spot.PlaceOrder(orders[i])
+	})
+}
+
+func (spot *Spot) BatchCancelOrders(orders []*Order) ([]error, []byte, error) {
+	return BatchSequential(len(orders), func(i int) ([]byte, error) {
+		return spot.CancelOrder(orders[i])
+	})
+}
+
 func (spot *Spot) GetOrder(order *Order) ([]byte, error) {
 	if order.OrderId == "" {
 		return nil, errors.New("You must get the order_id. ")
@@ -414,6 +539,19 @@ func (spot *remoteOrder) merge(order *Order, location *time.Location) {
 	order.DealAmount = spot.ExecutedQty
 }
 
+// getRule return the cached rule of the pair, the rule is queried at the first time.
+func (spot *Spot) getRule(pair Pair) (*Rule, error) {
+	if rule, exist := spot.rules.Load(pair.String()); exist {
+		return rule.(*Rule), nil
+	}
+	var rule, _, err = spot.GetExchangeRule(pair)
+	if err != nil {
+		return nil, err
+	}
+	spot.rules.Store(pair.String(), rule)
+	return rule, nil
+}
+
 func (spot *Spot) GetExchangeRule(pair Pair) (*Rule, []byte, error) {
 	uri := "/api/v3/exchangeInfo"
 
@@ -442,25 +580,28 @@ func (spot *Spot) GetExchangeRule(pair Pair) (*Rule, []byte, error) {
 				continue
 			}
 
-			basePrecision, counterPrecision, baseMinSize := int(0), int(0), float64(0)
-			for _, f := range r.Filters {
-				if f["filterType"] == "PRICE_FILTER" {
-					counterPrecision = GetPrecision(ToFloat64(f["tickSize"]))
-				}
-				if f["filterType"] == "LOT_SIZE" {
-					basePrecision = GetPrecision(ToFloat64(f["stepSize"]))
-					baseMinSize = ToFloat64(f["minQty"])
-				}
+			var filters = parseFilters(r.Filters)
+			var basePrecision, counterPrecision = 0, 0
+			if filters.TickSize > 0 {
+				counterPrecision = GetPrecision(filters.TickSize)
+			}
+			if filters.LotSize > 0 {
+				basePrecision = GetPrecision(filters.LotSize)
 			}
 
 			rule := Rule{
 				Pair:          pair,
 				Base:          NewCurrency(r.BaseAsset, ""),
 				BasePrecision: basePrecision,
-				BaseMinSize:   baseMinSize,
+				BaseMinSize:   filters.MinAmount,
 
 				Counter:          NewCurrency(r.QuotaAsset, ""),
 				CounterPrecision: counterPrecision,
+
+				TickSize:        filters.TickSize,
+				LotSize:         filters.LotSize,
+				MinNotional:     filters.MinNotional,
+				MaxMarketAmount: filters.MaxMarketAmount,
 			}
 			return &rule, input, nil
 		}
@@ -3,7 +3,6 @@ package binance
 import (
 	"encoding/json"
 	"fmt"
-	"log"
 	"net/http"
 	"net/url"
 	"sort"
@@ -72,7 +71,7 @@ func (this *LocalSpotBooks) ReceiveDelta(msg string) {
 	_ = json.Unmarshal([]byte(msg), &delta)
 	if delta.EventType != "depthUpdate" {
 		// it's not for depth update, ignore it.
-		log.Println(msg)
+		this.logger().Debug("receive message", LogF("msg", msg))
 		return
 	}
 
@@ -127,7 +126,13 @@ func (this *LocalSpotBooks) ReceiveDelta(msg string) {
 
 	if !withCache && delta.StartSeq != (this.SeqData[productId]+1) {
 		// 有丢包现象，需要重新申请snapshot
-		fmt.Println(delta.StartSeq, this.SeqData[productId])
+		this.logger().Warn(
+			"The sequence is not continuous, get the snapshot again. ",
+			LogF(LOG_FIELD_INSTRUMENT, productId),
+			LogF("start_seq", delta.StartSeq),
+			LogF("last_seq", this.SeqData[productId]),
+		)
+		this.Config.GetMetrics().IncBookResync(BINANCE, productId)
 		go this.getSnapshot(productId, 0)
 	} else {
 		for _, bid := range delta.Bids {
@@ -217,7 +222,9 @@ func (this *LocalSpotBooks) getDepthById(productId string, size int) (*Depth, er
 	params.Set("symbol", strings.ToUpper(productId))
 	params.Set("limit", fmt.Sprintf("%d", size))
 
-	var resp, err = NewHttpRequest(
+	var resp, err = NewHttpRequestWithMetrics(
+		this.Config.GetMetrics(),
+		BINANCE,
 		this.Config.HttpClient,
 		http.MethodGet,
 		"https://api.binance.com/api/v3/depth?"+params.Encode(),
@@ -279,6 +286,7 @@ func (this *LocalSpotBooks) SnapshotById(productId string) (*Depth, error) {
 	defer mux.Unlock()
 
 	var lastTime = time.UnixMilli(this.TsData[productId]).In(this.WSMarketSpot.Config.Location)
+	this.Config.GetMetrics().SetBookStaleness(BINANCE, productId, time.Since(lastTime))
 	var depth = &Depth{
 		Sequence:  this.SeqData[productId],
 		Timestamp: lastTime.UnixMilli(),
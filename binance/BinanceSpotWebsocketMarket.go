@@ -2,7 +2,6 @@ package binance
 
 import (
 	"fmt"
-	"log"
 	"strings"
 	"time"
 
@@ -15,6 +14,7 @@ type WSMarketSpot struct {
 	RecvHandler  func(string)
 	ErrorHandler func(error)
 	Config       *APIConfig
+	DialerConfig *WsDialerConfig // the proxy, tls and local address setting of the connection, not necessary
 
 	conn   *websocket.Conn
 	connId string
@@ -97,6 +97,7 @@ func (this *WSMarketSpot) Unsubscribe(v interface{}) {
 }
 
 func (this *WSMarketSpot) Restart() {
+	this.Config.GetMetrics().IncWSReconnect(BINANCE, "WSMarketSpot")
 	this.restartTS[time.Now().Unix()] = this.connId
 	this.Stop()
 	this.ErrorHandler(
@@ -130,7 +131,11 @@ func (this *WSMarketSpot) Restart() {
 
 func (this *WSMarketSpot) noLoginConn(wss string) (*websocket.Conn, error) {
 	this.initDefaultValue()
-	var conn, _, err = websocket.DefaultDialer.Dial(
+	var dialer, dialErr = NewWsDialer(this.DialerConfig)
+	if dialErr != nil {
+		return nil, dialErr
+	}
+	var conn, _, err = dialer.Dial(
 		wss,
 		nil,
 	)
@@ -148,7 +153,7 @@ func (this *WSMarketSpot) startCheck() error {
 			restartNum++
 		}
 	}
-	fmt.Println("Restarted time: ", restartNum, "Restart limit time: ", this.restartLimitNum)
+	this.logger().Info("restart check", LogF("restart_num", restartNum), LogF("restart_limit", this.restartLimitNum))
 	if restartNum > this.restartLimitNum {
 		var wsErr = &WSStopError{
 			Msg: fmt.Sprintf(
@@ -156,6 +161,7 @@ func (this *WSMarketSpot) startCheck() error {
 				restartNum, this.restartLimitSec,
 			),
 		}
+		this.Config.GetMetrics().IncWSRestartLimit(BINANCE, "WSMarketSpot")
 		return wsErr
 	}
 	return nil
@@ -195,7 +201,7 @@ func (this *WSMarketSpot) recvRoutine() {
 		if readErr != nil {
 			// conn closed by user.
 			if strings.Index(readErr.Error(), "use of closed network connection") > 0 {
-				fmt.Println("conn closed by user. ")
+				this.logger().Info("conn closed by user")
 				return
 			}
 			this.ErrorHandler(readErr)
@@ -208,6 +214,7 @@ func (this *WSMarketSpot) recvRoutine() {
 		}
 
 		this.lastPingTS = time.Now().Unix()
+		this.Config.GetMetrics().IncWSMessage(BINANCE, "WSMarketSpot")
 		this.RecvHandler(string(msg))
 	}
 
@@ -229,12 +236,12 @@ func (this *WSMarketSpot) Stop() {
 func (this *WSMarketSpot) initDefaultValue() {
 	if this.RecvHandler == nil {
 		this.RecvHandler = func(msg string) {
-			log.Println(msg)
+			this.logger().Debug("receive message", LogF("msg", msg))
 		}
 	}
 	if this.ErrorHandler == nil {
 		this.ErrorHandler = func(err error) {
-			log.Println(err)
+			this.logger().Error("websocket error", LogF(LOG_FIELD_ERROR, err))
 		}
 	}
 	if this.restartSec == 0 {
@@ -254,3 +261,10 @@ func (this *WSMarketSpot) initDefaultValue() {
 	}
 
 }
+
+func (this *WSMarketSpot) logger() Logger {
+	return this.Config.GetLogger().With(
+		LogF(LOG_FIELD_EXCHANGE, BINANCE),
+		LogF(LOG_FIELD_CONN_ID, this.connId),
+	)
+}
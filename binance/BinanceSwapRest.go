@@ -7,6 +7,7 @@ import (
 	"math"
 	"net/http"
 	"net/url"
+	"strings"
 	"sync"
 	"time"
 
@@ -23,6 +24,10 @@ const (
 	SWAP_COUNTER_GET_ORDER_URI    = "/fapi/v1/order?"
 	SWAP_COUNTER_CANCEL_ORDER_URI = "/fapi/v1/order?"
 	SWAP_COUNTER_INCOME_URI       = "/fapi/v1/income?"
+	SWAP_COUNTER_BATCH_ORDERS_URI = "/fapi/v1/batchOrders?"
+	SWAP_COUNTER_CANCEL_ALL_URI   = "/fapi/v1/allOpenOrders?"
+	SWAP_COUNTER_COUNTDOWN_URI    = "/fapi/v1/countdownCancelAll?"
+	SWAP_COUNTER_DUAL_SIDE_URI    = "/fapi/v1/positionSide/dual?"
 
 	SWAP_BASIS_ENDPOINT   = "https://dapi.binance.com"
 	SWAP_BASIS_DEPTH_URI  = "/dapi/v1/depth?"
@@ -33,6 +38,13 @@ const (
 	SWAP_BASIS_GET_ORDER_URI    = "/dapi/v1/order?"
 	SWAP_BASIS_CANCEL_ORDER_URI = "/dapi/v1/order?"
 	SWAP_BASIS_INCOME_URI       = "/dapi/v1/income?"
+	SWAP_BASIS_BATCH_ORDERS_URI = "/dapi/v1/batchOrders?"
+	SWAP_BASIS_CANCEL_ALL_URI   = "/dapi/v1/allOpenOrders?"
+	SWAP_BASIS_COUNTDOWN_URI    = "/dapi/v1/countdownCancelAll?"
+	SWAP_BASIS_DUAL_SIDE_URI    = "/dapi/v1/positionSide/dual?"
+
+	SWAP_BATCH_PLACE_LIMIT  = 5
+	SWAP_BATCH_CANCEL_LIMIT = 10
 
 	SWAP_ACCOUNT_URI    = "/fapi/v1/account?"
 	SWAP_GET_ORDERS_URI = "/fapi/v1/allOrders?"
@@ -393,28 +405,105 @@ func (swap *Swap) GetKline(pair Pair, period, size, since int) ([]*SwapKline, []
 	return GetAscSwapKline(swapKlines), resp, nil
 }
 
-func (swap *Swap) GetOpenAmount(pair Pair) (float64, int64, []byte, error) {
-	params := url.Values{}
-	params.Set("symbol", pair.ToSymbol("", true))
-	params.Set("period", "5m")
-	params.Set("limit", "500")
+const (
+	SWAP_MARK_KLINE_API    = "markPriceKlines"
+	SWAP_INDEX_KLINE_API   = "indexPriceKlines"
+	SWAP_PREMIUM_KLINE_API = "premiumIndexKlines"
+)
 
-	responses := make([]struct {
-		Amount    float64 `json:"sumOpenInterest,string"`
-		Quota     float64 `json:"sumOpenInterestValue,string"`
-		Timestamp int64   `json:"timestamp"`
-	}, 0)
+func (swap *Swap) GetMarkKline(pair Pair, period, size, since int) ([]*SwapKline, []byte, error) {
+	return swap.getSwapPriceKline(SWAP_MARK_KLINE_API, pair, period, size, since)
+}
+
+func (swap *Swap) GetIndexKline(pair Pair, period, size, since int) ([]*SwapKline, []byte, error) {
+	return swap.getSwapPriceKline(SWAP_INDEX_KLINE_API, pair, period, size, since)
+}
+
+func (swap *Swap) GetPremiumKline(pair Pair, period, size, since int) ([]*SwapKline, []byte, error) {
+	return swap.getSwapPriceKline(SWAP_PREMIUM_KLINE_API, pair, period, size, since)
+}
+
+func (swap *Swap) getSwapPriceKline(api string, pair Pair, period, size, since int) ([]*SwapKline, []byte, error) {
+	var symbol, settleMode = swap.contractSymbol(pair)
+	var klines, resp, err = swap.getPriceKline(api, pair, symbol, settleMode, period, size, since)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	var swapKlines = make([]*SwapKline, 0, len(klines))
+	for _, k := range klines {
+		var timestamp = ToInt64(k[0])
+		swapKlines = append(swapKlines, &SwapKline{
+			Pair:      pair,
+			Exchange:  BINANCE,
+			Timestamp: timestamp,
+			Date:      time.Unix(timestamp/1000, 0).In(swap.config.Location).Format(GO_BIRTHDAY),
+			Open:      ToFloat64(k[1]),
+			High:      ToFloat64(k[2]),
+			Low:       ToFloat64(k[3]),
+			Close:     ToFloat64(k[4]),
+		})
+	}
+	return GetAscSwapKline(swapKlines), resp, nil
+}
+
+// getPriceKline request the price klines of the symbol, the index price klines use the pair instead of the symbol.
+// It is shared by the swap and the delivery future, the since 0 means the latest klines.
+func (swap *Swap) getPriceKline(
+	api string,
+	pair Pair,
+	symbol string,
+	settleMode int64,
+	period, size, since int,
+) ([][]interface{}, []byte, error) {
+	var interval, exist = _INERNAL_KLINE_PERIOD_CONVERTER[period]
+	if !exist {
+		return nil, nil, fmt.Errorf("the period %d is not supported. ", period)
+	}
+	if size <= 0 || size > 1500 {
+		size = 1500
+	}
+
+	var params = url.Values{}
+	if api == SWAP_INDEX_KLINE_API {
+		params.Set("pair", pair.ToSymbol("", true))
+	} else {
+		params.Set("symbol", symbol)
+	}
+	params.Set("interval", interval)
+	params.Set("limit", fmt.Sprintf("%d", size))
+	if since > 0 {
+		var endTimestamp = since + size*_INERNAL_KLINE_SECOND_CONVERTER[period]
+		if endTimestamp > since+200*24*60*60*1000 {
+			endTimestamp = since + 200*24*60*60*1000
+		}
+		if endTimestamp > int(time.Now().Unix()*1000) {
+			endTimestamp = int(time.Now().Unix() * 1000)
+		}
+		params.Set("startTime", fmt.Sprintf("%d", since))
+		params.Set("endTime", fmt.Sprintf("%d", endTimestamp))
+	}
+
+	var klines = make([][]interface{}, 0)
 	resp, err := swap.DoRequest(
 		http.MethodGet,
-		"/futures/data/openInterestHist?"+params.Encode(),
+		leverageApiRelation[settleMode]+"/v1/"+api+"?"+params.Encode(),
 		"",
-		&responses,
-		SETTLE_MODE_COUNTER,
+		&klines,
+		settleMode,
 	)
 	if err != nil {
-		return 0, 0, nil, err
+		return nil, resp, err
+	}
+	return klines, resp, nil
+}
+
+func (swap *Swap) GetOpenAmount(pair Pair) (float64, int64, []byte, error) {
+	var openInterest, resp, err = swap.GetOpenInterest(pair)
+	if err != nil {
+		return 0, 0, resp, err
 	}
-	return responses[len(responses)-1].Amount, responses[len(responses)-1].Timestamp, resp, nil
+	return openInterest.Amount, openInterest.Timestamp, resp, nil
 }
 
 func (swap *Swap) GetFundingFee(pair Pair) (float64, error) {
@@ -470,6 +559,134 @@ func (swap *Swap) GetFundingFees(pair Pair) ([][]interface{}, []byte, error) {
 	}
 }
 
+const (
+	SWAP_FUNDING_LIMIT    = 1000
+	SWAP_FUNDING_INTERVAL = 8 * 60 * 60 * 1000 // unit: ms, the default funding interval
+)
+
+// GetFundingRateHistory request the fundingRate api from the start page by page, 1000 rates in one page.
+func (swap *Swap) GetFundingRateHistory(pair Pair, start, end time.Time) ([]*FundingRate, []byte, error) {
+	var symbol, settleMode = swap.contractSymbol(pair)
+	var rates = make([]*FundingRate, 0)
+	var responses = make([][]byte, 0)
+	var startTime = start.UnixMilli()
+	for startTime <= end.UnixMilli() {
+		var param = url.Values{}
+		param.Set("symbol", symbol)
+		param.Set("startTime", fmt.Sprintf("%d", startTime))
+		param.Set("endTime", fmt.Sprintf("%d", end.UnixMilli()))
+		param.Set("limit", fmt.Sprintf("%d", SWAP_FUNDING_LIMIT))
+
+		var response = make([]struct {
+			FundingRate float64 `json:"fundingRate,string"`
+			FundingTime int64   `json:"fundingTime"`
+			MarkPrice   string  `json:"markPrice"`
+		}, 0)
+		resp, err := swap.DoRequest(
+			http.MethodGet,
+			leverageApiRelation[settleMode]+"/v1/fundingRate?"+param.Encode(),
+			"",
+			&response,
+			settleMode,
+		)
+		if err != nil {
+			return nil, BatchResponse(append(responses, resp)), err
+		}
+		responses = append(responses, resp)
+		for _, item := range response {
+			rates = append(rates, &FundingRate{
+				Pair:        pair,
+				Exchange:    BINANCE,
+				Rate:        item.FundingRate,
+				FundingTime: item.FundingTime,
+				MarkPrice:   ToFloat64(item.MarkPrice),
+			})
+		}
+		if len(response) < SWAP_FUNDING_LIMIT {
+			break
+		}
+		startTime = response[len(response)-1].FundingTime + 1
+	}
+
+	SortFundingRates(rates, swap.getFundingInterval(symbol, settleMode))
+	return rates, BatchResponse(responses), nil
+}
+
+// GetNextFundingRate return the last funding rate in the premium index, it is the rate of the next funding time.
+func (swap *Swap) GetNextFundingRate(pair Pair) (*FundingRate, []byte, error) {
+	var symbol, settleMode = swap.contractSymbol(pair)
+	var param = url.Values{}
+	param.Set("symbol", symbol)
+
+	var raw json.RawMessage
+	resp, err := swap.DoRequest(
+		http.MethodGet,
+		leverageApiRelation[settleMode]+"/v1/premiumIndex?"+param.Encode(),
+		"",
+		&raw,
+		settleMode,
+	)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	// the usdt margined endpoint return an object, the coin margined endpoint return an array.
+	var response = make([]struct {
+		Symbol          string  `json:"symbol"`
+		MarkPrice       float64 `json:"markPrice,string"`
+		LastFundingRate float64 `json:"lastFundingRate,string"`
+		NextFundingTime int64   `json:"nextFundingTime"`
+	}, 0)
+	var data = strings.TrimSpace(string(resp))
+	if strings.HasPrefix(data, "{") {
+		data = "[" + data + "]"
+	}
+	if err := json.Unmarshal([]byte(data), &response); err != nil {
+		return nil, resp, err
+	}
+	for _, item := range response {
+		if item.Symbol != symbol {
+			continue
+		}
+		return &FundingRate{
+			Pair:        pair,
+			Exchange:    BINANCE,
+			Rate:        item.LastFundingRate,
+			IsPredicted: true,
+			FundingTime: item.NextFundingTime,
+			Interval:    swap.getFundingInterval(symbol, settleMode),
+			MarkPrice:   item.MarkPrice,
+		}, resp, nil
+	}
+	return nil, resp, fmt.Errorf("the premium index of %s is not found. ", symbol)
+}
+
+// getFundingInterval return the interval of the symbol in the fundingInfo api, only the adjusted symbols are in it.
+func (swap *Swap) getFundingInterval(symbol string, settleMode int64) int64 {
+	if settleMode != SETTLE_MODE_COUNTER {
+		return SWAP_FUNDING_INTERVAL
+	}
+	var response = make([]struct {
+		Symbol               string `json:"symbol"`
+		FundingIntervalHours int64  `json:"fundingIntervalHours"`
+	}, 0)
+	if _, err := swap.DoRequest(
+		http.MethodGet,
+		"/fapi/v1/fundingInfo",
+		"",
+		&response,
+		SETTLE_MODE_COUNTER,
+	); err != nil {
+		return SWAP_FUNDING_INTERVAL
+	}
+	for _, item := range response {
+		if item.Symbol == symbol && item.FundingIntervalHours > 0 {
+			return item.FundingIntervalHours * 60 * 60 * 1000
+		}
+	}
+	return SWAP_FUNDING_INTERVAL
+}
+
 var placeTypeRelation = map[PlaceType]string{
 	NORMAL:     "GTC",
 	ONLY_MAKER: "GTX",
@@ -485,11 +702,10 @@ var sideRelation = map[FutureType]string{
 	LIQUIDATE_SHORT: "BUY",
 }
 
-var positionSideRelation = map[FutureType]string{
-	OPEN_LONG:       "LONG",
-	OPEN_SHORT:      "SHORT",
-	LIQUIDATE_LONG:  "LONG",
-	LIQUIDATE_SHORT: "SHORT",
+var positionSideRelation = map[PositionSide]string{
+	POSITION_SIDE_BOTH:  "BOTH",
+	POSITION_SIDE_LONG:  "LONG",
+	POSITION_SIDE_SHORT: "SHORT",
 }
 
 var statusRelation = map[string]TradeStatus{
@@ -506,34 +722,87 @@ func (swap *Swap) PlaceOrder(order *SwapOrder) ([]byte, error) {
 		return nil, errors.New("order param is nil")
 	}
 
-	var side, positionSide, placeType = "", "", ""
+	var param, contract, err = swap.placeParams(order)
+	if err != nil {
+		return nil, err
+	}
+	var uri = SWAP_COUNTER_PLACE_ORDER_URI
+	if contract.SettleMode == SETTLE_MODE_BASIS {
+		uri = SWAP_BASIS_PLACE_ORDER_URI
+	}
+
+	var response swapOrderResponse
+	now := time.Now()
+	if err := swap.buildParamsSigned(&param); err != nil {
+		return nil, err
+	}
+	resp, err := swap.DoRequest(
+		http.MethodPost,
+		uri+param.Encode(),
+		"",
+		&response,
+		contract.SettleMode,
+	)
+	if err != nil {
+		return nil, err
+	}
+	response.merge(order, now, swap.config.Location)
+	return resp, nil
+}
+
+// placeParams build the params of the order without the sign, the batch orders use it too.
+func (swap *Swap) placeParams(order *SwapOrder) (url.Values, *SwapContract, error) {
+	var side, placeType = "", ""
 	var exist = false
 
 	if side, exist = sideRelation[order.Type]; !exist {
-		return nil, errors.New("swap type not found. ")
-	}
-	if positionSide, exist = positionSideRelation[order.Type]; !exist {
-		return nil, errors.New("swap type not found. ")
+		return nil, nil, errors.New("swap type not found. ")
 	}
 	if placeType, exist = placeTypeRelation[order.PlaceType]; !exist {
-		return nil, errors.New("place type not found. ")
+		return nil, nil, errors.New("place type not found. ")
+	}
+	var positionSide, reduceOnly, err = order.ResolvePosition()
+	if err != nil {
+		return nil, nil, err
+	}
+	// binance close the position in the stop market and the take profit market order only.
+	if order.ClosePosition && (placeType != "MARKET" ||
+		(order.ConditionType != CONDITION_STOP && order.ConditionType != CONDITION_TAKE_PROFIT)) {
+		return nil, nil, errors.New("binance close position only in the stop or take profit market order. ")
 	}
 
 	var contract = swap.GetContract(order.Pair)
 	var paramSymbol = order.Pair.ToSymbol("", true)
-	var uri = SWAP_COUNTER_PLACE_ORDER_URI
 	if contract.SettleMode == SETTLE_MODE_BASIS {
 		paramSymbol += "_PERP"
-		uri = SWAP_BASIS_PLACE_ORDER_URI
+	}
+
+	var normalizer = contract.GetNormalizer()
+	normalizer.ReduceOnly = reduceOnly
+	var price, amount = order.Price, order.Amount
+	if order.ClosePosition {
+		price, err = normalizer.NormalizePrice(order.Price, placeType == "MARKET")
+	} else {
+		price, amount, err = normalizer.Normalize(order.Price, order.Amount, placeType == "MARKET")
+	}
+	if err != nil {
+		return nil, nil, err
 	}
 
 	var param = url.Values{}
 	param.Set("symbol", paramSymbol)
 	param.Set("side", side)
-	param.Set("positionSide", positionSide)
+	param.Set("positionSide", positionSideRelation[positionSide])
 	param.Set("type", "LIMIT")
-	param.Set("price", FloatToPrice(order.Price, contract.PricePrecision, contract.TickSize))
-	param.Set("quantity", FloatToString(order.Amount, contract.AmountPrecision))
+	param.Set("price", normalizer.FormatPrice(price))
+	param.Set("quantity", normalizer.FormatAmount(amount))
+	if order.ClosePosition {
+		param.Set("closePosition", "true")
+		param.Del("quantity")
+	} else if reduceOnly && positionSide == POSITION_SIDE_BOTH {
+		// the reduce only is rejected in the hedge mode, the liquidate side reduce the position already.
+		param.Set("reduceOnly", "true")
+	}
 
 	if placeType == "MARKET" {
 		param.Set("type", "MARKET")
@@ -545,49 +814,276 @@ func (swap *Swap) PlaceOrder(order *SwapOrder) ([]byte, error) {
 		// "GTX": 无法成为挂单方就撤销。
 		param.Set("timeInForce", placeType)
 	}
+	if order.ConditionType != CONDITION_NONE {
+		if err := swap.setConditionParams(&param, order, normalizer, placeType == "MARKET"); err != nil {
+			return nil, nil, err
+		}
+	}
 	if order.Cid != "" {
 		param.Set("newClientOrderId", order.Cid)
 	}
+	return param, contract, nil
+}
 
-	var response struct {
-		Cid        string  `json:"clientOrderId"`
-		Status     string  `json:"status"`
-		CumQuote   float64 `json:"cumQuote,string"`
-		DealAmount float64 `json:"executedQty,string"`
-		OrderId    int64   `json:"orderId"`
-		UpdateTime int64   `json:"updateTime"`
-		Price      float64 `json:"price,string"`
-		Amount     float64 `json:"origQty,string"`
-	}
+// swapOrderResponse is the order in the place and the batch response, the Code is not 0 when the batch order fail.
+type swapOrderResponse struct {
+	Code       int64   `json:"code"`
+	Msg        string  `json:"msg"`
+	Cid        string  `json:"clientOrderId"`
+	Status     string  `json:"status"`
+	CumQuote   float64 `json:"cumQuote,string"`
+	DealAmount float64 `json:"executedQty,string"`
+	OrderId    int64   `json:"orderId"`
+	UpdateTime int64   `json:"updateTime"`
+	Price      float64 `json:"price,string"`
+	Amount     float64 `json:"origQty,string"`
+	AvgPrice   float64 `json:"avgPrice,string"`
+}
 
-	now := time.Now()
-	if err := swap.buildParamsSigned(&param); err != nil {
-		return nil, err
-	}
-	resp, err := swap.DoRequest(
-		http.MethodPost,
-		uri+param.Encode(),
-		"",
-		&response,
-		contract.SettleMode,
-	)
-	if err != nil {
-		return nil, err
-	}
+func (response *swapOrderResponse) merge(order *SwapOrder, placeTime time.Time, loc *time.Location) {
 	orderTime := time.Unix(response.UpdateTime/1000, 0)
 	order.OrderId = fmt.Sprintf("%d", response.OrderId)
-	order.PlaceTimestamp = now.UnixNano() / int64(time.Millisecond)
-	order.PlaceDatetime = now.In(swap.config.Location).Format(GO_BIRTHDAY)
+	order.PlaceTimestamp = placeTime.UnixNano() / int64(time.Millisecond)
+	order.PlaceDatetime = placeTime.In(loc).Format(GO_BIRTHDAY)
 	order.DealTimestamp = response.UpdateTime
-	order.DealDatetime = orderTime.In(swap.config.Location).Format(GO_BIRTHDAY)
+	order.DealDatetime = orderTime.In(loc).Format(GO_BIRTHDAY)
 	order.Status = statusRelation[response.Status]
 	order.Price = response.Price
 	order.Amount = response.Amount
 	if response.DealAmount > 0 {
 		order.AvgPrice = response.CumQuote / response.DealAmount
+		if response.AvgPrice > 0 {
+			order.AvgPrice = response.AvgPrice
+		}
 		order.DealAmount = response.DealAmount
 	}
-	return resp, nil
+}
+
+// swapBatchGroup is the orders in one batch request, they are in the same endpoint and the same symbol.
+type swapBatchGroup struct {
+	settleMode int64
+	symbol     string
+	byCid      bool
+	indexes    []int
+}
+
+// BatchPlaceOrders place the orders by the batchOrders api, 5 orders in one request at most.
+func (swap *Swap) BatchPlaceOrders(orders []*SwapOrder) ([]error, []byte, error) {
+	var errs = make([]error, len(orders))
+	var params = make([]url.Values, len(orders))
+	var groups = make([]*swapBatchGroup, 0)
+	for i, order := range orders {
+		if order == nil {
+			errs[i] = errors.New("order param is nil")
+			continue
+		}
+		var param, contract, err = swap.placeParams(order)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		params[i] = param
+		// the counter and the basis swaps are in the different endpoints.
+		var group = findSwapBatchGroup(&groups, contract.SettleMode, "", false)
+		group.indexes = append(group.indexes, i)
+	}
+
+	var responses = make([][]byte, 0)
+	for _, group := range groups {
+		var uri = SWAP_COUNTER_BATCH_ORDERS_URI
+		if group.settleMode == SETTLE_MODE_BASIS {
+			uri = SWAP_BASIS_BATCH_ORDERS_URI
+		}
+		for _, r := range BatchSplit(len(group.indexes), SWAP_BATCH_PLACE_LIMIT) {
+			var indexes = group.indexes[r[0]:r[1]]
+			var batchOrders = make([]map[string]string, 0, len(indexes))
+			for _, i := range indexes {
+				var batchOrder = make(map[string]string)
+				for key := range params[i] {
+					batchOrder[key] = params[i].Get(key)
+				}
+				batchOrders = append(batchOrders, batchOrder)
+			}
+			var raw, _ = json.Marshal(batchOrders)
+			var param = url.Values{}
+			param.Set("batchOrders", string(raw))
+			if err := swap.buildParamsSigned(&param); err != nil {
+				for _, i := range indexes {
+					errs[i] = err
+				}
+				continue
+			}
+
+			var response = make([]swapOrderResponse, 0)
+			var now = time.Now()
+			var resp, err = swap.DoRequest(
+				http.MethodPost,
+				uri+param.Encode(),
+				"",
+				&response,
+				group.settleMode,
+			)
+			if len(resp) > 0 {
+				responses = append(responses, resp)
+			}
+			swap.mergeBatch(orders, indexes, response, resp, err, now, errs)
+		}
+	}
+	return errs, BatchResponse(responses), BatchResult(errs)
+}
+
+// BatchCancelOrders cancel the orders by the batchOrders api, 10 orders of the same symbol in one request at most.
+func (swap *Swap) BatchCancelOrders(orders []*SwapOrder) ([]error, []byte, error) {
+	var errs = make([]error, len(orders))
+	var groups = make([]*swapBatchGroup, 0)
+	for i, order := range orders {
+		if order == nil {
+			errs[i] = errors.New("order param is nil")
+			continue
+		}
+		if order.OrderId == "" && order.Cid == "" {
+			errs[i] = errors.New("The orderid and cid is empty. ")
+			continue
+		}
+		var contract = swap.GetContract(order.Pair)
+		var symbol = order.Pair.ToSymbol("", true)
+		if contract.SettleMode == SETTLE_MODE_BASIS {
+			symbol += "_PERP"
+		}
+		var group = findSwapBatchGroup(&groups, contract.SettleMode, symbol, order.OrderId == "")
+		group.indexes = append(group.indexes, i)
+	}
+
+	var responses = make([][]byte, 0)
+	for _, group := range groups {
+		var uri = SWAP_COUNTER_BATCH_ORDERS_URI
+		if group.settleMode == SETTLE_MODE_BASIS {
+			uri = SWAP_BASIS_BATCH_ORDERS_URI
+		}
+		for _, r := range BatchSplit(len(group.indexes), SWAP_BATCH_CANCEL_LIMIT) {
+			var indexes = group.indexes[r[0]:r[1]]
+			var param = url.Values{}
+			param.Set("symbol", group.symbol)
+			if group.byCid {
+				var cids = make([]string, 0, len(indexes))
+				for _, i := range indexes {
+					cids = append(cids, orders[i].Cid)
+				}
+				var raw, _ = json.Marshal(cids)
+				param.Set("origClientOrderIdList", string(raw))
+			} else {
+				var orderIds = make([]int64, 0, len(indexes))
+				for _, i := range indexes {
+					orderIds = append(orderIds, ToInt64(orders[i].OrderId))
+				}
+				var raw, _ = json.Marshal(orderIds)
+				param.Set("orderIdList", string(raw))
+			}
+			if err := swap.buildParamsSigned(&param); err != nil {
+				for _, i := range indexes {
+					errs[i] = err
+				}
+				continue
+			}
+
+			var response = make([]swapOrderResponse, 0)
+			var now = time.Now()
+			var resp, err = swap.DoRequest(
+				http.MethodDelete,
+				uri+param.Encode(),
+				"",
+				&response,
+				group.settleMode,
+			)
+			if len(resp) > 0 {
+				responses = append(responses, resp)
+			}
+			swap.mergeBatch(orders, indexes, response, resp, err, now, errs)
+		}
+	}
+	return errs, BatchResponse(responses), BatchResult(errs)
+}
+
+// mergeBatch merge the batch response to the orders, the response is in the same order of the request.
+func (swap *Swap) mergeBatch(
+	orders []*SwapOrder, indexes []int, response []swapOrderResponse, resp []byte, err error, now time.Time, errs []error,
+) {
+	for j, i := range indexes {
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		if j >= len(response) {
+			errs[i] = errors.New(string(resp))
+			continue
+		}
+		if response[j].Code != 0 {
+			errs[i] = fmt.Errorf("%d: %s", response[j].Code, response[j].Msg)
+			continue
+		}
+		response[j].merge(orders[i], now, swap.config.Location)
+	}
+}
+
+func findSwapBatchGroup(groups *[]*swapBatchGroup, settleMode int64, symbol string, byCid bool) *swapBatchGroup {
+	for _, group := range *groups {
+		if group.settleMode == settleMode && group.symbol == symbol && group.byCid == byCid {
+			return group
+		}
+	}
+	var group = &swapBatchGroup{settleMode: settleMode, symbol: symbol, byCid: byCid}
+	*groups = append(*groups, group)
+	return group
+}
+
+var conditionTypeRelation = map[ConditionType][2]string{
+	CONDITION_STOP:          {"STOP", "STOP_MARKET"},
+	CONDITION_TAKE_PROFIT:   {"TAKE_PROFIT", "TAKE_PROFIT_MARKET"},
+	CONDITION_TRAILING_STOP: {"TRAILING_STOP_MARKET", "TRAILING_STOP_MARKET"},
+}
+
+var workingTypeRelation = map[TriggerType]string{
+	TRIGGER_LAST: "CONTRACT_PRICE",
+	TRIGGER_MARK: "MARK_PRICE",
+}
+
+// setConditionParams change the order type to the conditional one, binance not support the index trigger.
+func (swap *Swap) setConditionParams(param *url.Values, order *SwapOrder, normalizer *Normalizer, isMarket bool) error {
+	var orderTypes, exist = conditionTypeRelation[order.ConditionType]
+	if !exist {
+		return errors.New("condition type not found. ")
+	}
+	var workingType, isSupport = workingTypeRelation[order.TriggerType]
+	if !isSupport {
+		return fmt.Errorf("binance not support the %s trigger. ", order.TriggerType)
+	}
+	param.Set("workingType", workingType)
+
+	if order.ConditionType == CONDITION_TRAILING_STOP {
+		if order.CallbackRate <= 0 {
+			return errors.New("the callback rate of the trailing stop must be positive. ")
+		}
+		param.Set("type", orderTypes[1])
+		// the callback rate is in percent, eg: 1 means 1%
+		param.Set("callbackRate", FloatToString(order.CallbackRate*100, 1))
+		if order.TriggerPrice > 0 {
+			param.Set("activationPrice", normalizer.FormatPrice(order.TriggerPrice))
+		}
+		param.Del("price")
+		param.Del("timeInForce")
+		return nil
+	}
+
+	if order.TriggerPrice <= 0 {
+		return errors.New("the trigger price must be positive. ")
+	}
+	param.Set("stopPrice", normalizer.FormatPrice(order.TriggerPrice))
+	if isMarket {
+		param.Set("type", orderTypes[1])
+	} else {
+		param.Set("type", orderTypes[0])
+	}
+	return nil
 }
 
 func (swap *Swap) CancelOrder(order *SwapOrder) ([]byte, error) {
@@ -680,6 +1176,133 @@ func (swap *Swap) CancelOrder(order *SwapOrder) ([]byte, error) {
 	return resp, nil
 }
 
+// AmendOrder modify the open limit order by the PUT order api, the order keep the queue position
+// when only the amount is reduced.
+func (swap *Swap) AmendOrder(order *SwapOrder, newPrice, newAmount float64) ([]byte, error) {
+	if order.OrderId == "" && order.Cid == "" {
+		return nil, errors.New("The orderid and cid is empty. ")
+	}
+	var side, exist = sideRelation[order.Type]
+	if !exist {
+		return nil, errors.New("swap type not found. ")
+	}
+	if newPrice <= 0 {
+		newPrice = order.Price
+	}
+	if newAmount <= 0 {
+		newAmount = order.Amount
+	}
+
+	var contract = swap.GetContract(order.Pair)
+	var paramSymbol = order.Pair.ToSymbol("", true)
+	var uri = SWAP_COUNTER_PLACE_ORDER_URI
+	if contract.SettleMode == SETTLE_MODE_BASIS {
+		paramSymbol += "_PERP"
+		uri = SWAP_BASIS_PLACE_ORDER_URI
+	}
+	var normalizer = contract.GetNormalizer()
+	normalizer.ReduceOnly = IsReduce(order.Type, order.ReduceOnly)
+	var price, amount, err = normalizer.Normalize(newPrice, newAmount, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var param = url.Values{}
+	param.Set("symbol", paramSymbol)
+	param.Set("side", side)
+	param.Set("price", normalizer.FormatPrice(price))
+	param.Set("quantity", normalizer.FormatAmount(amount))
+	if order.OrderId != "" {
+		param.Set("orderId", order.OrderId)
+	} else {
+		param.Set("origClientOrderId", order.Cid)
+	}
+	if err := swap.buildParamsSigned(&param); err != nil {
+		return nil, err
+	}
+
+	var response swapOrderResponse
+	resp, err := swap.DoRequest(
+		http.MethodPut,
+		uri+param.Encode(),
+		"",
+		&response,
+		contract.SettleMode,
+	)
+	if err != nil {
+		return resp, err
+	}
+	var placeTime = time.Now()
+	if order.PlaceTimestamp > 0 {
+		placeTime = time.UnixMilli(order.PlaceTimestamp)
+	}
+	response.merge(order, placeTime, swap.config.Location)
+	return resp, nil
+}
+
+func (swap *Swap) CancelAllOrders(pair Pair) ([]byte, error) {
+	var contract = swap.GetContract(pair)
+	var param = url.Values{}
+	var uri = SWAP_COUNTER_CANCEL_ALL_URI
+	if contract.SettleMode == SETTLE_MODE_BASIS {
+		param.Set("symbol", pair.ToSymbol("", true)+"_PERP")
+		uri = SWAP_BASIS_CANCEL_ALL_URI
+	} else {
+		param.Set("symbol", pair.ToSymbol("", true))
+	}
+	if err := swap.buildParamsSigned(&param); err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		Code int64  `json:"code"`
+		Msg  string `json:"msg"`
+	}
+	resp, err := swap.DoRequest(
+		http.MethodDelete,
+		uri+param.Encode(),
+		"",
+		&response,
+		contract.SettleMode,
+	)
+	if err != nil {
+		return resp, err
+	}
+	if response.Code != 200 {
+		return resp, errors.New(string(resp))
+	}
+	return resp, nil
+}
+
+// CancelAllAfter set the countdownCancelAll of the symbol, binance check it every 10 seconds.
+func (swap *Swap) CancelAllAfter(pair Pair, timeout time.Duration) ([]byte, error) {
+	var contract = swap.GetContract(pair)
+	var param = url.Values{}
+	var uri = SWAP_COUNTER_COUNTDOWN_URI
+	if contract.SettleMode == SETTLE_MODE_BASIS {
+		param.Set("symbol", pair.ToSymbol("", true)+"_PERP")
+		uri = SWAP_BASIS_COUNTDOWN_URI
+	} else {
+		param.Set("symbol", pair.ToSymbol("", true))
+	}
+	param.Set("countdownTime", fmt.Sprintf("%d", timeout.Milliseconds()))
+	if err := swap.buildParamsSigned(&param); err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		Symbol        string `json:"symbol"`
+		CountdownTime string `json:"countdownTime"`
+	}
+	return swap.DoRequest(
+		http.MethodPost,
+		uri+param.Encode(),
+		"",
+		&response,
+		contract.SettleMode,
+	)
+}
+
 func (swap *Swap) GetOrder(order *SwapOrder) ([]byte, error) {
 	if order.OrderId == "" && order.Cid == "" {
 		return nil, errors.New("The orderid and cid is empty. ")
@@ -777,20 +1400,62 @@ func (swap *Swap) GetOrder(order *SwapOrder) ([]byte, error) {
 	return resp, nil
 }
 
+// swapRawOrder is the order in the binance order list.
+type swapRawOrder struct {
+	Price          float64 `json:"price,string"`
+	Amount         float64 `json:"origQty,string"`
+	AvgPrice       float64 `json:"avgPrice,string"`
+	DealAmount     float64 `json:"executedQty,string"`
+	Cid            string  `json:"clientOrderId"`
+	OrderId        int64   `json:"orderId"`
+	Status         string  `json:"status"`
+	OrderTimestamp int64   `json:"time"`
+	DealTimestamp  int64   `json:"updateTime"`
+	Side           string  `json:"side"`
+	PositionSide   string  `json:"positionSide"`
+	ReduceOnly     bool    `json:"reduceOnly"`
+	ClosePosition  bool    `json:"closePosition"`
+}
+
+func (swap *Swap) toSwapOrder(pair Pair, rawOrder *swapRawOrder) (*SwapOrder, error) {
+	var futureType, err = getFutureType(rawOrder.Side, rawOrder.PositionSide, rawOrder.ReduceOnly || rawOrder.ClosePosition)
+	if err != nil {
+		return nil, err
+	}
+	var positionSide = POSITION_SIDE_BOTH
+	for side, remoteSide := range positionSideRelation {
+		if remoteSide == rawOrder.PositionSide {
+			positionSide = side
+		}
+	}
+
+	var orderTime = time.Unix(rawOrder.OrderTimestamp/1000, 0)
+	var dealTime = time.Unix(rawOrder.DealTimestamp/1000, 0)
+	return &SwapOrder{
+		Cid:        rawOrder.Cid,
+		OrderId:    fmt.Sprintf("%d", rawOrder.OrderId),
+		Type:       futureType,
+		Price:      rawOrder.Price,
+		Amount:     rawOrder.Amount,
+		AvgPrice:   rawOrder.AvgPrice,
+		DealAmount: rawOrder.DealAmount,
+		Status:     statusRelation[rawOrder.Status],
+
+		Pair:           pair,
+		Exchange:       BINANCE,
+		PlaceTimestamp: rawOrder.OrderTimestamp,
+		PlaceDatetime:  orderTime.In(swap.config.Location).Format(GO_BIRTHDAY),
+		DealTimestamp:  rawOrder.DealTimestamp,
+		DealDatetime:   dealTime.In(swap.config.Location).Format(GO_BIRTHDAY),
+
+		ReduceOnly:    rawOrder.ReduceOnly,
+		ClosePosition: rawOrder.ClosePosition,
+		PositionSide:  positionSide,
+	}, nil
+}
+
 func (swap *Swap) GetOrders(pair Pair) ([]*SwapOrder, []byte, error) {
-	var rawOrders = make([]struct {
-		Price          float64 `json:"price,string"`
-		Amount         float64 `json:"origQty,string"`
-		AvgPrice       float64 `json:"avgPrice,string"`
-		DealAmount     float64 `json:"executedQty,string"`
-		Cid            string  `json:"clientOrderId"`
-		OrderId        int64   `json:"orderId"`
-		Status         string  `json:"status"`
-		OrderTimestamp int64   `json:"time"`
-		DealTimestamp  int64   `json:"updateTime"`
-		Side           string  `json:"side"`
-		PositionSide   string  `json:"positionSide"`
-	}, 0)
+	var rawOrders = make([]*swapRawOrder, 0)
 
 	params := url.Values{}
 	params.Set("symbol", pair.ToSymbol("", true))
@@ -808,35 +1473,12 @@ func (swap *Swap) GetOrders(pair Pair) ([]*SwapOrder, []byte, error) {
 	if err != nil {
 		return nil, nil, err
 	}
-	if len(rawOrders) == 0 {
-		return make([]*SwapOrder, 0), resp, nil
-	}
 
-	swapOrders := make([]*SwapOrder, 0)
+	swapOrders := make([]*SwapOrder, 0, len(rawOrders))
 	for _, rawOrder := range rawOrders {
-		orderTime := time.Unix(rawOrder.OrderTimestamp/1000, 0)
-		dealTime := time.Unix(rawOrder.DealTimestamp/1000, 0)
-		// do not support the both position in binance
-		if rawOrder.PositionSide == "BOTH" {
-			continue
-		}
-
-		s := &SwapOrder{
-			Cid:        rawOrder.Cid,
-			OrderId:    fmt.Sprintf("%d", rawOrder.OrderId),
-			Type:       swap.getFutureType(rawOrder.Side, rawOrder.PositionSide),
-			Price:      rawOrder.Price,
-			Amount:     rawOrder.Amount,
-			AvgPrice:   rawOrder.AvgPrice,
-			DealAmount: rawOrder.DealAmount,
-			Status:     statusRelation[rawOrder.Status],
-
-			Pair:           pair,
-			Exchange:       BINANCE,
-			PlaceTimestamp: rawOrder.OrderTimestamp,
-			PlaceDatetime:  orderTime.In(swap.config.Location).Format(GO_BIRTHDAY),
-			DealTimestamp:  rawOrder.DealTimestamp,
-			DealDatetime:   dealTime.In(swap.config.Location).Format(GO_BIRTHDAY),
+		s, err := swap.toSwapOrder(pair, rawOrder)
+		if err != nil {
+			return nil, resp, err
 		}
 		swapOrders = append(swapOrders, s)
 	}
@@ -851,52 +1493,23 @@ func (swap *Swap) GetUnFinishOrders(pair Pair) ([]*SwapOrder, []byte, error) {
 		return nil, nil, err
 	}
 
-	response := make([]struct {
-		Price          float64 `json:"price,string"`
-		Amount         float64 `json:"origQty,string"`
-		AvgPrice       float64 `json:"avgPrice,string"`
-		DealAmount     float64 `json:"executedQty,string"`
-		Cid            string  `json:"clientOrderId"`
-		OrderId        int64   `json:"orderId"`
-		Status         string  `json:"status"`
-		OrderTimestamp int64   `json:"time"`
-		DealTimestamp  int64   `json:"updateTime"`
-		Side           string  `json:"side"`
-		PositionSide   string  `json:"positionSide"`
-	}, 0)
-
+	var rawOrders = make([]*swapRawOrder, 0)
 	resp, err := swap.DoRequest(
 		http.MethodGet,
 		"/fapi/v1/openOrders?"+param.Encode(),
 		"",
-		&response,
+		&rawOrders,
 		SETTLE_MODE_COUNTER,
 	)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	swapOrders := make([]*SwapOrder, 0)
-	for _, rawOrder := range response {
-		if rawOrder.PositionSide == "BOTH" {
-			continue
-		}
-		orderTime := time.Unix(rawOrder.OrderTimestamp/1000, 0)
-		dealTime := time.Unix(rawOrder.DealTimestamp/1000, 0)
-		s := &SwapOrder{
-			Cid:            rawOrder.Cid,
-			OrderId:        fmt.Sprintf("%d", rawOrder.OrderId),
-			Price:          rawOrder.Price,
-			Amount:         rawOrder.Amount,
-			AvgPrice:       rawOrder.AvgPrice,
-			DealAmount:     rawOrder.DealAmount,
-			Status:         statusRelation[rawOrder.Status],
-			Pair:           pair,
-			Exchange:       BINANCE,
-			PlaceTimestamp: rawOrder.OrderTimestamp,
-			PlaceDatetime:  orderTime.In(swap.config.Location).Format(GO_BIRTHDAY),
-			DealTimestamp:  rawOrder.DealTimestamp,
-			DealDatetime:   dealTime.In(swap.config.Location).Format(GO_BIRTHDAY),
+	swapOrders := make([]*SwapOrder, 0, len(rawOrders))
+	for _, rawOrder := range rawOrders {
+		s, err := swap.toSwapOrder(pair, rawOrder)
+		if err != nil {
+			return nil, resp, err
 		}
 		swapOrders = append(swapOrders, s)
 	}
@@ -935,17 +1548,20 @@ func (swap *Swap) GetPosition(pair Pair, openType FutureType) (*SwapPosition, []
 	}
 
 	for _, p := range response {
-		//do not support the binance both mode.
-		if p.PositionSide == "BOTH" {
+		// the one-way mode position is in the BOTH side, it is short when the amount is negative.
+		if p.PositionSide == "BOTH" && p.PositionAmt == 0 {
 			continue
 		}
 		if p.Symbol != pair.ToSymbol("", true) {
 			continue
 		}
 		positionType := OPEN_LONG
-		if p.PositionSide == "SHORT" {
+		if p.PositionSide == "SHORT" || (p.PositionSide == "BOTH" && p.PositionAmt < 0) {
 			positionType = OPEN_SHORT
 		}
+		if p.PositionAmt < 0 {
+			p.PositionAmt = -p.PositionAmt
+		}
 		if openType != positionType {
 			continue
 		}
@@ -967,6 +1583,70 @@ func (swap *Swap) GetPosition(pair Pair, openType FutureType) (*SwapPosition, []
 	return nil, resp, errors.New("Can not find the position. ")
 }
 
+// GetPositionMode return the dual side position setting of the settle mode of the pair.
+func (swap *Swap) GetPositionMode(pair Pair) (PositionMode, []byte, error) {
+	var contract = swap.GetContract(pair)
+	var uri = SWAP_COUNTER_DUAL_SIDE_URI
+	if contract.SettleMode == SETTLE_MODE_BASIS {
+		uri = SWAP_BASIS_DUAL_SIDE_URI
+	}
+	var param = url.Values{}
+	if err := swap.buildParamsSigned(&param); err != nil {
+		return POSITION_MODE_ONE_WAY, nil, err
+	}
+
+	var response struct {
+		DualSidePosition bool `json:"dualSidePosition"`
+	}
+	resp, err := swap.DoRequest(
+		http.MethodGet,
+		uri+param.Encode(),
+		"",
+		&response,
+		contract.SettleMode,
+	)
+	if err != nil {
+		return POSITION_MODE_ONE_WAY, resp, err
+	}
+	if response.DualSidePosition {
+		return POSITION_MODE_HEDGE, resp, nil
+	}
+	return POSITION_MODE_ONE_WAY, resp, nil
+}
+
+// SetPositionMode change the dual side position setting, it fails when there are open orders or positions.
+func (swap *Swap) SetPositionMode(pair Pair, mode PositionMode) ([]byte, error) {
+	var contract = swap.GetContract(pair)
+	var uri = SWAP_COUNTER_DUAL_SIDE_URI
+	if contract.SettleMode == SETTLE_MODE_BASIS {
+		uri = SWAP_BASIS_DUAL_SIDE_URI
+	}
+	var param = url.Values{}
+	param.Set("dualSidePosition", fmt.Sprintf("%t", mode == POSITION_MODE_HEDGE))
+	if err := swap.buildParamsSigned(&param); err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		Code int64  `json:"code"`
+		Msg  string `json:"msg"`
+	}
+	resp, err := swap.DoRequest(
+		http.MethodPost,
+		uri+param.Encode(),
+		"",
+		&response,
+		contract.SettleMode,
+	)
+	if err != nil {
+		return resp, err
+	}
+	if response.Code != 200 {
+		return resp, errors.New(string(resp))
+	}
+	return resp, nil
+}
+
 func (swap *Swap) AddMargin(pair Pair, openType FutureType, marginAmount float64) ([]byte, error) {
 	return swap.modifyMargin(pair, openType, marginAmount, 1)
 }
@@ -1017,7 +1697,9 @@ func (swap *Swap) DoRequest(httpMethod, uri, reqBody string, response interface{
 	} else {
 		bnUrl = SWAP_BASIS_ENDPOINT + uri
 	}
-	resp, err := NewHttpRequest(
+	resp, err := NewHttpRequestWithMetrics(
+		swap.config.GetMetrics(),
+		BINANCE,
 		swap.config.HttpClient,
 		httpMethod,
 		bnUrl,
@@ -1190,18 +1872,7 @@ func (swap *Swap) updateContracts() ([]byte, error) {
 			settleMode = SETTLE_MODE_COUNTER
 		}
 
-		var tickSize = float64(-1)
-		for _, f := range c.Filters {
-			var ft, isFt = f["filterType"]
-			if !isFt || ft != "PRICE_FILTER" {
-				continue
-			}
-			var ts, isTs = f["tickSize"]
-			if isTs {
-				tickSize = ToFloat64(ts)
-				break
-			}
-		}
+		var filters = parseFilters(c.Filters)
 
 		contract := SwapContract{
 			Pair:            pair,
@@ -1210,9 +1881,13 @@ func (swap *Swap) updateContracts() ([]byte, error) {
 			ContractName:    stdContractName,
 			SettleMode:      settleMode,
 			UnitAmount:      1,
-			TickSize:        tickSize,
+			TickSize:        filters.TickSize,
 			PricePrecision:  c.PricePrecision,
 			AmountPrecision: c.QuantityPrecision,
+			LotSize:         filters.LotSize,
+			MinAmount:       filters.MinAmount,
+			MinNotional:     filters.MinNotional,
+			MaxMarketAmount: filters.MaxMarketAmount,
 		}
 
 		swapContracts.ContractNameKV[stdContractName] = &contract
@@ -1233,18 +1908,7 @@ func (swap *Swap) updateContracts() ([]byte, error) {
 			settleMode = SETTLE_MODE_COUNTER
 		}
 
-		var tickSize = float64(-1)
-		for _, f := range c.Filters {
-			var ft, isFt = f["filterType"]
-			if !isFt || ft != "PRICE_FILTER" {
-				continue
-			}
-			var ts, isTs = f["tickSize"]
-			if isTs {
-				tickSize = ToFloat64(ts)
-				break
-			}
-		}
+		var filters = parseFilters(c.Filters)
 
 		contract := SwapContract{
 			Pair:            pair,
@@ -1253,9 +1917,13 @@ func (swap *Swap) updateContracts() ([]byte, error) {
 			ContractName:    stdContractName,
 			SettleMode:      settleMode,
 			UnitAmount:      c.ContractSize,
-			TickSize:        tickSize,
+			TickSize:        filters.TickSize,
 			PricePrecision:  c.PricePrecision,
 			AmountPrecision: c.QuantityPrecision,
+			LotSize:         filters.LotSize,
+			MinAmount:       filters.MinAmount,
+			MinNotional:     filters.MinNotional,
+			MaxMarketAmount: filters.MaxMarketAmount,
 		}
 		swapContracts.ContractNameKV[stdContractName] = &contract
 	}
@@ -1275,17 +1943,27 @@ func (swap *Swap) updateContracts() ([]byte, error) {
 	return respCounter, nil
 }
 
-// not support the binance both mode.
-func (swap *Swap) getFutureType(side, sidePosition string) FutureType {
-	if side == "BUY" && sidePosition == "LONG" {
-		return OPEN_LONG
-	} else if side == "SELL" && sidePosition == "SHORT" {
-		return OPEN_SHORT
-	} else if side == "SELL" && sidePosition == "LONG" {
-		return LIQUIDATE_LONG
-	} else if side == "BUY" && sidePosition == "SHORT" {
-		return LIQUIDATE_SHORT
-	} else {
-		panic("input error, do not use BOTH. ")
+// getFutureType return the future type of the binance order, the order in the BOTH position side
+// (the one-way mode) is the liquidate type when it only reduce the position.
+func getFutureType(side, positionSide string, reduceOnly bool) (FutureType, error) {
+	switch {
+	case side == "BUY" && positionSide == "LONG":
+		return OPEN_LONG, nil
+	case side == "SELL" && positionSide == "SHORT":
+		return OPEN_SHORT, nil
+	case side == "SELL" && positionSide == "LONG":
+		return LIQUIDATE_LONG, nil
+	case side == "BUY" && positionSide == "SHORT":
+		return LIQUIDATE_SHORT, nil
+	case side == "BUY" && positionSide == "BOTH" && reduceOnly:
+		return LIQUIDATE_SHORT, nil
+	case side == "SELL" && positionSide == "BOTH" && reduceOnly:
+		return LIQUIDATE_LONG, nil
+	case side == "BUY" && positionSide == "BOTH":
+		return OPEN_LONG, nil
+	case side == "SELL" && positionSide == "BOTH":
+		return OPEN_SHORT, nil
+	default:
+		return 0, fmt.Errorf("unknown order side %s in the position side %s. ", side, positionSide)
 	}
 }
@@ -2,6 +2,7 @@ package binance
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -107,10 +108,6 @@ func (swap *Swap) GetAccount() (*SwapAccount, []byte, error) {
 	}
 
 	for _, p := range response.Positions {
-		// do not support binance, the one side trade.
-		if p.PositionSide == "BOTH" {
-			continue
-		}
 		// There don't have position.
 		if p.InitialMargin == 0.0 {
 			continue
@@ -121,7 +118,8 @@ func (swap *Swap) GetAccount() (*SwapAccount, []byte, error) {
 			Counter: NewCurrency(p.Symbol[len(p.Symbol)-4:len(p.Symbol)], ""),
 		}
 		futureType := OPEN_LONG
-		if p.PositionSide == "SHORT" {
+		// the one-way mode position is in the BOTH side, it is short when the amount is negative.
+		if p.PositionSide == "SHORT" || (p.PositionSide == "BOTH" && p.PositionAmt < 0) {
 			futureType = OPEN_SHORT
 		}
 
@@ -356,3 +354,208 @@ func (swap *Swap) GetPairFlow(pair Pair) ([]*SwapAccountItem, []byte, error) {
 
 	return items, resp, nil
 }
+
+// the leverage api is the same in the usdt margined and the coin margined endpoints, the delivery future use it too.
+var leverageApiRelation = map[int64]string{
+	SETTLE_MODE_COUNTER: "/fapi",
+	SETTLE_MODE_BASIS:   "/dapi",
+}
+
+var marginTypeRelation = map[string]string{
+	CROSS:    "CROSSED",
+	ISOLATED: "ISOLATED",
+}
+
+func (swap *Swap) contractSymbol(pair Pair) (string, int64) {
+	var contract = swap.GetContract(pair)
+	if contract.SettleMode == SETTLE_MODE_BASIS {
+		return pair.ToSymbol("", true) + "_PERP", contract.SettleMode
+	}
+	return pair.ToSymbol("", true), contract.SettleMode
+}
+
+// SetLeverage set the leverage of the symbol, binance has one leverage for both sides.
+func (swap *Swap) SetLeverage(pair Pair, lever int64, side PositionSide) ([]byte, error) {
+	var symbol, settleMode = swap.contractSymbol(pair)
+	return swap.setLeverage(symbol, settleMode, lever)
+}
+
+func (swap *Swap) GetLeverage(pair Pair, side PositionSide) (int64, []byte, error) {
+	var symbol, settleMode = swap.contractSymbol(pair)
+	return swap.getLeverage(symbol, settleMode)
+}
+
+func (swap *Swap) SetMarginMode(pair Pair, marginType string) ([]byte, error) {
+	var symbol, settleMode = swap.contractSymbol(pair)
+	return swap.setMarginMode(symbol, settleMode, marginType)
+}
+
+func (swap *Swap) GetLeverageBrackets(pair Pair) ([]*LeverageBracket, []byte, error) {
+	var symbol, settleMode = swap.contractSymbol(pair)
+	return swap.getLeverageBrackets(pair, symbol, settleMode)
+}
+
+func (swap *Swap) setLeverage(symbol string, settleMode int64, lever int64) ([]byte, error) {
+	if lever <= 0 {
+		return nil, errors.New("the leverage must be positive. ")
+	}
+	var param = url.Values{}
+	param.Set("symbol", symbol)
+	param.Set("leverage", fmt.Sprintf("%d", lever))
+	if err := swap.buildParamsSigned(&param); err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		Leverage int64  `json:"leverage"`
+		Symbol   string `json:"symbol"`
+	}
+	return swap.DoRequest(
+		http.MethodPost,
+		leverageApiRelation[settleMode]+"/v1/leverage?"+param.Encode(),
+		"",
+		&response,
+		settleMode,
+	)
+}
+
+// getLeverage read the leverage in the position risk, the coin margined endpoint query it by the pair.
+func (swap *Swap) getLeverage(symbol string, settleMode int64) (int64, []byte, error) {
+	var param = url.Values{}
+	var uri = "/fapi/v2/positionRisk?"
+	if settleMode == SETTLE_MODE_BASIS {
+		param.Set("pair", strings.Split(symbol, "_")[0])
+		uri = "/dapi/v1/positionRisk?"
+	} else {
+		param.Set("symbol", symbol)
+	}
+	if err := swap.buildParamsSigned(&param); err != nil {
+		return 0, nil, err
+	}
+
+	var response = make([]struct {
+		Symbol   string `json:"symbol"`
+		Leverage int64  `json:"leverage,string"`
+	}, 0)
+	resp, err := swap.DoRequest(
+		http.MethodGet,
+		uri+param.Encode(),
+		"",
+		&response,
+		settleMode,
+	)
+	if err != nil {
+		return 0, resp, err
+	}
+	for _, p := range response {
+		if p.Symbol == symbol {
+			return p.Leverage, resp, nil
+		}
+	}
+	return 0, resp, fmt.Errorf("the leverage of %s is not found. ", symbol)
+}
+
+func (swap *Swap) setMarginMode(symbol string, settleMode int64, marginType string) ([]byte, error) {
+	var bnMarginType, exist = marginTypeRelation[marginType]
+	if !exist {
+		return nil, fmt.Errorf("the margin type %s is not supported. ", marginType)
+	}
+	var param = url.Values{}
+	param.Set("symbol", symbol)
+	param.Set("marginType", bnMarginType)
+	if err := swap.buildParamsSigned(&param); err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		Code int64  `json:"code"`
+		Msg  string `json:"msg"`
+	}
+	resp, err := swap.DoRequest(
+		http.MethodPost,
+		leverageApiRelation[settleMode]+"/v1/marginType?"+param.Encode(),
+		"",
+		&response,
+		settleMode,
+	)
+	// -4046 means the margin type is the same, no need to change.
+	if err != nil && strings.Contains(err.Error(), "-4046") {
+		return resp, nil
+	}
+	return resp, err
+}
+
+func (swap *Swap) getLeverageBrackets(pair Pair, symbol string, settleMode int64) ([]*LeverageBracket, []byte, error) {
+	var param = url.Values{}
+	param.Set("symbol", symbol)
+	if err := swap.buildParamsSigned(&param); err != nil {
+		return nil, nil, err
+	}
+	var uri = "/fapi/v1/leverageBracket?"
+	if settleMode == SETTLE_MODE_BASIS {
+		uri = "/dapi/v2/leverageBracket?"
+	}
+
+	var raw json.RawMessage
+	resp, err := swap.DoRequest(
+		http.MethodGet,
+		uri+param.Encode(),
+		"",
+		&raw,
+		settleMode,
+	)
+	if err != nil {
+		return nil, resp, err
+	}
+	brackets, err := parseLeverageBrackets(pair, symbol, resp)
+	return brackets, resp, err
+}
+
+// parseLeverageBrackets parse the brackets of the symbol, the usdt margined endpoint return an object when
+// the symbol is set, the coin margined endpoint return the array with the qty bounds.
+func parseLeverageBrackets(pair Pair, symbol string, resp []byte) ([]*LeverageBracket, error) {
+	var response = make([]struct {
+		Symbol   string `json:"symbol"`
+		Brackets []struct {
+			Bracket          int64   `json:"bracket"`
+			InitialLeverage  float64 `json:"initialLeverage"`
+			NotionalCap      float64 `json:"notionalCap"`
+			NotionalFloor    float64 `json:"notionalFloor"`
+			QtyCap           float64 `json:"qtyCap"`
+			QtyFloor         float64 `json:"qtyFloor"`
+			MaintMarginRatio float64 `json:"maintMarginRatio"`
+		} `json:"brackets"`
+	}, 0)
+	var data = strings.TrimSpace(string(resp))
+	if strings.HasPrefix(data, "{") {
+		data = "[" + data + "]"
+	}
+	if err := json.Unmarshal([]byte(data), &response); err != nil {
+		return nil, err
+	}
+
+	var brackets = make([]*LeverageBracket, 0)
+	for _, item := range response {
+		if item.Symbol != symbol {
+			continue
+		}
+		for _, b := range item.Brackets {
+			var bracket = &LeverageBracket{
+				Pair:             pair,
+				Tier:             b.Bracket,
+				Floor:            b.NotionalFloor,
+				Cap:              b.NotionalCap,
+				MaxLeverage:      b.InitialLeverage,
+				MaintMarginRatio: b.MaintMarginRatio,
+			}
+			if b.QtyCap > 0 {
+				bracket.Floor, bracket.Cap = b.QtyFloor, b.QtyCap
+			}
+			brackets = append(brackets, bracket)
+		}
+	}
+	if len(brackets) == 0 {
+		return nil, fmt.Errorf("the leverage brackets of %s are not found. ", symbol)
+	}
+	return brackets, nil
+}
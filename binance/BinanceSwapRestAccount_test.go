@@ -98,3 +98,31 @@ func TestSwap_Pair_Flow_Basis(t *testing.T) {
 		}
 	}
 }
+
+// go test -v ./binance/... -count=1 -run=TestSwap_LeverageBrackets
+func TestSwap_LeverageBrackets(t *testing.T) {
+	var pair = Pair{Basis: BTC, Counter: USDT}
+	var counter = `{"symbol":"BTCUSDT","brackets":[
+		{"bracket":1,"initialLeverage":125,"notionalCap":50000,"notionalFloor":0,"maintMarginRatio":0.004},
+		{"bracket":2,"initialLeverage":100,"notionalCap":250000,"notionalFloor":50000,"maintMarginRatio":0.005}]}`
+	brackets, err := parseLeverageBrackets(pair, "BTCUSDT", []byte(counter))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(brackets) != 2 || brackets[1].Floor != 50000 || brackets[1].MaxLeverage != 100 {
+		t.Error("the counter brackets are wrong: ", brackets)
+		return
+	}
+
+	var basis = `[{"symbol":"BTCUSD_PERP","brackets":[
+		{"bracket":1,"initialLeverage":125,"qtyCap":5,"qtyFloor":0,"maintMarginRatio":0.004}]}]`
+	brackets, err = parseLeverageBrackets(pair, "BTCUSD_PERP", []byte(basis))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(brackets) != 1 || brackets[0].Cap != 5 {
+		t.Error("the basis brackets are wrong: ", brackets)
+	}
+}
@@ -0,0 +1,320 @@
+package binance
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"time"
+
+	. "github.com/deforceHK/goghostex"
+)
+
+const (
+	SWAP_STAT_LIMIT = 500
+	SWAP_STAT_URI   = "/futures/data/"
+)
+
+var statPeriodRelation = map[int]string{
+	KLINE_PERIOD_5MIN:  "5m",
+	KLINE_PERIOD_15MIN: "15m",
+	KLINE_PERIOD_30MIN: "30m",
+	KLINE_PERIOD_60MIN: "1h",
+	KLINE_PERIOD_1H:    "1h",
+	KLINE_PERIOD_2H:    "2h",
+	KLINE_PERIOD_4H:    "4h",
+	KLINE_PERIOD_6H:    "6h",
+	KLINE_PERIOD_12H:   "12h",
+	KLINE_PERIOD_1DAY:  "1d",
+}
+
+var longShortRatioRelation = map[LongShortType]string{
+	LONG_SHORT_TOP_ACCOUNT:    "topLongShortAccountRatio",
+	LONG_SHORT_TOP_POSITION:   "topLongShortPositionRatio",
+	LONG_SHORT_GLOBAL_ACCOUNT: "globalLongShortAccountRatio",
+}
+
+// statParams build the params of the futures data api, the um use the symbol, the cm use the pair and the contract type.
+func (swap *Swap) statParams(pair Pair, period, size, since int, withContractType bool) (url.Values, int64, error) {
+	var interval, exist = statPeriodRelation[period]
+	if !exist {
+		return nil, 0, fmt.Errorf("the period %d is not supported in the futures data. ", period)
+	}
+	if size <= 0 || size > SWAP_STAT_LIMIT {
+		size = SWAP_STAT_LIMIT
+	}
+
+	var contract = swap.GetContract(pair)
+	var params = url.Values{}
+	if contract.SettleMode == SETTLE_MODE_BASIS {
+		params.Set("pair", pair.ToSymbol("", true))
+		if withContractType {
+			params.Set("contractType", "PERPETUAL")
+		}
+	} else {
+		params.Set("symbol", pair.ToSymbol("", true))
+	}
+	params.Set("period", interval)
+	params.Set("limit", fmt.Sprintf("%d", size))
+	if since > 0 {
+		var endTimestamp = since + size*_INERNAL_KLINE_SECOND_CONVERTER[period]
+		if now := int(time.Now().UnixNano() / int64(time.Millisecond)); endTimestamp > now {
+			endTimestamp = now
+		}
+		params.Set("startTime", fmt.Sprintf("%d", since))
+		params.Set("endTime", fmt.Sprintf("%d", endTimestamp))
+	}
+	return params, contract.SettleMode, nil
+}
+
+// GetOpenInterest return the current open interest, the cm open interest is in contracts, the Amount is by the last price.
+func (swap *Swap) GetOpenInterest(pair Pair) (*OpenInterest, []byte, error) {
+	var contract = swap.GetContract(pair)
+	var symbol, settleMode = swap.contractSymbol(pair)
+	var params = url.Values{}
+	params.Set("symbol", symbol)
+
+	var response = struct {
+		OpenInterest float64 `json:"openInterest,string"`
+		Time         int64   `json:"time"`
+	}{}
+	resp, err := swap.DoRequest(
+		http.MethodGet,
+		leverageApiRelation[settleMode]+"/v1/openInterest?"+params.Encode(),
+		"",
+		&response,
+		settleMode,
+	)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	var openInterest = &OpenInterest{
+		Pair:      pair,
+		Exchange:  BINANCE,
+		Amount:    response.OpenInterest,
+		Timestamp: response.Time,
+	}
+	if settleMode == SETTLE_MODE_BASIS {
+		ticker, _, err := swap.GetTicker(pair)
+		if err != nil {
+			return nil, resp, err
+		}
+		if ticker.Last <= 0 {
+			return nil, resp, errors.New("the last price is wrong. ")
+		}
+		openInterest.Value = response.OpenInterest * contract.UnitAmount
+		openInterest.Amount = openInterest.Value / ticker.Last
+	}
+	return openInterest, resp, nil
+}
+
+func (swap *Swap) GetOpenInterestHistory(pair Pair, period, size, since int) ([]*OpenInterest, []byte, error) {
+	var params, settleMode, err = swap.statParams(pair, period, size, since, true)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// the um sumOpenInterest is in the basis currency, the cm sumOpenInterest is in contracts.
+	var response = make([]struct {
+		SumOpenInterest      float64 `json:"sumOpenInterest,string"`
+		SumOpenInterestValue float64 `json:"sumOpenInterestValue,string"`
+		Timestamp            int64   `json:"timestamp"`
+	}, 0)
+	resp, err := swap.DoRequest(
+		http.MethodGet,
+		SWAP_STAT_URI+"openInterestHist?"+params.Encode(),
+		"",
+		&response,
+		settleMode,
+	)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	var contract = swap.GetContract(pair)
+	var openInterests = make([]*OpenInterest, 0, len(response))
+	for _, item := range response {
+		var openInterest = &OpenInterest{
+			Pair:      pair,
+			Exchange:  BINANCE,
+			Amount:    item.SumOpenInterest,
+			Value:     item.SumOpenInterestValue,
+			Timestamp: item.Timestamp,
+		}
+		if settleMode == SETTLE_MODE_BASIS {
+			openInterest.Amount = item.SumOpenInterestValue
+			openInterest.Value = item.SumOpenInterest * contract.UnitAmount
+		}
+		openInterests = append(openInterests, openInterest)
+	}
+	sort.Slice(openInterests, func(i, j int) bool {
+		return openInterests[i].Timestamp < openInterests[j].Timestamp
+	})
+	return openInterests, resp, nil
+}
+
+func (swap *Swap) GetLongShortRatio(
+	pair Pair,
+	ratioType LongShortType,
+	period, size, since int,
+) ([]*LongShortRatio, []byte, error) {
+	var api, exist = longShortRatioRelation[ratioType]
+	if !exist {
+		return nil, nil, fmt.Errorf("the long short type %d is not supported. ", ratioType)
+	}
+	var params, settleMode, err = swap.statParams(pair, period, size, since, false)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// the cm top position ratio use the longPosition and the shortPosition.
+	var response = make([]struct {
+		LongShortRatio float64 `json:"longShortRatio,string"`
+		LongAccount    float64 `json:"longAccount,string"`
+		ShortAccount   float64 `json:"shortAccount,string"`
+		LongPosition   float64 `json:"longPosition,string"`
+		ShortPosition  float64 `json:"shortPosition,string"`
+		Timestamp      int64   `json:"timestamp"`
+	}, 0)
+	resp, err := swap.DoRequest(
+		http.MethodGet,
+		SWAP_STAT_URI+api+"?"+params.Encode(),
+		"",
+		&response,
+		settleMode,
+	)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	var ratios = make([]*LongShortRatio, 0, len(response))
+	for _, item := range response {
+		var ratio = &LongShortRatio{
+			Pair:      pair,
+			Exchange:  BINANCE,
+			Type:      ratioType,
+			Ratio:     item.LongShortRatio,
+			Long:      item.LongAccount,
+			Short:     item.ShortAccount,
+			Timestamp: item.Timestamp,
+		}
+		if item.LongPosition > 0 || item.ShortPosition > 0 {
+			ratio.Long, ratio.Short = item.LongPosition, item.ShortPosition
+		}
+		ratios = append(ratios, ratio)
+	}
+	sort.Slice(ratios, func(i, j int) bool {
+		return ratios[i].Timestamp < ratios[j].Timestamp
+	})
+	return ratios, resp, nil
+}
+
+func (swap *Swap) GetTakerVolume(pair Pair, period, size, since int) ([]*TakerVolume, []byte, error) {
+	var params, settleMode, err = swap.statParams(pair, period, size, since, true)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// the um takerlongshortRatio is in the basis currency, the cm takerBuySellVol value is in the basis currency.
+	var api = "takerlongshortRatio"
+	if settleMode == SETTLE_MODE_BASIS {
+		api = "takerBuySellVol"
+	}
+	var response = make([]struct {
+		BuyVol            float64 `json:"buyVol,string"`
+		SellVol           float64 `json:"sellVol,string"`
+		TakerBuyVolValue  float64 `json:"takerBuyVolValue,string"`
+		TakerSellVolValue float64 `json:"takerSellVolValue,string"`
+		Timestamp         int64   `json:"timestamp"`
+	}, 0)
+	resp, err := swap.DoRequest(
+		http.MethodGet,
+		SWAP_STAT_URI+api+"?"+params.Encode(),
+		"",
+		&response,
+		settleMode,
+	)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	var volumes = make([]*TakerVolume, 0, len(response))
+	for _, item := range response {
+		var volume = &TakerVolume{
+			Pair:       pair,
+			Exchange:   BINANCE,
+			BuyVolume:  item.BuyVol,
+			SellVolume: item.SellVol,
+			Timestamp:  item.Timestamp,
+		}
+		if settleMode == SETTLE_MODE_BASIS {
+			volume.BuyVolume, volume.SellVolume = item.TakerBuyVolValue, item.TakerSellVolValue
+		}
+		volumes = append(volumes, volume)
+	}
+	sort.Slice(volumes, func(i, j int) bool {
+		return volumes[i].Timestamp < volumes[j].Timestamp
+	})
+	return volumes, resp, nil
+}
+
+// GetLiquidations return the force orders of the account, binance stopped the public liquidation api.
+// The public liquidations are in the forceOrder stream of the Liquidations.
+func (swap *Swap) GetLiquidations(pair Pair, size int) ([]*Liquidation, []byte, error) {
+	if size <= 0 || size > 100 {
+		size = 100
+	}
+	var symbol, settleMode = swap.contractSymbol(pair)
+	var params = url.Values{}
+	params.Set("symbol", symbol)
+	params.Set("limit", fmt.Sprintf("%d", size))
+	if err := swap.buildParamsSigned(&params); err != nil {
+		return nil, nil, err
+	}
+
+	// the um executedQty is in the basis currency, the cm cumBase is in the basis currency.
+	var response = make([]struct {
+		Symbol      string  `json:"symbol"`
+		Side        string  `json:"side"`
+		AvgPrice    float64 `json:"avgPrice,string"`
+		ExecutedQty float64 `json:"executedQty,string"`
+		CumBase     float64 `json:"cumBase,string"`
+		Time        int64   `json:"time"`
+	}, 0)
+	resp, err := swap.DoRequest(
+		http.MethodGet,
+		leverageApiRelation[settleMode]+"/v1/forceOrders?"+params.Encode(),
+		"",
+		&response,
+		settleMode,
+	)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	var liquidations = make([]*Liquidation, 0, len(response))
+	for _, item := range response {
+		var liquidation = &Liquidation{
+			Pair:      pair,
+			Exchange:  BINANCE,
+			Symbol:    item.Symbol,
+			Side:      SELL,
+			Price:     item.AvgPrice,
+			Amount:    item.ExecutedQty,
+			Timestamp: item.Time,
+		}
+		if item.Side == "BUY" {
+			liquidation.Side = BUY
+		}
+		if settleMode == SETTLE_MODE_BASIS {
+			liquidation.Amount = item.CumBase
+		}
+		liquidations = append(liquidations, liquidation)
+	}
+	sort.Slice(liquidations, func(i, j int) bool {
+		return liquidations[i].Timestamp < liquidations[j].Timestamp
+	})
+	return liquidations, resp, nil
+}
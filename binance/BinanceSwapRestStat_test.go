@@ -0,0 +1,63 @@
+package binance
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	. "github.com/deforceHK/goghostex"
+)
+
+// go test -v ./binance/... -count=1 -run=TestSwap_StatAPI
+func TestSwap_StatAPI(t *testing.T) {
+
+	var config = &APIConfig{
+		Endpoint: ENDPOINT,
+		HttpClient: &http.Client{
+			Transport: &http.Transport{
+				Proxy: func(req *http.Request) (*url.URL, error) {
+					return url.Parse(PROXY_URL)
+				},
+			},
+		},
+		Location: time.Now().Location(),
+	}
+
+	var bn = New(config)
+	var pair = Pair{Basis: BTC, Counter: USDT}
+	var since = int(time.Now().Add(-24*time.Hour).UnixNano() / int64(time.Millisecond))
+
+	if openInterest, _, err := bn.Swap.GetOpenInterest(pair); err != nil {
+		t.Error(err)
+		return
+	} else {
+		t.Log(*openInterest)
+	}
+
+	if openInterests, _, err := bn.Swap.GetOpenInterestHistory(pair, KLINE_PERIOD_1H, 24, since); err != nil {
+		t.Error(err)
+		return
+	} else if len(openInterests) > 1 && openInterests[0].Timestamp > openInterests[1].Timestamp {
+		t.Error("the open interests are not sorted. ")
+		return
+	}
+
+	for _, ratioType := range []LongShortType{
+		LONG_SHORT_TOP_ACCOUNT, LONG_SHORT_TOP_POSITION, LONG_SHORT_GLOBAL_ACCOUNT,
+	} {
+		if ratios, _, err := bn.Swap.GetLongShortRatio(pair, ratioType, KLINE_PERIOD_1H, 24, since); err != nil {
+			t.Error(err)
+			return
+		} else if len(ratios) > 0 {
+			t.Log(ratioType, *ratios[len(ratios)-1])
+		}
+	}
+
+	if volumes, _, err := bn.Swap.GetTakerVolume(pair, KLINE_PERIOD_1H, 24, since); err != nil {
+		t.Error(err)
+		return
+	} else if len(volumes) > 0 {
+		t.Log(*volumes[len(volumes)-1])
+	}
+}
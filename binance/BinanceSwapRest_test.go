@@ -822,3 +822,113 @@ func TestSwap_TradeAPI_COUNTER_MARKET(t *testing.T) {
 		t.Log(string(stdOrder))
 	}
 }
+
+// go test -v ./binance/... -count=1 -run=TestSwap_ConditionParams
+func TestSwap_ConditionParams(t *testing.T) {
+	var swap = &Swap{}
+	var normalizer = &Normalizer{TickSize: 0.1, PricePrecision: 1}
+
+	var param = url.Values{}
+	param.Set("price", "100.0")
+	param.Set("timeInForce", "GTC")
+	var stop = &SwapOrder{ConditionType: CONDITION_STOP, TriggerPrice: 99.04, TriggerType: TRIGGER_MARK}
+	if err := swap.setConditionParams(&param, stop, normalizer, false); err != nil {
+		t.Error(err)
+		return
+	}
+	if param.Get("type") != "STOP" || param.Get("stopPrice") != "99" || param.Get("workingType") != "MARK_PRICE" {
+		t.Error("the stop limit params are wrong: ", param.Encode())
+		return
+	}
+
+	var trailing = &SwapOrder{ConditionType: CONDITION_TRAILING_STOP, CallbackRate: 0.01}
+	if err := swap.setConditionParams(&param, trailing, normalizer, false); err != nil {
+		t.Error(err)
+		return
+	}
+	if param.Get("type") != "TRAILING_STOP_MARKET" || param.Get("callbackRate") != "1" || param.Has("price") {
+		t.Error("the trailing stop params are wrong: ", param.Encode())
+		return
+	}
+
+	var index = &SwapOrder{ConditionType: CONDITION_STOP, TriggerPrice: 99, TriggerType: TRIGGER_INDEX}
+	if err := swap.setConditionParams(&url.Values{}, index, normalizer, true); err == nil {
+		t.Error("the index trigger should not be supported")
+	}
+}
+
+// go test -v ./binance/... -count=1 -run=TestSwap_OneWayOrders
+func TestSwap_OneWayOrders(t *testing.T) {
+	var swap = &Swap{Binance: &Binance{config: &APIConfig{Location: time.UTC}}}
+	var raw = []byte(`[
+		{"orderId":1,"clientOrderId":"c1","price":"100","origQty":"2","executedQty":"0","avgPrice":"0",
+		"status":"NEW","side":"SELL","positionSide":"BOTH","reduceOnly":true,"closePosition":false,
+		"time":1700000000000,"updateTime":1700000000000},
+		{"orderId":2,"clientOrderId":"c2","price":"99","origQty":"1","executedQty":"1","avgPrice":"99",
+		"status":"FILLED","side":"BUY","positionSide":"BOTH","reduceOnly":false,"closePosition":false,
+		"time":1700000000000,"updateTime":1700000001000}
+	]`)
+	var rawOrders = make([]*swapRawOrder, 0)
+	if err := json.Unmarshal(raw, &rawOrders); err != nil {
+		t.Error(err)
+		return
+	}
+
+	var expects = []struct {
+		futureType FutureType
+		reduceOnly bool
+	}{
+		{LIQUIDATE_LONG, true},
+		{OPEN_LONG, false},
+	}
+	for i, rawOrder := range rawOrders {
+		var order, err = swap.toSwapOrder(BTC_USDT, rawOrder)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if order.Type != expects[i].futureType || order.ReduceOnly != expects[i].reduceOnly ||
+			order.PositionSide != POSITION_SIDE_BOTH {
+			t.Error("the one-way order is wrong: ", i, order.Type, order.ReduceOnly, order.PositionSide)
+			return
+		}
+	}
+
+	if _, err := getFutureType("BUY", "UNKNOWN", false); err == nil {
+		t.Error("the unknown position side should be the error")
+	}
+}
+
+// go test -v ./binance/... -count=1 -run=TestSwap_PriceKline
+func TestSwap_PriceKline(t *testing.T) {
+	var config = &APIConfig{
+		Endpoint: ENDPOINT,
+		HttpClient: &http.Client{
+			Transport: &http.Transport{
+				Proxy: func(req *http.Request) (*url.URL, error) {
+					return url.Parse(SWAP_PROXY_URL)
+				},
+			},
+		},
+		Location: time.Now().Location(),
+	}
+
+	var bn = New(config)
+	var since = int(time.Now().Add(-24*time.Hour).UnixNano() / int64(time.Millisecond))
+	for _, pair := range []Pair{{Basis: BTC, Counter: USDT}, {Basis: BTC, Counter: USD}} {
+		for _, getKline := range []func(Pair, int, int, int) ([]*SwapKline, []byte, error){
+			bn.Swap.GetMarkKline, bn.Swap.GetIndexKline, bn.Swap.GetPremiumKline,
+		} {
+			klines, _, err := getKline(pair, KLINE_PERIOD_1H, 24, since)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if len(klines) == 0 || klines[0].Timestamp < int64(since) {
+				t.Error("the price klines are wrong. ")
+				return
+			}
+			t.Log(pair, *klines[len(klines)-1])
+		}
+	}
+}
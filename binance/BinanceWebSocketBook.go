@@ -3,7 +3,6 @@ package binance
 import (
 	"encoding/json"
 	"fmt"
-	"log"
 	"net/http"
 	"net/url"
 	"sort"
@@ -88,7 +87,7 @@ func (this *LocalOrderBooks) ReceiveDelta(msg string) {
 
 	_ = json.Unmarshal([]byte(msg), &delta)
 	if delta.Stream == "" {
-		log.Println(msg)
+		this.logger().Debug("receive message", LogF("msg", msg))
 		return
 	}
 
@@ -143,6 +142,7 @@ func (this *LocalOrderBooks) ReceiveDelta(msg string) {
 
 	if !withCache && delta.Data.PrevSeq != this.SeqData[productId] {
 		// 有丢包现象，需要重新申请snapshot
+		this.Config.GetMetrics().IncBookResync(BINANCE, productId)
 		go this.getSnapshot(productId, 0)
 	} else {
 		for _, bid := range delta.Data.Bids {
@@ -240,6 +240,7 @@ func (this *LocalOrderBooks) SnapshotById(productId string) (*Depth, error) {
 	defer mux.Unlock()
 	var pair = this.getPairByProductId(productId)
 	var lastTime = time.UnixMilli(this.TsData[productId]).In(this.WSMarketUMBN.Config.Location)
+	this.Config.GetMetrics().SetBookStaleness(BINANCE, productId, time.Since(lastTime))
 	var depth = &Depth{
 		Pair:      pair,
 		Sequence:  this.SeqData[productId],
@@ -2,7 +2,6 @@ package binance
 
 import (
 	"fmt"
-	"log"
 	"net/url"
 	"strings"
 	"time"
@@ -16,6 +15,7 @@ type WSTradeUMBN struct {
 	RecvHandler  func(string)
 	ErrorHandler func(error)
 	Config       *APIConfig
+	DialerConfig *WsDialerConfig // the proxy, tls and local address setting of the connection, not necessary
 
 	conn   *websocket.Conn
 	connId string
@@ -102,7 +102,7 @@ func (this *WSTradeUMBN) recvRoutine() {
 		if readErr != nil {
 			// conn closed by user.
 			if strings.Index(readErr.Error(), "use of closed network connection") > 0 {
-				fmt.Println("conn closed by user. ")
+				this.logger().Info("conn closed by user")
 				return
 			}
 			this.ErrorHandler(readErr)
@@ -115,6 +115,7 @@ func (this *WSTradeUMBN) recvRoutine() {
 		}
 
 		this.lastPingTS = time.Now().Unix()
+		this.Config.GetMetrics().IncWSMessage(BINANCE, "WSTradeUMBN")
 		this.RecvHandler(string(msg))
 	}
 
@@ -137,6 +138,7 @@ func (this *WSTradeUMBN) Stop() {
 }
 
 func (this *WSTradeUMBN) Restart() {
+	this.Config.GetMetrics().IncWSReconnect(BINANCE, "WSTradeUMBN")
 	this.restartTS[time.Now().Unix()] = this.connId
 	this.Stop()
 	this.ErrorHandler(
@@ -168,12 +170,12 @@ func (this *WSTradeUMBN) Restart() {
 func (this *WSTradeUMBN) initDefaultValue() {
 	if this.RecvHandler == nil {
 		this.RecvHandler = func(msg string) {
-			log.Println(msg)
+			this.logger().Debug("receive message", LogF("msg", msg))
 		}
 	}
 	if this.ErrorHandler == nil {
 		this.ErrorHandler = func(err error) {
-			log.Println(err)
+			this.logger().Error("websocket error", LogF(LOG_FIELD_ERROR, err))
 		}
 	}
 	if this.restartSec == 0 {
@@ -201,7 +203,7 @@ func (this *WSTradeUMBN) startCheck() error {
 			restartNum++
 		}
 	}
-	fmt.Println("Restarted time: ", restartNum, "Restart limit time: ", this.restartLimitNum)
+	this.logger().Info("restart check", LogF("restart_num", restartNum), LogF("restart_limit", this.restartLimitNum))
 	if restartNum > this.restartLimitNum {
 		var wsErr = &WSStopError{
 			Msg: fmt.Sprintf(
@@ -209,6 +211,7 @@ func (this *WSTradeUMBN) startCheck() error {
 				restartNum, this.restartLimitSec,
 			),
 		}
+		this.Config.GetMetrics().IncWSRestartLimit(BINANCE, "WSTradeUMBN")
 		return wsErr
 	}
 	return nil
@@ -216,7 +219,11 @@ func (this *WSTradeUMBN) startCheck() error {
 
 func (this *WSTradeUMBN) noLoginConn(wss string) (*websocket.Conn, error) {
 	this.initDefaultValue()
-	var conn, _, err = websocket.DefaultDialer.Dial(
+	var dialer, dialErr = NewWsDialer(this.DialerConfig)
+	if dialErr != nil {
+		return nil, dialErr
+	}
+	var conn, _, err = dialer.Dial(
 		wss,
 		nil,
 	)
@@ -226,3 +233,10 @@ func (this *WSTradeUMBN) noLoginConn(wss string) (*websocket.Conn, error) {
 	this.connId = UUID()
 	return conn, nil
 }
+
+func (this *WSTradeUMBN) logger() Logger {
+	return this.Config.GetLogger().With(
+		LogF(LOG_FIELD_EXCHANGE, BINANCE),
+		LogF(LOG_FIELD_CONN_ID, this.connId),
+	)
+}
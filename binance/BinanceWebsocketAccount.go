@@ -2,7 +2,6 @@ package binance
 
 import (
 	"fmt"
-	"log"
 	"net/http"
 	"strings"
 	"time"
@@ -24,6 +23,7 @@ type WSAccountUMBN struct {
 	RecvHandler  func(string)
 	ErrorHandler func(error)
 	Config       *APIConfig
+	DialerConfig *WsDialerConfig // the proxy, tls and local address setting of the connection, not necessary
 
 	conn   *websocket.Conn
 	connId string
@@ -179,7 +179,7 @@ func (this *WSAccountUMBN) recvRoutine() {
 		if readErr != nil {
 			// conn closed by user.
 			if strings.Index(readErr.Error(), "use of closed network connection") > 0 {
-				fmt.Println("conn closed by user. ")
+				this.logger().Info("conn closed by user")
 				return
 			}
 			this.ErrorHandler(readErr)
@@ -192,6 +192,7 @@ func (this *WSAccountUMBN) recvRoutine() {
 		}
 
 		this.lastPingTS = time.Now().Unix()
+		this.Config.GetMetrics().IncWSMessage(BINANCE, "WSAccountUMBN")
 		this.RecvHandler(string(msg))
 	}
 
@@ -214,6 +215,7 @@ func (this *WSAccountUMBN) Stop() {
 }
 
 func (this *WSAccountUMBN) Restart() {
+	this.Config.GetMetrics().IncWSReconnect(BINANCE, "WSAccountUMBN")
 	this.restartTS[time.Now().Unix()] = this.connId
 	this.Stop()
 	this.ErrorHandler(
@@ -245,12 +247,12 @@ func (this *WSAccountUMBN) Restart() {
 func (this *WSAccountUMBN) initDefaultValue() {
 	if this.RecvHandler == nil {
 		this.RecvHandler = func(msg string) {
-			log.Println(msg)
+			this.logger().Debug("receive message", LogF("msg", msg))
 		}
 	}
 	if this.ErrorHandler == nil {
 		this.ErrorHandler = func(err error) {
-			log.Println(err)
+			this.logger().Error("websocket error", LogF(LOG_FIELD_ERROR, err))
 		}
 	}
 	if this.restartSec == 0 {
@@ -278,7 +280,7 @@ func (this *WSAccountUMBN) startCheck() error {
 			restartNum++
 		}
 	}
-	fmt.Println("Restarted time: ", restartNum, "Restart limit time: ", this.restartLimitNum)
+	this.logger().Info("restart check", LogF("restart_num", restartNum), LogF("restart_limit", this.restartLimitNum))
 	if restartNum > this.restartLimitNum {
 		var wsErr = &WSStopError{
 			Msg: fmt.Sprintf(
@@ -286,6 +288,7 @@ func (this *WSAccountUMBN) startCheck() error {
 				restartNum, this.restartLimitSec,
 			),
 		}
+		this.Config.GetMetrics().IncWSRestartLimit(BINANCE, "WSAccountUMBN")
 		return wsErr
 	}
 	return nil
@@ -310,7 +313,11 @@ func (this *WSAccountUMBN) loginConn() (*websocket.Conn, error) {
 		return nil, fmt.Errorf(string(resp))
 	}
 
-	var conn, _, err = websocket.DefaultDialer.Dial(
+	var dialer, dialErr = NewWsDialer(this.DialerConfig)
+	if dialErr != nil {
+		return nil, dialErr
+	}
+	var conn, _, err = dialer.Dial(
 		fmt.Sprintf("wss://fstream.binance.com/ws/%s", response.ListenKey),
 		nil,
 	)
@@ -323,3 +330,10 @@ func (this *WSAccountUMBN) loginConn() (*websocket.Conn, error) {
 
 	return conn, nil
 }
+
+func (this *WSAccountUMBN) logger() Logger {
+	return this.Config.GetLogger().With(
+		LogF(LOG_FIELD_EXCHANGE, BINANCE),
+		LogF(LOG_FIELD_CONN_ID, this.connId),
+	)
+}
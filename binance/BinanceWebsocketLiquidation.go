@@ -0,0 +1,104 @@
+package binance
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"sync"
+
+	. "github.com/deforceHK/goghostex"
+)
+
+// the quote currencies to split the symbol in the !forceOrder@arr stream, the longer one first.
+var liquidationQuotes = []string{"FDUSD", "USDT", "USDC", "BUSD"}
+
+// Liquidations is the typed forceOrder stream of the um swap, the latest liquidation of the symbol in every 1000ms.
+type Liquidations struct {
+	*WSMarketUMBN
+	LiquidationHandler func(liquidation *Liquidation)
+
+	pairs sync.Map // the symbol to the pair of the subscribed
+}
+
+type forceOrderBN struct {
+	EventType string `json:"e"`
+	EventTime int64  `json:"E"`
+	Order     struct {
+		Symbol       string `json:"s"`
+		Side         string `json:"S"`
+		AvgPrice     string `json:"ap"`
+		FilledAmount string `json:"z"`
+		Timestamp    int64  `json:"T"`
+	} `json:"o"`
+}
+
+func (this *Liquidations) Init() error {
+	if this.LiquidationHandler == nil {
+		this.LiquidationHandler = func(liquidation *Liquidation) {
+			this.logger().Debug("receive liquidation", LogF("liquidation", *liquidation))
+		}
+	}
+	this.RecvHandler = func(s string) {
+		this.Receiver(s)
+	}
+	return this.Start()
+}
+
+// Subscribe the liquidations of the pair, use SubscribeAll for the whole market.
+func (this *Liquidations) Subscribe(pair Pair) {
+	var symbol = pair.ToSymbol("", true)
+	this.pairs.Store(symbol, pair)
+	this.WSMarketUMBN.Subscribe(strings.ToLower(symbol) + "@forceOrder")
+}
+
+func (this *Liquidations) Unsubscribe(pair Pair) {
+	this.WSMarketUMBN.Unsubscribe(strings.ToLower(pair.ToSymbol("", true)) + "@forceOrder")
+}
+
+func (this *Liquidations) SubscribeAll() {
+	this.WSMarketUMBN.Subscribe("!forceOrder@arr")
+}
+
+func (this *Liquidations) Receiver(msg string) {
+	var response = struct {
+		Stream string        `json:"stream"`
+		Data   *forceOrderBN `json:"data"`
+	}{}
+	if err := json.Unmarshal([]byte(msg), &response); err != nil {
+		this.ErrorHandler(err)
+		return
+	}
+	if response.Data == nil || response.Data.EventType != "forceOrder" {
+		this.logger().Debug("receive message", LogF("msg", msg))
+		return
+	}
+
+	var order = response.Data.Order
+	var price, _ = strconv.ParseFloat(order.AvgPrice, 64)
+	var amount, _ = strconv.ParseFloat(order.FilledAmount, 64)
+	var liquidation = &Liquidation{
+		Pair:      this.getPair(order.Symbol),
+		Exchange:  BINANCE,
+		Symbol:    order.Symbol,
+		Side:      SELL,
+		Price:     price,
+		Amount:    amount,
+		Timestamp: order.Timestamp,
+	}
+	if order.Side == "BUY" {
+		liquidation.Side = BUY
+	}
+	this.LiquidationHandler(liquidation)
+}
+
+func (this *Liquidations) getPair(symbol string) Pair {
+	if pair, exist := this.pairs.Load(symbol); exist {
+		return pair.(Pair)
+	}
+	for _, quote := range liquidationQuotes {
+		if strings.HasSuffix(symbol, quote) && len(symbol) > len(quote) {
+			return NewPair(strings.TrimSuffix(symbol, quote)+"_"+quote, "_")
+		}
+	}
+	return NewPair(symbol, "_")
+}
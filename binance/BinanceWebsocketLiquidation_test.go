@@ -0,0 +1,31 @@
+package binance
+
+import (
+	"testing"
+
+	. "github.com/deforceHK/goghostex"
+)
+
+// go test -v ./binance/... -count=1 -run=TestLiquidations_Receiver
+func TestLiquidations_Receiver(t *testing.T) {
+	var received []*Liquidation
+	var liquidations = &Liquidations{
+		WSMarketUMBN: &WSMarketUMBN{Config: &APIConfig{}},
+		LiquidationHandler: func(liquidation *Liquidation) {
+			received = append(received, liquidation)
+		},
+	}
+
+	liquidations.Receiver(`{"stream":"!forceOrder@arr","data":{"e":"forceOrder","E":1568014460893,"o":{"s":"ETHUSDT",` +
+		`"S":"SELL","o":"LIMIT","f":"IOC","q":"0.014","p":"9910","ap":"9910.5","X":"FILLED","l":"0.014","z":"0.014",` +
+		`"T":1568014460893}}}`)
+	if len(received) != 1 {
+		t.Error("the liquidation is not received. ")
+		return
+	}
+	var liquidation = received[0]
+	if liquidation.Pair.ToSymbol("_", true) != "ETH_USDT" || liquidation.Side != SELL ||
+		liquidation.Price != 9910.5 || liquidation.Amount != 0.014 || liquidation.Timestamp != 1568014460893 {
+		t.Error("the liquidation is wrong: ", *liquidation)
+	}
+}
@@ -2,7 +2,6 @@ package binance
 
 import (
 	"fmt"
-	"log"
 	"strings"
 	"time"
 
@@ -15,6 +14,8 @@ type WSMarketUMBN struct {
 	RecvHandler  func(string)
 	ErrorHandler func(error)
 	Config       *APIConfig
+	DialerConfig *WsDialerConfig // the proxy, tls and local address setting of the connection, not necessary
+	Wss          string          // the combined stream endpoint, default the um swap stream, eg: OPTION_STREAM_WSS
 
 	conn   *websocket.Conn
 	connId string
@@ -40,7 +41,11 @@ func (this *WSMarketUMBN) Start() error {
 		return err
 	}
 
-	var conn, err = this.noLoginConn("wss://fstream.binance.com/stream")
+	var wss = "wss://fstream.binance.com/stream"
+	if this.Wss != "" {
+		wss = this.Wss
+	}
+	var conn, err = this.noLoginConn(wss)
 	if err != nil {
 		// it means stopped at least once.
 		if len(this.restartTS) != 0 {
@@ -98,6 +103,7 @@ func (this *WSMarketUMBN) Unsubscribe(v interface{}) {
 }
 
 func (this *WSMarketUMBN) Restart() {
+	this.Config.GetMetrics().IncWSReconnect(BINANCE, "WSMarketUMBN")
 	this.restartTS[time.Now().Unix()] = this.connId
 	this.Stop()
 	this.ErrorHandler(
@@ -131,7 +137,11 @@ func (this *WSMarketUMBN) Restart() {
 
 func (this *WSMarketUMBN) noLoginConn(wss string) (*websocket.Conn, error) {
 	this.initDefaultValue()
-	var conn, _, err = websocket.DefaultDialer.Dial(
+	var dialer, dialErr = NewWsDialer(this.DialerConfig)
+	if dialErr != nil {
+		return nil, dialErr
+	}
+	var conn, _, err = dialer.Dial(
 		wss,
 		nil,
 	)
@@ -149,7 +159,7 @@ func (this *WSMarketUMBN) startCheck() error {
 			restartNum++
 		}
 	}
-	fmt.Println("Restarted time: ", restartNum, "Restart limit time: ", this.restartLimitNum)
+	this.logger().Info("restart check", LogF("restart_num", restartNum), LogF("restart_limit", this.restartLimitNum))
 	if restartNum > this.restartLimitNum {
 		var wsErr = &WSStopError{
 			Msg: fmt.Sprintf(
@@ -157,6 +167,7 @@ func (this *WSMarketUMBN) startCheck() error {
 				restartNum, this.restartLimitSec,
 			),
 		}
+		this.Config.GetMetrics().IncWSRestartLimit(BINANCE, "WSMarketUMBN")
 		return wsErr
 	}
 	return nil
@@ -196,7 +207,7 @@ func (this *WSMarketUMBN) recvRoutine() {
 		if readErr != nil {
 			// conn closed by user.
 			if strings.Index(readErr.Error(), "use of closed network connection") > 0 {
-				fmt.Println("conn closed by user. ")
+				this.logger().Info("conn closed by user")
 				return
 			}
 			this.ErrorHandler(readErr)
@@ -209,6 +220,7 @@ func (this *WSMarketUMBN) recvRoutine() {
 		}
 
 		this.lastPingTS = time.Now().Unix()
+		this.Config.GetMetrics().IncWSMessage(BINANCE, "WSMarketUMBN")
 		this.RecvHandler(string(msg))
 	}
 
@@ -230,12 +242,12 @@ func (this *WSMarketUMBN) Stop() {
 func (this *WSMarketUMBN) initDefaultValue() {
 	if this.RecvHandler == nil {
 		this.RecvHandler = func(msg string) {
-			log.Println(msg)
+			this.logger().Debug("receive message", LogF("msg", msg))
 		}
 	}
 	if this.ErrorHandler == nil {
 		this.ErrorHandler = func(err error) {
-			log.Println(err)
+			this.logger().Error("websocket error", LogF(LOG_FIELD_ERROR, err))
 		}
 	}
 	if this.restartSec == 0 {
@@ -255,3 +267,10 @@ func (this *WSMarketUMBN) initDefaultValue() {
 	}
 
 }
+
+func (this *WSMarketUMBN) logger() Logger {
+	return this.Config.GetLogger().With(
+		LogF(LOG_FIELD_EXCHANGE, BINANCE),
+		LogF(LOG_FIELD_CONN_ID, this.connId),
+	)
+}
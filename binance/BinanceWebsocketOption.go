@@ -0,0 +1,149 @@
+package binance
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	. "github.com/deforceHK/goghostex"
+)
+
+const OPTION_STREAM_WSS = "wss://nbstream.binance.com/eoptions/stream"
+
+// OptionTickers is the typed ticker and markPrice stream of the option, the greeks and the mark price of the same
+// symbol are merged, the handler receive the latest ticker of the symbol in every push.
+// The WSMarketUMBN.Wss is set to OPTION_STREAM_WSS in the Init when it is empty.
+type OptionTickers struct {
+	*WSMarketUMBN
+	TickerHandler func(ticker *OptionTicker)
+
+	tickers sync.Map // the symbol to the latest *OptionTicker
+}
+
+// optionTickerStreamBN is the item of the ticker stream, the T, V and A are declared to keep them from the t, v and a.
+type optionTickerStreamBN struct {
+	EventType  string `json:"e"`
+	EventTime  int64  `json:"E"`
+	CloseTime  int64  `json:"T"`
+	Symbol     string `json:"s"`
+	Last       string `json:"c"`
+	Volume     string `json:"V"`
+	Amount     string `json:"A"`
+	BuyPrice   string `json:"bo"`
+	SellPrice  string `json:"ao"`
+	BuyIV      string `json:"b"`
+	SellIV     string `json:"a"`
+	Delta      string `json:"d"`
+	Theta      string `json:"t"`
+	Gamma      string `json:"g"`
+	Vega       string `json:"v"`
+	MarkIV     string `json:"vo"`
+	MarkPrice  string `json:"mp"`
+	IndexPrice string `json:"eep"`
+}
+
+type optionMarkStreamBN struct {
+	EventType string `json:"e"`
+	EventTime int64  `json:"E"`
+	Symbol    string `json:"s"`
+	MarkPrice string `json:"mp"`
+}
+
+func (this *OptionTickers) Init() error {
+	if this.Wss == "" {
+		this.Wss = OPTION_STREAM_WSS
+	}
+	if this.TickerHandler == nil {
+		this.TickerHandler = func(ticker *OptionTicker) {
+			this.logger().Debug("receive option ticker", LogF("ticker", *ticker))
+		}
+	}
+	this.RecvHandler = func(s string) {
+		this.Receiver(s)
+	}
+	return this.Start()
+}
+
+// SubscribeTicker subscribe the greeks and the implied volatility of the options of the pair expire in the expiry.
+func (this *OptionTickers) SubscribeTicker(pair Pair, expiry time.Time) {
+	this.WSMarketUMBN.Subscribe(pair.Basis.Symbol + "@ticker@" + expiry.In(time.UTC).Format("060102"))
+}
+
+// SubscribeMarkPrice subscribe the mark price of all the options of the pair.
+func (this *OptionTickers) SubscribeMarkPrice(pair Pair) {
+	this.WSMarketUMBN.Subscribe(pair.Basis.Symbol + "@markPrice")
+}
+
+func (this *OptionTickers) Receiver(msg string) {
+	var response = struct {
+		Stream string          `json:"stream"`
+		Data   json.RawMessage `json:"data"`
+	}{}
+	if err := json.Unmarshal([]byte(msg), &response); err != nil {
+		this.ErrorHandler(err)
+		return
+	}
+	// the data is the array in the underlying streams, the object in the symbol streams.
+	var data = bytes.TrimSpace(response.Data)
+	if len(data) > 0 && data[0] == '{' {
+		data = append(append([]byte{'['}, data...), ']')
+	}
+
+	switch {
+	case strings.Contains(response.Stream, "@ticker"):
+		var items = make([]*optionTickerStreamBN, 0)
+		if err := json.Unmarshal(data, &items); err != nil {
+			this.ErrorHandler(err)
+			return
+		}
+		for _, item := range items {
+			var ticker = this.getTicker(item.Symbol)
+			ticker.Last = ToFloat64(item.Last)
+			ticker.Buy, ticker.Sell = ToFloat64(item.BuyPrice), ToFloat64(item.SellPrice)
+			ticker.BuyIV, ticker.SellIV, ticker.MarkIV = ToFloat64(item.BuyIV), ToFloat64(item.SellIV), ToFloat64(item.MarkIV)
+			ticker.MarkPrice = ToFloat64(item.MarkPrice)
+			ticker.UnderlyingPrice = ToFloat64(item.IndexPrice)
+			ticker.Greeks = OptionGreeks{
+				Delta: ToFloat64(item.Delta),
+				Gamma: ToFloat64(item.Gamma),
+				Vega:  ToFloat64(item.Vega),
+				Theta: ToFloat64(item.Theta),
+			}
+			this.emit(ticker, item.EventTime)
+		}
+	case strings.Contains(response.Stream, "@markPrice"):
+		var items = make([]*optionMarkStreamBN, 0)
+		if err := json.Unmarshal(data, &items); err != nil {
+			this.ErrorHandler(err)
+			return
+		}
+		for _, item := range items {
+			var ticker = this.getTicker(item.Symbol)
+			ticker.MarkPrice = ToFloat64(item.MarkPrice)
+			this.emit(ticker, item.EventTime)
+		}
+	default:
+		this.logger().Debug("receive message", LogF("msg", msg))
+	}
+}
+
+func (this *OptionTickers) getTicker(symbol string) *OptionTicker {
+	var ticker, _ = this.tickers.LoadOrStore(symbol, &OptionTicker{
+		Pair:     Pair{Basis: NewCurrency(strings.Split(symbol, "-")[0], ""), Counter: USDT},
+		Symbol:   symbol,
+		Exchange: BINANCE,
+	})
+	return ticker.(*OptionTicker)
+}
+
+// emit send the copy of the latest ticker to the handler.
+func (this *OptionTickers) emit(ticker *OptionTicker, timestamp int64) {
+	ticker.Timestamp = timestamp
+	if this.Config.Location != nil {
+		ticker.Date = time.Unix(timestamp/1000, 0).In(this.Config.Location).Format(GO_BIRTHDAY)
+	}
+	var copied = *ticker
+	this.TickerHandler(&copied)
+}
@@ -0,0 +1,39 @@
+package binance
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/deforceHK/goghostex"
+)
+
+// go test -v ./binance/... -count=1 -run=TestOptionTickers_Receiver
+func TestOptionTickers_Receiver(t *testing.T) {
+	var received []*OptionTicker
+	var tickers = &OptionTickers{
+		WSMarketUMBN: &WSMarketUMBN{Config: &APIConfig{Location: time.UTC}},
+		TickerHandler: func(ticker *OptionTicker) {
+			received = append(received, ticker)
+		},
+	}
+
+	tickers.Receiver(`{"stream":"BTC@ticker@241227","data":[{"e":"24hrTicker","E":1700000000000,"T":1700000000100,` +
+		`"s":"BTC-241227-60000-C","o":"2000","h":"2100","l":"1900","c":"2050","V":"12.5","A":"25600","P":"0.02",` +
+		`"p":"50","Q":"0.1","F":"1","L":"20","n":20,"bo":"2040","ao":"2060","bq":"1","aq":"2","b":"0.51","a":"0.53",` +
+		`"d":"0.55","t":"-35.2","g":"0.00003","v":"120.5","vo":"0.52","mp":"2052","hl":"3000","ll":"1000","eep":"61234.5"}]}`)
+	tickers.Receiver(`{"stream":"BTC@markPrice","data":[{"e":"markPrice","E":1700000001000,"s":"BTC-241227-60000-C","mp":"2055"}]}`)
+	if len(received) != 2 {
+		t.Error("the tickers are wrong: ", len(received))
+		return
+	}
+	var ticker = received[0]
+	if ticker.Pair.ToSymbol("_", true) != "BTC_USDT" || ticker.Last != 2050 || ticker.Buy != 2040 || ticker.Sell != 2060 {
+		t.Error("the ticker is wrong: ", *ticker)
+	}
+	if ticker.Greeks.Theta != -35.2 || ticker.Greeks.Vega != 120.5 || ticker.SellIV != 0.53 || ticker.MarkIV != 0.52 {
+		t.Error("the greeks are wrong: ", *ticker)
+	}
+	if received[1].MarkPrice != 2055 || received[1].Greeks.Delta != 0.55 || received[1].Timestamp != 1700000001000 {
+		t.Error("the merged ticker is wrong: ", *received[1])
+	}
+}
@@ -2,11 +2,56 @@ package bitstamp
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
 	"time"
 
+	"github.com/google/uuid"
+
 	. "github.com/deforceHK/goghostex"
 )
 
+const (
+	CONTENT_TYPE = "Content-Type"
+
+	APPLICATION_FORM = "application/x-www-form-urlencoded"
+
+	// the headers of the v2 private api, the signature is the hmac sha256 of the request info.
+	X_AUTH           = "X-Auth"
+	X_AUTH_SIGNATURE = "X-Auth-Signature"
+	X_AUTH_NONCE     = "X-Auth-Nonce"
+	X_AUTH_TIMESTAMP = "X-Auth-Timestamp"
+	X_AUTH_VERSION   = "X-Auth-Version"
+
+	AUTH_VERSION = "v2"
+
+	// the uri templates which have the pair symbol, eg: btcusd.
+	TICKER_URI            = "/api/v2/ticker/%s/"
+	ORDER_BOOK_URI        = "/api/v2/order_book/%s/"
+	TRANSACTIONS_URI      = "/api/v2/transactions/%s/"
+	BUY_URI               = "/api/v2/buy/%s/"
+	SELL_URI              = "/api/v2/sell/%s/"
+	BUY_MARKET_URI        = "/api/v2/buy/market/%s/"
+	SELL_MARKET_URI       = "/api/v2/sell/market/%s/"
+	OPEN_ORDERS_URI       = "/api/v2/open_orders/%s/"
+	USER_TRANSACTIONS_URI = "/api/v2/user_transactions/%s/"
+)
+
+// the route label of the rest metrics, the market order uri is before the limit one, cause both of them match it.
+var _INERNAL_ROUTES = []string{
+	TICKER_URI,
+	ORDER_BOOK_URI,
+	TRANSACTIONS_URI,
+	BUY_MARKET_URI,
+	SELL_MARKET_URI,
+	BUY_URI,
+	SELL_URI,
+	OPEN_ORDERS_URI,
+	USER_TRANSACTIONS_URI,
+}
+
 var (
 	ENDPOINT = "https://www.bitstamp.net"
 )
@@ -19,15 +64,63 @@ type Bitstamp struct {
 
 func New(config *APIConfig) *Bitstamp {
 	bitstamp := &Bitstamp{config: config}
-	bitstamp.Spot = &Spot{bitstamp}
+	bitstamp.Spot = &Spot{Bitstamp: bitstamp}
 	return bitstamp
 }
 
 func (bitstamp *Bitstamp) DoRequest(httpMethod, uri, reqBody string, response interface{}) ([]byte, error) {
-	resp, err := NewHttpRequest(
+	return bitstamp.doRequest(httpMethod, uri, reqBody, response, nil)
+}
+
+// DoSignRequest request the v2 private api, the params are posted in the form body. The signature message is
+// "BITSTAMP {api_key}{method}{host}{path}{query}{content_type}{nonce}{timestamp}v2{body}", the content type is
+// empty when the body is empty.
+func (bitstamp *Bitstamp) DoSignRequest(httpMethod, uri string, params url.Values, response interface{}) ([]byte, error) {
+	endpoint, err := url.Parse(bitstamp.config.Endpoint + uri)
+	if err != nil {
+		return nil, err
+	}
+
+	var reqBody, contentType = "", ""
+	if len(params) > 0 {
+		reqBody, contentType = params.Encode(), APPLICATION_FORM
+	}
+	var nonce = uuid.New().String()
+	var timestamp = fmt.Sprintf("%d", time.Now().UnixNano()/int64(time.Millisecond))
+	var message = "BITSTAMP " + bitstamp.config.ApiKey + httpMethod + endpoint.Host + endpoint.Path +
+		endpoint.RawQuery + contentType + nonce + timestamp + AUTH_VERSION + reqBody
+	sign, err := GetParamHmacSHA256Sign(bitstamp.config.ApiSecretKey, message)
+	if err != nil {
+		return nil, err
+	}
+
+	var headers = map[string]string{
+		X_AUTH:           "BITSTAMP " + bitstamp.config.ApiKey,
+		X_AUTH_SIGNATURE: strings.ToUpper(sign),
+		X_AUTH_NONCE:     nonce,
+		X_AUTH_TIMESTAMP: timestamp,
+		X_AUTH_VERSION:   AUTH_VERSION,
+	}
+	if contentType != "" {
+		headers[CONTENT_TYPE] = contentType
+	}
+	return bitstamp.doRequest(httpMethod, uri, reqBody, response, headers)
+}
+
+func (bitstamp *Bitstamp) doRequest(
+	httpMethod,
+	uri,
+	reqBody string,
+	response interface{},
+	headers map[string]string,
+) ([]byte, error) {
+	resp, err := NewHttpRequestWithRoute(
+		bitstamp.config.GetMetrics(),
+		BITSTAMP,
+		RouteOf(uri, _INERNAL_ROUTES),
 		bitstamp.config.HttpClient,
 		httpMethod, bitstamp.config.Endpoint+uri, reqBody,
-		nil,
+		headers,
 	)
 
 	if err != nil {
@@ -37,6 +130,16 @@ func (bitstamp *Bitstamp) DoRequest(httpMethod, uri, reqBody string, response in
 		if bitstamp.config.LastTimestamp < nowTimestamp {
 			bitstamp.config.LastTimestamp = nowTimestamp
 		}
+
+		// bitstamp return the error with the http status 200, eg: {"status":"error","reason":{"__all__":["..."]}}
+		var errResponse = struct {
+			Status string      `json:"status"`
+			Reason interface{} `json:"reason"`
+			Code   string      `json:"code"`
+		}{}
+		if json.Unmarshal(resp, &errResponse) == nil && errResponse.Status == "error" {
+			return resp, errors.New(string(resp))
+		}
 		return resp, json.Unmarshal(resp, &response)
 	}
 }
@@ -3,8 +3,10 @@ package bitstamp
 import (
 	"errors"
 	"fmt"
+	"net/http"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	. "github.com/deforceHK/goghostex"
@@ -12,12 +14,14 @@ import (
 
 type Spot struct {
 	*Bitstamp
+
+	rules sync.Map // the cache of the exchange rule, key is the pair string
 }
 
 // public api
 func (spot *Spot) GetTicker(pair Pair) (*Ticker, []byte, error) {
 
-	uri := "/api/v2/ticker/" + pair.ToSymbol("", false)
+	uri := fmt.Sprintf(TICKER_URI, pair.ToSymbol("", false))
 	response := struct {
 		High      float64 `json:"high,string"`
 		Low       float64 `json:"low,string"`
@@ -50,7 +54,7 @@ func (spot *Spot) GetTicker(pair Pair) (*Ticker, []byte, error) {
 }
 
 func (spot *Spot) GetDepth(pair Pair, size int) (*Depth, []byte, error) {
-	uri := "/api/v2/order_book/" + pair.ToSymbol("", false)
+	uri := fmt.Sprintf(ORDER_BOOK_URI, pair.ToSymbol("", false))
 	response := struct {
 		Bids      [][]interface{} `json:"bids"`
 		Asks      [][]interface{} `json:"asks"`
@@ -97,7 +101,7 @@ func (spot *Spot) GetKlineRecords(pair Pair, period, size, since int) ([]*Kline,
 	}
 
 	uri := fmt.Sprintf(
-		"/api/v2/transactions/%s/?time=day",
+		TRANSACTIONS_URI+"?time=day",
 		strings.ToLower(pair.ToSymbol("", false)),
 	)
 	response := make([]struct {
@@ -157,40 +161,149 @@ func (spot *Spot) GetKlineRecords(pair Pair, period, size, since int) ([]*Kline,
 }
 
 // private api
-func (spot *Spot) GetAccount() (*Account, []byte, error) {
-	panic("implement me")
-}
-
-func (spot *Spot) PlaceOrder(*Order) ([]byte, error) {
-	panic("implement me")
-}
 
-func (spot *Spot) CancelOrder(*Order) ([]byte, error) {
-	panic("implement me")
+// BatchPlaceOrders place the orders one by one.
+func (spot *Spot) BatchPlaceOrders(orders []*Order) ([]error, []byte, error) {
+	return BatchSequential(len(orders), func(i int) ([]byte, error) {
+		return spot.PlaceOrder(orders[i])
+	})
 }
 
-func (spot *Spot) GetOrder(*Order) ([]byte, error) {
-	panic("implement me")
+func (spot *Spot) BatchCancelOrders(orders []*Order) ([]error, []byte, error) {
+	return BatchSequential(len(orders), func(i int) ([]byte, error) {
+		return spot.CancelOrder(orders[i])
+	})
 }
 
-func (spot *Spot) GetOrders(pair Pair) ([]*Order, error) {
-	panic("implement me")
+// AmendOrder cancel the order and place the new one, the OrderId is changed and the queue position is lost.
+func (spot *Spot) AmendOrder(order *Order, newPrice, newAmount float64) ([]byte, error) {
+	if resp, err := spot.CancelOrder(order); err != nil {
+		return resp, err
+	}
+	if newPrice > 0 {
+		order.Price = newPrice
+	}
+	if newAmount > 0 {
+		order.Amount = newAmount
+	}
+	order.OrderId = ""
+	return spot.PlaceOrder(order)
 }
 
-func (spot *Spot) GetUnFinishOrders(pair Pair) ([]*Order, []byte, error) {
-	panic("implement me")
+// CancelAllOrders cancel the unfinished orders of the pair one by one.
+func (spot *Spot) CancelAllOrders(pair Pair) ([]byte, error) {
+	var orders, resp, err = spot.GetUnFinishOrders(pair)
+	if err != nil {
+		return resp, err
+	}
+	_, resp, err = spot.BatchCancelOrders(orders)
+	return resp, err
 }
 
 func (spot *Spot) GetExchangeName() string {
-	panic("implement me")
+	return BITSTAMP
 }
 
+// GetExchangeRule return the rule from the trading pairs info, the minimum_order is the min notional, eg: 10.0 USD.
 func (spot *Spot) GetExchangeRule(pair Pair) (*Rule, []byte, error) {
-	panic("implete me. ")
+	var response = make([]*struct {
+		Name            string `json:"name"` // BTC/USD
+		UrlSymbol       string `json:"url_symbol"`
+		BaseDecimals    int    `json:"base_decimals"`
+		CounterDecimals int    `json:"counter_decimals"`
+		MinimumOrder    string `json:"minimum_order"`
+		Trading         string `json:"trading"`
+	}, 0)
+	resp, err := spot.DoRequest(http.MethodGet, "/api/v2/trading-pairs-info/", "", &response)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	var symbol = pair.ToSymbol("", false)
+	for _, item := range response {
+		if item.UrlSymbol != symbol {
+			continue
+		}
+		if item.Trading != "Enabled" {
+			return nil, resp, errors.New("The pair is not enabled in exchange. ")
+		}
+		var minNotional = 0.0
+		if fields := strings.Fields(item.MinimumOrder); len(fields) > 0 {
+			minNotional = ToFloat64(fields[0])
+		}
+		var currencies = strings.Split(item.Name, "/")
+		if len(currencies) != 2 {
+			currencies = []string{pair.Basis.Symbol, pair.Counter.Symbol}
+		}
+
+		rule := Rule{
+			Pair:    pair,
+			Base:    NewCurrency(currencies[0], ""),
+			Counter: NewCurrency(currencies[1], ""),
+
+			BasePrecision:    item.BaseDecimals,
+			CounterPrecision: item.CounterDecimals,
+			MinNotional:      minNotional,
+		}
+		return &rule, resp, nil
+	}
+	return nil, resp, errors.New("Can not find the pair in exchange. ")
+}
+
+// getRule return the cached rule of the pair, the rule is queried at the first time.
+func (spot *Spot) getRule(pair Pair) (*Rule, error) {
+	if rule, exist := spot.rules.Load(pair.String()); exist {
+		return rule.(*Rule), nil
+	}
+	var rule, _, err = spot.GetExchangeRule(pair)
+	if err != nil {
+		return nil, err
+	}
+	spot.rules.Store(pair.String(), rule)
+	return rule, nil
 }
 
+// GetTrades return the trades after the since(ms) in the last day at most, the trades are in ascending order.
 func (spot *Spot) GetTrades(pair Pair, since int64) ([]*Trade, error) {
-	panic("implement me")
+	var period = "day"
+	if since > 0 && time.Now().UnixNano()/int64(time.Millisecond)-since < 60*1000 {
+		period = "minute"
+	} else if since > 0 && time.Now().UnixNano()/int64(time.Millisecond)-since < 60*60*1000 {
+		period = "hour"
+	}
+
+	var response = make([]*struct {
+		Tid    interface{} `json:"tid"`
+		Date   int64       `json:"date,string"`
+		Price  string      `json:"price"`
+		Amount string      `json:"amount"`
+		Type   interface{} `json:"type"` // 0 buy, 1 sell
+	}, 0)
+	var uri = fmt.Sprintf(TRANSACTIONS_URI+"?time=%s", pair.ToSymbol("", false), period)
+	if _, err := spot.DoRequest(http.MethodGet, uri, "", &response); err != nil {
+		return nil, err
+	}
+
+	var trades = make([]*Trade, 0, len(response))
+	for i := len(response) - 1; i >= 0; i-- {
+		var item = response[i]
+		if item.Date*1000 < since {
+			continue
+		}
+		var side = BUY
+		if ToInt64(item.Type) == 1 {
+			side = SELL
+		}
+		trades = append(trades, &Trade{
+			Tid:       ToInt64(item.Tid),
+			Type:      side,
+			Amount:    ToFloat64(item.Amount),
+			Price:     ToFloat64(item.Price),
+			Timestamp: item.Date * 1000,
+			Pair:      pair,
+		})
+	}
+	return trades, nil
 }
 
 // util api
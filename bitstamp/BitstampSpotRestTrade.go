@@ -0,0 +1,370 @@
+package bitstamp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	. "github.com/deforceHK/goghostex"
+)
+
+// the place type flags of the limit order, the maker or cancel order is the post only order.
+var _INERNAL_SPOT_PLACE_TYPE_CONVERTER = map[PlaceType]string{
+	NORMAL:     "",
+	ONLY_MAKER: "moc_order",
+	IOC:        "ioc_order",
+	FOK:        "fok_order",
+}
+
+// the datetime of bitstamp is in utc, eg: 2022-01-31 14:43:15.123456
+const DATETIME_LAYOUT = "2006-01-02 15:04:05"
+
+func parseDatetime(datetime string) (time.Time, error) {
+	return time.ParseInLocation(DATETIME_LAYOUT, datetime, time.UTC)
+}
+
+// the order in the order_status api, the deal info is from the transactions. The keys of the amount in the
+// transaction are the currencies, eg: btc usd.
+type spotOrderBS struct {
+	Id              json.Number              `json:"id"`
+	Datetime        string                   `json:"datetime"`
+	Type            interface{}              `json:"type"` // 0 buy, 1 sell
+	Status          string                   `json:"status"`
+	Market          string                   `json:"market"` // BTC/USD
+	AmountRemaining string                   `json:"amount_remaining"`
+	ClientOrderId   string                   `json:"client_order_id"`
+	Transactions    []map[string]interface{} `json:"transactions"`
+}
+
+// merge the remote order into the order. The order_status api has not the price, so the Price of the order is not
+// changed, and the Amount is the deal amount plus the remaining amount.
+func (sob *spotOrderBS) merge(order *Order, location *time.Location) {
+	order.OrderId = sob.Id.String()
+	if sob.ClientOrderId != "" {
+		order.Cid = sob.ClientOrderId
+	}
+	if sob.Market != "" {
+		order.Pair = NewPair(sob.Market, "/")
+	}
+	if order.Side != BUY_MARKET && order.Side != SELL_MARKET {
+		order.Side = BUY
+		if ToInt64(sob.Type) == 1 {
+			order.Side = SELL
+		}
+	}
+
+	var base = strings.ToLower(order.Pair.Basis.Symbol)
+	var counter = strings.ToLower(order.Pair.Counter.Symbol)
+	var dealAmount, dealValue, fee, lastDeal = 0.0, 0.0, 0.0, ""
+	for _, transaction := range sob.Transactions {
+		dealAmount += ToFloat64(transaction[base])
+		dealValue += ToFloat64(transaction[counter])
+		fee += ToFloat64(transaction["fee"])
+		if datetime, ok := transaction["datetime"].(string); ok && datetime > lastDeal {
+			lastDeal = datetime
+		}
+	}
+	order.DealAmount = dealAmount
+	order.Fee = fee
+	if dealAmount > 0 {
+		order.AvgPrice = dealValue / dealAmount
+	}
+	order.Amount = dealAmount + ToFloat64(sob.AmountRemaining)
+
+	switch sob.Status {
+	case "Open":
+		order.Status = ORDER_UNFINISH
+		if order.DealAmount > 0 {
+			order.Status = ORDER_PART_FINISH
+		}
+	case "Finished":
+		order.Status = ORDER_FINISH
+	case "Canceled", "Expired":
+		order.Status = ORDER_CANCEL
+	default:
+		order.Status = ORDER_FAIL
+	}
+
+	if orderTime, err := parseDatetime(sob.Datetime); err == nil {
+		order.OrderTimestamp = orderTime.UnixNano() / int64(time.Millisecond)
+		order.OrderDate = orderTime.In(location).Format(GO_BIRTHDAY)
+	}
+	if dealTime, err := parseDatetime(lastDeal); err == nil && order.Status.IsFinal() {
+		order.DealTimestamp = dealTime.UnixNano() / int64(time.Millisecond)
+		order.DealDatetime = dealTime.In(location).Format(GO_BIRTHDAY)
+	}
+}
+
+func (spot *Spot) GetAccount() (*Account, []byte, error) {
+	var response = make([]*struct {
+		Currency  string `json:"currency"`
+		Available string `json:"available"`
+		Reserved  string `json:"reserved"`
+	}, 0)
+	resp, err := spot.DoSignRequest(http.MethodPost, "/api/v2/account_balances/", nil, &response)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	var account = &Account{
+		Exchange:    BITSTAMP,
+		SubAccounts: make(map[string]SubAccount, 0),
+	}
+	for _, item := range response {
+		var currency = strings.ToUpper(item.Currency)
+		account.SubAccounts[currency] = SubAccount{
+			Currency:     NewCurrency(currency, ""),
+			Amount:       ToFloat64(item.Available),
+			AmountFrozen: ToFloat64(item.Reserved),
+		}
+	}
+	return account, resp, nil
+}
+
+// PlaceOrder place the order by the normalized price and amount, the amount of the market order is in the base
+// currency too. The client order id is generated when the Cid is empty.
+func (spot *Spot) PlaceOrder(order *Order) ([]byte, error) {
+	var side = "buy"
+	if order.Side == SELL || order.Side == SELL_MARKET {
+		side = "sell"
+	} else if order.Side != BUY && order.Side != BUY_MARKET {
+		return nil, errors.New("Can not deal the order side. ")
+	}
+	var isMarket = order.Side == BUY_MARKET || order.Side == SELL_MARKET || order.OrderType == MARKET
+
+	rule, err := spot.getRule(order.Pair)
+	if err != nil {
+		return nil, err
+	}
+	var normalizer = rule.GetNormalizer()
+	price, amount, err := normalizer.Normalize(order.Price, order.Amount, isMarket)
+	if err != nil {
+		return nil, err
+	}
+
+	if order.Cid == "" {
+		order.Cid = UUID()
+	}
+	var params = url.Values{}
+	params.Set("amount", normalizer.FormatAmount(amount))
+	params.Set("client_order_id", order.Cid)
+
+	var uri = fmt.Sprintf(BUY_MARKET_URI, order.Pair.ToSymbol("", false))
+	if side == "sell" {
+		uri = fmt.Sprintf(SELL_MARKET_URI, order.Pair.ToSymbol("", false))
+	}
+	if !isMarket {
+		var flag, exist = _INERNAL_SPOT_PLACE_TYPE_CONVERTER[order.OrderType]
+		if !exist {
+			return nil, errors.New("not support the place type in bitstamp. ")
+		}
+		if flag != "" {
+			params.Set(flag, "True")
+		}
+		params.Set("price", normalizer.FormatPrice(price))
+		uri = fmt.Sprintf(BUY_URI, order.Pair.ToSymbol("", false))
+		if side == "sell" {
+			uri = fmt.Sprintf(SELL_URI, order.Pair.ToSymbol("", false))
+		}
+	}
+
+	var now = time.Now()
+	order.PlaceTimestamp = now.UnixNano() / int64(time.Millisecond)
+	order.PlaceDatetime = now.In(spot.config.Location).Format(GO_BIRTHDAY)
+	var response = struct {
+		Id       json.Number `json:"id"`
+		Datetime string      `json:"datetime"`
+	}{}
+	resp, err := spot.DoSignRequest(http.MethodPost, uri, params, &response)
+	if err != nil {
+		return resp, err
+	}
+	if response.Id == "" {
+		return resp, errors.New(string(resp))
+	}
+	order.OrderId = response.Id.String()
+	order.Price, order.Amount = price, amount
+	order.Status = ORDER_UNFINISH
+	if orderTime, err := parseDatetime(response.Datetime); err == nil {
+		order.OrderTimestamp = orderTime.UnixNano() / int64(time.Millisecond)
+		order.OrderDate = orderTime.In(spot.config.Location).Format(GO_BIRTHDAY)
+	}
+	return resp, nil
+}
+
+// CancelOrder cancel the order and query it for the deal amount, the status is canceling if the query failed.
+func (spot *Spot) CancelOrder(order *Order) ([]byte, error) {
+	if order.OrderId == "" {
+		return nil, errors.New("The orderid is empty. ")
+	}
+	var params = url.Values{}
+	params.Set("id", order.OrderId)
+
+	var response = struct {
+		Id json.Number `json:"id"`
+	}{}
+	resp, err := spot.DoSignRequest(http.MethodPost, "/api/v2/cancel_order/", params, &response)
+	if err != nil {
+		return resp, err
+	}
+	if response.Id.String() != order.OrderId {
+		return resp, errors.New(string(resp))
+	}
+
+	if _, err := spot.GetOrder(order); err != nil {
+		order.Status = ORDER_CANCEL_ING
+	}
+	return resp, nil
+}
+
+// GetOrder query the order by the OrderId, or by the Cid when the OrderId is empty.
+func (spot *Spot) GetOrder(order *Order) ([]byte, error) {
+	var params = url.Values{}
+	if order.OrderId != "" {
+		params.Set("id", order.OrderId)
+	} else if order.Cid != "" {
+		params.Set("client_order_id", order.Cid)
+	} else {
+		return nil, errors.New("The orderid and the cid are empty. ")
+	}
+
+	var response = &spotOrderBS{}
+	resp, err := spot.DoSignRequest(http.MethodPost, "/api/v2/order_status/", params, response)
+	if err != nil {
+		return resp, err
+	}
+	if response.Id == "" {
+		return resp, errors.New(string(resp))
+	}
+	response.merge(order, spot.config.Location)
+	return resp, nil
+}
+
+// GetOrders return the latest dealed orders of the pair, they are built from the trades in the user transactions.
+// The canceled part is not in the transactions, so the Amount is the deal amount and the Status is finish.
+func (spot *Spot) GetOrders(pair Pair) ([]*Order, error) {
+	var params = url.Values{}
+	params.Set("limit", "1000")
+	params.Set("sort", "desc")
+
+	var response = make([]map[string]interface{}, 0)
+	var uri = fmt.Sprintf(USER_TRANSACTIONS_URI, pair.ToSymbol("", false))
+	if _, err := spot.DoSignRequest(http.MethodPost, uri, params, &response); err != nil {
+		return nil, err
+	}
+
+	var base = strings.ToLower(pair.Basis.Symbol)
+	var counter = strings.ToLower(pair.Counter.Symbol)
+	var priceKey = base + "_" + counter
+	var orderMap = make(map[string]*Order)
+	var dealValues = make(map[string]float64)
+	for _, item := range response {
+		// the type 2 is the market trade, the others are the deposit, withdrawal and so on.
+		if ToInt64(item["type"]) != 2 || item["order_id"] == nil {
+			continue
+		}
+		var orderId = fmt.Sprint(item["order_id"])
+		if value, ok := item["order_id"].(float64); ok {
+			orderId = fmt.Sprintf("%.0f", value)
+		}
+
+		var baseAmount = ToFloat64(item[base])
+		var dealTime, _ = parseDatetime(fmt.Sprint(item["datetime"]))
+		var order, exist = orderMap[orderId]
+		if !exist {
+			order = &Order{
+				OrderId:   orderId,
+				Pair:      pair,
+				Side:      BUY,
+				OrderType: NORMAL,
+				Status:    ORDER_FINISH,
+			}
+			if baseAmount < 0 {
+				order.Side = SELL
+			}
+			orderMap[orderId] = order
+		}
+		order.DealAmount += baseAmount
+		order.Fee += ToFloat64(item["fee"])
+		dealValues[orderId] += ToFloat64(item[base]) * ToFloat64(item[priceKey])
+
+		var timestamp = dealTime.UnixNano() / int64(time.Millisecond)
+		if order.OrderTimestamp == 0 || timestamp < order.OrderTimestamp {
+			order.OrderTimestamp = timestamp
+			order.OrderDate = dealTime.In(spot.config.Location).Format(GO_BIRTHDAY)
+		}
+		if timestamp > order.DealTimestamp {
+			order.DealTimestamp = timestamp
+			order.DealDatetime = dealTime.In(spot.config.Location).Format(GO_BIRTHDAY)
+		}
+	}
+
+	var orders = make([]*Order, 0, len(orderMap))
+	for orderId, order := range orderMap {
+		if order.DealAmount < 0 {
+			order.DealAmount, dealValues[orderId] = -order.DealAmount, -dealValues[orderId]
+		}
+		if order.DealAmount > 0 {
+			order.AvgPrice = dealValues[orderId] / order.DealAmount
+		}
+		order.Amount, order.Price = order.DealAmount, order.AvgPrice
+		orders = append(orders, order)
+	}
+	sort.Slice(orders, func(i, j int) bool {
+		return orders[i].DealTimestamp > orders[j].DealTimestamp
+	})
+	return orders, nil
+}
+
+func (spot *Spot) GetUnFinishOrders(pair Pair) ([]*Order, []byte, error) {
+	var response = make([]*struct {
+		Id             json.Number `json:"id"`
+		Datetime       string      `json:"datetime"`
+		Type           interface{} `json:"type"` // 0 buy, 1 sell
+		Price          string      `json:"price"`
+		Amount         string      `json:"amount"` // the remaining amount
+		AmountAtCreate string      `json:"amount_at_create"`
+		ClientOrderId  string      `json:"client_order_id"`
+	}, 0)
+	var uri = fmt.Sprintf(OPEN_ORDERS_URI, pair.ToSymbol("", false))
+	resp, err := spot.DoSignRequest(http.MethodPost, uri, nil, &response)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	var orders = make([]*Order, 0, len(response))
+	for _, item := range response {
+		var amount = ToFloat64(item.AmountAtCreate)
+		if amount == 0 {
+			amount = ToFloat64(item.Amount)
+		}
+		var order = &Order{
+			Cid:        item.ClientOrderId,
+			OrderId:    item.Id.String(),
+			Pair:       pair,
+			Side:       BUY,
+			OrderType:  NORMAL,
+			Price:      ToFloat64(item.Price),
+			Amount:     amount,
+			DealAmount: amount - ToFloat64(item.Amount),
+			Status:     ORDER_UNFINISH,
+		}
+		if ToInt64(item.Type) == 1 {
+			order.Side = SELL
+		}
+		if order.DealAmount > 0 {
+			order.Status = ORDER_PART_FINISH
+		}
+		if orderTime, err := parseDatetime(item.Datetime); err == nil {
+			order.OrderTimestamp = orderTime.UnixNano() / int64(time.Millisecond)
+			order.OrderDate = orderTime.In(spot.config.Location).Format(GO_BIRTHDAY)
+		}
+		orders = append(orders, order)
+	}
+	return orders, resp, nil
+}
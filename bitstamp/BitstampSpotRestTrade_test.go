@@ -0,0 +1,82 @@
+package bitstamp
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	. "github.com/deforceHK/goghostex"
+)
+
+// go test -v ./bitstamp/... -count=1 -run=TestSpot_TradeAPI
+func TestSpot_TradeAPI(t *testing.T) {
+	var bs = New(&APIConfig{
+		Endpoint: ENDPOINT,
+		HttpClient: &http.Client{
+			Transport: &http.Transport{
+				Proxy: func(req *http.Request) (*url.URL, error) {
+					return url.Parse("socks5://127.0.0.1:1090")
+				},
+			},
+		},
+		ApiKey:       "",
+		ApiSecretKey: "",
+		Location:     time.Now().Location(),
+	})
+
+	var account, _, err = bs.Spot.GetAccount()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	fmt.Println(account.SubAccounts)
+
+	var orders, _, ordersErr = bs.Spot.GetUnFinishOrders(Pair{Basis: BTC, Counter: EUR})
+	if ordersErr != nil {
+		t.Error(ordersErr)
+		return
+	}
+	for _, order := range orders {
+		fmt.Println(*order)
+	}
+}
+
+// go test -v ./bitstamp/... -count=1 -run=TestSpotOrderBS_Merge
+func TestSpotOrderBS_Merge(t *testing.T) {
+	var sob = spotOrderBS{}
+	var raw = `{"id":1458532827766784,"datetime":"2024-01-02 03:04:05","type":"0",
+		"status":"Canceled","market":"BTC/EUR","amount_remaining":"0.4","client_order_id":"c-1",
+		"transactions":[{"tid":1,"price":"40000","btc":"0.1","eur":"4000","fee":"2.4",
+		"datetime":"2024-01-02 03:05:06.5","type":2}]}`
+	if err := json.Unmarshal([]byte(raw), &sob); err != nil {
+		t.Error(err)
+		return
+	}
+
+	var order = &Order{Price: 40000}
+	sob.merge(order, time.UTC)
+	if order.OrderId != "1458532827766784" || order.Cid != "c-1" || order.Pair.ToSymbol("/", true) != "BTC/EUR" ||
+		order.Side != BUY || order.Status != ORDER_CANCEL || order.DealAmount != 0.1 || order.Amount != 0.5 ||
+		order.AvgPrice != 40000 || order.Fee != 2.4 || order.OrderTimestamp != 1704164645000 ||
+		order.DealTimestamp != 1704164706500 {
+		t.Error("the merged order is wrong: ", *order)
+		return
+	}
+
+	// the open order without the transactions.
+	raw = `{"id":"1458532827766785","datetime":"2024-01-02 03:04:05","type":1,"status":"Open",
+		"market":"BTC/EUR","amount_remaining":"0.2","transactions":[]}`
+	var openSob = spotOrderBS{}
+	if err := json.Unmarshal([]byte(raw), &openSob); err != nil {
+		t.Error(err)
+		return
+	}
+	var open = &Order{}
+	openSob.merge(open, time.UTC)
+	if open.Side != SELL || open.Status != ORDER_UNFINISH || open.Amount != 0.2 || open.DealTimestamp != 0 {
+		t.Error("the open order is wrong: ", *open)
+	}
+}
@@ -0,0 +1,276 @@
+package bitstamp
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	. "github.com/deforceHK/goghostex"
+)
+
+// DiffOrderBook is the data of the diff_order_book channel, the amount 0 means remove the price level.
+type DiffOrderBook struct {
+	Microtimestamp int64       `json:"microtimestamp,string"`
+	Bids           [][2]string `json:"bids"`
+	Asks           [][2]string `json:"asks"`
+}
+
+// LocalOrderBooks maintain the diff_order_book channel on the rest snapshot. There is no sequence in the channel,
+// the diffs are matched to the snapshot by the microtimestamp, and the books are dropped in the new connection
+// cause the diffs in the restart are lost.
+type LocalOrderBooks struct {
+	*WSMarketBS
+	BidData map[string]map[int64]float64
+	AskData map[string]map[int64]float64
+	SeqData map[string]int64 // the microtimestamp of the last diff
+	TsData  map[string]int64
+	Cache   map[string][]*DiffOrderBook
+
+	// if the channel is not nil, send the update message to the channel. User should read the channel in the loop.
+	UpdateChan chan string
+
+	// the receive goroutine, the snapshot goroutine and the user all touch the books, they are guarded by the mux.
+	mux        sync.Mutex
+	ready      map[string]bool // the symbol has the snapshot
+	lastConnId string          // the connection of the last diff
+}
+
+func (this *LocalOrderBooks) Init() error {
+	this.initBooks()
+	this.RecvHandler = func(s string) {
+		this.ReceiveDiff(s)
+	}
+	return this.Start()
+}
+
+func (this *LocalOrderBooks) initBooks() {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	if this.ready == nil {
+		this.ready = make(map[string]bool)
+	}
+	if this.BidData == nil {
+		this.BidData = make(map[string]map[int64]float64)
+	}
+	if this.AskData == nil {
+		this.AskData = make(map[string]map[int64]float64)
+	}
+	if this.SeqData == nil {
+		this.SeqData = make(map[string]int64)
+	}
+	if this.TsData == nil {
+		this.TsData = make(map[string]int64)
+	}
+	if this.Cache == nil {
+		this.Cache = make(map[string][]*DiffOrderBook)
+	}
+}
+
+func (this *LocalOrderBooks) Subscribe(pair Pair) {
+	this.WSMarketBS.Subscribe(NewWSRequestBS("bts:subscribe", "diff_order_book_"+pair.ToSymbol("", false)))
+}
+
+func (this *LocalOrderBooks) Unsubscribe(pair Pair) {
+	this.WSMarketBS.Unsubscribe(NewWSRequestBS("bts:unsubscribe", "diff_order_book_"+pair.ToSymbol("", false)))
+}
+
+// reset drop the local book of the symbol, the next diff will get the snapshot again. The caller must hold the mux.
+func (this *LocalOrderBooks) reset(symbol string) {
+	delete(this.ready, symbol)
+	this.Cache[symbol] = nil
+}
+
+func (this *LocalOrderBooks) ReceiveDiff(msg string) {
+	var diff = struct {
+		Event   string         `json:"event"`
+		Channel string         `json:"channel"`
+		Data    *DiffOrderBook `json:"data"`
+	}{}
+
+	_ = json.Unmarshal([]byte(msg), &diff)
+	if diff.Event != "data" || !strings.HasPrefix(diff.Channel, "diff_order_book_") || diff.Data == nil {
+		this.logger().Debug("receive message", LogF("msg", msg))
+		return
+	}
+
+	var symbol = strings.TrimPrefix(diff.Channel, "diff_order_book_")
+	if !this.applyDiff(symbol, diff.Data) {
+		return
+	}
+	if this.UpdateChan != nil {
+		this.mux.Lock()
+		var ts = this.TsData[symbol]
+		this.mux.Unlock()
+		this.UpdateChan <- fmt.Sprintf("%s:%d", symbol, ts)
+	}
+}
+
+// applyDiff cache the diff until the snapshot is ready, or apply it to the book, it returns true if the book is updated.
+func (this *LocalOrderBooks) applyDiff(symbol string, data *DiffOrderBook) bool {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+
+	// the diffs in the restart are lost, all the books get the snapshot again.
+	if this.lastConnId != this.connId {
+		for readySymbol := range this.ready {
+			this.Config.GetMetrics().IncBookResync(BITSTAMP, readySymbol)
+		}
+		for cachedSymbol := range this.Cache {
+			this.reset(cachedSymbol)
+		}
+		this.lastConnId = this.connId
+	}
+
+	// 如果还没有snapshot，说明还没有申请过snapshot，或者snapshot重置了。
+	if !this.ready[symbol] {
+		if this.Cache[symbol] == nil {
+			this.BidData[symbol] = make(map[int64]float64)
+			this.AskData[symbol] = make(map[int64]float64)
+			this.Cache[symbol] = []*DiffOrderBook{data}
+			go this.getSnapshot(symbol, this.lastConnId, 0)
+		} else {
+			this.Cache[symbol] = append(this.Cache[symbol], data)
+		}
+		return false
+	}
+
+	//	已经有了snapshot，则直接处理diff
+	var diffs = append(this.Cache[symbol], data)
+	this.Cache[symbol] = make([]*DiffOrderBook, 0)
+	for _, d := range diffs {
+		// the diff before the snapshot.
+		if d.Microtimestamp <= this.SeqData[symbol] {
+			continue
+		}
+		for _, bid := range d.Bids {
+			this.BidData[symbol][int64(ToFloat64(bid[0])*100000000)] = ToFloat64(bid[1])
+		}
+		for _, ask := range d.Asks {
+			this.AskData[symbol][int64(ToFloat64(ask[0])*100000000)] = ToFloat64(ask[1])
+		}
+		this.SeqData[symbol] = d.Microtimestamp
+		this.TsData[symbol] = d.Microtimestamp / 1000
+	}
+	return true
+}
+
+// getSnapshot get the rest snapshot out of the mux, and install it only when the connection is not changed,
+// the snapshot of the old connection is dropped, the new connection has got another one.
+func (this *LocalOrderBooks) getSnapshot(symbol, connId string, times int) {
+	if times > 5 {
+		this.Stop()
+		this.ErrorHandler(&WSStopError{
+			Msg: "get snapshot failed, and retry 5 times, stop the websocket. ",
+		})
+		return
+	}
+
+	var snapshot, err = this.getOrderBook(symbol)
+	if err != nil {
+		this.ErrorHandler(err)
+		time.Sleep(5 * time.Second)
+		this.getSnapshot(symbol, connId, times+1)
+		return
+	}
+
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	if this.lastConnId != connId || this.ready[symbol] {
+		return
+	}
+	this.SeqData[symbol] = snapshot.Microtimestamp
+	this.TsData[symbol] = snapshot.Microtimestamp / 1000
+	for _, bid := range snapshot.Bids {
+		this.BidData[symbol][int64(ToFloat64(bid[0])*100000000)] = ToFloat64(bid[1])
+	}
+	for _, ask := range snapshot.Asks {
+		this.AskData[symbol][int64(ToFloat64(ask[0])*100000000)] = ToFloat64(ask[1])
+	}
+	this.ready[symbol] = true
+}
+
+// IsReady return true if the book of the pair has the snapshot.
+func (this *LocalOrderBooks) IsReady(pair Pair) bool {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	return this.ready[pair.ToSymbol("", false)]
+}
+
+// getOrderBook get the rest order book, the microtimestamp is the same clock as the diff_order_book channel.
+func (this *LocalOrderBooks) getOrderBook(symbol string) (*DiffOrderBook, error) {
+	var response = &DiffOrderBook{}
+	var bitstamp = New(this.Config)
+	var _, err = bitstamp.DoRequest(http.MethodGet, fmt.Sprintf(ORDER_BOOK_URI, symbol), "", response)
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (this *LocalOrderBooks) Snapshot(pair Pair) (*Depth, error) {
+	var symbol = pair.ToSymbol("", false)
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	if this.BidData[symbol] == nil || this.AskData[symbol] == nil || !this.ready[symbol] {
+		return nil, fmt.Errorf("The order book data is not ready or you need subscribe the pair. ")
+	}
+
+	var lastTime = time.UnixMilli(this.TsData[symbol]).In(this.Config.Location)
+	this.Config.GetMetrics().SetBookStaleness(BITSTAMP, symbol, time.Since(lastTime))
+	var depth = &Depth{
+		Pair:      pair,
+		Sequence:  this.SeqData[symbol],
+		Timestamp: lastTime.UnixMilli(),
+		Date:      lastTime.Format(GO_BIRTHDAY),
+		AskList:   make(DepthRecords, 0),
+		BidList:   make(DepthRecords, 0),
+	}
+	var zeroCount, sumCount = 0.0, 0.0
+	for stdPrice, amount := range this.BidData[symbol] {
+		if amount > 0 {
+			depth.BidList = append(depth.BidList, DepthRecord{
+				Price:  float64(stdPrice) / 100000000,
+				Amount: amount,
+			})
+		} else {
+			zeroCount++
+		}
+		sumCount++
+	}
+
+	for stdPrice, amount := range this.AskData[symbol] {
+		if amount > 0 {
+			depth.AskList = append(depth.AskList, DepthRecord{
+				Price:  float64(stdPrice) / 100000000,
+				Amount: amount,
+			})
+		} else {
+			zeroCount++
+		}
+		sumCount++
+	}
+	sort.Sort(sort.Reverse(depth.BidList))
+	sort.Sort(depth.AskList)
+
+	// collect the zero amount data
+	if sumCount > 0 && zeroCount/sumCount > 0.3 {
+		for priceKey, amountValue := range this.BidData[symbol] {
+			if amountValue > 0 {
+				continue
+			}
+			delete(this.BidData[symbol], priceKey)
+		}
+		for priceKey, amountValue := range this.AskData[symbol] {
+			if amountValue > 0 {
+				continue
+			}
+			delete(this.AskData[symbol], priceKey)
+		}
+	}
+
+	return depth, nil
+}
@@ -0,0 +1,102 @@
+package bitstamp
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/deforceHK/goghostex"
+)
+
+// go test -v ./bitstamp/... -count=1 -run=TestLocalOrderBooks_Init
+func TestLocalOrderBooks_Init(t *testing.T) {
+	var book = &LocalOrderBooks{
+		WSMarketBS: &WSMarketBS{
+			Config: &APIConfig{
+				Endpoint:   ENDPOINT,
+				HttpClient: &http.Client{},
+				Location:   time.Now().Location(),
+			},
+		},
+	}
+	var err = book.Init()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	var pair = Pair{Basis: BTC, Counter: EUR}
+	book.Subscribe(pair)
+
+	for i := 0; i < 10; i++ {
+		time.Sleep(5 * time.Second)
+		depth, depthErr := book.Snapshot(pair)
+		if depthErr != nil {
+			t.Error(depthErr)
+			return
+		}
+		var depthData, _ = json.Marshal(depth)
+		t.Log(string(depthData))
+	}
+}
+
+// go test -v ./bitstamp/... -count=1 -run=TestLocalOrderBooks_ReceiveDiff
+func TestLocalOrderBooks_ReceiveDiff(t *testing.T) {
+	var server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2/order_book/btceur/" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`{"timestamp":"1700000000","microtimestamp":"1700000000200000",
+			"bids":[["100","5"],["99","1"]],"asks":[["101","7"]]}`))
+	}))
+	defer server.Close()
+
+	var book = &LocalOrderBooks{
+		WSMarketBS: &WSMarketBS{
+			Config: &APIConfig{Endpoint: server.URL, HttpClient: server.Client(), Location: time.UTC},
+		},
+	}
+	book.initBooks()
+	book.initDefaultValue()
+
+	// the diffs are cached until the snapshot, the diff before the snapshot is dropped.
+	book.ReceiveDiff(`{"event":"data","channel":"diff_order_book_btceur","data":{"timestamp":"1700000000",
+		"microtimestamp":"1700000000100000","bids":[["100","0"]],"asks":[]}}`)
+	book.ReceiveDiff(`{"event":"data","channel":"diff_order_book_btceur","data":{"timestamp":"1700000000",
+		"microtimestamp":"1700000000300000","bids":[["99.5","3"]],"asks":[["101","0"]]}}`)
+	for i := 0; i < 20 && !book.IsReady(Pair{Basis: BTC, Counter: EUR}); i++ {
+		time.Sleep(100 * time.Millisecond)
+	}
+	book.ReceiveDiff(`{"event":"data","channel":"diff_order_book_btceur","data":{"timestamp":"1700000000",
+		"microtimestamp":"1700000000400000","bids":[["99","0"]],"asks":[["102","4"]]}}`)
+
+	var depth, err = book.Snapshot(Pair{Basis: BTC, Counter: EUR})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if depth.Sequence != 1700000000400000 || depth.Timestamp != 1700000000400 {
+		t.Error("the sequence is wrong: ", depth.Sequence, depth.Timestamp)
+		return
+	}
+	if len(depth.BidList) != 2 || depth.BidList[0].Price != 100 || depth.BidList[0].Amount != 5 ||
+		depth.BidList[1].Price != 99.5 || depth.BidList[1].Amount != 3 {
+		t.Error("the bids are wrong: ", depth.BidList)
+		return
+	}
+	if len(depth.AskList) != 1 || depth.AskList[0].Price != 102 || depth.AskList[0].Amount != 4 {
+		t.Error("the asks are wrong: ", depth.AskList)
+		return
+	}
+
+	// the diffs in the restart are lost, the book is dropped in the new connection.
+	book.connId = "new-connection"
+	book.ReceiveDiff(`{"event":"data","channel":"diff_order_book_btceur","data":{"timestamp":"1700000001",
+		"microtimestamp":"1700000001000000","bids":[],"asks":[]}}`)
+	if _, err := book.Snapshot(Pair{Basis: BTC, Counter: EUR}); err == nil {
+		t.Error("the book should be reset in the new connection. ")
+	}
+}
@@ -0,0 +1,305 @@
+package bitstamp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	. "github.com/deforceHK/goghostex"
+)
+
+const (
+	WEBSOCKET_ENDPOINT = "wss://ws.bitstamp.net"
+
+	DEFAULT_WEBSOCKET_RESTART_SLEEP_SEC  = 30
+	DEFAULT_WEBSOCKET_PING_SEC           = 20
+	DEFAULT_WEBSOCKET_PENDING_SEC        = 100
+	DERFAULT_WEBSOCKET_RESTART_LIMIT_NUM = 10
+	DERFAULT_WEBSOCKET_RESTART_LIMIT_SEC = 300
+)
+
+// WSRequestBS is the request of the websocket, eg: {"event":"bts:subscribe","data":{"channel":"live_trades_btcusd"}}
+type WSRequestBS struct {
+	Event string `json:"event"`
+	Data  struct {
+		Channel string `json:"channel,omitempty"`
+	} `json:"data"`
+}
+
+func NewWSRequestBS(event, channel string) WSRequestBS {
+	var request = WSRequestBS{Event: event}
+	request.Data.Channel = channel
+	return request
+}
+
+// WSMarketBS is the public websocket of bitstamp. The bts:heartbeat is sent to keep the connection alive, and the
+// connection is restarted when bitstamp send the bts:request_reconnect.
+type WSMarketBS struct {
+	RecvHandler  func(string)
+	ErrorHandler func(error)
+	Config       *APIConfig
+	DialerConfig *WsDialerConfig // the proxy, tls and local address setting of the connection, not necessary
+
+	conn       *websocket.Conn
+	connId     string
+	subscribed []interface{}
+
+	restartSleepSec int
+	restartLimitNum int // In X(restartLimitSec) seconds, the limit times(restartLimitNum) of restart
+	restartLimitSec int // In X(restartLimitSec) seconds, the limit times(restartLimitNum) of restart
+
+	lastPingTS int64
+	restartTS  map[int64]string
+
+	stopChecSign chan bool
+	stopPingSign chan bool
+}
+
+func (this *WSMarketBS) Start() error {
+	this.initDefaultValue()
+	var stopErr = this.startCheck()
+	if stopErr != nil {
+		return stopErr
+	}
+
+	var conn, err = this.getConn(WEBSOCKET_ENDPOINT)
+	if err != nil {
+		if len(this.restartTS) != 0 {
+			this.Restart()
+		}
+		return err
+	}
+	this.conn = conn
+	this.connId = UUID()
+	this.lastPingTS = time.Now().Unix()
+
+	go this.recvRoutine()
+	go this.checkRoutine()
+	go this.pingRoutine()
+	return nil
+}
+
+func (this *WSMarketBS) Subscribe(v interface{}) {
+	var err = this.write(v)
+	if err != nil {
+		this.ErrorHandler(err)
+	}
+	this.subscribed = append(this.subscribed, v)
+}
+
+func (this *WSMarketBS) Unsubscribe(v interface{}) {
+	var err = this.write(v)
+	if err != nil {
+		this.ErrorHandler(err)
+	}
+}
+
+func (this *WSMarketBS) write(v interface{}) error {
+	if this.conn == nil {
+		return errors.New("The websocket is not connected. ")
+	}
+	return this.conn.WriteJSON(v)
+}
+
+func (this *WSMarketBS) Restart() {
+	this.Config.GetMetrics().IncWSReconnect(BITSTAMP, "WSMarketBS")
+	this.ErrorHandler(
+		&WSRestartError{
+			Msg: fmt.Sprintf("bitstamp market websocket will restart in next %d seconds...", this.restartSleepSec),
+		},
+	)
+	this.restartTS[time.Now().Unix()] = this.connId
+	this.Stop()
+
+	time.Sleep(time.Duration(this.restartSleepSec) * time.Second)
+	if err := this.Start(); err != nil {
+		this.ErrorHandler(err)
+		return
+	}
+
+	// subscribe unsubscribe the channel
+	for _, channel := range this.subscribed {
+		var err = this.write(channel)
+		if err != nil {
+			this.ErrorHandler(err)
+			var errMsg, _ = json.Marshal(channel)
+			this.ErrorHandler(fmt.Errorf("subscribe error: %s", string(errMsg)))
+		}
+	}
+}
+
+func (this *WSMarketBS) checkRoutine() {
+	var stopChecChn = make(chan bool, 1)
+	this.stopChecSign = stopChecChn
+
+	var ticker = time.NewTicker(DEFAULT_WEBSOCKET_PENDING_SEC * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			// 超过x秒没有收到消息，重新连接，如果超出重连次数，ws将停止。
+			if time.Now().Unix()-this.lastPingTS > DEFAULT_WEBSOCKET_PENDING_SEC {
+				this.ErrorHandler(fmt.Errorf("ping timeout, last ping ts: %d", this.lastPingTS))
+				this.Restart()
+				continue
+			}
+		case _, opened := <-stopChecChn:
+			if opened {
+				close(stopChecChn)
+			}
+			this.stopChecSign = nil
+			return
+		}
+	}
+}
+
+// pingRoutine send the bts:heartbeat, bitstamp answer it with the bts:heartbeat event.
+func (this *WSMarketBS) pingRoutine() {
+	var stopPingChn = make(chan bool, 1)
+	this.stopPingSign = stopPingChn
+	var ticker = time.NewTicker(DEFAULT_WEBSOCKET_PING_SEC * time.Second)
+	defer ticker.Stop()
+	var conn = this.conn
+
+	for {
+		select {
+		case <-ticker.C:
+			var err = conn.WriteJSON(NewWSRequestBS("bts:heartbeat", ""))
+			if err != nil {
+				this.logger().Warn("ping error", LogF(LOG_FIELD_ERROR, err))
+			}
+		case _, opened := <-stopPingChn:
+			if opened {
+				close(stopPingChn)
+			}
+			this.stopPingSign = nil
+			return
+		}
+	}
+}
+
+func (this *WSMarketBS) recvRoutine() {
+	for {
+		var msgType, msg, readErr = this.conn.ReadMessage()
+		if readErr != nil {
+			// conn closed by user.
+			if strings.Index(readErr.Error(), "use of closed network connection") > 0 {
+				this.logger().Info("conn closed by user")
+				return
+			}
+
+			this.ErrorHandler(readErr)
+			this.Restart()
+			return
+		}
+
+		if msgType != websocket.TextMessage {
+			continue
+		}
+		var event = struct {
+			Event string `json:"event"`
+		}{}
+		_ = json.Unmarshal(msg, &event)
+		this.lastPingTS = time.Now().Unix()
+		this.Config.GetMetrics().IncWSMessage(BITSTAMP, "WSMarketBS")
+		if event.Event == "bts:heartbeat" {
+			continue
+		}
+		// bitstamp ask the client to reconnect before the maintenance of the server.
+		if event.Event == "bts:request_reconnect" {
+			this.Restart()
+			return
+		}
+		this.RecvHandler(string(msg))
+	}
+}
+
+func (this *WSMarketBS) startCheck() error {
+	var restartNum, limitTS = 0, time.Now().Unix() - int64(this.restartLimitSec)
+	for ts := range this.restartTS {
+		if ts > limitTS {
+			restartNum++
+		}
+	}
+	if restartNum > this.restartLimitNum {
+		var wsErr = &WSStopError{
+			Msg: fmt.Sprintf(
+				"The ws restarted %d times in %d seconds, stop the ws",
+				restartNum, this.restartLimitSec,
+			),
+		}
+		this.Config.GetMetrics().IncWSRestartLimit(BITSTAMP, "WSMarketBS")
+		return wsErr
+	}
+	return nil
+}
+
+func (this *WSMarketBS) getConn(wss string) (*websocket.Conn, error) {
+	var dialer, dialErr = NewWsDialer(this.DialerConfig)
+	if dialErr != nil {
+		return nil, dialErr
+	}
+	var conn, _, err = dialer.Dial(
+		wss,
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return conn, nil
+}
+
+func (this *WSMarketBS) initDefaultValue() {
+	if this.RecvHandler == nil {
+		this.RecvHandler = func(msg string) {
+			this.logger().Debug("receive message", LogF("msg", msg))
+		}
+	}
+	if this.ErrorHandler == nil {
+		this.ErrorHandler = func(err error) {
+			this.logger().Error("websocket error", LogF(LOG_FIELD_ERROR, err))
+		}
+	}
+	if this.restartSleepSec == 0 {
+		this.restartSleepSec = DEFAULT_WEBSOCKET_RESTART_SLEEP_SEC
+	}
+
+	if this.restartLimitNum == 0 {
+		this.restartLimitNum = DERFAULT_WEBSOCKET_RESTART_LIMIT_NUM
+	}
+
+	if this.restartLimitSec == 0 {
+		this.restartLimitSec = DERFAULT_WEBSOCKET_RESTART_LIMIT_SEC
+	}
+
+	if this.restartTS == nil {
+		this.restartTS = make(map[int64]string, 0)
+	}
+}
+
+func (this *WSMarketBS) Stop() {
+	if this.stopChecSign != nil {
+		this.stopChecSign <- true
+	}
+	if this.stopPingSign != nil {
+		this.stopPingSign <- true
+	}
+
+	if this.conn != nil {
+		_ = this.conn.Close()
+		this.conn = nil
+	}
+	this.connId = ""
+}
+
+func (this *WSMarketBS) logger() Logger {
+	return this.Config.GetLogger().With(
+		LogF(LOG_FIELD_EXCHANGE, BITSTAMP),
+		LogF(LOG_FIELD_CONN_ID, this.connId),
+	)
+}
@@ -0,0 +1,80 @@
+package bitstamp
+
+import (
+	"encoding/json"
+	"strings"
+
+	. "github.com/deforceHK/goghostex"
+)
+
+// MarketStreams is the typed live_trades stream, the messages of the other channels are ignored.
+type MarketStreams struct {
+	*WSMarketBS
+	TradeHandler func(trade *Trade)
+
+	pairs map[string]Pair // the channel symbol to the pair, eg: btcusd => BTC_USD
+}
+
+type wsTradeBS struct {
+	Id             int64   `json:"id"`
+	Amount         float64 `json:"amount"`
+	Price          float64 `json:"price"`
+	Type           int     `json:"type"` // 0 buy, 1 sell
+	Microtimestamp string  `json:"microtimestamp"`
+}
+
+func (this *MarketStreams) Init() error {
+	if this.pairs == nil {
+		this.pairs = make(map[string]Pair)
+	}
+	if this.TradeHandler == nil {
+		this.TradeHandler = func(trade *Trade) {
+			this.logger().Debug("receive trade", LogF("trade", *trade))
+		}
+	}
+	this.RecvHandler = func(s string) {
+		this.Receiver(s)
+	}
+	return this.Start()
+}
+
+func (this *MarketStreams) SubscribeTrades(pair Pair) {
+	var symbol = pair.ToSymbol("", false)
+	this.pairs[symbol] = pair
+	this.WSMarketBS.Subscribe(NewWSRequestBS("bts:subscribe", "live_trades_"+symbol))
+}
+
+func (this *MarketStreams) UnsubscribeTrades(pair Pair) {
+	this.WSMarketBS.Unsubscribe(NewWSRequestBS("bts:unsubscribe", "live_trades_"+pair.ToSymbol("", false)))
+}
+
+func (this *MarketStreams) Receiver(msg string) {
+	var response = struct {
+		Event   string     `json:"event"`
+		Channel string     `json:"channel"`
+		Data    *wsTradeBS `json:"data"`
+	}{}
+	if err := json.Unmarshal([]byte(msg), &response); err != nil {
+		this.ErrorHandler(err)
+		return
+	}
+
+	var pair, exist = this.pairs[strings.TrimPrefix(response.Channel, "live_trades_")]
+	if response.Event != "trade" || response.Data == nil || !exist {
+		this.logger().Debug("receive message", LogF("msg", msg))
+		return
+	}
+
+	var side = BUY
+	if response.Data.Type == 1 {
+		side = SELL
+	}
+	this.TradeHandler(&Trade{
+		Tid:       response.Data.Id,
+		Type:      side,
+		Amount:    response.Data.Amount,
+		Price:     response.Data.Price,
+		Timestamp: ToInt64(response.Data.Microtimestamp) / 1000,
+		Pair:      pair,
+	})
+}
@@ -0,0 +1,29 @@
+package bitstamp
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/deforceHK/goghostex"
+)
+
+// go test -v ./bitstamp/... -count=1 -run=TestMarketStreams_Receiver
+func TestMarketStreams_Receiver(t *testing.T) {
+	var trades = make([]*Trade, 0)
+	var streams = &MarketStreams{
+		WSMarketBS:   &WSMarketBS{Config: &APIConfig{Location: time.UTC}},
+		TradeHandler: func(trade *Trade) { trades = append(trades, trade) },
+		pairs:        map[string]Pair{"btceur": {Basis: BTC, Counter: EUR}},
+	}
+
+	streams.Receiver(`{"event":"bts:subscription_succeeded","channel":"live_trades_btceur","data":{}}`)
+	streams.Receiver(`{"event":"trade","channel":"live_trades_btceur","data":{"id":123,"timestamp":"1704164645",
+		"amount":0.5,"amount_str":"0.50000000","price":40000,"price_str":"40000","type":1,
+		"microtimestamp":"1704164645900123","buy_order_id":1,"sell_order_id":2}}`)
+
+	if len(trades) != 1 || trades[0].Tid != 123 || trades[0].Type != SELL || trades[0].Amount != 0.5 ||
+		trades[0].Price != 40000 || trades[0].Pair != (Pair{Basis: BTC, Counter: EUR}) ||
+		trades[0].Timestamp != 1704164645900 {
+		t.Error("the trade is wrong: ", trades)
+	}
+}
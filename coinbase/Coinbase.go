@@ -1,7 +1,17 @@
 package coinbase
 
 import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"net/url"
+	"strings"
 	"time"
 
 	. "github.com/deforceHK/goghostex"
@@ -16,11 +26,47 @@ const (
 	APPLICATION_JSON_UTF8 = "application/json; charset=UTF-8"
 
 	ENDPOINT = "https://api.exchange.coinbase.com"
+
+	// the advanced trade api, the private api is signed by the jwt of the cdp api key.
+	ADVANCED_ENDPOINT   = "https://api.coinbase.com"
+	ADVANCED_URI_PREFIX = "/api/v3/brokerage"
+
+	AUTHORIZATION = "Authorization"
+
+	JWT_EXPIRE_SEC = 120
+
+	// the uri templates which have the product id or the order id, eg: BTC-USD.
+	PRODUCT_TICKER_URI  = "/products/%s/ticker"
+	PRODUCT_STATS_URI   = "/products/%s/stats"
+	PRODUCT_CANDLES_URI = "/products/%s/candles"
+
+	ADVANCED_PRODUCT_URI        = "/market/products/%s"
+	ADVANCED_PRODUCT_TICKER_URI = "/market/products/%s/ticker"
+	ADVANCED_ORDERS_BATCH_URI   = "/orders/historical/batch"
+	ADVANCED_ORDERS_FILLS_URI   = "/orders/historical/fills"
+	ADVANCED_ORDER_URI          = "/orders/historical/%s"
 )
 
+// the route label of the rest metrics, the static uri is before the order uri, cause the order uri match it too.
+var _INERNAL_ROUTES = []string{
+	PRODUCT_TICKER_URI,
+	PRODUCT_STATS_URI,
+	PRODUCT_CANDLES_URI,
+}
+
+var _INERNAL_ADVANCED_ROUTES = []string{
+	ADVANCED_PRODUCT_URI,
+	ADVANCED_PRODUCT_TICKER_URI,
+	ADVANCED_ORDERS_BATCH_URI,
+	ADVANCED_ORDERS_FILLS_URI,
+	ADVANCED_ORDER_URI,
+}
+
 type Coinbase struct {
 	config *APIConfig
 	Spot   *Spot
+
+	advancedEndpoint string
 	//Future *Future
 	//Margin *Margin
 	//Wallet *Wallet
@@ -35,8 +81,8 @@ var _INERNAL_KLINE_PERIOD_CONVERTER = map[int]int{
 }
 
 func New(config *APIConfig) *Coinbase {
-	cb := &Coinbase{config: config}
-	cb.Spot = &Spot{cb}
+	cb := &Coinbase{config: config, advancedEndpoint: ADVANCED_ENDPOINT}
+	cb.Spot = &Spot{Coinbase: cb}
 
 	return cb
 }
@@ -49,7 +95,10 @@ func (coinbase *Coinbase) DoRequest(
 ) ([]byte, error) {
 
 	url := coinbase.config.Endpoint + uri
-	resp, err := NewHttpRequest(
+	resp, err := NewHttpRequestWithRoute(
+		coinbase.config.GetMetrics(),
+		COINBASE,
+		RouteOf(uri, _INERNAL_ROUTES),
 		coinbase.config.HttpClient,
 		httpMethod,
 		url,
@@ -82,7 +131,10 @@ func (coinbase *Coinbase) DoRequestHistorical(
 ) ([]byte, error) {
 
 	url := "https://api.pro.coinbase.com" + uri
-	resp, err := NewHttpRequest(
+	resp, err := NewHttpRequestWithRoute(
+		coinbase.config.GetMetrics(),
+		COINBASE,
+		RouteOf(uri, _INERNAL_ROUTES),
 		coinbase.config.HttpClient,
 		httpMethod,
 		url,
@@ -104,3 +156,130 @@ func (coinbase *Coinbase) DoRequestHistorical(
 		return resp, json.Unmarshal(resp, &response)
 	}
 }
+
+// DoAdvancedRequest request the public api of the advanced trade, the uri is after the ADVANCED_URI_PREFIX.
+func (coinbase *Coinbase) DoAdvancedRequest(
+	httpMethod,
+	uri,
+	reqBody string,
+	response interface{},
+) ([]byte, error) {
+	return coinbase.doAdvancedRequest(httpMethod, uri, reqBody, response, map[string]string{
+		CONTENT_TYPE: APPLICATION_JSON_UTF8,
+		ACCEPT:       APPLICATION_JSON,
+	})
+}
+
+// DoSignRequest request the private api of the advanced trade with the jwt, the ApiKey is the cdp key name
+// organizations/{org_id}/apiKeys/{key_id}, and the ApiSecretKey is the pem of the ec private key.
+func (coinbase *Coinbase) DoSignRequest(
+	httpMethod,
+	uri,
+	reqBody string,
+	response interface{},
+) ([]byte, error) {
+	var token, err = coinbase.buildJWT(httpMethod, ADVANCED_URI_PREFIX+uri)
+	if err != nil {
+		return nil, err
+	}
+	return coinbase.doAdvancedRequest(httpMethod, uri, reqBody, response, map[string]string{
+		CONTENT_TYPE:  APPLICATION_JSON_UTF8,
+		ACCEPT:        APPLICATION_JSON,
+		AUTHORIZATION: "Bearer " + token,
+	})
+}
+
+func (coinbase *Coinbase) doAdvancedRequest(
+	httpMethod,
+	uri,
+	reqBody string,
+	response interface{},
+	headers map[string]string,
+) ([]byte, error) {
+	resp, err := NewHttpRequestWithRoute(
+		coinbase.config.GetMetrics(),
+		COINBASE,
+		ADVANCED_URI_PREFIX+RouteOf(uri, _INERNAL_ADVANCED_ROUTES),
+		coinbase.config.HttpClient,
+		httpMethod,
+		coinbase.advancedEndpoint+ADVANCED_URI_PREFIX+uri,
+		reqBody,
+		headers,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	nowTimestamp := time.Now().Unix() * 1000
+	if nowTimestamp > coinbase.config.LastTimestamp {
+		coinbase.config.LastTimestamp = nowTimestamp
+	}
+	return resp, json.Unmarshal(resp, &response)
+}
+
+// buildJWT sign the ES256 jwt of the request, the uri claim is the method, the host and the path without the query.
+func (coinbase *Coinbase) buildJWT(httpMethod, path string) (string, error) {
+	var privateKey, err = coinbase.getPrivateKey()
+	if err != nil {
+		return "", err
+	}
+	var endpoint, parseErr = url.Parse(coinbase.advancedEndpoint)
+	if parseErr != nil {
+		return "", parseErr
+	}
+	if index := strings.IndexByte(path, '?'); index >= 0 {
+		path = path[:index]
+	}
+
+	var nonce = make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	var now = time.Now().Unix()
+	header, _ := json.Marshal(map[string]interface{}{
+		"alg":   "ES256",
+		"kid":   coinbase.config.ApiKey,
+		"nonce": hex.EncodeToString(nonce),
+		"typ":   "JWT",
+	})
+	claims, _ := json.Marshal(map[string]interface{}{
+		"sub": coinbase.config.ApiKey,
+		"iss": "cdp",
+		"nbf": now,
+		"exp": now + JWT_EXPIRE_SEC,
+		"uri": httpMethod + " " + endpoint.Host + path,
+	})
+
+	var signingInput = base64.RawURLEncoding.EncodeToString(header) + "." +
+		base64.RawURLEncoding.EncodeToString(claims)
+	var digest = sha256.Sum256([]byte(signingInput))
+	r, s, err := ecdsa.Sign(rand.Reader, privateKey, digest[:])
+	if err != nil {
+		return "", err
+	}
+	// the jws signature is the fixed length r and s, not the asn.1 der.
+	var signature = make([]byte, 64)
+	r.FillBytes(signature[:32])
+	s.FillBytes(signature[32:])
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// getPrivateKey parse the sec1 or the pkcs8 pem, the escaped newline of the downloaded key is supported.
+func (coinbase *Coinbase) getPrivateKey() (*ecdsa.PrivateKey, error) {
+	var block, _ = pem.Decode([]byte(strings.ReplaceAll(coinbase.config.ApiSecretKey, `\n`, "\n")))
+	if block == nil {
+		return nil, errors.New("The api secret key is not the pem of the ec private key. ")
+	}
+	if key, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	ecKey, ok := key.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("The api secret key is not the ec private key. ")
+	}
+	return ecKey, nil
+}
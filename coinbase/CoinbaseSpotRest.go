@@ -3,6 +3,7 @@ package coinbase
 import (
 	"errors"
 	"fmt"
+	"net/http"
 	"net/url"
 	"strconv"
 	"sync"
@@ -13,6 +14,8 @@ import (
 
 type Spot struct {
 	*Coinbase
+
+	rules sync.Map // pair.String() => *Rule, the cache of GetExchangeRule for PlaceOrder
 }
 
 // public api
@@ -36,13 +39,13 @@ func (spot *Spot) GetTicker(pair Pair) (*Ticker, []byte, error) {
 	var tickerErr, statErr error
 	go func() {
 		defer wg.Done()
-		uri := fmt.Sprintf("/products/%s/ticker", pair.ToSymbol("-", true))
+		uri := fmt.Sprintf(PRODUCT_TICKER_URI, pair.ToSymbol("-", true))
 		tickerResp, tickerErr = spot.DoRequest("GET", uri, "", &t)
 	}()
 
 	go func() {
 		defer wg.Done()
-		uri := fmt.Sprintf("/products/%s/stats", pair.ToSymbol("-", true))
+		uri := fmt.Sprintf(PRODUCT_STATS_URI, pair.ToSymbol("-", true))
 		_, statErr = spot.DoRequest("GET", uri, "", &s)
 	}()
 
@@ -72,8 +75,50 @@ func (spot *Spot) GetTicker(pair Pair) (*Ticker, []byte, error) {
 	return ticker, tickerResp, nil
 }
 
-func (*Spot) GetDepth(pair Pair, size int) (*Depth, []byte, error) {
-	panic("implement me")
+// GetDepth return the product book of the advanced trade, the book has no sequence so it is the timestamp.
+func (spot *Spot) GetDepth(pair Pair, size int) (*Depth, []byte, error) {
+	var params = url.Values{}
+	params.Set("product_id", pair.ToSymbol("-", true))
+	params.Set("limit", fmt.Sprintf("%d", size))
+
+	var response = struct {
+		Pricebook struct {
+			ProductId string `json:"product_id"`
+			Bids      []*struct {
+				Price float64 `json:"price,string"`
+				Size  float64 `json:"size,string"`
+			} `json:"bids"`
+			Asks []*struct {
+				Price float64 `json:"price,string"`
+				Size  float64 `json:"size,string"`
+			} `json:"asks"`
+			Time string `json:"time"`
+		} `json:"pricebook"`
+	}{}
+	resp, err := spot.DoAdvancedRequest(http.MethodGet, "/market/product_book?"+params.Encode(), "", &response)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	var bookTime, parseErr = time.Parse(time.RFC3339Nano, response.Pricebook.Time)
+	if parseErr != nil {
+		bookTime = time.Now()
+	}
+	var depth = &Depth{
+		Pair:      pair,
+		Timestamp: bookTime.UnixNano() / int64(time.Millisecond),
+		Date:      bookTime.In(spot.config.Location).Format(GO_BIRTHDAY),
+		AskList:   make(DepthRecords, 0, len(response.Pricebook.Asks)),
+		BidList:   make(DepthRecords, 0, len(response.Pricebook.Bids)),
+	}
+	depth.Sequence = depth.Timestamp
+	for _, bid := range response.Pricebook.Bids {
+		depth.BidList = append(depth.BidList, DepthRecord{Price: bid.Price, Amount: bid.Size})
+	}
+	for _, ask := range response.Pricebook.Asks {
+		depth.AskList = append(depth.AskList, DepthRecord{Price: ask.Price, Amount: ask.Size})
+	}
+	return depth, resp, nil
 }
 
 func (spot *Spot) GetKlineRecords(pair Pair, period, size, since int) ([]*Kline, []byte, error) {
@@ -87,7 +132,7 @@ func (spot *Spot) GetKlineRecords(pair Pair, period, size, since int) ([]*Kline,
 	}
 
 	uri := fmt.Sprintf(
-		"/products/%s/candles?",
+		PRODUCT_CANDLES_URI+"?",
 		pair.ToSymbol("-", true),
 	)
 
@@ -151,7 +196,7 @@ func (spot *Spot) GetHistoricalCandles(pair Pair, period, size, since int) ([]*K
 	}
 
 	uri := fmt.Sprintf(
-		"/products/%s/candles?",
+		PRODUCT_CANDLES_URI+"?",
 		pair.ToSymbol("-", true),
 	)
 
@@ -204,67 +249,150 @@ func (spot *Spot) GetHistoricalCandles(pair Pair, period, size, since int) ([]*K
 	return GetAscKline(klines), resp, nil
 }
 
-func (*Spot) GetTrades(pair Pair, since int64) ([]*Trade, error) {
-	panic("implement me")
+// GetTrades return the recent trades of the product, the since is the timestamp in ms, 0 means the latest.
+func (spot *Spot) GetTrades(pair Pair, since int64) ([]*Trade, error) {
+	var params = url.Values{}
+	params.Set("limit", "100")
+	if since > 0 {
+		params.Set("start", fmt.Sprintf("%d", since/1000))
+		params.Set("end", fmt.Sprintf("%d", time.Now().Unix()))
+	}
+
+	var response = struct {
+		Trades []*struct {
+			TradeId string  `json:"trade_id"`
+			Price   float64 `json:"price,string"`
+			Size    float64 `json:"size,string"`
+			Time    string  `json:"time"`
+			Side    string  `json:"side"`
+		} `json:"trades"`
+	}{}
+	if _, err := spot.DoAdvancedRequest(
+		http.MethodGet,
+		fmt.Sprintf(ADVANCED_PRODUCT_TICKER_URI+"?%s", pair.ToSymbol("-", true), params.Encode()),
+		"",
+		&response,
+	); err != nil {
+		return nil, err
+	}
+
+	var trades = make([]*Trade, 0, len(response.Trades))
+	for _, item := range response.Trades {
+		var side = BUY
+		if item.Side == "SELL" {
+			side = SELL
+		}
+		var tradeTime, _ = time.Parse(time.RFC3339Nano, item.Time)
+		trades = append(trades, &Trade{
+			Tid:       ToInt64(item.TradeId),
+			Type:      side,
+			Amount:    item.Size,
+			Price:     item.Price,
+			Timestamp: tradeTime.UnixNano() / int64(time.Millisecond),
+			Pair:      pair,
+		})
+	}
+	return trades, nil
 }
 
 func (*Spot) GetExchangeName() string {
 	return COINBASE
 }
 
+// GetExchangeRule return the rule of the product in the advanced trade, the increments are the price and the amount step.
 func (spot *Spot) GetExchangeRule(pair Pair) (*Rule, []byte, error) {
-
-	uri := fmt.Sprintf("/products/%s", pair.ToSymbol("-", true))
-	r := struct {
-		BaseCurrency  string  `json:"base_currency"`
-		BaseIncrement float64 `json:"base_increment,string"`
-		BaseMinSize   float64 `json:"base_min_size,string"`
-
-		QuoteCurrency  string  `json:"quote_currency"`
-		QuoteIncrement float64 `json:"quote_increments,string"`
+	var r = struct {
+		ProductId      string  `json:"product_id"`
+		BaseCurrencyId string  `json:"base_currency_id"`
+		BaseIncrement  float64 `json:"base_increment,string"`
+		BaseMinSize    float64 `json:"base_min_size,string"`
+
+		QuoteCurrencyId string  `json:"quote_currency_id"`
+		QuoteIncrement  float64 `json:"quote_increment,string"`
+		QuoteMinSize    float64 `json:"quote_min_size,string"`
+		PriceIncrement  float64 `json:"price_increment,string"`
 	}{}
 
-	resp, err := spot.DoRequest("GET", uri, "", &r)
+	resp, err := spot.DoAdvancedRequest(http.MethodGet, fmt.Sprintf(ADVANCED_PRODUCT_URI, pair.ToSymbol("-", true)), "", &r)
 	if err != nil {
 		return nil, resp, err
 	}
+	if r.ProductId == "" {
+		return nil, resp, errors.New("Can not find the pair in exchange. ")
+	}
+	var tickSize = r.PriceIncrement
+	if tickSize <= 0 {
+		tickSize = r.QuoteIncrement
+	}
 
 	rule := Rule{
 		Pair:    pair,
-		Base:    NewCurrency(r.BaseCurrency, ""),
-		Counter: NewCurrency(r.QuoteCurrency, ""),
+		Base:    NewCurrency(r.BaseCurrencyId, ""),
+		Counter: NewCurrency(r.QuoteCurrencyId, ""),
 
 		BaseMinSize:      r.BaseMinSize,
 		BasePrecision:    GetPrecision(r.BaseIncrement),
-		CounterPrecision: GetPrecision(r.QuoteIncrement),
+		CounterPrecision: GetPrecision(tickSize),
+		TickSize:         tickSize,
+		LotSize:          r.BaseIncrement,
+		MinNotional:      r.QuoteMinSize,
 	}
 
 	return &rule, resp, nil
 }
 
-// private api
-func (*Spot) PlaceOrder(*Order) ([]byte, error) {
-	panic("implement me")
+// getRule return the cached rule of the pair, the rule is queried at the first time.
+func (spot *Spot) getRule(pair Pair) (*Rule, error) {
+	if rule, exist := spot.rules.Load(pair.String()); exist {
+		return rule.(*Rule), nil
+	}
+	var rule, _, err = spot.GetExchangeRule(pair)
+	if err != nil {
+		return nil, err
+	}
+	spot.rules.Store(pair.String(), rule)
+	return rule, nil
 }
 
-func (*Spot) CancelOrder(*Order) ([]byte, error) {
-	panic("implement me")
-}
+// private api
 
-func (*Spot) GetOrder(*Order) ([]byte, error) {
-	panic("implement me")
+// BatchPlaceOrders place the orders one by one.
+func (spot *Spot) BatchPlaceOrders(orders []*Order) ([]error, []byte, error) {
+	return BatchSequential(len(orders), func(i int) ([]byte, error) {
+		return spot.PlaceOrder(orders[i])
+	})
 }
 
-func (*Spot) GetOrders(pair Pair) ([]*Order, error) {
-	panic("implement me")
+func (spot *Spot) BatchCancelOrders(orders []*Order) ([]error, []byte, error) {
+	return BatchSequential(len(orders), func(i int) ([]byte, error) {
+		return spot.CancelOrder(orders[i])
+	})
 }
 
-func (*Spot) GetUnFinishOrders(pair Pair) ([]*Order, []byte, error) {
-	panic("implement me")
+// AmendOrder cancel the order and place the new one, the OrderId is changed and the queue position is lost.
+func (spot *Spot) AmendOrder(order *Order, newPrice, newAmount float64) ([]byte, error) {
+	if resp, err := spot.CancelOrder(order); err != nil {
+		return resp, err
+	}
+	if newPrice > 0 {
+		order.Price = newPrice
+	}
+	if newAmount > 0 {
+		order.Amount = newAmount
+	}
+	// the client order id can not be reused in coinbase, the new one is generated.
+	order.OrderId, order.Cid = "", ""
+	return spot.PlaceOrder(order)
 }
 
-func (*Spot) GetAccount() (*Account, []byte, error) {
-	panic("implement me")
+// CancelAllOrders cancel the unfinished orders of the pair one by one.
+func (spot *Spot) CancelAllOrders(pair Pair) ([]byte, error) {
+	var orders, resp, err = spot.GetUnFinishOrders(pair)
+	if err != nil {
+		return resp, err
+	}
+	_, resp, err = spot.BatchCancelOrders(orders)
+	return resp, err
 }
 
 // util api
@@ -0,0 +1,402 @@
+package coinbase
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	. "github.com/deforceHK/goghostex"
+)
+
+// the order configuration of the advanced trade, the post only order is the gtc limit order with the post_only.
+var _INERNAL_SPOT_CONFIG_CONVERTER = map[PlaceType]string{
+	NORMAL:     "limit_limit_gtc",
+	ONLY_MAKER: "limit_limit_gtc",
+	FOK:        "limit_limit_fok",
+	IOC:        "sor_limit_ioc",
+	MARKET:     "market_market_ioc",
+}
+
+type spotOrderConfigCB struct {
+	QuoteSize  string `json:"quote_size,omitempty"`
+	BaseSize   string `json:"base_size,omitempty"`
+	LimitPrice string `json:"limit_price,omitempty"`
+	PostOnly   bool   `json:"post_only,omitempty"`
+}
+
+// the order of the advanced trade, the numbers are strings and may be empty.
+type spotOrderCB struct {
+	OrderId            string                        `json:"order_id"`
+	ProductId          string                        `json:"product_id"`
+	Side               string                        `json:"side"` // BUY SELL
+	ClientOrderId      string                        `json:"client_order_id"`
+	Status             string                        `json:"status"`
+	CreatedTime        string                        `json:"created_time"`
+	LastFillTime       string                        `json:"last_fill_time"`
+	FilledSize         string                        `json:"filled_size"`
+	AverageFilledPrice string                        `json:"average_filled_price"`
+	TotalFees          string                        `json:"total_fees"`
+	OrderConfiguration map[string]*spotOrderConfigCB `json:"order_configuration"`
+}
+
+// merge the remote order into the order. The market buy order is placed by the quote size, so the Amount of it is
+// not changed, and the DealAmount is from the filled size.
+func (soc *spotOrderCB) merge(order *Order, location *time.Location) {
+	order.OrderId = soc.OrderId
+	order.Cid = soc.ClientOrderId
+	order.Pair = NewPair(soc.ProductId, "-")
+	order.AvgPrice = ToFloat64(soc.AverageFilledPrice)
+	order.DealAmount = ToFloat64(soc.FilledSize)
+	order.Fee = ToFloat64(soc.TotalFees)
+
+	for key, config := range soc.OrderConfiguration {
+		if config == nil {
+			continue
+		}
+		if key == "market_market_ioc" {
+			order.Side, order.OrderType = BUY_MARKET, MARKET
+			if soc.Side == "SELL" {
+				order.Side = SELL_MARKET
+			}
+			if config.BaseSize != "" {
+				order.Amount = ToFloat64(config.BaseSize)
+			}
+			continue
+		}
+
+		order.Side = BUY
+		if soc.Side == "SELL" {
+			order.Side = SELL
+		}
+		order.Price = ToFloat64(config.LimitPrice)
+		order.Amount = ToFloat64(config.BaseSize)
+		switch key {
+		case "limit_limit_fok":
+			order.OrderType = FOK
+		case "sor_limit_ioc":
+			order.OrderType = IOC
+		default:
+			order.OrderType = NORMAL
+			if config.PostOnly {
+				order.OrderType = ONLY_MAKER
+			}
+		}
+	}
+
+	switch soc.Status {
+	case "OPEN", "PENDING", "QUEUED":
+		order.Status = ORDER_UNFINISH
+		if order.DealAmount > 0 {
+			order.Status = ORDER_PART_FINISH
+		}
+	case "CANCEL_QUEUED":
+		order.Status = ORDER_CANCEL_ING
+	case "FILLED":
+		order.Status = ORDER_FINISH
+	case "CANCELLED", "EXPIRED":
+		order.Status = ORDER_CANCEL
+	default:
+		order.Status = ORDER_FAIL
+	}
+
+	if createdTime, err := time.Parse(time.RFC3339Nano, soc.CreatedTime); err == nil {
+		order.OrderTimestamp = createdTime.UnixNano() / int64(time.Millisecond)
+		order.OrderDate = createdTime.In(location).Format(GO_BIRTHDAY)
+	}
+	if fillTime, err := time.Parse(time.RFC3339Nano, soc.LastFillTime); err == nil && order.Status.IsFinal() {
+		order.DealTimestamp = fillTime.UnixNano() / int64(time.Millisecond)
+		order.DealDatetime = fillTime.In(location).Format(GO_BIRTHDAY)
+	}
+}
+
+func (spot *Spot) GetAccount() (*Account, []byte, error) {
+	var account = &Account{
+		Exchange:    COINBASE,
+		SubAccounts: make(map[string]SubAccount, 0),
+	}
+
+	var params = url.Values{}
+	params.Set("limit", "250")
+	var resp []byte
+	for {
+		var response = struct {
+			Accounts []*struct {
+				Currency         string `json:"currency"`
+				AvailableBalance struct {
+					Value string `json:"value"`
+				} `json:"available_balance"`
+				Hold struct {
+					Value string `json:"value"`
+				} `json:"hold"`
+			} `json:"accounts"`
+			HasNext bool   `json:"has_next"`
+			Cursor  string `json:"cursor"`
+		}{}
+		var err error
+		resp, err = spot.DoSignRequest(http.MethodGet, "/accounts?"+params.Encode(), "", &response)
+		if err != nil {
+			return nil, resp, err
+		}
+
+		for _, item := range response.Accounts {
+			var currency = strings.ToUpper(item.Currency)
+			var subAccount = account.SubAccounts[currency]
+			subAccount.Currency = NewCurrency(currency, "")
+			subAccount.Amount += ToFloat64(item.AvailableBalance.Value)
+			subAccount.AmountFrozen += ToFloat64(item.Hold.Value)
+			account.SubAccounts[currency] = subAccount
+		}
+		if !response.HasNext || response.Cursor == "" {
+			break
+		}
+		params.Set("cursor", response.Cursor)
+	}
+	return account, resp, nil
+}
+
+// PlaceOrder place the order by the normalized price and amount. The market buy order is placed by the quote size,
+// it is converted by the Price of the order, so the Price must be the reference price. The client order id is
+// required in coinbase, it is generated when the Cid is empty.
+func (spot *Spot) PlaceOrder(order *Order) ([]byte, error) {
+	var side = "BUY"
+	if order.Side == SELL || order.Side == SELL_MARKET {
+		side = "SELL"
+	} else if order.Side != BUY && order.Side != BUY_MARKET {
+		return nil, errors.New("Can not deal the order side. ")
+	}
+	var isMarket = order.Side == BUY_MARKET || order.Side == SELL_MARKET || order.OrderType == MARKET
+
+	rule, err := spot.getRule(order.Pair)
+	if err != nil {
+		return nil, err
+	}
+	var normalizer = rule.GetNormalizer()
+	price, amount, err := normalizer.Normalize(order.Price, order.Amount, isMarket)
+	if err != nil {
+		return nil, err
+	}
+
+	var configKey = _INERNAL_SPOT_CONFIG_CONVERTER[MARKET]
+	var config = &spotOrderConfigCB{BaseSize: normalizer.FormatAmount(amount)}
+	if isMarket {
+		if side == "BUY" {
+			if order.Price <= 0 {
+				return nil, &OrderInvalidError{Field: "price", Msg: "the market buy order need the reference price"}
+			}
+			config.BaseSize = ""
+			config.QuoteSize = FloatToString(
+				RoundToStep(amount*order.Price, math.Pow10(-rule.CounterPrecision), ROUND_FLOOR),
+				int64(rule.CounterPrecision),
+			)
+		}
+	} else {
+		var exist bool
+		if configKey, exist = _INERNAL_SPOT_CONFIG_CONVERTER[order.OrderType]; !exist {
+			return nil, errors.New("not support the place type in coinbase. ")
+		}
+		config.LimitPrice = normalizer.FormatPrice(price)
+		config.PostOnly = order.OrderType == ONLY_MAKER
+	}
+
+	if order.Cid == "" {
+		order.Cid = UUID()
+	}
+	var request = struct {
+		ClientOrderId      string                        `json:"client_order_id"`
+		ProductId          string                        `json:"product_id"`
+		Side               string                        `json:"side"`
+		OrderConfiguration map[string]*spotOrderConfigCB `json:"order_configuration"`
+	}{
+		ClientOrderId:      order.Cid,
+		ProductId:          order.Pair.ToSymbol("-", true),
+		Side:               side,
+		OrderConfiguration: map[string]*spotOrderConfigCB{configKey: config},
+	}
+	reqBody, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	var now = time.Now()
+	order.PlaceTimestamp = now.UnixNano() / int64(time.Millisecond)
+	order.PlaceDatetime = now.In(spot.config.Location).Format(GO_BIRTHDAY)
+	var response = struct {
+		Success         bool `json:"success"`
+		SuccessResponse struct {
+			OrderId string `json:"order_id"`
+		} `json:"success_response"`
+	}{}
+	resp, err := spot.DoSignRequest(http.MethodPost, "/orders", string(reqBody), &response)
+	if err != nil {
+		return resp, err
+	}
+	if !response.Success || response.SuccessResponse.OrderId == "" {
+		return resp, errors.New(string(resp))
+	}
+	order.OrderId = response.SuccessResponse.OrderId
+	order.Price, order.Amount = price, amount
+	order.Status = ORDER_UNFINISH
+	return resp, nil
+}
+
+// CancelOrder cancel the order and query it for the deal amount, the status is canceling if the query failed.
+func (spot *Spot) CancelOrder(order *Order) ([]byte, error) {
+	if order.OrderId == "" {
+		return nil, errors.New("The orderid is empty. ")
+	}
+	reqBody, _ := json.Marshal(map[string][]string{"order_ids": {order.OrderId}})
+
+	var response = struct {
+		Results []*struct {
+			Success       bool   `json:"success"`
+			FailureReason string `json:"failure_reason"`
+			OrderId       string `json:"order_id"`
+		} `json:"results"`
+	}{}
+	resp, err := spot.DoSignRequest(http.MethodPost, "/orders/batch_cancel", string(reqBody), &response)
+	if err != nil {
+		return resp, err
+	}
+	if len(response.Results) == 0 || !response.Results[0].Success {
+		return resp, errors.New(string(resp))
+	}
+
+	if _, err := spot.GetOrder(order); err != nil {
+		order.Status = ORDER_CANCEL_ING
+	}
+	return resp, nil
+}
+
+func (spot *Spot) GetOrder(order *Order) ([]byte, error) {
+	if order.OrderId == "" {
+		return nil, errors.New("The orderid is empty. ")
+	}
+
+	var response = struct {
+		Order *spotOrderCB `json:"order"`
+	}{}
+	resp, err := spot.DoSignRequest(http.MethodGet, fmt.Sprintf(ADVANCED_ORDER_URI, order.OrderId), "", &response)
+	if err != nil {
+		return resp, err
+	}
+	if response.Order == nil || response.Order.OrderId == "" {
+		return resp, errors.New(string(resp))
+	}
+	response.Order.merge(order, spot.config.Location)
+	return resp, nil
+}
+
+// getOrders return the orders of the status, all the pages are queried when the allPages is true.
+func (spot *Spot) getOrders(pair Pair, statuses []string, allPages bool) ([]*Order, []byte, error) {
+	var params = url.Values{}
+	params.Set("product_ids", pair.ToSymbol("-", true))
+	for _, status := range statuses {
+		params.Add("order_status", status)
+	}
+	params.Set("limit", "100")
+
+	var orders = make([]*Order, 0)
+	var resp []byte
+	for {
+		var response = struct {
+			Orders  []*spotOrderCB `json:"orders"`
+			HasNext bool           `json:"has_next"`
+			Cursor  string         `json:"cursor"`
+		}{}
+		var err error
+		resp, err = spot.DoSignRequest(http.MethodGet, ADVANCED_ORDERS_BATCH_URI+"?"+params.Encode(), "", &response)
+		if err != nil {
+			return nil, resp, err
+		}
+
+		for _, item := range response.Orders {
+			var order = &Order{}
+			item.merge(order, spot.config.Location)
+			orders = append(orders, order)
+		}
+		if !allPages || !response.HasNext || response.Cursor == "" {
+			break
+		}
+		params.Set("cursor", response.Cursor)
+	}
+	return orders, resp, nil
+}
+
+// GetOrders return the latest finished orders of the pair.
+func (spot *Spot) GetOrders(pair Pair) ([]*Order, error) {
+	var orders, _, err = spot.getOrders(pair, []string{"FILLED", "CANCELLED", "EXPIRED"}, false)
+	return orders, err
+}
+
+func (spot *Spot) GetUnFinishOrders(pair Pair) ([]*Order, []byte, error) {
+	return spot.getOrders(pair, []string{"OPEN"}, true)
+}
+
+// Fill is the deal of the order in coinbase, the Fee is the commission in the counter currency.
+type Fill struct {
+	TradeId   string
+	OrderId   string
+	Pair      Pair
+	Side      TradeSide
+	Price     float64
+	Amount    float64
+	Fee       float64
+	IsMaker   bool
+	Timestamp int64
+	Date      string
+}
+
+// GetFills return the fills of the order.
+func (spot *Spot) GetFills(order *Order) ([]*Fill, []byte, error) {
+	if order.OrderId == "" {
+		return nil, nil, errors.New("The orderid is empty. ")
+	}
+	var params = url.Values{}
+	params.Set("order_ids", order.OrderId)
+	params.Set("limit", "100")
+
+	var response = struct {
+		Fills []*struct {
+			TradeId            string `json:"trade_id"`
+			OrderId            string `json:"order_id"`
+			TradeTime          string `json:"trade_time"`
+			Price              string `json:"price"`
+			Size               string `json:"size"`
+			Commission         string `json:"commission"`
+			ProductId          string `json:"product_id"`
+			LiquidityIndicator string `json:"liquidity_indicator"`
+			Side               string `json:"side"`
+		} `json:"fills"`
+	}{}
+	resp, err := spot.DoSignRequest(http.MethodGet, ADVANCED_ORDERS_FILLS_URI+"?"+params.Encode(), "", &response)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	var fills = make([]*Fill, 0, len(response.Fills))
+	for _, item := range response.Fills {
+		var side = BUY
+		if item.Side == "SELL" {
+			side = SELL
+		}
+		var tradeTime, _ = time.Parse(time.RFC3339Nano, item.TradeTime)
+		fills = append(fills, &Fill{
+			TradeId:   item.TradeId,
+			OrderId:   item.OrderId,
+			Pair:      NewPair(item.ProductId, "-"),
+			Side:      side,
+			Price:     ToFloat64(item.Price),
+			Amount:    ToFloat64(item.Size),
+			Fee:       ToFloat64(item.Commission),
+			IsMaker:   item.LiquidityIndicator == "MAKER",
+			Timestamp: tradeTime.UnixNano() / int64(time.Millisecond),
+			Date:      tradeTime.In(spot.config.Location).Format(GO_BIRTHDAY),
+		})
+	}
+	return fills, resp, nil
+}
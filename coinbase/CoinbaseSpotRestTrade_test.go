@@ -0,0 +1,148 @@
+package coinbase
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/deforceHK/goghostex"
+)
+
+// go test -v ./coinbase/... -count=1 -run=TestSpot_TradeAPI
+func TestSpot_TradeAPI(t *testing.T) {
+	var cb = New(&APIConfig{
+		Endpoint: ENDPOINT,
+		HttpClient: &http.Client{
+			Transport: &http.Transport{
+				Proxy: func(req *http.Request) (*url.URL, error) {
+					return url.Parse(SPOT_PROXY_URL)
+				},
+			},
+		},
+		ApiKey:       SPOT_API_KEY,
+		ApiSecretKey: SPOT_API_SECRETKEY,
+		Location:     time.Now().Location(),
+	})
+
+	var account, _, err = cb.Spot.GetAccount()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	fmt.Println(account.SubAccounts)
+
+	var orders, _, ordersErr = cb.Spot.GetUnFinishOrders(Pair{Basis: BTC, Counter: USD})
+	if ordersErr != nil {
+		t.Error(ordersErr)
+		return
+	}
+	for _, order := range orders {
+		fmt.Println(*order)
+	}
+}
+
+// go test -v ./coinbase/... -count=1 -run=TestCoinbase_BuildJWT
+func TestCoinbase_BuildJWT(t *testing.T) {
+	var privateKey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	var der, _ = x509.MarshalECPrivateKey(privateKey)
+	var secret = string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}))
+	var cb = New(&APIConfig{
+		ApiKey:       "organizations/org/apiKeys/key",
+		ApiSecretKey: strings.ReplaceAll(secret, "\n", `\n`),
+		Location:     time.UTC,
+	})
+
+	token, err := cb.buildJWT(http.MethodGet, ADVANCED_URI_PREFIX+"/orders/historical/batch?product_ids=BTC-USD")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	var parts = strings.Split(token, ".")
+	if len(parts) != 3 {
+		t.Error("the jwt is wrong: ", token)
+		return
+	}
+	var digest = sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	var signature, _ = base64.RawURLEncoding.DecodeString(parts[2])
+	if len(signature) != 64 || !ecdsa.Verify(
+		&privateKey.PublicKey, digest[:], new(big.Int).SetBytes(signature[:32]), new(big.Int).SetBytes(signature[32:]),
+	) {
+		t.Error("the jwt signature is wrong. ")
+		return
+	}
+
+	var header, claims = make(map[string]interface{}), make(map[string]interface{})
+	var headerRaw, _ = base64.RawURLEncoding.DecodeString(parts[0])
+	var claimsRaw, _ = base64.RawURLEncoding.DecodeString(parts[1])
+	_ = json.Unmarshal(headerRaw, &header)
+	_ = json.Unmarshal(claimsRaw, &claims)
+	if header["alg"] != "ES256" || header["kid"] != "organizations/org/apiKeys/key" || header["nonce"] == "" {
+		t.Error("the jwt header is wrong: ", header)
+		return
+	}
+	var endpoint, _ = url.Parse(ADVANCED_ENDPOINT)
+	if claims["iss"] != "cdp" || claims["sub"] != "organizations/org/apiKeys/key" ||
+		ToFloat64(claims["exp"])-ToFloat64(claims["nbf"]) != JWT_EXPIRE_SEC ||
+		claims["uri"] != "GET "+endpoint.Host+ADVANCED_URI_PREFIX+"/orders/historical/batch" {
+		t.Error("the jwt claims are wrong: ", claims)
+	}
+}
+
+// go test -v ./coinbase/... -count=1 -run=TestSpotOrderCB_Merge
+func TestSpotOrderCB_Merge(t *testing.T) {
+	var response = struct {
+		Order *spotOrderCB `json:"order"`
+	}{}
+	var raw = `{"order":{"order_id":"o-1","product_id":"BTC-USD","side":"BUY",
+		"client_order_id":"c-1","status":"OPEN","created_time":"2024-01-02T03:04:05.678Z",
+		"filled_size":"0.1","average_filled_price":"40000","total_fees":"2.4",
+		"order_configuration":{"limit_limit_gtc":{"base_size":"0.5","limit_price":"40000","post_only":true}}}}`
+	if err := json.Unmarshal([]byte(raw), &response); err != nil {
+		t.Error(err)
+		return
+	}
+	var order = &Order{}
+	response.Order.merge(order, time.UTC)
+	if order.OrderId != "o-1" || order.Cid != "c-1" || order.Pair.ToSymbol("-", true) != "BTC-USD" ||
+		order.Side != BUY || order.OrderType != ONLY_MAKER || order.Status != ORDER_PART_FINISH ||
+		order.Price != 40000 || order.Amount != 0.5 || order.DealAmount != 0.1 || order.Fee != 2.4 ||
+		order.OrderTimestamp != 1704164645678 {
+		t.Error("the limit order is wrong: ", *order)
+		return
+	}
+
+	// the market buy order is placed by the quote size, the amount is not changed.
+	raw = `{"order":{"order_id":"o-2","product_id":"BTC-USD","side":"BUY","status":"FILLED",
+		"created_time":"2024-01-02T03:04:05Z","last_fill_time":"2024-01-02T03:04:06.5Z",
+		"filled_size":"0.01","average_filled_price":"40000","total_fees":"0.24",
+		"order_configuration":{"market_market_ioc":{"quote_size":"400"}}}}`
+	var marketResponse = struct {
+		Order *spotOrderCB `json:"order"`
+	}{}
+	if err := json.Unmarshal([]byte(raw), &marketResponse); err != nil {
+		t.Error(err)
+		return
+	}
+	var market = &Order{Amount: 0.01}
+	marketResponse.Order.merge(market, time.UTC)
+	if market.Side != BUY_MARKET || market.OrderType != MARKET || market.Status != ORDER_FINISH ||
+		market.Amount != 0.01 || market.DealAmount != 0.01 || market.DealTimestamp != 1704164646500 {
+		t.Error("the market order is wrong: ", *market)
+	}
+}
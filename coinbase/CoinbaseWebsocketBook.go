@@ -0,0 +1,250 @@
+package coinbase
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	. "github.com/deforceHK/goghostex"
+)
+
+type L2Update struct {
+	Side        string `json:"side"` // bid offer
+	PriceLevel  string `json:"price_level"`
+	NewQuantity string `json:"new_quantity"`
+}
+
+type L2Event struct {
+	Type      string      `json:"type"` // snapshot update
+	ProductId string      `json:"product_id"`
+	Updates   []*L2Update `json:"updates"`
+}
+
+// LocalOrderBooks maintain the level2 channel. The sequence_num is continuous in all the messages of the connection,
+// so the gap drop all the books and resubscribe them, the books are ready again after the snapshot events.
+type LocalOrderBooks struct {
+	*WSMarketCB
+	BidData       map[string]map[int64]float64
+	AskData       map[string]map[int64]float64
+	SeqData       map[string]int64
+	TsData        map[string]int64
+	OrderBookMuxs map[string]*sync.Mutex
+
+	// if the channel is not nil, send the update message to the channel. User should read the channel in the loop.
+	UpdateChan chan string
+
+	lastSeq int64 // the sequence_num of the last message in the connection
+}
+
+func (this *LocalOrderBooks) Init() error {
+	if this.OrderBookMuxs == nil {
+		this.OrderBookMuxs = make(map[string]*sync.Mutex)
+	}
+	if this.BidData == nil {
+		this.BidData = make(map[string]map[int64]float64)
+	}
+	if this.AskData == nil {
+		this.AskData = make(map[string]map[int64]float64)
+	}
+	if this.SeqData == nil {
+		this.SeqData = make(map[string]int64)
+	}
+	if this.TsData == nil {
+		this.TsData = make(map[string]int64)
+	}
+
+	this.RecvHandler = func(s string) {
+		this.Receiver(s)
+	}
+	return this.Start()
+}
+
+func (this *LocalOrderBooks) Subscribe(pair Pair) {
+	this.WSMarketCB.Subscribe(WSRequestCB{"subscribe", []string{pair.ToSymbol("-", true)}, "level2"})
+}
+
+func (this *LocalOrderBooks) Unsubscribe(pair Pair) {
+	this.WSMarketCB.Unsubscribe(WSRequestCB{"unsubscribe", []string{pair.ToSymbol("-", true)}, "level2"})
+}
+
+func (this *LocalOrderBooks) Receiver(msg string) {
+	var response = struct {
+		Channel     string     `json:"channel"`
+		Timestamp   string     `json:"timestamp"`
+		SequenceNum int64      `json:"sequence_num"`
+		Events      []*L2Event `json:"events"`
+	}{}
+	if err := json.Unmarshal([]byte(msg), &response); err != nil {
+		this.ErrorHandler(err)
+		return
+	}
+
+	// the sequence_num start from 0 in the new connection.
+	var lastSeq = this.lastSeq
+	this.lastSeq = response.SequenceNum
+	if response.SequenceNum != 0 && response.SequenceNum != lastSeq+1 {
+		this.resync()
+		return
+	}
+
+	if response.Channel != "l2_data" {
+		this.logger().Debug("receive message", LogF("msg", msg))
+		return
+	}
+
+	var msgTime, _ = time.Parse(time.RFC3339Nano, response.Timestamp)
+	var timestamp = msgTime.UnixNano() / int64(time.Millisecond)
+	for _, event := range response.Events {
+		if event.Type == "snapshot" {
+			this.recvSnapshot(event, response.SequenceNum, timestamp)
+		} else if this.recvUpdate(event, response.SequenceNum, timestamp) && this.UpdateChan != nil {
+			this.UpdateChan <- fmt.Sprintf("%s:%d", event.ProductId, timestamp)
+		}
+	}
+}
+
+func (this *LocalOrderBooks) recvSnapshot(event *L2Event, seq, timestamp int64) {
+	var _, exist = this.OrderBookMuxs[event.ProductId]
+	if !exist {
+		this.OrderBookMuxs[event.ProductId] = &sync.Mutex{}
+	}
+
+	var mux = this.OrderBookMuxs[event.ProductId]
+	mux.Lock()
+	defer mux.Unlock()
+
+	var bidData = make(map[int64]float64)
+	var askData = make(map[int64]float64)
+	for _, update := range event.Updates {
+		var stdPrice = int64(ToFloat64(update.PriceLevel) * 100000000)
+		if update.Side == "bid" {
+			bidData[stdPrice] = ToFloat64(update.NewQuantity)
+		} else {
+			askData[stdPrice] = ToFloat64(update.NewQuantity)
+		}
+	}
+
+	this.BidData[event.ProductId] = bidData
+	this.AskData[event.ProductId] = askData
+	this.SeqData[event.ProductId] = seq
+	this.TsData[event.ProductId] = timestamp
+}
+
+// recvUpdate return false when the book is not ready, the update before the snapshot is dropped.
+func (this *LocalOrderBooks) recvUpdate(event *L2Event, seq, timestamp int64) bool {
+	var mux, exist = this.OrderBookMuxs[event.ProductId]
+	if !exist {
+		return false
+	}
+
+	mux.Lock()
+	defer mux.Unlock()
+	if this.BidData[event.ProductId] == nil || this.AskData[event.ProductId] == nil {
+		return false
+	}
+	for _, update := range event.Updates {
+		var stdPrice = int64(ToFloat64(update.PriceLevel) * 100000000)
+		if update.Side == "bid" {
+			this.BidData[event.ProductId][stdPrice] = ToFloat64(update.NewQuantity)
+		} else {
+			this.AskData[event.ProductId][stdPrice] = ToFloat64(update.NewQuantity)
+		}
+	}
+	this.SeqData[event.ProductId] = seq
+	this.TsData[event.ProductId] = timestamp
+	return true
+}
+
+// resync drop all the books and resubscribe the level2 channel for the new snapshots.
+func (this *LocalOrderBooks) resync() {
+	var productIds = make([]string, 0, len(this.OrderBookMuxs))
+	for productId, mux := range this.OrderBookMuxs {
+		this.Config.GetMetrics().IncBookResync(COINBASE, productId)
+		mux.Lock()
+		this.BidData[productId] = nil
+		this.AskData[productId] = nil
+		mux.Unlock()
+		productIds = append(productIds, productId)
+	}
+	if len(productIds) == 0 {
+		return
+	}
+
+	if err := this.write(WSRequestCB{"unsubscribe", productIds, "level2"}); err != nil {
+		this.ErrorHandler(err)
+		return
+	}
+	if err := this.write(WSRequestCB{"subscribe", productIds, "level2"}); err != nil {
+		this.ErrorHandler(err)
+	}
+}
+
+func (this *LocalOrderBooks) Snapshot(pair Pair) (*Depth, error) {
+	var productId = pair.ToSymbol("-", true)
+	var mux = this.OrderBookMuxs[productId]
+	if mux == nil {
+		return nil, fmt.Errorf("The order book data is not ready or you need subscribe the productid. ")
+	}
+
+	mux.Lock()
+	defer mux.Unlock()
+	if this.BidData[productId] == nil || this.AskData[productId] == nil {
+		return nil, fmt.Errorf("The order book data is not ready or you need subscribe the productid. ")
+	}
+
+	var lastTime = time.UnixMilli(this.TsData[productId]).In(this.Config.Location)
+	this.Config.GetMetrics().SetBookStaleness(COINBASE, productId, time.Since(lastTime))
+	var depth = &Depth{
+		Pair:      pair,
+		Timestamp: lastTime.UnixMilli(),
+		Sequence:  this.SeqData[productId],
+		Date:      lastTime.Format(GO_BIRTHDAY),
+		AskList:   make(DepthRecords, 0),
+		BidList:   make(DepthRecords, 0),
+	}
+	var zeroCount, sumCount = 0.0, 0.0
+	for stdPrice, amount := range this.BidData[productId] {
+		if amount > 0 {
+			depth.BidList = append(depth.BidList, DepthRecord{
+				Price:  float64(stdPrice) / 100000000,
+				Amount: amount,
+			})
+		} else {
+			zeroCount++
+		}
+		sumCount++
+	}
+
+	for stdPrice, amount := range this.AskData[productId] {
+		if amount > 0 {
+			depth.AskList = append(depth.AskList, DepthRecord{
+				Price:  float64(stdPrice) / 100000000,
+				Amount: amount,
+			})
+		} else {
+			zeroCount++
+		}
+		sumCount++
+	}
+	sort.Sort(sort.Reverse(depth.BidList))
+	sort.Sort(depth.AskList)
+
+	// collect the zero amount data
+	if sumCount > 0 && zeroCount/sumCount > 0.3 {
+		for stdPrice, amount := range this.BidData[productId] {
+			if amount > 0 {
+				continue
+			}
+			delete(this.BidData[productId], stdPrice)
+		}
+		for stdPrice, amount := range this.AskData[productId] {
+			if amount > 0 {
+				continue
+			}
+			delete(this.AskData[productId], stdPrice)
+		}
+	}
+	return depth, nil
+}
@@ -0,0 +1,96 @@
+package coinbase
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/deforceHK/goghostex"
+)
+
+// go test -v ./coinbase/... -count=1 -run=TestLocalOrderBooks_Init
+func TestLocalOrderBooks_Init(t *testing.T) {
+	var book = &LocalOrderBooks{
+		WSMarketCB: &WSMarketCB{
+			Config: &APIConfig{
+				Endpoint:   ENDPOINT,
+				HttpClient: &http.Client{},
+				Location:   time.Now().Location(),
+			},
+		},
+	}
+	var err = book.Init()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	var pair = Pair{Basis: BTC, Counter: USD}
+	book.Subscribe(pair)
+
+	for i := 0; i < 10; i++ {
+		time.Sleep(5 * time.Second)
+		depth, depthErr := book.Snapshot(pair)
+		if depthErr != nil {
+			t.Error(depthErr)
+			return
+		}
+		var depthData, _ = json.Marshal(depth)
+		t.Log(string(depthData))
+	}
+}
+
+// go test -v ./coinbase/... -count=1 -run=TestLocalOrderBooks_Receiver
+func TestLocalOrderBooks_Receiver(t *testing.T) {
+	var errs = make([]error, 0)
+	var book = &LocalOrderBooks{
+		WSMarketCB: &WSMarketCB{
+			Config:       &APIConfig{Location: time.UTC},
+			ErrorHandler: func(err error) { errs = append(errs, err) },
+		},
+		BidData:       map[string]map[int64]float64{},
+		AskData:       map[string]map[int64]float64{},
+		SeqData:       map[string]int64{},
+		TsData:        map[string]int64{},
+		OrderBookMuxs: map[string]*sync.Mutex{},
+	}
+	var pair = Pair{Basis: BTC, Counter: USD}
+
+	book.Receiver(`{"channel":"heartbeats","timestamp":"2024-01-02T03:04:05Z","sequence_num":0,"events":[]}`)
+	book.Receiver(`{"channel":"l2_data","timestamp":"2024-01-02T03:04:06Z","sequence_num":1,"events":[
+		{"type":"snapshot","product_id":"BTC-USD","updates":[
+			{"side":"bid","price_level":"100","new_quantity":"1"},{"side":"bid","price_level":"99","new_quantity":"2"},
+			{"side":"offer","price_level":"101","new_quantity":"3"}]}]}`)
+	book.Receiver(`{"channel":"l2_data","timestamp":"2024-01-02T03:04:07Z","sequence_num":2,"events":[
+		{"type":"update","product_id":"BTC-USD","updates":[
+			{"side":"bid","price_level":"100","new_quantity":"0"},{"side":"offer","price_level":"102","new_quantity":"4"}]}]}`)
+
+	var depth, err = book.Snapshot(pair)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if depth.Sequence != 2 || depth.Timestamp != 1704164647000 {
+		t.Error("the sequence is wrong: ", depth.Sequence, depth.Timestamp)
+		return
+	}
+	if len(depth.BidList) != 1 || depth.BidList[0].Price != 99 || len(depth.AskList) != 2 ||
+		depth.AskList[0].Price != 101 || depth.AskList[1].Amount != 4 {
+		t.Error("the depth is wrong: ", depth.BidList, depth.AskList)
+		return
+	}
+
+	// the gap drop the book, and the resubscribe fail without the connection.
+	book.Receiver(`{"channel":"heartbeats","timestamp":"2024-01-02T03:04:08Z","sequence_num":4,"events":[]}`)
+	if _, err := book.Snapshot(pair); err == nil || len(errs) != 1 {
+		t.Error("the book should be dropped after the gap: ", errs)
+		return
+	}
+	book.Receiver(`{"channel":"l2_data","timestamp":"2024-01-02T03:04:09Z","sequence_num":5,"events":[
+		{"type":"update","product_id":"BTC-USD","updates":[{"side":"bid","price_level":"98","new_quantity":"1"}]}]}`)
+	if _, err := book.Snapshot(pair); err == nil {
+		t.Error("the update before the snapshot should be dropped. ")
+	}
+}
@@ -0,0 +1,264 @@
+package coinbase
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	. "github.com/deforceHK/goghostex"
+)
+
+const (
+	WEBSOCKET_ENDPOINT = "wss://advanced-trade-ws.coinbase.com"
+
+	DEFAULT_WEBSOCKET_RESTART_SLEEP_SEC  = 30
+	DEFAULT_WEBSOCKET_PENDING_SEC        = 100
+	DERFAULT_WEBSOCKET_RESTART_LIMIT_NUM = 10
+	DERFAULT_WEBSOCKET_RESTART_LIMIT_SEC = 300
+)
+
+// WSRequestCB is the subscribe and the unsubscribe request, the product ids are the coinbase product, eg: BTC-USD.
+type WSRequestCB struct {
+	Type       string   `json:"type"`
+	ProductIds []string `json:"product_ids,omitempty"`
+	Channel    string   `json:"channel"`
+}
+
+// WSMarketCB is the public websocket of the advanced trade. The heartbeats channel is subscribed to keep the
+// connection alive, its messages are passed to the RecvHandler too, cause the sequence_num is continuous in
+// all the messages of the connection.
+type WSMarketCB struct {
+	RecvHandler  func(string)
+	ErrorHandler func(error)
+	Config       *APIConfig
+	DialerConfig *WsDialerConfig // the proxy, tls and local address setting of the connection, not necessary
+
+	conn       *websocket.Conn
+	connId     string
+	subscribed []interface{}
+
+	restartSleepSec int
+	restartLimitNum int // In X(restartLimitSec) seconds, the limit times(restartLimitNum) of restart
+	restartLimitSec int // In X(restartLimitSec) seconds, the limit times(restartLimitNum) of restart
+
+	lastPingTS int64
+	restartTS  map[int64]string
+
+	stopChecSign chan bool
+}
+
+func (this *WSMarketCB) Start() error {
+	this.initDefaultValue()
+	var stopErr = this.startCheck()
+	if stopErr != nil {
+		return stopErr
+	}
+
+	var conn, err = this.getConn(WEBSOCKET_ENDPOINT)
+	if err != nil {
+		if len(this.restartTS) != 0 {
+			this.Restart()
+		}
+		return err
+	}
+	this.conn = conn
+	this.connId = UUID()
+	this.lastPingTS = time.Now().Unix()
+
+	err = this.write(WSRequestCB{Type: "subscribe", Channel: "heartbeats"})
+	if err != nil {
+		if len(this.restartTS) != 0 {
+			this.Restart()
+		}
+		return err
+	}
+
+	go this.recvRoutine()
+	go this.checkRoutine()
+	return nil
+}
+
+func (this *WSMarketCB) Subscribe(v interface{}) {
+	var err = this.write(v)
+	if err != nil {
+		this.ErrorHandler(err)
+	}
+	this.subscribed = append(this.subscribed, v)
+}
+
+func (this *WSMarketCB) Unsubscribe(v interface{}) {
+	var err = this.write(v)
+	if err != nil {
+		this.ErrorHandler(err)
+	}
+}
+
+func (this *WSMarketCB) write(v interface{}) error {
+	if this.conn == nil {
+		return errors.New("The websocket is not connected. ")
+	}
+	return this.conn.WriteJSON(v)
+}
+
+func (this *WSMarketCB) Restart() {
+	this.Config.GetMetrics().IncWSReconnect(COINBASE, "WSMarketCB")
+	this.ErrorHandler(
+		&WSRestartError{
+			Msg: fmt.Sprintf("cb market websocket will restart in next %d seconds...", this.restartSleepSec),
+		},
+	)
+	this.restartTS[time.Now().Unix()] = this.connId
+	this.Stop()
+
+	time.Sleep(time.Duration(this.restartSleepSec) * time.Second)
+	if err := this.Start(); err != nil {
+		this.ErrorHandler(err)
+		return
+	}
+
+	// subscribe unsubscribe the channel
+	for _, channel := range this.subscribed {
+		var err = this.write(channel)
+		if err != nil {
+			this.ErrorHandler(err)
+			var errMsg, _ = json.Marshal(channel)
+			this.ErrorHandler(fmt.Errorf("subscribe error: %s", string(errMsg)))
+		}
+	}
+}
+
+func (this *WSMarketCB) checkRoutine() {
+	var stopChecChn = make(chan bool, 1)
+	this.stopChecSign = stopChecChn
+
+	var ticker = time.NewTicker(DEFAULT_WEBSOCKET_PENDING_SEC * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			// 超过x秒没有收到消息，重新连接，如果超出重连次数，ws将停止。
+			if time.Now().Unix()-this.lastPingTS > DEFAULT_WEBSOCKET_PENDING_SEC {
+				this.ErrorHandler(fmt.Errorf("ping timeout, last ping ts: %d", this.lastPingTS))
+				this.Restart()
+				continue
+			}
+		case _, opened := <-stopChecChn:
+			if opened {
+				close(stopChecChn)
+			}
+			this.stopChecSign = nil
+			return
+		}
+	}
+}
+
+func (this *WSMarketCB) recvRoutine() {
+	for {
+		var msgType, msg, readErr = this.conn.ReadMessage()
+		if readErr != nil {
+			// conn closed by user.
+			if strings.Index(readErr.Error(), "use of closed network connection") > 0 {
+				this.logger().Info("conn closed by user")
+				return
+			}
+
+			this.ErrorHandler(readErr)
+			this.Restart()
+			return
+		}
+
+		if msgType != websocket.TextMessage {
+			continue
+		}
+		this.lastPingTS = time.Now().Unix()
+		this.Config.GetMetrics().IncWSMessage(COINBASE, "WSMarketCB")
+		this.RecvHandler(string(msg))
+	}
+}
+
+func (this *WSMarketCB) startCheck() error {
+	var restartNum, limitTS = 0, time.Now().Unix() - int64(this.restartLimitSec)
+	for ts := range this.restartTS {
+		if ts > limitTS {
+			restartNum++
+		}
+	}
+	if restartNum > this.restartLimitNum {
+		var wsErr = &WSStopError{
+			Msg: fmt.Sprintf(
+				"The ws restarted %d times in %d seconds, stop the ws",
+				restartNum, this.restartLimitSec,
+			),
+		}
+		this.Config.GetMetrics().IncWSRestartLimit(COINBASE, "WSMarketCB")
+		return wsErr
+	}
+	return nil
+}
+
+func (this *WSMarketCB) getConn(wss string) (*websocket.Conn, error) {
+	var dialer, dialErr = NewWsDialer(this.DialerConfig)
+	if dialErr != nil {
+		return nil, dialErr
+	}
+	var conn, _, err = dialer.Dial(
+		wss,
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return conn, nil
+}
+
+func (this *WSMarketCB) initDefaultValue() {
+	if this.RecvHandler == nil {
+		this.RecvHandler = func(msg string) {
+			this.logger().Debug("receive message", LogF("msg", msg))
+		}
+	}
+	if this.ErrorHandler == nil {
+		this.ErrorHandler = func(err error) {
+			this.logger().Error("websocket error", LogF(LOG_FIELD_ERROR, err))
+		}
+	}
+	if this.restartSleepSec == 0 {
+		this.restartSleepSec = DEFAULT_WEBSOCKET_RESTART_SLEEP_SEC
+	}
+
+	if this.restartLimitNum == 0 {
+		this.restartLimitNum = DERFAULT_WEBSOCKET_RESTART_LIMIT_NUM
+	}
+
+	if this.restartLimitSec == 0 {
+		this.restartLimitSec = DERFAULT_WEBSOCKET_RESTART_LIMIT_SEC
+	}
+
+	if this.restartTS == nil {
+		this.restartTS = make(map[int64]string, 0)
+	}
+}
+
+func (this *WSMarketCB) Stop() {
+	if this.stopChecSign != nil {
+		this.stopChecSign <- true
+	}
+
+	if this.conn != nil {
+		_ = this.conn.Close()
+		this.conn = nil
+	}
+	this.connId = ""
+}
+
+func (this *WSMarketCB) logger() Logger {
+	return this.Config.GetLogger().With(
+		LogF(LOG_FIELD_EXCHANGE, COINBASE),
+		LogF(LOG_FIELD_CONN_ID, this.connId),
+	)
+}
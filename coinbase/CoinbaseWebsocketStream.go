@@ -0,0 +1,124 @@
+package coinbase
+
+import (
+	"encoding/json"
+	"time"
+
+	. "github.com/deforceHK/goghostex"
+)
+
+// MarketStreams is the typed ticker and market_trades stream, the messages of the other channels are ignored.
+type MarketStreams struct {
+	*WSMarketCB
+	TickerHandler func(ticker *Ticker)
+	TradeHandler  func(trade *Trade)
+}
+
+type wsTickerCB struct {
+	ProductId string `json:"product_id"`
+	Price     string `json:"price"`
+	Volume24H string `json:"volume_24_h"`
+	Low24H    string `json:"low_24_h"`
+	High24H   string `json:"high_24_h"`
+	BestBid   string `json:"best_bid"`
+	BestAsk   string `json:"best_ask"`
+}
+
+type wsTradeCB struct {
+	TradeId   string `json:"trade_id"`
+	ProductId string `json:"product_id"`
+	Price     string `json:"price"`
+	Size      string `json:"size"`
+	Side      string `json:"side"`
+	Time      string `json:"time"`
+}
+
+func (this *MarketStreams) Init() error {
+	if this.TickerHandler == nil {
+		this.TickerHandler = func(ticker *Ticker) {
+			this.logger().Debug("receive ticker", LogF("ticker", *ticker))
+		}
+	}
+	if this.TradeHandler == nil {
+		this.TradeHandler = func(trade *Trade) {
+			this.logger().Debug("receive trade", LogF("trade", *trade))
+		}
+	}
+	this.RecvHandler = func(s string) {
+		this.Receiver(s)
+	}
+	return this.Start()
+}
+
+func (this *MarketStreams) SubscribeTicker(pair Pair) {
+	this.WSMarketCB.Subscribe(WSRequestCB{"subscribe", []string{pair.ToSymbol("-", true)}, "ticker"})
+}
+
+func (this *MarketStreams) UnsubscribeTicker(pair Pair) {
+	this.WSMarketCB.Unsubscribe(WSRequestCB{"unsubscribe", []string{pair.ToSymbol("-", true)}, "ticker"})
+}
+
+// SubscribeTrades subscribe the market_trades channel, the snapshot event of the recent trades is passed too.
+func (this *MarketStreams) SubscribeTrades(pair Pair) {
+	this.WSMarketCB.Subscribe(WSRequestCB{"subscribe", []string{pair.ToSymbol("-", true)}, "market_trades"})
+}
+
+func (this *MarketStreams) UnsubscribeTrades(pair Pair) {
+	this.WSMarketCB.Unsubscribe(WSRequestCB{"unsubscribe", []string{pair.ToSymbol("-", true)}, "market_trades"})
+}
+
+func (this *MarketStreams) Receiver(msg string) {
+	var response = struct {
+		Channel   string `json:"channel"`
+		Timestamp string `json:"timestamp"`
+		Events    []*struct {
+			Type    string        `json:"type"`
+			Tickers []*wsTickerCB `json:"tickers"`
+			Trades  []*wsTradeCB  `json:"trades"`
+		} `json:"events"`
+	}{}
+	if err := json.Unmarshal([]byte(msg), &response); err != nil {
+		this.ErrorHandler(err)
+		return
+	}
+
+	switch response.Channel {
+	case "ticker":
+		var msgTime, _ = time.Parse(time.RFC3339Nano, response.Timestamp)
+		for _, event := range response.Events {
+			for _, item := range event.Tickers {
+				this.TickerHandler(&Ticker{
+					Pair:      NewPair(item.ProductId, "-"),
+					Last:      ToFloat64(item.Price),
+					Buy:       ToFloat64(item.BestBid),
+					Sell:      ToFloat64(item.BestAsk),
+					High:      ToFloat64(item.High24H),
+					Low:       ToFloat64(item.Low24H),
+					Vol:       ToFloat64(item.Volume24H),
+					Timestamp: msgTime.UnixNano() / int64(time.Millisecond),
+					Date:      msgTime.In(this.Config.Location).Format(GO_BIRTHDAY),
+				})
+			}
+		}
+	case "market_trades":
+		for _, event := range response.Events {
+			for _, item := range event.Trades {
+				var side = BUY
+				if item.Side == "SELL" {
+					side = SELL
+				}
+				var tradeTime, _ = time.Parse(time.RFC3339Nano, item.Time)
+				this.TradeHandler(&Trade{
+					Tid:       ToInt64(item.TradeId),
+					Type:      side,
+					Amount:    ToFloat64(item.Size),
+					Price:     ToFloat64(item.Price),
+					Timestamp: tradeTime.UnixNano() / int64(time.Millisecond),
+					Pair:      NewPair(item.ProductId, "-"),
+				})
+			}
+		}
+	default:
+		this.logger().Debug("receive message", LogF("msg", msg))
+	}
+}
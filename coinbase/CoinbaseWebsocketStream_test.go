@@ -0,0 +1,35 @@
+package coinbase
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/deforceHK/goghostex"
+)
+
+// go test -v ./coinbase/... -count=1 -run=TestMarketStreams_Receiver
+func TestMarketStreams_Receiver(t *testing.T) {
+	var tickers, trades = make([]*Ticker, 0), make([]*Trade, 0)
+	var streams = &MarketStreams{
+		WSMarketCB:    &WSMarketCB{Config: &APIConfig{Location: time.UTC}},
+		TickerHandler: func(ticker *Ticker) { tickers = append(tickers, ticker) },
+		TradeHandler:  func(trade *Trade) { trades = append(trades, trade) },
+	}
+
+	streams.Receiver(`{"channel":"ticker","timestamp":"2024-01-02T03:04:05.5Z","sequence_num":1,"events":[
+		{"type":"update","tickers":[{"type":"ticker","product_id":"BTC-USD","price":"40000","volume_24_h":"1234.5",
+		"low_24_h":"39000","high_24_h":"41000","best_bid":"39999.99","best_ask":"40000.01"}]}]}`)
+	streams.Receiver(`{"channel":"market_trades","timestamp":"2024-01-02T03:04:06Z","sequence_num":2,"events":[
+		{"type":"update","trades":[{"trade_id":"123","product_id":"ETH-USD","price":"2000","size":"0.5",
+		"side":"SELL","time":"2024-01-02T03:04:05.9Z"}]}]}`)
+
+	if len(tickers) != 1 || tickers[0].Pair != (Pair{Basis: BTC, Counter: USD}) || tickers[0].Last != 40000 ||
+		tickers[0].Buy != 39999.99 || tickers[0].Vol != 1234.5 || tickers[0].Timestamp != 1704164645500 {
+		t.Error("the ticker is wrong: ", tickers)
+		return
+	}
+	if len(trades) != 1 || trades[0].Tid != 123 || trades[0].Type != SELL || trades[0].Amount != 0.5 ||
+		trades[0].Pair != (Pair{Basis: ETH, Counter: USD}) || trades[0].Timestamp != 1704164645900 {
+		t.Error("the trade is wrong: ", trades)
+	}
+}
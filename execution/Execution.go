@@ -0,0 +1,157 @@
+package execution
+
+import (
+	"math"
+	"time"
+
+	. "github.com/deforceHK/goghostex"
+)
+
+const (
+	ALGO_TWAP    = "twap"    // the same amount in every slice
+	ALGO_VWAP    = "vwap"    // the slice amount follow the historical volume profile
+	ALGO_POV     = "pov"     // the child amount follow the market volume in the last interval
+	ALGO_ICEBERG = "iceberg" // only show the display amount, place the next one when it is filled
+
+	DEFAULT_SLICES         = 10
+	DEFAULT_INTERVAL_SEC   = 10
+	DEFAULT_PROFILE_PERIOD = KLINE_PERIOD_60MIN
+	DEFAULT_PROFILE_SIZE   = 24 * 7
+)
+
+// Config is the setting of the execution algorithm.
+type Config struct {
+	Algo string // ALGO_TWAP, ALGO_VWAP, ALGO_POV or ALGO_ICEBERG
+
+	Duration time.Duration // twap and vwap, the total execution time
+	Slices   int           // twap and vwap, default DEFAULT_SLICES
+	Interval time.Duration // pov and iceberg, the poll interval, default DEFAULT_INTERVAL_SEC seconds
+
+	PlaceType  PlaceType // the place type of the child orders, NORMAL, ONLY_MAKER, FOK, IOC or MARKET
+	LimitPrice float64   // the worst price of the child orders, 0 means no limit
+
+	ProfilePeriod int     // vwap, the kline period of the volume profile, default DEFAULT_PROFILE_PERIOD
+	ProfileSize   int     // vwap, the kline count of the volume profile, default DEFAULT_PROFILE_SIZE
+	Participation float64 // pov, the ratio of the market volume, eg: 0.1
+	DisplayAmount float64 // iceberg, the amount of every child order
+}
+
+// Progress is the execution state, it is reported after every child order changes.
+type Progress struct {
+	Algo      string
+	Exchange  string
+	Pair      Pair
+	Target    float64
+	Filled    float64
+	Remaining float64
+	AvgPrice  float64
+	// the last price when the execution start
+	ArrivalPrice float64
+	// (AvgPrice-ArrivalPrice)/ArrivalPrice, the sell side is reversed, positive means worse than the arrival.
+	Slippage  float64
+	Children  int // the child order count
+	Finished  bool
+	Timestamp int64 // unit: ms
+}
+
+// bar is the volume of the kline, both the swap kline and the spot kline are converted to it.
+type bar struct {
+	timestamp int64 // unit: ms
+	vol       float64
+}
+
+// slice is the cumulative amount which should be done at the time.
+type slice struct {
+	at     time.Time
+	amount float64
+}
+
+// twapSlices split the amount to the same size slices in the duration.
+func twapSlices(start time.Time, duration time.Duration, count int, amount float64) []slice {
+	var weights = make([]float64, count)
+	for i := range weights {
+		weights[i] = 1
+	}
+	return weightSlices(start, duration, weights, amount)
+}
+
+// vwapSlices split the amount by the average volume of the same time in the day of the bars,
+// it falls back to the twap if there is no volume.
+func vwapSlices(
+	start time.Time, duration time.Duration, count int, amount float64, period int, bars []bar,
+) []slice {
+	var periodMs = PeriodMillisecond[period]
+	if periodMs <= 0 || len(bars) == 0 {
+		return twapSlices(start, duration, count, amount)
+	}
+
+	// the average volume of every period in the day.
+	var bucketCount = int64(24*60*60*1000) / periodMs
+	if bucketCount <= 0 {
+		bucketCount = 1
+	}
+	var volumes = make([]float64, bucketCount)
+	var counts = make([]float64, bucketCount)
+	for _, b := range bars {
+		var bucket = (b.timestamp % (24 * 60 * 60 * 1000)) / periodMs % bucketCount
+		volumes[bucket] += b.vol
+		counts[bucket]++
+	}
+
+	var weights = make([]float64, count)
+	var total = float64(0)
+	var step = duration / time.Duration(count)
+	for i := range weights {
+		var ts = start.Add(step * time.Duration(i)).UnixMilli()
+		var bucket = (ts % (24 * 60 * 60 * 1000)) / periodMs % bucketCount
+		if counts[bucket] > 0 {
+			weights[i] = volumes[bucket] / counts[bucket]
+		}
+		total += weights[i]
+	}
+	if total <= 0 {
+		return twapSlices(start, duration, count, amount)
+	}
+	return weightSlices(start, duration, weights, amount)
+}
+
+func weightSlices(start time.Time, duration time.Duration, weights []float64, amount float64) []slice {
+	var total = float64(0)
+	for _, weight := range weights {
+		total += weight
+	}
+
+	var slices = make([]slice, 0, len(weights))
+	var step = duration / time.Duration(len(weights))
+	var cumulative = float64(0)
+	for i, weight := range weights {
+		cumulative += amount * weight / total
+		slices = append(slices, slice{at: start.Add(step * time.Duration(i)), amount: cumulative})
+	}
+	// make sure the last slice is the whole amount.
+	slices[len(slices)-1].amount = amount
+	return slices
+}
+
+// slippage return the signed slippage ratio, positive means worse than the arrival price.
+func slippage(isBuy bool, avgPrice, arrivalPrice float64) float64 {
+	if avgPrice <= 0 || arrivalPrice <= 0 {
+		return 0
+	}
+	var ratio = (avgPrice - arrivalPrice) / arrivalPrice
+	if !isBuy {
+		ratio = -ratio
+	}
+	return ratio
+}
+
+// limitPrice cap the price by the limit, buy never above it and sell never below it.
+func limitPrice(isBuy bool, price, limit float64) float64 {
+	if limit <= 0 {
+		return price
+	}
+	if isBuy {
+		return math.Min(price, limit)
+	}
+	return math.Max(price, limit)
+}
@@ -0,0 +1,189 @@
+package execution
+
+import (
+	. "github.com/deforceHK/goghostex"
+)
+
+// child is the child order placed by the executor, it keeps the swap order or the spot order.
+type child struct {
+	price      float64
+	amount     float64
+	dealAmount float64
+	avgPrice   float64
+	status     TradeStatus
+
+	swap *SwapOrder
+	spot *Order
+}
+
+// venue is where the child orders go, the swap api and the spot api are wrapped to it.
+type venue interface {
+	exchange() string
+	pair() Pair
+	isBuy() bool
+	lastPrice() (float64, error)
+	bars(period, size, since int) ([]bar, error)
+	roundAmount(amount float64) float64
+	place(price, amount float64, placeType PlaceType) (*child, error)
+	query(c *child) error
+	cancel(c *child) error
+}
+
+type swapVenue struct {
+	api        SwapRestAPI
+	symbol     Pair
+	futureType FutureType
+}
+
+func (v *swapVenue) exchange() string {
+	return v.api.GetExchangeName()
+}
+
+func (v *swapVenue) pair() Pair {
+	return v.symbol
+}
+
+func (v *swapVenue) isBuy() bool {
+	return v.futureType == OPEN_LONG || v.futureType == LIQUIDATE_SHORT
+}
+
+func (v *swapVenue) lastPrice() (float64, error) {
+	var ticker, _, err = v.api.GetTicker(v.symbol)
+	if err != nil {
+		return 0, err
+	}
+	return ticker.Last, nil
+}
+
+func (v *swapVenue) bars(period, size, since int) ([]bar, error) {
+	var klines, _, err = v.api.GetKline(v.symbol, period, size, since)
+	if err != nil {
+		return nil, err
+	}
+	var bars = make([]bar, 0, len(klines))
+	for _, kline := range klines {
+		bars = append(bars, bar{timestamp: kline.Timestamp, vol: kline.Vol})
+	}
+	return bars, nil
+}
+
+func (v *swapVenue) roundAmount(amount float64) float64 {
+	var contract = v.api.GetContract(v.symbol)
+	if contract == nil {
+		return amount
+	}
+	return contract.GetNormalizer().RoundAmount(amount)
+}
+
+func (v *swapVenue) place(price, amount float64, placeType PlaceType) (*child, error) {
+	var order = &SwapOrder{
+		Cid:       UUID(),
+		Pair:      v.symbol,
+		Type:      v.futureType,
+		PlaceType: placeType,
+		Price:     price,
+		Amount:    amount,
+	}
+	var c = &child{price: price, amount: amount, status: ORDER_UNFINISH, swap: order}
+	if _, err := v.api.PlaceOrder(order); err != nil {
+		return c, err
+	}
+	return c, nil
+}
+
+func (v *swapVenue) query(c *child) error {
+	if _, err := v.api.GetOrder(c.swap); err != nil {
+		return err
+	}
+	c.dealAmount, c.avgPrice, c.status = c.swap.DealAmount, c.swap.AvgPrice, c.swap.Status
+	return nil
+}
+
+func (v *swapVenue) cancel(c *child) error {
+	var _, err = v.api.CancelOrder(c.swap)
+	return err
+}
+
+type spotVenue struct {
+	api    SpotRestAPI
+	symbol Pair
+	side   TradeSide
+}
+
+func (v *spotVenue) exchange() string {
+	return v.api.GetExchangeName()
+}
+
+func (v *spotVenue) pair() Pair {
+	return v.symbol
+}
+
+func (v *spotVenue) isBuy() bool {
+	return v.side == BUY || v.side == BUY_MARKET
+}
+
+func (v *spotVenue) lastPrice() (float64, error) {
+	var ticker, _, err = v.api.GetTicker(v.symbol)
+	if err != nil {
+		return 0, err
+	}
+	return ticker.Last, nil
+}
+
+func (v *spotVenue) bars(period, size, since int) ([]bar, error) {
+	var klines, _, err = v.api.GetKlineRecords(v.symbol, period, size, since)
+	if err != nil {
+		return nil, err
+	}
+	var bars = make([]bar, 0, len(klines))
+	for _, kline := range klines {
+		bars = append(bars, bar{timestamp: kline.Timestamp, vol: kline.Vol})
+	}
+	return bars, nil
+}
+
+// roundAmount do nothing, the spot adapters normalize the amount by their own rules.
+func (v *spotVenue) roundAmount(amount float64) float64 {
+	return amount
+}
+
+func (v *spotVenue) place(price, amount float64, placeType PlaceType) (*child, error) {
+	var side = BUY
+	if !v.isBuy() {
+		side = SELL
+	}
+	if placeType == MARKET {
+		if side == BUY {
+			side = BUY_MARKET
+		} else {
+			side = SELL_MARKET
+		}
+	}
+
+	var order = &Order{
+		Cid:       UUID(),
+		Pair:      v.symbol,
+		Side:      side,
+		OrderType: placeType,
+		Price:     price,
+		Amount:    amount,
+	}
+	var c = &child{price: price, amount: amount, status: ORDER_UNFINISH, spot: order}
+	if _, err := v.api.PlaceOrder(order); err != nil {
+		return c, err
+	}
+	return c, nil
+}
+
+func (v *spotVenue) query(c *child) error {
+	if _, err := v.api.GetOrder(c.spot); err != nil {
+		return err
+	}
+	c.dealAmount, c.avgPrice, c.status = c.spot.DealAmount, c.spot.AvgPrice, c.spot.Status
+	return nil
+}
+
+func (v *spotVenue) cancel(c *child) error {
+	var _, err = v.api.CancelOrder(c.spot)
+	return err
+}
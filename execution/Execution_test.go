@@ -0,0 +1,99 @@
+package execution
+
+import (
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/deforceHK/goghostex"
+)
+
+// fakeSwap fill the half of every order, the leftover must be canceled and replaced.
+type fakeSwap struct {
+	SwapRestAPI
+	mux    sync.Mutex
+	orders map[string]*SwapOrder
+	cancel int
+}
+
+func (this *fakeSwap) GetExchangeName() string {
+	return OKEX
+}
+
+func (this *fakeSwap) GetTicker(pair Pair) (*SwapTicker, []byte, error) {
+	return &SwapTicker{Pair: pair, Last: 100}, nil, nil
+}
+
+func (this *fakeSwap) GetContract(pair Pair) *SwapContract {
+	return &SwapContract{Pair: pair, SettleMode: SETTLE_MODE_COUNTER, UnitAmount: 1, AmountPrecision: 2}
+}
+
+func (this *fakeSwap) PlaceOrder(order *SwapOrder) ([]byte, error) {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	order.OrderId = order.Cid
+	order.Status = ORDER_PART_FINISH
+	order.DealAmount = math.Round(order.Amount/2*100) / 100
+	order.AvgPrice = 101
+	this.orders[order.OrderId] = order
+	return nil, nil
+}
+
+func (this *fakeSwap) GetOrder(order *SwapOrder) ([]byte, error) {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	var remote = this.orders[order.OrderId]
+	order.Status, order.DealAmount, order.AvgPrice = remote.Status, remote.DealAmount, remote.AvgPrice
+	return nil, nil
+}
+
+func (this *fakeSwap) CancelOrder(order *SwapOrder) ([]byte, error) {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	this.orders[order.OrderId].Status = ORDER_CANCEL
+	this.cancel++
+	return nil, nil
+}
+
+// go test -v ./execution/... -count=1 -run=TestTWAP
+func TestTWAP(t *testing.T) {
+	var api = &fakeSwap{orders: make(map[string]*SwapOrder)}
+	var executor = NewSwapExecutor(api, BTC_USDT, OPEN_LONG, 8, Config{
+		Algo:     ALGO_TWAP,
+		Duration: 200 * time.Millisecond,
+		Slices:   4,
+	})
+	if err := executor.Start(); err != nil {
+		t.Error(err)
+		return
+	}
+
+	var progress = executor.Wait()
+	if !progress.Finished || progress.Filled <= 4 || progress.Filled > 8 || api.cancel == 0 {
+		t.Error("the twap is wrong: ", progress, api.cancel)
+		return
+	}
+	if math.Abs(progress.AvgPrice-101) > 1e-9 || math.Abs(progress.Slippage-0.01) > 1e-9 {
+		t.Error("the slippage is wrong: ", progress.AvgPrice, progress.Slippage)
+	}
+}
+
+// go test -v ./execution/... -count=1 -run=TestSlices
+func TestSlices(t *testing.T) {
+	var start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var bars = []bar{
+		{timestamp: start.UnixMilli(), vol: 100},
+		{timestamp: start.Add(time.Hour).UnixMilli(), vol: 300},
+	}
+	var slices = vwapSlices(start, 2*time.Hour, 2, 8, KLINE_PERIOD_60MIN, bars)
+	if len(slices) != 2 || slices[0].amount != 2 || slices[1].amount != 8 {
+		t.Error("the vwap slices is wrong: ", slices)
+		return
+	}
+
+	slices = twapSlices(start, time.Hour, 4, 8)
+	if slices[0].amount != 2 || !slices[3].at.Equal(start.Add(45*time.Minute)) {
+		t.Error("the twap slices is wrong: ", slices)
+	}
+}
@@ -0,0 +1,388 @@
+package execution
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	. "github.com/deforceHK/goghostex"
+)
+
+// the tolerance of the filled amount, the execution is finished when the remaining is less than it.
+const amountEpsilon = 1e-9
+
+// Executor split the parent order to the child orders by the algorithm, and run them in the background.
+// The leftover of the child order is canceled before the next one, and it is added to the next one.
+type Executor struct {
+	Config Config
+
+	// the progress is reported after every child order changes, not necessary
+	ProgressHandler func(progress Progress)
+	ErrorHandler    func(err error)
+
+	venue  venue
+	target float64
+
+	mux      sync.Mutex
+	start    time.Time
+	step     time.Duration
+	slices   []slice
+	active   *child
+	filled   float64
+	cost     float64 // sum of the deal amount * the avg price
+	children int
+	arrival  float64
+	seen     map[int64]float64 // pov, the volume of the klines which is counted
+	finished bool
+	stopSign chan bool
+	done     chan bool
+}
+
+func NewSwapExecutor(api SwapRestAPI, pair Pair, futureType FutureType, amount float64, config Config) *Executor {
+	return &Executor{
+		Config: config,
+		venue:  &swapVenue{api: api, symbol: pair, futureType: futureType},
+		target: amount,
+	}
+}
+
+func NewSpotExecutor(api SpotRestAPI, pair Pair, side TradeSide, amount float64, config Config) *Executor {
+	return &Executor{
+		Config: config,
+		venue:  &spotVenue{api: api, symbol: pair, side: side},
+		target: amount,
+	}
+}
+
+func (this *Executor) initDefaultValue() error {
+	if this.Config.Slices <= 0 {
+		this.Config.Slices = DEFAULT_SLICES
+	}
+	if this.Config.Interval <= 0 {
+		this.Config.Interval = DEFAULT_INTERVAL_SEC * time.Second
+	}
+	if this.Config.ProfilePeriod == 0 {
+		this.Config.ProfilePeriod = DEFAULT_PROFILE_PERIOD
+	}
+	if this.Config.ProfileSize <= 0 {
+		this.Config.ProfileSize = DEFAULT_PROFILE_SIZE
+	}
+	if this.ProgressHandler == nil {
+		this.ProgressHandler = func(progress Progress) {}
+	}
+	if this.ErrorHandler == nil {
+		this.ErrorHandler = func(err error) {
+			GetLogger().Error(
+				"execution error",
+				LogF(LOG_FIELD_EXCHANGE, this.venue.exchange()),
+				LogF(LOG_FIELD_INSTRUMENT, this.venue.pair().String()),
+				LogF(LOG_FIELD_ERROR, err),
+			)
+		}
+	}
+
+	if this.target <= 0 {
+		return errors.New("the amount of the parent order must be positive")
+	}
+	switch this.Config.Algo {
+	case ALGO_TWAP, ALGO_VWAP:
+		if this.Config.Duration <= 0 {
+			return fmt.Errorf("the duration of %s must be positive", this.Config.Algo)
+		}
+	case ALGO_POV:
+		if this.Config.Participation <= 0 || this.Config.Participation > 1 {
+			return errors.New("the participation of pov must be in (0, 1]")
+		}
+	case ALGO_ICEBERG:
+		if this.Config.DisplayAmount <= 0 {
+			return errors.New("the display amount of iceberg must be positive")
+		}
+	default:
+		return fmt.Errorf("the algo %s is not supported", this.Config.Algo)
+	}
+	return nil
+}
+
+// Start record the arrival price, build the schedule and run the child orders in the background.
+func (this *Executor) Start() error {
+	if this.stopSign != nil {
+		return errors.New("the executor is started already")
+	}
+	if err := this.initDefaultValue(); err != nil {
+		return err
+	}
+
+	var arrival, err = this.venue.lastPrice()
+	if err != nil {
+		return fmt.Errorf("can not get the arrival price: %s", err)
+	}
+
+	var now = time.Now()
+	this.start, this.arrival = now, arrival
+	switch this.Config.Algo {
+	case ALGO_TWAP:
+		this.step = this.Config.Duration / time.Duration(this.Config.Slices)
+		this.slices = twapSlices(now, this.Config.Duration, this.Config.Slices, this.target)
+	case ALGO_VWAP:
+		var periodMs = PeriodMillisecond[this.Config.ProfilePeriod]
+		var since = now.UnixMilli() - int64(this.Config.ProfileSize)*periodMs
+		var bars, err = this.venue.bars(this.Config.ProfilePeriod, this.Config.ProfileSize, int(since))
+		if err != nil {
+			return fmt.Errorf("can not get the volume profile: %s", err)
+		}
+		this.step = this.Config.Duration / time.Duration(this.Config.Slices)
+		this.slices = vwapSlices(
+			now, this.Config.Duration, this.Config.Slices, this.target, this.Config.ProfilePeriod, bars,
+		)
+	case ALGO_POV:
+		this.step = this.Config.Interval
+		// only the volume after the start is counted.
+		if _, err := this.marketVolume(); err != nil {
+			return fmt.Errorf("can not get the market volume: %s", err)
+		}
+	case ALGO_ICEBERG:
+		this.step = this.Config.Interval
+	}
+
+	this.stopSign = make(chan bool)
+	this.done = make(chan bool)
+	go this.run()
+	return nil
+}
+
+// Stop cancel the active child order and stop the execution, it returns after the executor is finished.
+func (this *Executor) Stop() {
+	if this.stopSign == nil {
+		return
+	}
+	select {
+	case <-this.done:
+		return
+	default:
+	}
+	close(this.stopSign)
+	<-this.done
+}
+
+// Wait block until the execution is finished, and return the final progress.
+func (this *Executor) Wait() Progress {
+	if this.done != nil {
+		<-this.done
+	}
+	return this.GetProgress()
+}
+
+func (this *Executor) GetProgress() Progress {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	return this.progress()
+}
+
+func (this *Executor) progress() Progress {
+	var avgPrice = float64(0)
+	if this.filled > 0 {
+		avgPrice = this.cost / this.filled
+	}
+	return Progress{
+		Algo:         this.Config.Algo,
+		Exchange:     this.venue.exchange(),
+		Pair:         this.venue.pair(),
+		Target:       this.target,
+		Filled:       this.filled,
+		Remaining:    math.Max(this.target-this.filled, 0),
+		AvgPrice:     avgPrice,
+		ArrivalPrice: this.arrival,
+		Slippage:     slippage(this.venue.isBuy(), avgPrice, this.arrival),
+		Children:     this.children,
+		Finished:     this.finished,
+		Timestamp:    time.Now().UnixMilli(),
+	}
+}
+
+func (this *Executor) run() {
+	defer close(this.done)
+
+	var ticker = time.NewTicker(this.step)
+	defer ticker.Stop()
+
+	for finished := this.next(time.Now()); !finished; {
+		select {
+		case now := <-ticker.C:
+			finished = this.next(now)
+		case <-this.stopSign:
+			this.settle(true)
+			this.finish()
+			return
+		}
+	}
+}
+
+// next settle the active child order and place the new one, it returns true when the execution is finished.
+func (this *Executor) next(now time.Time) bool {
+	var isIceberg = this.Config.Algo == ALGO_ICEBERG
+	// the iceberg child order is waiting for the fill, the others are replaced in every step.
+	if !this.settle(!isIceberg) {
+		return false
+	}
+
+	this.mux.Lock()
+	var remaining = this.target - this.filled
+	this.mux.Unlock()
+	if remaining <= amountEpsilon {
+		this.finish()
+		return true
+	}
+	var isScheduled = this.Config.Algo == ALGO_TWAP || this.Config.Algo == ALGO_VWAP
+	if isScheduled && now.After(this.start.Add(this.Config.Duration+this.step)) {
+		// the catch-up child order is done, give up the remaining.
+		this.finish()
+		return true
+	}
+
+	var desired = float64(0)
+	switch this.Config.Algo {
+	case ALGO_TWAP, ALGO_VWAP:
+		var scheduled = float64(0)
+		for _, s := range this.slices {
+			if !s.at.After(now) {
+				scheduled = s.amount
+			}
+		}
+		this.mux.Lock()
+		desired = scheduled - this.filled
+		this.mux.Unlock()
+	case ALGO_POV:
+		var volume, err = this.marketVolume()
+		if err != nil {
+			this.ErrorHandler(err)
+			return false
+		}
+		desired = volume * this.Config.Participation
+	case ALGO_ICEBERG:
+		desired = this.Config.DisplayAmount
+	}
+
+	var amount = this.venue.roundAmount(math.Min(desired, remaining))
+	if amount <= amountEpsilon {
+		return false
+	}
+
+	var price = float64(0)
+	var isBuy = this.venue.isBuy()
+	if this.Config.PlaceType != MARKET || this.Config.LimitPrice > 0 {
+		var last, err = this.venue.lastPrice()
+		if err != nil {
+			this.ErrorHandler(err)
+			return false
+		}
+		if this.Config.PlaceType == MARKET {
+			// the market order is not placed when the price is beyond the limit.
+			if (isBuy && last > this.Config.LimitPrice) || (!isBuy && last < this.Config.LimitPrice) {
+				return false
+			}
+		} else {
+			price = limitPrice(isBuy, last, this.Config.LimitPrice)
+		}
+	}
+
+	var c, err = this.venue.place(price, amount, this.Config.PlaceType)
+	this.mux.Lock()
+	this.children++
+	if err == nil {
+		this.active = c
+	}
+	var progress = this.progress()
+	this.mux.Unlock()
+
+	if err != nil {
+		this.ErrorHandler(fmt.Errorf("place the child order error: %s", err))
+	}
+	this.ProgressHandler(progress)
+	return false
+}
+
+// settle query the active child order, and cancel it if it is not final and replace is true.
+// It returns true when there is no active child order after it.
+func (this *Executor) settle(replace bool) bool {
+	this.mux.Lock()
+	var c = this.active
+	this.mux.Unlock()
+	if c == nil {
+		return true
+	}
+
+	if err := this.venue.query(c); err != nil {
+		this.ErrorHandler(fmt.Errorf("query the child order error: %s", err))
+		return false
+	}
+	if !c.status.IsFinal() {
+		if !replace {
+			return false
+		}
+		if err := this.venue.cancel(c); err != nil {
+			this.ErrorHandler(fmt.Errorf("cancel the child order error: %s", err))
+			return false
+		}
+		if err := this.venue.query(c); err != nil {
+			this.ErrorHandler(fmt.Errorf("query the child order error: %s", err))
+			return false
+		}
+		// the cancel is not finished in the exchange, wait for the next step.
+		if !c.status.IsFinal() {
+			return false
+		}
+	}
+
+	this.mux.Lock()
+	this.filled += c.dealAmount
+	this.cost += c.dealAmount * c.avgPrice
+	this.active = nil
+	var progress = this.progress()
+	this.mux.Unlock()
+
+	this.ProgressHandler(progress)
+	return true
+}
+
+func (this *Executor) finish() {
+	this.mux.Lock()
+	this.finished = true
+	var progress = this.progress()
+	this.mux.Unlock()
+	this.ProgressHandler(progress)
+}
+
+// marketVolume return the market volume since the last call, it is counted by the 1 minute klines.
+func (this *Executor) marketVolume() (float64, error) {
+	var periodMs = PeriodMillisecond[KLINE_PERIOD_1MIN]
+	var size = int(this.Config.Interval.Milliseconds()/periodMs) + 2
+	var since = time.Now().UnixMilli() - int64(size)*periodMs
+	var bars, err = this.venue.bars(KLINE_PERIOD_1MIN, size, int(since))
+	if err != nil {
+		return 0, err
+	}
+
+	// the first call only record the volume.
+	var primed = this.seen != nil
+	if !primed {
+		this.seen = make(map[int64]float64)
+	}
+	var volume = float64(0)
+	for _, b := range bars {
+		if last, exist := this.seen[b.timestamp]; exist {
+			volume += math.Max(b.vol-last, 0)
+		} else if primed {
+			volume += b.vol
+		}
+		this.seen[b.timestamp] = b.vol
+	}
+	// forget the old klines.
+	for ts := range this.seen {
+		if ts < since {
+			delete(this.seen, ts)
+		}
+	}
+	return volume, nil
+}
@@ -5,6 +5,7 @@ import (
 	"crypto/sha512"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strconv"
 	"time"
@@ -20,8 +21,31 @@ const (
 	APPLICATION_JSON_UTF8 = "application/json; charset=UTF-8"
 
 	ENDPOINT = "https://api.gateio.ws"
+
+	// the uri templates which have the pair or the order id, the settle of the futures is usdt or btc.
+	SPOT_PAIR_URI  = "/api/v4/spot/currency_pairs/%s"
+	SPOT_ORDER_URI = "/api/v4/spot/orders/%s"
+
+	SWAP_CONTRACT_URI    = "/api/v4/futures/%s/contracts/%s"
+	SWAP_ORDER_URI       = "/api/v4/futures/%s/orders/%s"
+	SWAP_POSITION_URI    = "/api/v4/futures/%s/positions/%s"
+	SWAP_LEVERAGE_URI    = "/api/v4/futures/%s/positions/%s/leverage"
+	SWAP_MARGIN_URI      = "/api/v4/futures/%s/positions/%s/margin"
+	SWAP_DUAL_MARGIN_URI = "/api/v4/futures/%s/dual_comp/positions/%s/margin"
 )
 
+// the route label of the rest metrics.
+var _INERNAL_ROUTES = []string{
+	SPOT_PAIR_URI,
+	SPOT_ORDER_URI,
+	SWAP_CONTRACT_URI,
+	SWAP_ORDER_URI,
+	SWAP_POSITION_URI,
+	SWAP_LEVERAGE_URI,
+	SWAP_MARGIN_URI,
+	SWAP_DUAL_MARGIN_URI,
+}
+
 var _INERNAL_KLINE_PERIOD_CONVERTER = map[int]string{
 	KLINE_PERIOD_1MIN:  "1m",
 	KLINE_PERIOD_5MIN:  "5m",
@@ -57,6 +81,7 @@ type SwapOrderGate struct {
 	Status       string  `json:"status,omitempty"`
 	Close        bool    `json:"close"`
 	ReduceOnly   bool    `json:"reduce_only"`
+	AutoSize     string  `json:"auto_size,omitempty"`
 	IsReduceOnly bool    `json:"is_reduce_only,omitempty"`
 	Tif          string  `json:"tif"`
 	Text         string  `json:"text"`
@@ -64,25 +89,60 @@ type SwapOrderGate struct {
 	FinishAt     string  `json:"finish_at"`
 }
 
-func (sog *SwapOrderGate) Merge(order *SwapOrder) {
+var GATE_AUTO_SIZE_CONVERTER = map[PositionSide]string{
+	POSITION_SIDE_LONG:  "close_long",
+	POSITION_SIDE_SHORT: "close_short",
+}
+
+// Merge build the order request, the price and the amount are normalized by the contract, the amount in the basis
+// currency is converted to the contracts.
+func (sog *SwapOrderGate) Merge(order *SwapOrder, contract *SwapContract) error {
 	placeType, exist := GATE_PLACE_TYPE_CONVERTER[order.PlaceType]
 	if !exist {
-		panic("not support the place type in gate. ")
+		return errors.New("not support the place type in gate. ")
+	}
+	positionSide, reduceOnly, err := order.ResolvePosition()
+	if err != nil {
+		return err
+	}
+
+	var normalizer = contract.GetNormalizer()
+	normalizer.ReduceOnly = reduceOnly
+	var price, amount = order.Price, order.Amount
+	if order.ClosePosition {
+		price, err = normalizer.NormalizePrice(order.Price, false)
+	} else {
+		price, amount, err = normalizer.Normalize(order.Price, order.Amount, false)
+	}
+	if err != nil {
+		return err
 	}
 
 	sog.Contract = order.Pair.ToSymbol("_", true)
-	sog.Size = int64(order.Price*order.Amount) / 1
-	sog.Price = order.Price
-	sog.Tif = placeType
-	if order.Type == LIQUIDATE_LONG || order.Type == LIQUIDATE_SHORT {
-		sog.ReduceOnly = true
+	sog.Size = toContracts(contract, amount, price)
+	sog.Price = price
+	if sog.Size <= 0 && !order.ClosePosition {
+		return &OrderInvalidError{Field: "amount", Msg: fmt.Sprintf("%v is less than one contract", amount)}
 	}
+	sog.Tif = placeType
+	// the dual mode position side is decided by the sign of the size and the reduce only.
+	sog.ReduceOnly = reduceOnly
 	if order.Type == LIQUIDATE_LONG || order.Type == OPEN_SHORT {
 		sog.Size = -sog.Size
 	}
+	if order.ClosePosition {
+		sog.Size = 0
+		if positionSide == POSITION_SIDE_BOTH {
+			sog.Close, sog.ReduceOnly = true, false
+		} else {
+			sog.AutoSize = GATE_AUTO_SIZE_CONVERTER[positionSide]
+		}
+	}
+	return nil
 }
 
-func (sog *SwapOrderGate) New(loc *time.Location) *SwapOrder {
+// New build the order from the response, the contracts are converted to the amount in the basis currency.
+func (sog *SwapOrderGate) New(loc *time.Location, contract *SwapContract) *SwapOrder {
 	placeTimestamp := sog.CreateTime * 1000
 	placeDatetime := time.Unix(sog.CreateTime, 0).In(loc).Format(GO_BIRTHDAY)
 
@@ -121,8 +181,8 @@ func (sog *SwapOrderGate) New(loc *time.Location) *SwapOrder {
 		Cid:     sog.Text,
 		OrderId: fmt.Sprintf("%d", sog.Id),
 
-		Amount:     float64(positiveSize),
-		DealAmount: float64(positiveSize - positiveLeft),
+		Amount:     toAmount(contract, float64(positiveSize), sog.Price),
+		DealAmount: toAmount(contract, float64(positiveSize-positiveLeft), sog.FillPrice),
 		Price:      sog.Price,
 		AvgPrice:   sog.FillPrice,
 
@@ -160,8 +220,8 @@ func (*Gate) GetExchangeName() string {
 
 func New(config *APIConfig) *Gate {
 	gate := &Gate{config: config}
-	gate.Spot = &Spot{gate}
-	gate.Swap = &Swap{gate}
+	gate.Spot = &Spot{Gate: gate}
+	gate.Swap = &Swap{Gate: gate}
 	return gate
 }
 
@@ -177,7 +237,10 @@ func (gate *Gate) DoRequest(
 		url += fmt.Sprintf("?%s", rawQuery)
 	}
 
-	resp, err := NewHttpRequest(
+	resp, err := NewHttpRequestWithRoute(
+		gate.config.GetMetrics(),
+		GATE,
+		RouteOf(uri, _INERNAL_ROUTES),
 		gate.config.HttpClient,
 		httpMethod,
 		url,
@@ -223,7 +286,10 @@ func (gate *Gate) DoSignRequest(
 		url += fmt.Sprintf("?%s", rawQuery)
 	}
 
-	resp, err := NewHttpRequest(
+	resp, err := NewHttpRequestWithRoute(
+		gate.config.GetMetrics(),
+		GATE,
+		RouteOf(uri, _INERNAL_ROUTES),
 		gate.config.HttpClient,
 		httpMethod,
 		url,
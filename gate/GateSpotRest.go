@@ -1,61 +1,817 @@
 package gate
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
 	. "github.com/deforceHK/goghostex"
 )
 
+// the side of gate and the order type, limit or market
+var _INERNAL_SPOT_SIDE_CONVERTER = map[TradeSide][]string{
+	BUY:         {"buy", "limit"},
+	SELL:        {"sell", "limit"},
+	BUY_MARKET:  {"buy", "market"},
+	SELL_MARKET: {"sell", "market"},
+}
+
+var _INERNAL_SPOT_TIME_IN_FORCE_CONVERTER = map[PlaceType]string{
+	NORMAL:     "gtc",
+	ONLY_MAKER: "poc",
+	FOK:        "fok",
+	IOC:        "ioc",
+}
+
 type Spot struct {
 	*Gate
+
+	rules sync.Map // pair.String() => *Rule, the cache of GetExchangeRule for PlaceOrder
+}
+
+// the common resp struct of the spot order apis
+type spotOrderGate struct {
+	Id           string  `json:"id"`
+	Text         string  `json:"text"`
+	CreateTimeMs int64   `json:"create_time_ms"`
+	UpdateTimeMs int64   `json:"update_time_ms"`
+	Status       string  `json:"status"` // open closed cancelled
+	CurrencyPair string  `json:"currency_pair"`
+	Type         string  `json:"type"` // limit market
+	Side         string  `json:"side"` // buy sell
+	Amount       float64 `json:"amount,string"`
+	Price        float64 `json:"price,string"`
+	TimeInForce  string  `json:"time_in_force"`
+	Left         float64 `json:"left,string"`
+	FilledAmount float64 `json:"filled_amount,string"`
+	FilledTotal  float64 `json:"filled_total,string"`
+	AvgDealPrice float64 `json:"avg_deal_price,string"`
+	Fee          float64 `json:"fee,string"`
+}
+
+// merge the remote order into the order. The amount of the market buy order is in the counter currency in gate,
+// so the Amount of it is not changed, and the DealAmount is from the filled amount.
+func (sog *spotOrderGate) merge(order *Order, location *time.Location) {
+	order.OrderId = sog.Id
+	order.Cid = strings.TrimPrefix(sog.Text, "t-")
+	order.Pair = NewPair(sog.CurrencyPair, "_")
+	order.AvgPrice = sog.AvgDealPrice
+	order.Fee = sog.Fee
+	if sog.Type == "market" && sog.Side == "buy" {
+		order.DealAmount = sog.FilledAmount
+		if order.DealAmount == 0 && sog.AvgDealPrice > 0 {
+			order.DealAmount = sog.FilledTotal / sog.AvgDealPrice
+		}
+	} else {
+		order.Price = sog.Price
+		order.Amount = sog.Amount
+		order.DealAmount = sog.Amount - sog.Left
+	}
+
+	for side, sideInfo := range _INERNAL_SPOT_SIDE_CONVERTER {
+		if sideInfo[0] == sog.Side && sideInfo[1] == sog.Type {
+			order.Side = side
+		}
+	}
+	if sog.Type == "market" {
+		order.OrderType = MARKET
+	} else {
+		for placeType, timeInForce := range _INERNAL_SPOT_TIME_IN_FORCE_CONVERTER {
+			if timeInForce == sog.TimeInForce {
+				order.OrderType = placeType
+			}
+		}
+	}
+
+	switch sog.Status {
+	case "open":
+		order.Status = ORDER_UNFINISH
+		if order.DealAmount > 0 {
+			order.Status = ORDER_PART_FINISH
+		}
+	case "closed":
+		order.Status = ORDER_FINISH
+	case "cancelled":
+		order.Status = ORDER_CANCEL
+	default:
+		order.Status = ORDER_FAIL
+	}
+
+	if sog.CreateTimeMs > 0 {
+		order.OrderTimestamp = sog.CreateTimeMs
+		order.OrderDate = time.Unix(sog.CreateTimeMs/1000, 0).In(location).Format(GO_BIRTHDAY)
+	}
+	if sog.UpdateTimeMs > 0 && order.Status != ORDER_UNFINISH {
+		order.DealTimestamp = sog.UpdateTimeMs
+		order.DealDatetime = time.Unix(sog.UpdateTimeMs/1000, 0).In(location).Format(GO_BIRTHDAY)
+	}
 }
 
 func (spot *Spot) GetExchangeRule(pair Pair) (*Rule, []byte, error) {
-	panic("implement me")
+	var response = struct {
+		Id              string  `json:"id"`
+		Base            string  `json:"base"`
+		Quote           string  `json:"quote"`
+		MinBaseAmount   float64 `json:"min_base_amount,string"`
+		MinQuoteAmount  float64 `json:"min_quote_amount,string"`
+		AmountPrecision int     `json:"amount_precision"`
+		Precision       int     `json:"precision"`
+	}{}
+	resp, err := spot.DoRequest(
+		http.MethodGet,
+		fmt.Sprintf(SPOT_PAIR_URI, pair.ToSymbol("_", true)),
+		"",
+		"",
+		&response,
+	)
+	if err != nil {
+		return nil, resp, err
+	}
+	if response.Id == "" {
+		return nil, resp, errors.New("Can not find the pair in exchange. ")
+	}
+
+	return &Rule{
+		Pair:             pair,
+		Base:             NewCurrency(response.Base, ""),
+		Counter:          NewCurrency(response.Quote, ""),
+		BaseMinSize:      response.MinBaseAmount,
+		BasePrecision:    response.AmountPrecision,
+		CounterPrecision: response.Precision,
+		TickSize:         math.Pow10(-response.Precision),
+		LotSize:          math.Pow10(-response.AmountPrecision),
+		MinNotional:      response.MinQuoteAmount,
+	}, resp, nil
+}
+
+// getRule return the cached rule of the pair, the rule is queried at the first time.
+func (spot *Spot) getRule(pair Pair) (*Rule, error) {
+	if rule, exist := spot.rules.Load(pair.String()); exist {
+		return rule.(*Rule), nil
+	}
+	var rule, _, err = spot.GetExchangeRule(pair)
+	if err != nil {
+		return nil, err
+	}
+	spot.rules.Store(pair.String(), rule)
+	return rule, nil
 }
 
 func (spot *Spot) GetTicker(pair Pair) (*Ticker, []byte, error) {
-	panic("implement me")
+	var params = url.Values{}
+	params.Set("currency_pair", pair.ToSymbol("_", true))
+
+	var response = make([]*struct {
+		Last       float64 `json:"last,string"`
+		LowestAsk  float64 `json:"lowest_ask,string"`
+		HighestBid float64 `json:"highest_bid,string"`
+		BaseVolume float64 `json:"base_volume,string"`
+		High24H    float64 `json:"high_24h,string"`
+		Low24H     float64 `json:"low_24h,string"`
+	}, 0)
+	resp, err := spot.DoRequest(
+		http.MethodGet,
+		"/api/v4/spot/tickers",
+		params.Encode(),
+		"",
+		&response,
+	)
+	if err != nil {
+		return nil, resp, err
+	}
+	if len(response) == 0 {
+		return nil, resp, errors.New("Can not find the pair in exchange. ")
+	}
+
+	var now = time.Now()
+	return &Ticker{
+		Pair:      pair,
+		Last:      response[0].Last,
+		Buy:       response[0].HighestBid,
+		Sell:      response[0].LowestAsk,
+		High:      response[0].High24H,
+		Low:       response[0].Low24H,
+		Vol:       response[0].BaseVolume,
+		Timestamp: now.UnixNano() / int64(time.Millisecond),
+		Date:      now.In(spot.config.Location).Format(GO_BIRTHDAY),
+	}, resp, nil
 }
 
 func (spot *Spot) GetDepth(pair Pair, size int) (*Depth, []byte, error) {
-	panic("implement me")
+	var params = url.Values{}
+	params.Set("currency_pair", pair.ToSymbol("_", true))
+	params.Set("limit", fmt.Sprintf("%d", size))
+	params.Set("with_id", "true")
+
+	var response = struct {
+		Id      int64      `json:"id"`
+		Current int64      `json:"current"`
+		Asks    [][]string `json:"asks"` // ascending ordered
+		Bids    [][]string `json:"bids"` // descending ordered
+	}{}
+	resp, err := spot.DoRequest(
+		http.MethodGet,
+		"/api/v4/spot/order_book",
+		params.Encode(),
+		"",
+		&response,
+	)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	var depth = &Depth{
+		Pair:      pair,
+		Timestamp: response.Current,
+		Sequence:  response.Id,
+		Date:      time.Unix(response.Current/1000, 0).In(spot.config.Location).Format(GO_BIRTHDAY),
+	}
+	for _, bid := range response.Bids {
+		depth.BidList = append(depth.BidList, DepthRecord{Price: ToFloat64(bid[0]), Amount: ToFloat64(bid[1])})
+	}
+	for _, ask := range response.Asks {
+		depth.AskList = append(depth.AskList, DepthRecord{Price: ToFloat64(ask[0]), Amount: ToFloat64(ask[1])})
+	}
+	return depth, resp, nil
+}
+
+// getCandlesticks return the candlesticks, gate rejects the limit with the from, so the to is computed by the size.
+func (spot *Spot) getCandlesticks(pair Pair, period, size, since int) ([][]interface{}, []byte, error) {
+	var interval, exist = _INERNAL_KLINE_PERIOD_CONVERTER[period]
+	if !exist {
+		return nil, nil, errors.New("Can not get the period kline data. ")
+	}
+
+	var params = url.Values{}
+	params.Set("currency_pair", pair.ToSymbol("_", true))
+	params.Set("interval", interval)
+	if since > 0 {
+		var from = int64(since) / 1000
+		var to = from + PeriodMillisecond[period]/1000*int64(size-1)
+		if now := time.Now().Unix(); to > now {
+			to = now
+		}
+		params.Set("from", fmt.Sprintf("%d", from))
+		params.Set("to", fmt.Sprintf("%d", to))
+	} else {
+		params.Set("limit", fmt.Sprintf("%d", size))
+	}
+
+	// [timestamp in seconds, quote volume, close, high, low, open, base volume, window closed]
+	var response = make([][]interface{}, 0)
+	resp, err := spot.DoRequest(
+		http.MethodGet,
+		"/api/v4/spot/candlesticks",
+		params.Encode(),
+		"",
+		&response,
+	)
+	if err != nil {
+		return nil, resp, err
+	}
+	return response, resp, nil
 }
 
 func (spot *Spot) GetKlineRecords(pair Pair, period, size, since int) ([]*Kline, []byte, error) {
-	panic("implement me")
+	var candlesticks, resp, err = spot.getCandlesticks(pair, period, size, since)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	var klines = make([]*Kline, 0, len(candlesticks))
+	for _, item := range candlesticks {
+		if len(item) < 7 {
+			continue
+		}
+		var t = time.Unix(ToInt64(item[0]), 0)
+		klines = append(klines, &Kline{
+			Pair:      pair,
+			Exchange:  GATE,
+			Timestamp: t.UnixNano() / int64(time.Millisecond),
+			Date:      t.In(spot.config.Location).Format(GO_BIRTHDAY),
+			Open:      ToFloat64(item[5]),
+			Close:     ToFloat64(item[2]),
+			High:      ToFloat64(item[3]),
+			Low:       ToFloat64(item[4]),
+			Vol:       ToFloat64(item[6]),
+		})
+	}
+	return GetAscKline(klines), resp, nil
 }
 
+// GetTrades return the recent trades of the exchange, the since is the timestamp in ms, 0 means the latest.
 func (spot *Spot) GetTrades(pair Pair, since int64) ([]*Trade, error) {
-	panic("implement me")
+	var params = url.Values{}
+	params.Set("currency_pair", pair.ToSymbol("_", true))
+	if since > 0 {
+		params.Set("from", fmt.Sprintf("%d", since/1000))
+		params.Set("to", fmt.Sprintf("%d", time.Now().Unix()))
+	}
+	params.Set("limit", "1000")
+
+	var response = make([]*struct {
+		Id           string  `json:"id"`
+		CreateTimeMs string  `json:"create_time_ms"`
+		Side         string  `json:"side"`
+		Amount       float64 `json:"amount,string"`
+		Price        float64 `json:"price,string"`
+	}, 0)
+	if _, err := spot.DoRequest(
+		http.MethodGet,
+		"/api/v4/spot/trades",
+		params.Encode(),
+		"",
+		&response,
+	); err != nil {
+		return nil, err
+	}
+
+	var trades = make([]*Trade, 0, len(response))
+	for _, item := range response {
+		var side = BUY
+		if item.Side == "sell" {
+			side = SELL
+		}
+		trades = append(trades, &Trade{
+			Tid:       ToInt64(item.Id),
+			Type:      side,
+			Amount:    item.Amount,
+			Price:     item.Price,
+			Timestamp: int64(ToFloat64(item.CreateTimeMs)),
+			Pair:      pair,
+		})
+	}
+	return trades, nil
 }
 
 func (spot *Spot) GetAccount() (*Account, []byte, error) {
-	panic("implement me")
+	var response = make([]*struct {
+		Currency  string  `json:"currency"`
+		Available float64 `json:"available,string"`
+		Locked    float64 `json:"locked,string"`
+	}, 0)
+	resp, err := spot.DoSignRequest(
+		http.MethodGet,
+		"/api/v4/spot/accounts",
+		"",
+		"",
+		&response,
+	)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	var account = &Account{
+		Exchange:    GATE,
+		SubAccounts: make(map[string]SubAccount, 0),
+	}
+	for _, item := range response {
+		account.SubAccounts[strings.ToUpper(item.Currency)] = SubAccount{
+			Currency:     NewCurrency(item.Currency, ""),
+			Amount:       item.Available,
+			AmountFrozen: item.Locked,
+		}
+	}
+	return account, resp, nil
+}
+
+// spotPlaceGate is the request of the place order and the batch place order.
+type spotPlaceGate struct {
+	Text         string `json:"text,omitempty"`
+	CurrencyPair string `json:"currency_pair"`
+	Type         string `json:"type"`
+	Account      string `json:"account"`
+	Side         string `json:"side"`
+	Amount       string `json:"amount"`
+	Price        string `json:"price,omitempty"`
+	TimeInForce  string `json:"time_in_force"`
 }
 
+// placeRequest build the request by the normalized price and amount. The amount of the market buy order is in the
+// counter currency in gate, it is converted by the Price of the order, so the Price must be the reference price.
+func (spot *Spot) placeRequest(order *Order) (*spotPlaceGate, float64, float64, error) {
+	var sideInfo, exist = _INERNAL_SPOT_SIDE_CONVERTER[order.Side]
+	if !exist {
+		return nil, 0, 0, errors.New("Can not deal the order side. ")
+	}
+	var isMarket = sideInfo[1] == "market" || order.OrderType == MARKET
+	if order.OrderType == MARKET {
+		sideInfo = []string{sideInfo[0], "market"}
+	}
+
+	rule, err := spot.getRule(order.Pair)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	var normalizer = rule.GetNormalizer()
+	price, amount, err := normalizer.Normalize(order.Price, order.Amount, isMarket)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	var request = &spotPlaceGate{
+		CurrencyPair: order.Pair.ToSymbol("_", true),
+		Type:         sideInfo[1],
+		Account:      "spot",
+		Side:         sideInfo[0],
+		Amount:       normalizer.FormatAmount(amount),
+	}
+	if order.Cid != "" {
+		request.Text = "t-" + strings.TrimPrefix(order.Cid, "t-")
+	}
+	if isMarket {
+		// the market order is ioc in gate.
+		request.TimeInForce = "ioc"
+		if request.Side == "buy" {
+			if order.Price <= 0 {
+				return nil, 0, 0, &OrderInvalidError{Field: "price", Msg: "the market buy order need the reference price"}
+			}
+			request.Amount = FloatToString(
+				RoundToStep(amount*order.Price, math.Pow10(-rule.CounterPrecision), ROUND_FLOOR),
+				int64(rule.CounterPrecision),
+			)
+		}
+	} else {
+		var timeInForce, exist = _INERNAL_SPOT_TIME_IN_FORCE_CONVERTER[order.OrderType]
+		if !exist {
+			return nil, 0, 0, errors.New("not support the place type in gate. ")
+		}
+		request.TimeInForce = timeInForce
+		request.Price = normalizer.FormatPrice(price)
+	}
+	return request, price, amount, nil
+}
+
+// PlaceOrder place the order by the normalized price and amount. The amount of the market buy order is in the
+// counter currency in gate, it is converted by the Price of the order, so the Price must be the reference price.
 func (spot *Spot) PlaceOrder(order *Order) ([]byte, error) {
-	panic("implement me")
+	request, price, amount, err := spot.placeRequest(order)
+	if err != nil {
+		return nil, err
+	}
+	reqBody, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	var now = time.Now()
+	order.PlaceTimestamp = now.UnixNano() / int64(time.Millisecond)
+	order.PlaceDatetime = now.In(spot.config.Location).Format(GO_BIRTHDAY)
+	var response = spotOrderGate{}
+	resp, err := spot.DoSignRequest(
+		http.MethodPost,
+		"/api/v4/spot/orders",
+		"",
+		string(reqBody),
+		&response,
+	)
+	if err != nil {
+		return resp, err
+	}
+	if response.Id == "" {
+		return resp, errors.New(string(resp))
+	}
+	order.Price, order.Amount = price, amount
+	response.merge(order, spot.config.Location)
+	return resp, nil
 }
 
 func (spot *Spot) CancelOrder(order *Order) ([]byte, error) {
-	panic("implement me")
+	if order.OrderId == "" {
+		return nil, errors.New("The orderid is empty. ")
+	}
+	var params = url.Values{}
+	params.Set("currency_pair", order.Pair.ToSymbol("_", true))
+
+	var response = spotOrderGate{}
+	resp, err := spot.DoSignRequest(
+		http.MethodDelete,
+		fmt.Sprintf(SPOT_ORDER_URI, order.OrderId),
+		params.Encode(),
+		"",
+		&response,
+	)
+	if err != nil {
+		return resp, err
+	}
+	response.merge(order, spot.config.Location)
+	return resp, nil
+}
+
+const (
+	SPOT_BATCH_PLACE_LIMIT  = 10
+	SPOT_BATCH_CANCEL_LIMIT = 20
+)
+
+// BatchPlaceOrders place the orders by the batch_orders api, 10 orders in one request at most.
+// The cid is required by the batch api of gate.
+func (spot *Spot) BatchPlaceOrders(orders []*Order) ([]error, []byte, error) {
+	var errs = make([]error, len(orders))
+	var requests = make([]*spotPlaceGate, len(orders))
+	var amounts = make([][2]float64, len(orders))
+	var indexes = make([]int, 0, len(orders))
+	for i, order := range orders {
+		if order == nil {
+			errs[i] = errors.New("order param is nil")
+			continue
+		}
+		if order.Cid == "" {
+			errs[i] = &OrderInvalidError{Field: "cid", Msg: "the cid is required by the batch api"}
+			continue
+		}
+		request, price, amount, err := spot.placeRequest(order)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		requests[i], amounts[i] = request, [2]float64{price, amount}
+		indexes = append(indexes, i)
+	}
+
+	var responses = make([][]byte, 0)
+	for _, r := range BatchSplit(len(indexes), SPOT_BATCH_PLACE_LIMIT) {
+		var chunk = indexes[r[0]:r[1]]
+		var request = make([]*spotPlaceGate, 0, len(chunk))
+		for _, i := range chunk {
+			request = append(request, requests[i])
+		}
+		reqBody, _ := json.Marshal(request)
+
+		var now = time.Now()
+		var response = make([]struct {
+			spotOrderGate
+			Succeeded bool   `json:"succeeded"`
+			Label     string `json:"label"`
+			Message   string `json:"message"`
+		}, 0)
+		resp, err := spot.DoSignRequest(
+			http.MethodPost,
+			"/api/v4/spot/batch_orders",
+			"",
+			string(reqBody),
+			&response,
+		)
+		if len(resp) > 0 {
+			responses = append(responses, resp)
+		}
+		for j, i := range chunk {
+			if err != nil {
+				errs[i] = err
+				continue
+			}
+			if j >= len(response) {
+				errs[i] = errors.New(string(resp))
+				continue
+			}
+			if !response[j].Succeeded {
+				errs[i] = fmt.Errorf("%s: %s", response[j].Label, response[j].Message)
+				continue
+			}
+			orders[i].PlaceTimestamp = now.UnixNano() / int64(time.Millisecond)
+			orders[i].PlaceDatetime = now.In(spot.config.Location).Format(GO_BIRTHDAY)
+			orders[i].Price, orders[i].Amount = amounts[i][0], amounts[i][1]
+			response[j].merge(orders[i], spot.config.Location)
+		}
+	}
+	return errs, BatchResponse(responses), BatchResult(errs)
+}
+
+// BatchCancelOrders cancel the orders by the cancel_batch_orders api, 20 orders in one request at most.
+func (spot *Spot) BatchCancelOrders(orders []*Order) ([]error, []byte, error) {
+	type cancelGate struct {
+		CurrencyPair string `json:"currency_pair"`
+		Id           string `json:"id"`
+	}
+	var errs = make([]error, len(orders))
+	var indexes = make([]int, 0, len(orders))
+	for i, order := range orders {
+		if order == nil {
+			errs[i] = errors.New("order param is nil")
+			continue
+		}
+		if order.OrderId == "" {
+			errs[i] = errors.New("The orderid is empty. ")
+			continue
+		}
+		indexes = append(indexes, i)
+	}
+
+	var responses = make([][]byte, 0)
+	for _, r := range BatchSplit(len(indexes), SPOT_BATCH_CANCEL_LIMIT) {
+		var chunk = indexes[r[0]:r[1]]
+		var request = make([]cancelGate, 0, len(chunk))
+		for _, i := range chunk {
+			request = append(request, cancelGate{
+				CurrencyPair: orders[i].Pair.ToSymbol("_", true),
+				Id:           orders[i].OrderId,
+			})
+		}
+		reqBody, _ := json.Marshal(request)
+
+		var response = make([]struct {
+			Id        string `json:"id"`
+			Succeeded bool   `json:"succeeded"`
+			Label     string `json:"label"`
+			Message   string `json:"message"`
+		}, 0)
+		resp, err := spot.DoSignRequest(
+			http.MethodPost,
+			"/api/v4/spot/cancel_batch_orders",
+			"",
+			string(reqBody),
+			&response,
+		)
+		if len(resp) > 0 {
+			responses = append(responses, resp)
+		}
+		for _, i := range chunk {
+			if err != nil {
+				errs[i] = err
+				continue
+			}
+			errs[i] = errors.New(string(resp))
+			for _, result := range response {
+				if result.Id != orders[i].OrderId {
+					continue
+				}
+				if result.Succeeded {
+					errs[i] = nil
+					orders[i].Status = ORDER_CANCEL
+				} else {
+					errs[i] = fmt.Errorf("%s: %s", result.Label, result.Message)
+				}
+				break
+			}
+		}
+	}
+	return errs, BatchResponse(responses), BatchResult(errs)
+}
+
+// AmendOrder modify the open order by the amend api, the amount includes the filled part.
+func (spot *Spot) AmendOrder(order *Order, newPrice, newAmount float64) ([]byte, error) {
+	if order.OrderId == "" {
+		return nil, errors.New("The orderid is empty. ")
+	}
+	if newPrice <= 0 {
+		newPrice = order.Price
+	}
+	if newAmount <= 0 {
+		newAmount = order.Amount
+	}
+
+	rule, err := spot.getRule(order.Pair)
+	if err != nil {
+		return nil, err
+	}
+	var normalizer = rule.GetNormalizer()
+	newPrice, newAmount, err = normalizer.Normalize(newPrice, newAmount, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var params = url.Values{}
+	params.Set("currency_pair", order.Pair.ToSymbol("_", true))
+	var request = struct {
+		Amount string `json:"amount"`
+		Price  string `json:"price"`
+	}{
+		normalizer.FormatAmount(newAmount),
+		normalizer.FormatPrice(newPrice),
+	}
+	reqBody, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	var response = make(map[string]interface{})
+	resp, err := spot.DoSignRequest(
+		http.MethodPatch,
+		fmt.Sprintf(SPOT_ORDER_URI, order.OrderId),
+		params.Encode(),
+		string(reqBody),
+		&response,
+	)
+	if err != nil {
+		return resp, err
+	}
+	order.Price, order.Amount = newPrice, newAmount
+	return resp, nil
+}
+
+func (spot *Spot) CancelAllOrders(pair Pair) ([]byte, error) {
+	var params = url.Values{}
+	params.Set("currency_pair", pair.ToSymbol("_", true))
+	var response = make([]map[string]interface{}, 0)
+	return spot.DoSignRequest(
+		http.MethodDelete,
+		"/api/v4/spot/orders",
+		params.Encode(),
+		"",
+		&response,
+	)
 }
 
 func (spot *Spot) GetOrder(order *Order) ([]byte, error) {
-	panic("implement me")
+	if order.OrderId == "" {
+		return nil, errors.New("The orderid is empty. ")
+	}
+	var params = url.Values{}
+	params.Set("currency_pair", order.Pair.ToSymbol("_", true))
+
+	var response = spotOrderGate{}
+	resp, err := spot.DoSignRequest(
+		http.MethodGet,
+		fmt.Sprintf(SPOT_ORDER_URI, order.OrderId),
+		params.Encode(),
+		"",
+		&response,
+	)
+	if err != nil {
+		return resp, err
+	}
+	if response.Id == "" {
+		return resp, errors.New(string(resp))
+	}
+	response.merge(order, spot.config.Location)
+	return resp, nil
 }
 
+// getOrders return the orders of the status, open or finished.
+func (spot *Spot) getOrders(pair Pair, status string) ([]*Order, []byte, error) {
+	var params = url.Values{}
+	params.Set("currency_pair", pair.ToSymbol("_", true))
+	params.Set("status", status)
+	params.Set("limit", "100")
+
+	var response = make([]*spotOrderGate, 0)
+	resp, err := spot.DoSignRequest(
+		http.MethodGet,
+		"/api/v4/spot/orders",
+		params.Encode(),
+		"",
+		&response,
+	)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	var orders = make([]*Order, 0, len(response))
+	for _, item := range response {
+		var order = &Order{}
+		item.merge(order, spot.config.Location)
+		orders = append(orders, order)
+	}
+	return orders, resp, nil
+}
+
+// GetOrders return the latest finished orders of the pair.
 func (spot *Spot) GetOrders(pair Pair) ([]*Order, error) {
-	panic("implement me")
+	var orders, _, err = spot.getOrders(pair, "finished")
+	return orders, err
 }
 
 func (spot *Spot) GetUnFinishOrders(pair Pair) ([]*Order, []byte, error) {
-	panic("implement me")
+	return spot.getOrders(pair, "open")
 }
 
 func (spot *Spot) KeepAlive() {
-	panic("implement me")
+	nowTimestamp := time.Now().Unix() * 1000
+	if (nowTimestamp - spot.config.LastTimestamp) < 5*1000 {
+		return
+	}
+	_, _, _ = spot.GetTicker(Pair{Basis: BTC, Counter: USDT})
 }
 
+// GetOHLCs the symbol is the gate currency pair, eg: BTC_USDT.
 func (spot *Spot) GetOHLCs(symbol string, period, size, since int) ([]*OHLC, []byte, error) {
-	panic("implement me")
+	var candlesticks, resp, err = spot.getCandlesticks(NewPair(symbol, "_"), period, size, since)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	var ohlcs = make([]*OHLC, 0, len(candlesticks))
+	for _, item := range candlesticks {
+		if len(item) < 7 {
+			continue
+		}
+		var t = time.Unix(ToInt64(item[0]), 0)
+		ohlcs = append(ohlcs, &OHLC{
+			Symbol:    symbol,
+			Exchange:  GATE,
+			Timestamp: t.UnixNano() / int64(time.Millisecond),
+			Date:      t.In(spot.config.Location).Format(GO_BIRTHDAY),
+			Open:      ToFloat64(item[5]),
+			Close:     ToFloat64(item[2]),
+			High:      ToFloat64(item[3]),
+			Low:       ToFloat64(item[4]),
+			Vol:       ToFloat64(item[6]),
+		})
+	}
+	return ohlcs, resp, nil
 }
@@ -0,0 +1,81 @@
+package gate
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	. "github.com/deforceHK/goghostex"
+)
+
+// go test -v ./gate/... -count=1 -run=TestSpot_MarketAPI
+func TestSpot_MarketAPI(t *testing.T) {
+	var gateCli = New(&APIConfig{
+		HttpClient: &http.Client{},
+		Location:   time.Now().Location(),
+	})
+	var pair = Pair{Basis: BTC, Counter: USDT}
+
+	if rule, _, err := gateCli.Spot.GetExchangeRule(pair); err != nil {
+		t.Error(err)
+		return
+	} else {
+		t.Log(*rule)
+	}
+
+	if ticker, _, err := gateCli.Spot.GetTicker(pair); err != nil {
+		t.Error(err)
+		return
+	} else {
+		t.Log(*ticker)
+	}
+
+	if depth, _, err := gateCli.Spot.GetDepth(pair, 20); err != nil {
+		t.Error(err)
+		return
+	} else if err := depth.Verify(); err != nil {
+		t.Error(err)
+		return
+	}
+
+	if klines, _, err := gateCli.Spot.GetKlineRecords(pair, KLINE_PERIOD_1MIN, 10, 0); err != nil {
+		t.Error(err)
+		return
+	} else if len(klines) == 0 {
+		t.Error("the klines are empty. ")
+		return
+	}
+}
+
+// go test -v ./gate/... -count=1 -run=TestSpotOrderGate_Merge
+func TestSpotOrderGate_Merge(t *testing.T) {
+	var raw = `{"id":"1852454420","text":"t-abc","create_time_ms":1710000000123,"update_time_ms":1710000001456,
+		"status":"open","currency_pair":"BTC_USDT","type":"limit","side":"sell","amount":"0.002","price":"70000",
+		"time_in_force":"poc","left":"0.0015","filled_amount":"0.0005","filled_total":"35","avg_deal_price":"70000","fee":"0.007"}`
+	var remote = spotOrderGate{}
+	if err := json.Unmarshal([]byte(raw), &remote); err != nil {
+		t.Error(err)
+		return
+	}
+
+	var order = &Order{}
+	remote.merge(order, time.UTC)
+	if order.OrderId != "1852454420" || order.Cid != "abc" || order.Side != SELL || order.OrderType != ONLY_MAKER ||
+		order.Status != ORDER_PART_FINISH || order.Amount != 0.002 || order.DealAmount != 0.0005 ||
+		order.Pair.String() != (Pair{Basis: BTC, Counter: USDT}).String() {
+		t.Error("the order is wrong: ", *order)
+		return
+	}
+
+	// the amount of the market buy order is in the counter currency, it is not merged.
+	remote = spotOrderGate{
+		Id: "2", Status: "closed", CurrencyPair: "BTC_USDT", Type: "market", Side: "buy",
+		Amount: 70, FilledAmount: 0.001, FilledTotal: 70, AvgDealPrice: 70000,
+	}
+	order = &Order{Amount: 0.001}
+	remote.merge(order, time.UTC)
+	if order.Side != BUY_MARKET || order.Status != ORDER_FINISH || order.Amount != 0.001 || order.DealAmount != 0.001 {
+		t.Error("the market order is wrong: ", *order)
+	}
+}
@@ -4,9 +4,12 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	. "github.com/deforceHK/goghostex"
@@ -14,6 +17,8 @@ import (
 
 type Swap struct {
 	*Gate
+
+	contracts sync.Map // pair.String() => *SwapContract, the cache of the contracts for the orders
 }
 
 //func (swap *Swap) GetExchangeRule(pair Pair) (*SwapRule, []byte, error) {
@@ -156,15 +161,131 @@ func (swap *Swap) GetDepth(pair Pair, size int) (*SwapDepth, []byte, error) {
 	}
 }
 
+// swapContractGate is the item of the contracts api, the sizes are in contracts.
+type swapContractGate struct {
+	Name              string  `json:"name"`
+	Type              string  `json:"type"` // direct: usdt settle, inverse: btc settle
+	QuantoMultiplier  float64 `json:"quanto_multiplier,string"`
+	OrderPriceRound   float64 `json:"order_price_round,string"`
+	OrderSizeMin      float64 `json:"order_size_min"`
+	OrderSizeMax      float64 `json:"order_size_max"`
+	MarkPrice         float64 `json:"mark_price,string"`
+	OrderPriceDeviate float64 `json:"order_price_deviate,string"`
+}
+
+func (swap *Swap) getContractGate(pair Pair) (*swapContractGate, []byte, error) {
+	var response = &swapContractGate{}
+	resp, err := swap.DoRequest(
+		http.MethodGet,
+		fmt.Sprintf(SWAP_CONTRACT_URI, swap.getSettle(pair), pair.ToSymbol("_", true)),
+		"",
+		"",
+		response,
+	)
+	if err != nil {
+		return nil, resp, err
+	}
+	if response.Name == "" {
+		return nil, resp, errors.New("Can not find the contract in exchange. ")
+	}
+	return response, resp, nil
+}
+
+// getContract return the cached contract of the pair, the contract is queried at the first time. The amount of the
+// usdt settle contract is in the basis currency and the step is the quanto multiplier, the btc settle contract is
+// 1 usd, its amount is in the basis currency too and it is converted to the contracts by the price.
+func (swap *Swap) getContract(pair Pair) (*SwapContract, error) {
+	if contract, exist := swap.contracts.Load(pair.String()); exist {
+		return contract.(*SwapContract), nil
+	}
+	var info, _, err = swap.getContractGate(pair)
+	if err != nil {
+		return nil, err
+	}
+
+	var contract = &SwapContract{
+		Pair:           pair,
+		Symbol:         pair.ToSymbol("_", false),
+		Exchange:       GATE,
+		ContractName:   pair.ToSwapContractName(),
+		TickSize:       info.OrderPriceRound,
+		PricePrecision: GetPrecisionInt64(info.OrderPriceRound),
+	}
+	if info.Type == "inverse" {
+		contract.SettleMode = SETTLE_MODE_BASIS
+		contract.UnitAmount = 1
+		contract.AmountPrecision = 8
+	} else {
+		contract.SettleMode = SETTLE_MODE_COUNTER
+		contract.UnitAmount = info.QuantoMultiplier
+		contract.AmountPrecision = GetPrecisionInt64(info.QuantoMultiplier)
+		contract.LotSize = info.QuantoMultiplier
+		contract.MinAmount = info.OrderSizeMin * info.QuantoMultiplier
+	}
+	swap.contracts.Store(pair.String(), contract)
+	return contract, nil
+}
+
+// toContracts convert the amount in the basis currency to the contracts.
+func toContracts(contract *SwapContract, amount, price float64) int64 {
+	if contract.SettleMode == SETTLE_MODE_BASIS {
+		return int64(math.Round(amount * price / contract.UnitAmount))
+	}
+	return int64(math.Round(amount / contract.UnitAmount))
+}
+
+// toAmount convert the contracts to the amount in the basis currency, it is 0 in the btc settle without the price.
+func toAmount(contract *SwapContract, contracts, price float64) float64 {
+	var amount = contracts * contract.UnitAmount
+	if contract.SettleMode == SETTLE_MODE_BASIS {
+		if price <= 0 {
+			return 0
+		}
+		amount = amount / price
+	}
+	return ToFloat64(FloatToString(amount, contract.AmountPrecision))
+}
+
+// GetContract return nil when the contract can not be queried.
 func (swap *Swap) GetContract(pair Pair) *SwapContract {
-	panic("implement me")
+	var contract, err = swap.getContract(pair)
+	if err != nil {
+		swap.config.GetLogger().Error("get contract failed", LogF(LOG_FIELD_EXCHANGE, GATE), LogF(LOG_FIELD_ERROR, err))
+		return nil
+	}
+	return contract
 }
 
+// GetLimit return the highest and the lowest order price, gate limit the deviation from the mark price.
 func (swap *Swap) GetLimit(pair Pair) (float64, float64, error) {
-	panic("implement me")
+	var info, _, err = swap.getContractGate(pair)
+	if err != nil {
+		return 0, 0, err
+	}
+	var highest = RoundToStep(info.MarkPrice*(1+info.OrderPriceDeviate), info.OrderPriceRound, ROUND_FLOOR)
+	var lowest = RoundToStep(info.MarkPrice*(1-info.OrderPriceDeviate), info.OrderPriceRound, ROUND_CEIL)
+	return highest, lowest, nil
 }
 
 func (swap *Swap) GetKline(pair Pair, period, size, since int) ([]*SwapKline, []byte, error) {
+	return swap.getKline("", pair, period, size, since)
+}
+
+func (swap *Swap) GetMarkKline(pair Pair, period, size, since int) ([]*SwapKline, []byte, error) {
+	return swap.getKline("mark_", pair, period, size, since)
+}
+
+func (swap *Swap) GetIndexKline(pair Pair, period, size, since int) ([]*SwapKline, []byte, error) {
+	return swap.getKline("index_", pair, period, size, since)
+}
+
+// GetPremiumKline gate has no premium index kline, the premium is the mark kline minus the index kline.
+func (swap *Swap) GetPremiumKline(pair Pair, period, size, since int) ([]*SwapKline, []byte, error) {
+	return nil, nil, errors.New("gate has no premium index kline. ")
+}
+
+// getKline request the candlesticks api, the contract with the mark_ or the index_ prefix is the price kline.
+func (swap *Swap) getKline(prefix string, pair Pair, period, size, since int) ([]*SwapKline, []byte, error) {
 	uri := "/api/v4/futures/%s/candlesticks"
 	symbol := pair.ToSymbol("_", true)
 	settle := ""
@@ -180,7 +301,7 @@ func (swap *Swap) GetKline(pair Pair, period, size, since int) ([]*SwapKline, []
 
 	params := url.Values{}
 	params.Add("settle", settle)
-	params.Add("contract", symbol)
+	params.Add("contract", prefix+symbol)
 	params.Add("interval", _INERNAL_KLINE_PERIOD_CONVERTER[period])
 	params.Add("limit", fmt.Sprintf("%d", size))
 
@@ -222,7 +343,11 @@ func (swap *Swap) GetKline(pair Pair, period, size, since int) ([]*SwapKline, []
 }
 
 func (swap *Swap) GetOpenAmount(pair Pair) (float64, int64, []byte, error) {
-	panic("implement me")
+	var openInterest, resp, err = swap.GetOpenInterest(pair)
+	if err != nil {
+		return 0, 0, resp, err
+	}
+	return openInterest.Amount, openInterest.Timestamp, resp, nil
 }
 
 func (swap *Swap) GetFundingFees(pair Pair) ([][]interface{}, []byte, error) {
@@ -289,6 +414,99 @@ func (swap *Swap) GetFundingFee(pair Pair) (float64, error) {
 	}
 }
 
+const (
+	SWAP_FUNDING_LIMIT    = 1000
+	SWAP_FUNDING_INTERVAL = 8 * 60 * 60 * 1000 // unit: ms
+)
+
+// GetFundingRateHistory page the funding_rate api backward from the end time.
+func (swap *Swap) GetFundingRateHistory(pair Pair, start, end time.Time) ([]*FundingRate, []byte, error) {
+	var rates = make([]*FundingRate, 0)
+	var to = end.Unix()
+	var resp []byte
+	for to >= start.Unix() {
+		var params = url.Values{}
+		params.Set("contract", pair.ToSymbol("_", true))
+		params.Set("from", fmt.Sprintf("%d", start.Unix()))
+		params.Set("to", fmt.Sprintf("%d", to))
+		params.Set("limit", fmt.Sprintf("%d", SWAP_FUNDING_LIMIT))
+		var response = make([]struct {
+			T int64   `json:"t"`
+			R float64 `json:"r,string"`
+		}, 0)
+		var err error
+		resp, err = swap.DoRequest(
+			http.MethodGet,
+			fmt.Sprintf("/api/v4/futures/%s/funding_rate", swap.getSettle(pair)),
+			params.Encode(),
+			"",
+			&response,
+		)
+		if err != nil {
+			return nil, resp, err
+		}
+
+		var earliest = to
+		for _, item := range response {
+			rates = append(rates, &FundingRate{
+				Pair:        pair,
+				Exchange:    GATE,
+				Rate:        item.R,
+				FundingTime: item.T * 1000,
+			})
+			if item.T < earliest {
+				earliest = item.T
+			}
+		}
+		if len(response) < SWAP_FUNDING_LIMIT {
+			break
+		}
+		to = earliest - 1
+	}
+	SortFundingRates(rates, SWAP_FUNDING_INTERVAL)
+	return rates, resp, nil
+}
+
+// GetNextFundingRate return the indicative rate of the contract, it is the funding rate if no indicative one.
+func (swap *Swap) GetNextFundingRate(pair Pair) (*FundingRate, []byte, error) {
+	var response = struct {
+		MarkPrice             float64 `json:"mark_price,string"`
+		FundingRate           float64 `json:"funding_rate,string"`
+		FundingRateIndicative string  `json:"funding_rate_indicative"`
+		FundingInterval       int64   `json:"funding_interval"`
+		FundingNextApply      int64   `json:"funding_next_apply"`
+	}{}
+	resp, err := swap.DoRequest(
+		http.MethodGet,
+		fmt.Sprintf(SWAP_CONTRACT_URI, swap.getSettle(pair), pair.ToSymbol("_", true)),
+		"",
+		"",
+		&response,
+	)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	var rate = &FundingRate{
+		Pair:        pair,
+		Exchange:    GATE,
+		Rate:        response.FundingRate,
+		IsPredicted: true,
+		FundingTime: response.FundingNextApply * 1000,
+		Interval:    response.FundingInterval * 1000,
+		MarkPrice:   response.MarkPrice,
+	}
+	if response.FundingRateIndicative != "" {
+		if indicative, err := strconv.ParseFloat(response.FundingRateIndicative, 64); err == nil {
+			rate.Rate = indicative
+		}
+	}
+	if rate.Interval == 0 {
+		rate.Interval = SWAP_FUNDING_INTERVAL
+	}
+	return rate, resp, nil
+}
+
 func (swap *Swap) GetAccount() (*SwapAccount, []byte, error) {
 	uri := "/api/v4/futures/usdt/accounts"
 	rawResp := struct {
@@ -336,8 +554,14 @@ func (swap *Swap) PlaceOrder(order *SwapOrder) ([]byte, error) {
 		settle = strings.ToLower(order.Pair.Counter.Symbol)
 	}
 
+	contract, err := swap.getContract(order.Pair)
+	if err != nil {
+		return nil, err
+	}
 	sog := &SwapOrderGate{}
-	sog.Merge(order)
+	if err := sog.Merge(order, contract); err != nil {
+		return nil, err
+	}
 	reqBody, err := json.Marshal(sog)
 	if err != nil {
 		return nil, err
@@ -354,6 +578,9 @@ func (swap *Swap) PlaceOrder(order *SwapOrder) ([]byte, error) {
 		string(reqBody),
 		&response,
 	)
+	if err != nil {
+		return resp, err
+	}
 	placeDate := time.Unix(response.CreateTime, 0).In(swap.config.Location).Format(GO_BIRTHDAY)
 	order.OrderId = fmt.Sprintf("%d", response.Id)
 	order.PlaceTimestamp = response.CreateTime * 1000
@@ -362,7 +589,7 @@ func (swap *Swap) PlaceOrder(order *SwapOrder) ([]byte, error) {
 }
 
 func (swap *Swap) CancelOrder(order *SwapOrder) ([]byte, error) {
-	uri := "/api/v4/futures/%s/orders/%s"
+	uri := SWAP_ORDER_URI
 	symbol := order.Pair.ToSymbol("_", true)
 	settle := strings.ToLower(order.Pair.Basis.Symbol)
 	if strings.Index(symbol, "_USDT") > 0 {
@@ -388,8 +615,13 @@ func (swap *Swap) CancelOrder(order *SwapOrder) ([]byte, error) {
 	if err != nil {
 		return resp, err
 	}
+	contract, err := swap.getContract(order.Pair)
+	if err != nil {
+		return resp, err
+	}
 
-	order.DealAmount = float64(response.Size - response.Left)
+	// the size and the left are negative in the short side.
+	order.DealAmount = toAmount(contract, math.Abs(float64(response.Size-response.Left)), response.FillPrice)
 	if response.Status == "finished" {
 		order.Status = ORDER_CANCEL
 		order.AvgPrice = response.FillPrice
@@ -401,8 +633,247 @@ func (swap *Swap) CancelOrder(order *SwapOrder) ([]byte, error) {
 	return resp, nil
 }
 
+const (
+	SWAP_BATCH_PLACE_LIMIT  = 10
+	SWAP_BATCH_CANCEL_LIMIT = 20
+)
+
+// getSettle return the settle currency of the pair in the futures uri.
+func (swap *Swap) getSettle(pair Pair) string {
+	if strings.Index(pair.ToSymbol("_", true), "_USDT") > 0 {
+		return strings.ToLower(pair.Counter.Symbol)
+	}
+	return strings.ToLower(pair.Basis.Symbol)
+}
+
+// groupBySettle group the index of the orders by the settle currency, the batch api is in one settle.
+func (swap *Swap) groupBySettle(orders []*SwapOrder, errs []error) ([]string, map[string][]int) {
+	var settles = make([]string, 0)
+	var groups = make(map[string][]int)
+	for i, order := range orders {
+		if errs[i] != nil {
+			continue
+		}
+		var settle = swap.getSettle(order.Pair)
+		if _, exist := groups[settle]; !exist {
+			settles = append(settles, settle)
+		}
+		groups[settle] = append(groups[settle], i)
+	}
+	return settles, groups
+}
+
+// BatchPlaceOrders place the orders by the batch_orders api, 10 orders in one request at most.
+func (swap *Swap) BatchPlaceOrders(orders []*SwapOrder) ([]error, []byte, error) {
+	var errs = make([]error, len(orders))
+	var sogs = make([]*SwapOrderGate, len(orders))
+	for i, order := range orders {
+		sogs[i] = &SwapOrderGate{}
+		contract, err := swap.getContract(order.Pair)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		if err := sogs[i].Merge(order, contract); err != nil {
+			errs[i] = err
+		}
+	}
+
+	var responses = make([][]byte, 0)
+	var settles, groups = swap.groupBySettle(orders, errs)
+	for _, settle := range settles {
+		for _, r := range BatchSplit(len(groups[settle]), SWAP_BATCH_PLACE_LIMIT) {
+			var indexes = groups[settle][r[0]:r[1]]
+			var request = make([]*SwapOrderGate, 0, len(indexes))
+			for _, i := range indexes {
+				request = append(request, sogs[i])
+			}
+			reqBody, _ := json.Marshal(request)
+
+			var response = make([]struct {
+				Succeeded  bool   `json:"succeeded"`
+				Label      string `json:"label"`
+				Detail     string `json:"detail"`
+				Id         int64  `json:"id"`
+				CreateTime int64  `json:"create_time"`
+			}, 0)
+			resp, err := swap.DoSignRequest(
+				http.MethodPost,
+				fmt.Sprintf("/api/v4/futures/%s/batch_orders", settle),
+				"",
+				string(reqBody),
+				&response,
+			)
+			if len(resp) > 0 {
+				responses = append(responses, resp)
+			}
+			for j, i := range indexes {
+				if err != nil {
+					errs[i] = err
+					continue
+				}
+				if j >= len(response) {
+					errs[i] = errors.New(string(resp))
+					continue
+				}
+				if !response[j].Succeeded {
+					errs[i] = fmt.Errorf("%s: %s", response[j].Label, response[j].Detail)
+					continue
+				}
+				orders[i].OrderId = fmt.Sprintf("%d", response[j].Id)
+				orders[i].PlaceTimestamp = response[j].CreateTime * 1000
+				orders[i].PlaceDatetime = time.Unix(
+					response[j].CreateTime, 0,
+				).In(swap.config.Location).Format(GO_BIRTHDAY)
+			}
+		}
+	}
+	return errs, BatchResponse(responses), BatchResult(errs)
+}
+
+// BatchCancelOrders cancel the orders by the batch_cancel_orders api, 20 orders in one request at most.
+func (swap *Swap) BatchCancelOrders(orders []*SwapOrder) ([]error, []byte, error) {
+	var errs = make([]error, len(orders))
+	for i, order := range orders {
+		if order.OrderId == "" {
+			errs[i] = errors.New("The orderid is empty. ")
+		}
+	}
+
+	var responses = make([][]byte, 0)
+	var settles, groups = swap.groupBySettle(orders, errs)
+	for _, settle := range settles {
+		for _, r := range BatchSplit(len(groups[settle]), SWAP_BATCH_CANCEL_LIMIT) {
+			var indexes = groups[settle][r[0]:r[1]]
+			var request = make([]string, 0, len(indexes))
+			for _, i := range indexes {
+				request = append(request, orders[i].OrderId)
+			}
+			reqBody, _ := json.Marshal(request)
+
+			var response = make([]struct {
+				Id        string `json:"id"`
+				Succeeded bool   `json:"succeeded"`
+				Message   string `json:"message"`
+			}, 0)
+			resp, err := swap.DoSignRequest(
+				http.MethodPost,
+				fmt.Sprintf("/api/v4/futures/%s/batch_cancel_orders", settle),
+				"",
+				string(reqBody),
+				&response,
+			)
+			if len(resp) > 0 {
+				responses = append(responses, resp)
+			}
+			for _, i := range indexes {
+				if err != nil {
+					errs[i] = err
+					continue
+				}
+				errs[i] = errors.New(string(resp))
+				for _, result := range response {
+					if result.Id != orders[i].OrderId {
+						continue
+					}
+					if result.Succeeded {
+						errs[i] = nil
+						orders[i].Status = ORDER_CANCEL
+					} else {
+						errs[i] = errors.New(result.Message)
+					}
+					break
+				}
+			}
+		}
+	}
+	return errs, BatchResponse(responses), BatchResult(errs)
+}
+
+// AmendOrder modify the open order by the amend api, the size includes the filled part.
+func (swap *Swap) AmendOrder(order *SwapOrder, newPrice, newAmount float64) ([]byte, error) {
+	if order.OrderId == "" {
+		return nil, errors.New("The orderid is empty. ")
+	}
+	if newPrice <= 0 {
+		newPrice = order.Price
+	}
+	if newAmount <= 0 {
+		newAmount = order.Amount
+	}
+
+	contract, err := swap.getContract(order.Pair)
+	if err != nil {
+		return nil, err
+	}
+	var amended = *order
+	amended.Price, amended.Amount = newPrice, newAmount
+	var sog = &SwapOrderGate{}
+	if err := sog.Merge(&amended, contract); err != nil {
+		return nil, err
+	}
+	var request = struct {
+		Size  int64   `json:"size"`
+		Price float64 `json:"price,string"`
+	}{sog.Size, sog.Price}
+	reqBody, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	var response = SwapOrderGate{}
+	resp, err := swap.DoSignRequest(
+		http.MethodPut,
+		fmt.Sprintf(SWAP_ORDER_URI, swap.getSettle(order.Pair), order.OrderId),
+		"",
+		string(reqBody),
+		&response,
+	)
+	if err != nil {
+		return resp, err
+	}
+	order.Price, order.Amount = newPrice, newAmount
+	return resp, nil
+}
+
+func (swap *Swap) CancelAllOrders(pair Pair) ([]byte, error) {
+	var params = url.Values{}
+	params.Set("contract", pair.ToSymbol("_", true))
+	var response = make([]*SwapOrderGate, 0)
+	return swap.DoSignRequest(
+		http.MethodDelete,
+		fmt.Sprintf("/api/v4/futures/%s/orders", swap.getSettle(pair)),
+		params.Encode(),
+		"",
+		&response,
+	)
+}
+
+// CancelAllAfter set the countdown_cancel_all of the contract, the timeout is 0 or at least 5 seconds.
+func (swap *Swap) CancelAllAfter(pair Pair, timeout time.Duration) ([]byte, error) {
+	var request = struct {
+		Timeout  int64  `json:"timeout"`
+		Contract string `json:"contract"`
+	}{int64(timeout.Seconds()), pair.ToSymbol("_", true)}
+	reqBody, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		TriggerTime int64 `json:"triggerTime"`
+	}
+	return swap.DoSignRequest(
+		http.MethodPost,
+		fmt.Sprintf("/api/v4/futures/%s/countdown_cancel_all", swap.getSettle(pair)),
+		"",
+		string(reqBody),
+		&response,
+	)
+}
+
 func (swap *Swap) GetOrder(order *SwapOrder) ([]byte, error) {
-	uri := "/api/v4/futures/%s/orders/%s"
+	uri := SWAP_ORDER_URI
 	symbol := order.Pair.ToSymbol("_", true)
 	settle := strings.ToLower(order.Pair.Basis.Symbol)
 	if strings.Index(symbol, "_USDT") > 0 {
@@ -430,8 +901,13 @@ func (swap *Swap) GetOrder(order *SwapOrder) ([]byte, error) {
 	if err != nil {
 		return resp, err
 	}
+	contract, err := swap.getContract(order.Pair)
+	if err != nil {
+		return resp, err
+	}
 
-	order.DealAmount = float64(response.Size - response.Left)
+	// the size and the left are negative in the short side.
+	order.DealAmount = toAmount(contract, math.Abs(float64(response.Size-response.Left)), response.FillPrice)
 	if response.Status == "finished" {
 		order.Status = ORDER_FINISH
 		order.AvgPrice = response.FillPrice
@@ -473,9 +949,13 @@ func (swap *Swap) GetOrders(pair Pair) ([]*SwapOrder, []byte, error) {
 	if err != nil {
 		return orders, resp, err
 	}
+	contract, err := swap.getContract(pair)
+	if err != nil {
+		return orders, resp, err
+	}
 
 	for _, o := range response {
-		so := o.New(swap.config.Location)
+		so := o.New(swap.config.Location, contract)
 		orders = append(orders, so)
 	}
 	return orders, resp, nil
@@ -505,28 +985,347 @@ func (swap *Swap) GetUnFinishOrders(pair Pair) ([]*SwapOrder, []byte, error) {
 	if err != nil {
 		return orders, resp, err
 	}
+	contract, err := swap.getContract(pair)
+	if err != nil {
+		return orders, resp, err
+	}
 
 	for _, o := range response {
-		so := o.New(swap.config.Location)
+		so := o.New(swap.config.Location, contract)
 		orders = append(orders, so)
 	}
 	return orders, resp, nil
 }
 
+type swapPositionGate struct {
+	Contract           string  `json:"contract"`
+	Size               int64   `json:"size"`
+	Leverage           int64   `json:"leverage,string"` // 0 means the cross margin
+	CrossLeverageLimit int64   `json:"cross_leverage_limit,string"`
+	Margin             float64 `json:"margin,string"`
+	EntryPrice         float64 `json:"entry_price,string"`
+	MarkPrice          float64 `json:"mark_price,string"`
+	LiqPrice           float64 `json:"liq_price,string"`
+	Mode               string  `json:"mode"` // single, dual_long, dual_short
+}
+
+// the open type of the position, the single mode position is decided by the sign of the size.
+func (pg *swapPositionGate) openType() FutureType {
+	if pg.Mode == "dual_short" || (pg.Mode != "dual_long" && pg.Size < 0) {
+		return OPEN_SHORT
+	}
+	return OPEN_LONG
+}
+
+func (pg *swapPositionGate) toPosition(pair Pair, contract *SwapContract) *SwapPosition {
+	var position = &SwapPosition{
+		Pair:           pair,
+		Type:           pg.openType(),
+		Amount:         toAmount(contract, math.Abs(float64(pg.Size)), pg.EntryPrice),
+		Price:          pg.EntryPrice,
+		MarkPrice:      pg.MarkPrice,
+		LiquidatePrice: pg.LiqPrice,
+		MarginType:     ISOLATED,
+		MarginAmount:   pg.Margin,
+		Leverage:       pg.Leverage,
+	}
+	if pg.Leverage == 0 {
+		position.MarginType = CROSS
+		position.Leverage = pg.CrossLeverageLimit
+	}
+	return position
+}
+
+// GetPosition return the holding position of the open type, the amount is in the basis currency.
 func (swap *Swap) GetPosition(pair Pair, openType FutureType) (*SwapPosition, []byte, error) {
-	panic("implement me")
+	var params = url.Values{}
+	params.Set("holding", "true")
+	var response = make([]*swapPositionGate, 0)
+	resp, err := swap.DoSignRequest(
+		http.MethodGet,
+		fmt.Sprintf("/api/v4/futures/%s/positions", swap.getSettle(pair)),
+		params.Encode(),
+		"",
+		&response,
+	)
+	if err != nil {
+		return nil, resp, err
+	}
+	contract, err := swap.getContract(pair)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	var symbol = pair.ToSymbol("_", true)
+	for _, pg := range response {
+		if pg.Contract != symbol || pg.Size == 0 || pg.openType() != openType {
+			continue
+		}
+		return pg.toPosition(pair, contract), resp, nil
+	}
+	return nil, resp, errors.New("Can not find the position. ")
+}
+
+// GetPositionMode return the dual mode of the futures account in the settle currency of the pair.
+func (swap *Swap) GetPositionMode(pair Pair) (PositionMode, []byte, error) {
+	var response struct {
+		InDualMode bool `json:"in_dual_mode"`
+	}
+	resp, err := swap.DoSignRequest(
+		http.MethodGet,
+		fmt.Sprintf("/api/v4/futures/%s/accounts", swap.getSettle(pair)),
+		"",
+		"",
+		&response,
+	)
+	if err != nil {
+		return POSITION_MODE_ONE_WAY, resp, err
+	}
+	if response.InDualMode {
+		return POSITION_MODE_HEDGE, resp, nil
+	}
+	return POSITION_MODE_ONE_WAY, resp, nil
+}
+
+// SetPositionMode change the dual mode of the settle currency, it fails when there are open orders or positions.
+func (swap *Swap) SetPositionMode(pair Pair, mode PositionMode) ([]byte, error) {
+	var params = url.Values{}
+	params.Set("dual_mode", fmt.Sprintf("%t", mode == POSITION_MODE_HEDGE))
+	var response struct {
+		InDualMode bool `json:"in_dual_mode"`
+	}
+	return swap.DoSignRequest(
+		http.MethodPost,
+		fmt.Sprintf("/api/v4/futures/%s/dual_mode", swap.getSettle(pair)),
+		params.Encode(),
+		"",
+		&response,
+	)
+}
+
+type swapLeverageGate struct {
+	Leverage           int64 `json:"leverage,string"` // 0 means the cross margin
+	CrossLeverageLimit int64 `json:"cross_leverage_limit,string"`
+	LeverageMax        int64 `json:"leverage_max,string"`
+}
+
+// the current leverage of the contract, it is the cross leverage limit in the cross margin.
+func (lg *swapLeverageGate) current() int64 {
+	if lg.Leverage > 0 {
+		return lg.Leverage
+	}
+	return lg.CrossLeverageLimit
+}
+
+func (swap *Swap) getPositionLeverage(pair Pair) (*swapLeverageGate, []byte, error) {
+	var response = &swapLeverageGate{}
+	resp, err := swap.DoSignRequest(
+		http.MethodGet,
+		fmt.Sprintf(SWAP_POSITION_URI, swap.getSettle(pair), pair.ToSymbol("_", true)),
+		"",
+		"",
+		response,
+	)
+	return response, resp, err
+}
+
+// postLeverage set the leverage of the contract, the leverage 0 means the cross margin with the cross leverage limit.
+func (swap *Swap) postLeverage(pair Pair, leverage, crossLimit int64) ([]byte, error) {
+	var params = url.Values{}
+	params.Set("leverage", fmt.Sprintf("%d", leverage))
+	if leverage == 0 {
+		params.Set("cross_leverage_limit", fmt.Sprintf("%d", crossLimit))
+	}
+	var response = swapLeverageGate{}
+	return swap.DoSignRequest(
+		http.MethodPost,
+		fmt.Sprintf(SWAP_LEVERAGE_URI, swap.getSettle(pair), pair.ToSymbol("_", true)),
+		params.Encode(),
+		"",
+		&response,
+	)
+}
+
+// SetLeverage keep the margin mode of the contract, the dual mode position is not supported.
+func (swap *Swap) SetLeverage(pair Pair, lever int64, side PositionSide) ([]byte, error) {
+	if lever <= 0 {
+		return nil, errors.New("the leverage must be positive. ")
+	}
+	position, resp, err := swap.getPositionLeverage(pair)
+	if err != nil {
+		return resp, err
+	}
+	if position.Leverage == 0 {
+		return swap.postLeverage(pair, 0, lever)
+	}
+	return swap.postLeverage(pair, lever, 0)
+}
+
+func (swap *Swap) GetLeverage(pair Pair, side PositionSide) (int64, []byte, error) {
+	position, resp, err := swap.getPositionLeverage(pair)
+	if err != nil {
+		return 0, resp, err
+	}
+	return position.current(), resp, nil
+}
+
+// SetMarginMode switch the margin mode and keep the current leverage, gate use the leverage 0 as the cross margin.
+func (swap *Swap) SetMarginMode(pair Pair, marginType string) ([]byte, error) {
+	if marginType != CROSS && marginType != ISOLATED {
+		return nil, fmt.Errorf("the margin type %s is not supported. ", marginType)
+	}
+	position, resp, err := swap.getPositionLeverage(pair)
+	if err != nil {
+		return resp, err
+	}
+	var isCross = position.Leverage == 0
+	if isCross == (marginType == CROSS) {
+		return resp, nil
+	}
+	if marginType == CROSS {
+		return swap.postLeverage(pair, 0, position.current())
+	}
+	return swap.postLeverage(pair, position.current(), 0)
+}
+
+// GetLeverageBrackets return the risk limit tiers of the contract, the Floor and the Cap are the position value.
+func (swap *Swap) GetLeverageBrackets(pair Pair) ([]*LeverageBracket, []byte, error) {
+	var params = url.Values{}
+	params.Set("contract", pair.ToSymbol("_", true))
+	var response = make([]struct {
+		Tier            int64   `json:"tier"`
+		RiskLimit       float64 `json:"risk_limit,string"`
+		MaintenanceRate float64 `json:"maintenance_rate,string"`
+		LeverageMax     float64 `json:"leverage_max,string"`
+	}, 0)
+	resp, err := swap.DoSignRequest(
+		http.MethodGet,
+		fmt.Sprintf("/api/v4/futures/%s/risk_limit_tiers", swap.getSettle(pair)),
+		params.Encode(),
+		"",
+		&response,
+	)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	var brackets = make([]*LeverageBracket, 0, len(response))
+	var floor = 0.0
+	for _, tier := range response {
+		brackets = append(brackets, &LeverageBracket{
+			Pair:             pair,
+			Tier:             tier.Tier,
+			Floor:            floor,
+			Cap:              tier.RiskLimit,
+			MaxLeverage:      tier.LeverageMax,
+			MaintMarginRatio: tier.MaintenanceRate,
+		})
+		floor = tier.RiskLimit
+	}
+	return brackets, resp, nil
 }
 
 func (swap *Swap) AddMargin(pair Pair, openType FutureType, marginAmount float64) ([]byte, error) {
-	panic("implement me")
+	return swap.modifyMargin(pair, openType, marginAmount)
 }
 
 func (swap *Swap) ReduceMargin(pair Pair, openType FutureType, marginAmount float64) ([]byte, error) {
-	panic("implement me")
+	return swap.modifyMargin(pair, openType, -marginAmount)
+}
+
+// modifyMargin change the isolated margin of the position, the dual mode position use the dual_comp uri.
+func (swap *Swap) modifyMargin(pair Pair, openType FutureType, change float64) ([]byte, error) {
+	if openType != OPEN_LONG && openType != OPEN_SHORT {
+		return nil, errors.New("the open type must be open_long or open_short. ")
+	}
+	mode, resp, err := swap.GetPositionMode(pair)
+	if err != nil {
+		return resp, err
+	}
+
+	var params = url.Values{}
+	params.Set("change", FloatToString(change, 8))
+	var uri = fmt.Sprintf(SWAP_MARGIN_URI, swap.getSettle(pair), pair.ToSymbol("_", true))
+	var response interface{}
+	if mode == POSITION_MODE_HEDGE {
+		uri = fmt.Sprintf(SWAP_DUAL_MARGIN_URI, swap.getSettle(pair), pair.ToSymbol("_", true))
+		params.Set("dual_side", "dual_long")
+		if openType == OPEN_SHORT {
+			params.Set("dual_side", "dual_short")
+		}
+	}
+	return swap.DoSignRequest(http.MethodPost, uri, params.Encode(), "", &response)
+}
+
+var _INERNAL_SWAP_SUBJECT_CONVERTER = map[string]string{
+	"pnl":  SUBJECT_SETTLE,
+	"fee":  SUBJECT_COMMISSION,
+	"fund": SUBJECT_FUNDING_FEE,
 }
 
+// only have funding_fee commision settle in the usdt settle account.
 func (swap *Swap) GetAccountFlow() ([]*SwapAccountItem, []byte, error) {
-	panic("implement me")
+	return swap.getAccountFlow("usdt", "")
+}
+
+func (swap *Swap) GetPairFlow(pair Pair) ([]*SwapAccountItem, []byte, error) {
+	return swap.getAccountFlow(swap.getSettle(pair), pair.ToSymbol("_", true))
+}
+
+func (swap *Swap) getAccountFlow(settle, symbol string) ([]*SwapAccountItem, []byte, error) {
+	var params = url.Values{}
+	if symbol != "" {
+		params.Set("contract", symbol)
+	}
+	var responses = make([]*struct {
+		Id       string  `json:"id"`
+		Time     float64 `json:"time"`
+		Change   float64 `json:"change,string"`
+		Balance  float64 `json:"balance,string"`
+		Type     string  `json:"type"`
+		Text     string  `json:"text"`
+		Contract string  `json:"contract"`
+		TradeId  string  `json:"trade_id"`
+	}, 0)
+	resp, err := swap.DoSignRequest(
+		http.MethodGet,
+		fmt.Sprintf("/api/v4/futures/%s/account_book", settle),
+		params.Encode(),
+		"",
+		&responses,
+	)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	var settleMode, settleCurrency = SETTLE_MODE_COUNTER, NewCurrency(settle, "")
+	if settle != "usdt" {
+		settleMode = SETTLE_MODE_BASIS
+	}
+	var items = make([]*SwapAccountItem, 0)
+	// the account book is in the desc order of the time.
+	for i := len(responses) - 1; i >= 0; i-- {
+		var r = responses[i]
+		var subject, exist = _INERNAL_SWAP_SUBJECT_CONVERTER[r.Type]
+		if !exist || r.Contract == "" {
+			continue
+		}
+		var timestamp = int64(r.Time * 1000)
+		var info, _ = json.Marshal(r)
+		items = append(items, &SwapAccountItem{
+			Pair:           NewPair(r.Contract, "_"),
+			Exchange:       GATE,
+			Subject:        subject,
+			Id:             r.Id,
+			SettleMode:     settleMode,
+			SettleCurrency: settleCurrency,
+			Amount:         r.Change,
+			Timestamp:      timestamp,
+			DateTime:       time.UnixMilli(timestamp).In(swap.config.Location).Format(GO_BIRTHDAY),
+			Info:           string(info),
+		})
+	}
+	return items, resp, nil
 }
 
 func (swap *Swap) KeepAlive() {
@@ -0,0 +1,248 @@
+package gate
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+
+	. "github.com/deforceHK/goghostex"
+)
+
+const SWAP_STAT_LIMIT = 100
+
+var _INERNAL_STAT_PERIOD_CONVERTER = map[int]string{
+	KLINE_PERIOD_5MIN:  "5m",
+	KLINE_PERIOD_15MIN: "15m",
+	KLINE_PERIOD_30MIN: "30m",
+	KLINE_PERIOD_60MIN: "1h",
+	KLINE_PERIOD_1H:    "1h",
+	KLINE_PERIOD_4H:    "4h",
+	KLINE_PERIOD_1DAY:  "1d",
+}
+
+// swapStatGate is the item of the contract_stats api, the sizes are in contracts.
+type swapStatGate struct {
+	Time            int64   `json:"time"`
+	LsrAccount      float64 `json:"lsr_account"`
+	TopLsrAccount   float64 `json:"top_lsr_account"`
+	TopLsrSize      float64 `json:"top_lsr_size"`
+	LongTakerSize   float64 `json:"long_taker_size"`
+	ShortTakerSize  float64 `json:"short_taker_size"`
+	OpenInterest    float64 `json:"open_interest"`
+	OpenInterestUsd float64 `json:"open_interest_usd"`
+	MarkPrice       float64 `json:"mark_price"`
+}
+
+// getQuantoMultiplier return the basis amount of one contract in the usdt settle, the btc settle contract is 1 usd.
+func (swap *Swap) getQuantoMultiplier(pair Pair) (float64, []byte, error) {
+	var response = struct {
+		QuantoMultiplier float64 `json:"quanto_multiplier,string"`
+	}{}
+	resp, err := swap.DoRequest(
+		http.MethodGet,
+		fmt.Sprintf(SWAP_CONTRACT_URI, swap.getSettle(pair), pair.ToSymbol("_", true)),
+		"",
+		"",
+		&response,
+	)
+	return response.QuantoMultiplier, resp, err
+}
+
+// contractAmount convert the contracts to the basis amount.
+func (swap *Swap) contractAmount(pair Pair, contracts, multiplier, markPrice float64) float64 {
+	if swap.getSettle(pair) == "usdt" {
+		return contracts * multiplier
+	}
+	if markPrice <= 0 {
+		return 0
+	}
+	return contracts / markPrice
+}
+
+// getContractStats request the contract_stats api, the since is the from and the size is the limit as GetKline.
+func (swap *Swap) getContractStats(pair Pair, period, size, since int) ([]*swapStatGate, float64, []byte, error) {
+	var interval, exist = _INERNAL_STAT_PERIOD_CONVERTER[period]
+	if !exist {
+		return nil, 0, nil, fmt.Errorf("the period %d is not supported in the contract stats. ", period)
+	}
+	if size <= 0 || size > SWAP_STAT_LIMIT {
+		size = SWAP_STAT_LIMIT
+	}
+
+	var params = url.Values{}
+	params.Set("contract", pair.ToSymbol("_", true))
+	params.Set("interval", interval)
+	params.Set("limit", fmt.Sprintf("%d", size))
+	if since > 0 {
+		params.Set("from", fmt.Sprintf("%d", since/1000))
+	}
+
+	var stats = make([]*swapStatGate, 0)
+	resp, err := swap.DoRequest(
+		http.MethodGet,
+		fmt.Sprintf("/api/v4/futures/%s/contract_stats", swap.getSettle(pair)),
+		params.Encode(),
+		"",
+		&stats,
+	)
+	if err != nil {
+		return nil, 0, resp, err
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Time < stats[j].Time
+	})
+
+	var multiplier = 0.0
+	if swap.getSettle(pair) == "usdt" {
+		if multiplier, resp, err = swap.getQuantoMultiplier(pair); err != nil {
+			return nil, 0, resp, err
+		}
+	}
+	return stats, multiplier, resp, nil
+}
+
+func (swap *Swap) GetOpenInterest(pair Pair) (*OpenInterest, []byte, error) {
+	var openInterests, resp, err = swap.GetOpenInterestHistory(pair, KLINE_PERIOD_5MIN, 1, 0)
+	if err != nil {
+		return nil, resp, err
+	}
+	if len(openInterests) == 0 {
+		return nil, resp, errors.New("lack response data. ")
+	}
+	return openInterests[len(openInterests)-1], resp, nil
+}
+
+func (swap *Swap) GetOpenInterestHistory(pair Pair, period, size, since int) ([]*OpenInterest, []byte, error) {
+	var stats, multiplier, resp, err = swap.getContractStats(pair, period, size, since)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	var openInterests = make([]*OpenInterest, 0, len(stats))
+	for _, stat := range stats {
+		openInterests = append(openInterests, &OpenInterest{
+			Pair:      pair,
+			Exchange:  GATE,
+			Amount:    swap.contractAmount(pair, stat.OpenInterest, multiplier, stat.MarkPrice),
+			Value:     stat.OpenInterestUsd,
+			Timestamp: stat.Time * 1000,
+		})
+	}
+	return openInterests, resp, nil
+}
+
+// GetLongShortRatio return the ratio only, gate has no long and short shares.
+func (swap *Swap) GetLongShortRatio(
+	pair Pair,
+	ratioType LongShortType,
+	period, size, since int,
+) ([]*LongShortRatio, []byte, error) {
+	var stats, _, resp, err = swap.getContractStats(pair, period, size, since)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	var ratios = make([]*LongShortRatio, 0, len(stats))
+	for _, stat := range stats {
+		var ratio = &LongShortRatio{
+			Pair:      pair,
+			Exchange:  GATE,
+			Type:      ratioType,
+			Timestamp: stat.Time * 1000,
+		}
+		switch ratioType {
+		case LONG_SHORT_TOP_ACCOUNT:
+			ratio.Ratio = stat.TopLsrAccount
+		case LONG_SHORT_TOP_POSITION:
+			ratio.Ratio = stat.TopLsrSize
+		case LONG_SHORT_GLOBAL_ACCOUNT:
+			ratio.Ratio = stat.LsrAccount
+		default:
+			return nil, resp, fmt.Errorf("the long short type %d is not supported. ", ratioType)
+		}
+		ratios = append(ratios, ratio)
+	}
+	return ratios, resp, nil
+}
+
+func (swap *Swap) GetTakerVolume(pair Pair, period, size, since int) ([]*TakerVolume, []byte, error) {
+	var stats, multiplier, resp, err = swap.getContractStats(pair, period, size, since)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	var volumes = make([]*TakerVolume, 0, len(stats))
+	for _, stat := range stats {
+		volumes = append(volumes, &TakerVolume{
+			Pair:       pair,
+			Exchange:   GATE,
+			BuyVolume:  swap.contractAmount(pair, stat.LongTakerSize, multiplier, stat.MarkPrice),
+			SellVolume: swap.contractAmount(pair, stat.ShortTakerSize, multiplier, stat.MarkPrice),
+			Timestamp:  stat.Time * 1000,
+		})
+	}
+	return volumes, resp, nil
+}
+
+// GetLiquidations return the public liquidation history, the positive size means the long position is liquidated.
+func (swap *Swap) GetLiquidations(pair Pair, size int) ([]*Liquidation, []byte, error) {
+	if size <= 0 || size > 1000 {
+		size = 1000
+	}
+	var params = url.Values{}
+	params.Set("contract", pair.ToSymbol("_", true))
+	params.Set("limit", fmt.Sprintf("%d", size))
+
+	var response = make([]struct {
+		Time      int64   `json:"time"`
+		Contract  string  `json:"contract"`
+		Size      float64 `json:"size"`
+		OrderSize float64 `json:"order_size"`
+		FillPrice float64 `json:"fill_price,string"`
+	}, 0)
+	resp, err := swap.DoRequest(
+		http.MethodGet,
+		fmt.Sprintf("/api/v4/futures/%s/liq_orders", swap.getSettle(pair)),
+		params.Encode(),
+		"",
+		&response,
+	)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	var multiplier = 0.0
+	if swap.getSettle(pair) == "usdt" {
+		if multiplier, resp, err = swap.getQuantoMultiplier(pair); err != nil {
+			return nil, resp, err
+		}
+	}
+
+	var liquidations = make([]*Liquidation, 0, len(response))
+	for _, item := range response {
+		var contracts = math.Abs(item.OrderSize)
+		if contracts == 0 {
+			contracts = math.Abs(item.Size)
+		}
+		var liquidation = &Liquidation{
+			Pair:      pair,
+			Exchange:  GATE,
+			Symbol:    item.Contract,
+			Side:      SELL,
+			Price:     item.FillPrice,
+			Amount:    swap.contractAmount(pair, contracts, multiplier, item.FillPrice),
+			Timestamp: item.Time * 1000,
+		}
+		if item.Size < 0 {
+			liquidation.Side = BUY
+		}
+		liquidations = append(liquidations, liquidation)
+	}
+	sort.Slice(liquidations, func(i, j int) bool {
+		return liquidations[i].Timestamp < liquidations[j].Timestamp
+	})
+	return liquidations, resp, nil
+}
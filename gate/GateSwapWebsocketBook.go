@@ -0,0 +1,326 @@
+package gate
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	. "github.com/deforceHK/goghostex"
+)
+
+type DeltaOrderBook struct {
+	Timestamp int64  `json:"t"`
+	Symbol    string `json:"s"`
+	StartSeq  int64  `json:"U"`
+	EndSeq    int64  `json:"u"`
+	Bids      []*struct {
+		P float64 `json:"p,string"`
+		S float64 `json:"s"`
+	} `json:"b"`
+	Asks []*struct {
+		P float64 `json:"p,string"`
+		S float64 `json:"s"`
+	} `json:"a"`
+}
+
+// LocalOrderBooks maintain the futures.order_book_update channel on the rest snapshot, the amount is in contracts
+// like the Swap.GetDepth.
+type LocalOrderBooks struct {
+	*WSSwapMarketGate
+	BidData       map[string]map[int64]float64
+	AskData       map[string]map[int64]float64
+	SeqData       map[string]int64
+	TsData        map[string]int64
+	OrderBookMuxs map[string]*sync.Mutex
+	Cache         map[string][]*DeltaOrderBook
+
+	// if the channel is not nil, send the update message to the channel. User should read the channel in the loop.
+	UpdateChan chan string
+}
+
+func (this *LocalOrderBooks) Init() error {
+	if this.OrderBookMuxs == nil {
+		this.OrderBookMuxs = make(map[string]*sync.Mutex)
+	}
+	if this.BidData == nil {
+		this.BidData = make(map[string]map[int64]float64)
+	}
+	if this.AskData == nil {
+		this.AskData = make(map[string]map[int64]float64)
+	}
+	if this.SeqData == nil {
+		this.SeqData = make(map[string]int64)
+	}
+	if this.TsData == nil {
+		this.TsData = make(map[string]int64)
+	}
+	if this.Cache == nil {
+		this.Cache = make(map[string][]*DeltaOrderBook)
+	}
+	this.RecvHandler = func(s string) {
+		this.ReceiveDelta(s)
+	}
+	return this.Start()
+}
+
+func (this *LocalOrderBooks) Restart() {
+	for productId := range this.OrderBookMuxs {
+		this.reset(productId)
+	}
+	this.WSSwapMarketGate.Restart()
+}
+
+// reset drop the local book of the product, the next delta will get the snapshot again.
+func (this *LocalOrderBooks) reset(productId string) {
+	var mux = this.OrderBookMuxs[productId]
+	if mux == nil {
+		return
+	}
+	mux.Lock()
+	this.OrderBookMuxs[productId] = nil
+	this.Cache[productId] = nil
+	mux.Unlock()
+}
+
+func (this *LocalOrderBooks) ReceiveDelta(msg string) {
+	var delta = struct {
+		Channel string          `json:"channel"`
+		Event   string          `json:"event"`
+		Result  *DeltaOrderBook `json:"result"`
+	}{}
+
+	_ = json.Unmarshal([]byte(msg), &delta)
+	if delta.Channel != "futures.order_book_update" || delta.Event != "update" || delta.Result == nil {
+		this.logger().Debug("receive message", LogF("msg", msg))
+		return
+	}
+
+	var productId = delta.Result.Symbol
+	// 如果还没有锁，说明还没有申请过snapshot，或者snapshot重置了。
+	if this.OrderBookMuxs[productId] == nil {
+		this.BidData[productId] = make(map[int64]float64)
+		this.AskData[productId] = make(map[int64]float64)
+		if this.Cache[productId] == nil {
+			this.Cache[productId] = []*DeltaOrderBook{delta.Result}
+			go this.getSnapshot(productId, 0)
+		} else {
+			this.Cache[productId] = append(this.Cache[productId], delta.Result)
+		}
+		return
+	}
+
+	//	已经有了snapshot，则直接处理delta
+	var mux = this.OrderBookMuxs[productId]
+	mux.Lock()
+	defer mux.Unlock()
+
+	var deltas = append(this.Cache[productId], delta.Result)
+	this.Cache[productId] = make([]*DeltaOrderBook, 0)
+	for _, d := range deltas {
+		// the update before the snapshot.
+		if d.EndSeq <= this.SeqData[productId] {
+			continue
+		}
+		// the first update after the snapshot cover the snapshot id, then the updates are continuous.
+		if d.StartSeq > this.SeqData[productId]+1 {
+			// 有丢包现象，需要重新申请snapshot
+			this.Config.GetMetrics().IncBookResync(GATE, productId)
+			this.OrderBookMuxs[productId] = nil
+			this.Cache[productId] = nil
+			return
+		}
+
+		for _, bid := range d.Bids {
+			this.BidData[productId][int64(bid.P*100000000)] = bid.S
+		}
+		for _, ask := range d.Asks {
+			this.AskData[productId][int64(ask.P*100000000)] = ask.S
+		}
+		this.SeqData[productId] = d.EndSeq
+		this.TsData[productId] = d.Timestamp
+	}
+
+	if this.UpdateChan != nil {
+		this.UpdateChan <- fmt.Sprintf("%s:%d", productId, delta.Result.Timestamp)
+	}
+}
+
+func (this *LocalOrderBooks) getSnapshot(productId string, times int) {
+	if this.OrderBookMuxs[productId] != nil {
+		this.reset(productId)
+		return
+	}
+	if times > 5 {
+		this.Stop()
+		this.ErrorHandler(&WSStopError{
+			Msg: "get snapshot failed, and retry 5 times, stop the websocket. ",
+		})
+		return
+	}
+
+	var depth, err = this.getDepthById(productId, 100)
+	if err != nil {
+		this.ErrorHandler(err)
+		time.Sleep(5 * time.Second)
+		this.getSnapshot(productId, times+1)
+		return
+	}
+
+	this.OrderBookMuxs[productId] = &sync.Mutex{}
+	var mux = this.OrderBookMuxs[productId]
+	mux.Lock()
+	this.SeqData[productId] = depth.Sequence
+	this.TsData[productId] = depth.Timestamp
+
+	for _, bid := range depth.BidList {
+		var stdPrice = int64(bid.Price * 100000000)
+		this.BidData[productId][stdPrice] = bid.Amount
+	}
+
+	for _, ask := range depth.AskList {
+		var stdPrice = int64(ask.Price * 100000000)
+		this.AskData[productId][stdPrice] = ask.Amount
+	}
+	mux.Unlock()
+}
+
+func (this *LocalOrderBooks) Snapshot(pair Pair) (*SwapDepth, error) {
+	return this.SnapshotById(pair.ToSymbol("_", true))
+}
+
+func (this *LocalOrderBooks) SnapshotById(productId string) (*SwapDepth, error) {
+	if this.BidData[productId] == nil || this.AskData[productId] == nil || this.OrderBookMuxs[productId] == nil {
+		return nil, fmt.Errorf("The order book data is not ready or you need subscribe the productid. ")
+	}
+
+	var mux = this.OrderBookMuxs[productId]
+	mux.Lock()
+	defer mux.Unlock()
+	var lastTime = time.UnixMilli(this.TsData[productId]).In(this.Config.Location)
+	this.Config.GetMetrics().SetBookStaleness(GATE, productId, time.Since(lastTime))
+	var depth = &SwapDepth{
+		Pair:      NewPair(productId, "_"),
+		Sequence:  this.SeqData[productId],
+		Timestamp: lastTime.UnixMilli(),
+		Date:      lastTime.Format(GO_BIRTHDAY),
+		AskList:   make(DepthRecords, 0),
+		BidList:   make(DepthRecords, 0),
+	}
+	var zeroCount, sumCount = 0.0, 0.0
+	for stdPrice, amount := range this.BidData[productId] {
+		if amount > 0 {
+			depth.BidList = append(depth.BidList, DepthRecord{
+				Price:  float64(stdPrice) / 100000000,
+				Amount: amount,
+			})
+		} else {
+			zeroCount++
+		}
+		sumCount++
+	}
+
+	for stdPrice, amount := range this.AskData[productId] {
+		if amount > 0 {
+			depth.AskList = append(depth.AskList, DepthRecord{
+				Price:  float64(stdPrice) / 100000000,
+				Amount: amount,
+			})
+		} else {
+			zeroCount++
+		}
+		sumCount++
+	}
+	sort.Sort(sort.Reverse(depth.BidList))
+	sort.Sort(depth.AskList)
+
+	// collect the zero amount data
+	if sumCount > 0 && zeroCount/sumCount > 0.3 {
+		for priceKey, amountValue := range this.BidData[productId] {
+			if amountValue > 0 {
+				continue
+			}
+			delete(this.BidData[productId], priceKey)
+		}
+		for priceKey, amountValue := range this.AskData[productId] {
+			if amountValue > 0 {
+				continue
+			}
+			delete(this.AskData[productId], priceKey)
+		}
+	}
+
+	return depth, nil
+}
+
+func (this *LocalOrderBooks) SubscribeById(productId string) {
+	this.WSSwapMarketGate.Subscribe(WSSwapRequestGate{
+		Time:    time.Now().Unix(),
+		Channel: "futures.order_book_update",
+		Event:   "subscribe",
+		Payload: []string{productId, "100ms", "100"},
+	})
+}
+
+func (this *LocalOrderBooks) Subscribe(pair Pair) {
+	this.SubscribeById(pair.ToSymbol("_", true))
+}
+
+func (this *LocalOrderBooks) Unsubscribe(pair Pair) {
+	this.WSSwapMarketGate.Unsubscribe(WSSwapRequestGate{
+		Time:    time.Now().Unix(),
+		Channel: "futures.order_book_update",
+		Event:   "unsubscribe",
+		Payload: []string{pair.ToSymbol("_", true), "100ms", "100"},
+	})
+}
+
+// getDepthById get the rest order book with the id, the id is the sequence to match the update.
+func (this *LocalOrderBooks) getDepthById(productId string, size int) (*SwapDepth, error) {
+	var response = struct {
+		Id      int64   `json:"id"`
+		Current float64 `json:"current"`
+		Asks    []*struct {
+			P float64 `json:"p,string"`
+			S float64 `json:"s"`
+		} `json:"asks"`
+		Bids []*struct {
+			P float64 `json:"p,string"`
+			S float64 `json:"s"`
+		} `json:"bids"`
+	}{}
+
+	var params = url.Values{}
+	params.Set("contract", productId)
+	params.Set("limit", fmt.Sprintf("%d", size))
+	params.Set("with_id", "true")
+	var gate = New(this.Config)
+	var _, err = gate.Swap.DoRequest(
+		http.MethodGet,
+		fmt.Sprintf("/api/v4/futures/%s/order_book", this.Settle),
+		params.Encode(),
+		"",
+		&response,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var timestamp = int64(response.Current * 1000)
+	var depth = &SwapDepth{
+		Pair:      NewPair(productId, "_"),
+		Timestamp: timestamp,
+		Sequence:  response.Id,
+		Date:      time.UnixMilli(timestamp).In(this.Config.Location).Format(GO_BIRTHDAY),
+	}
+	for _, bid := range response.Bids {
+		depth.BidList = append(depth.BidList, DepthRecord{Price: bid.P, Amount: bid.S})
+	}
+	for _, ask := range response.Asks {
+		depth.AskList = append(depth.AskList, DepthRecord{Price: ask.P, Amount: ask.S})
+	}
+	return depth, nil
+}
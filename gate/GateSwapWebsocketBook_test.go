@@ -0,0 +1,95 @@
+package gate
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/deforceHK/goghostex"
+)
+
+// go test -v ./gate/... -count=1 -run=TestLocalOrderBooks_Init
+func TestLocalOrderBooks_Init(t *testing.T) {
+	var config = &APIConfig{
+		Endpoint:   ENDPOINT,
+		HttpClient: &http.Client{},
+		Location:   time.Now().Location(),
+	}
+
+	var book = &LocalOrderBooks{
+		WSSwapMarketGate: &WSSwapMarketGate{
+			Config: config,
+		},
+	}
+	var err = book.Init()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	var pair = Pair{Basis: BTC, Counter: USDT}
+	book.Subscribe(pair)
+
+	for i := 0; i < 10; i++ {
+		time.Sleep(5 * time.Second)
+		depth, depthErr := book.Snapshot(pair)
+		if depthErr != nil {
+			t.Error(depthErr)
+			return
+		}
+		var depthData, _ = json.Marshal(depth)
+		t.Log(string(depthData))
+	}
+}
+
+// go test -v ./gate/... -count=1 -run=TestLocalOrderBooks_ReceiveDelta
+func TestLocalOrderBooks_ReceiveDelta(t *testing.T) {
+	var productId = "BTC_USDT"
+	var book = &LocalOrderBooks{
+		WSSwapMarketGate: &WSSwapMarketGate{
+			Config: &APIConfig{Location: time.UTC},
+		},
+		BidData:       map[string]map[int64]float64{productId: {int64(100 * 1e8): 5}},
+		AskData:       map[string]map[int64]float64{productId: {int64(101 * 1e8): 7}},
+		SeqData:       map[string]int64{productId: 100},
+		TsData:        map[string]int64{productId: 0},
+		OrderBookMuxs: map[string]*sync.Mutex{productId: {}},
+		Cache:         map[string][]*DeltaOrderBook{},
+	}
+
+	// the stale update is dropped, the first update cover the snapshot id.
+	book.ReceiveDelta(`{"channel":"futures.order_book_update","event":"update","result":
+		{"t":1700000000000,"s":"BTC_USDT","U":90,"u":100,"b":[{"p":"100","s":0}],"a":[]}}`)
+	book.ReceiveDelta(`{"channel":"futures.order_book_update","event":"update","result":
+		{"t":1700000000100,"s":"BTC_USDT","U":95,"u":105,"b":[{"p":"99.5","s":3}],"a":[{"p":"101","s":0}]}}`)
+	book.ReceiveDelta(`{"channel":"futures.order_book_update","event":"update","result":
+		{"t":1700000000200,"s":"BTC_USDT","U":106,"u":108,"b":[],"a":[{"p":"102","s":4}]}}`)
+
+	var depth, err = book.Snapshot(Pair{Basis: BTC, Counter: USDT})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if depth.Sequence != 108 || depth.Timestamp != 1700000000200 {
+		t.Error("the sequence is wrong: ", depth.Sequence, depth.Timestamp)
+		return
+	}
+	if len(depth.BidList) != 2 || depth.BidList[0].Price != 100 || depth.BidList[0].Amount != 5 ||
+		depth.BidList[1].Price != 99.5 {
+		t.Error("the bids are wrong: ", depth.BidList)
+		return
+	}
+	if len(depth.AskList) != 1 || depth.AskList[0].Price != 102 || depth.AskList[0].Amount != 4 {
+		t.Error("the asks are wrong: ", depth.AskList)
+		return
+	}
+
+	// the gap drop the local book, the snapshot is not ready until the next snapshot.
+	book.ReceiveDelta(`{"channel":"futures.order_book_update","event":"update","result":
+		{"t":1700000000300,"s":"BTC_USDT","U":110,"u":112,"b":[],"a":[]}}`)
+	if _, err := book.Snapshot(Pair{Basis: BTC, Counter: USDT}); err == nil {
+		t.Error("the book should be reset after the gap. ")
+	}
+}
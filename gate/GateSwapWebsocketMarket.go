@@ -0,0 +1,291 @@
+package gate
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	. "github.com/deforceHK/goghostex"
+)
+
+const (
+	SWAP_WEBSOCKET_ENDPOINT = "wss://fx-ws.gateio.ws/v4/ws/%s"
+
+	DEFAULT_WEBSOCKET_RESTART_SLEEP_SEC  = 30
+	DEFAULT_WEBSOCKET_PING_SEC           = 20
+	DEFAULT_WEBSOCKET_PENDING_SEC        = 100
+	DERFAULT_WEBSOCKET_RESTART_LIMIT_NUM = 10
+	DERFAULT_WEBSOCKET_RESTART_LIMIT_SEC = 300
+)
+
+// WSSwapRequestGate is the subscribe and the unsubscribe request of the futures websocket.
+type WSSwapRequestGate struct {
+	Time    int64    `json:"time"`
+	Channel string   `json:"channel"`
+	Event   string   `json:"event"`
+	Payload []string `json:"payload,omitempty"`
+}
+
+// WSSwapMarketGate is the public futures websocket, the connection is in the settle currency, usdt by default.
+type WSSwapMarketGate struct {
+	RecvHandler  func(string)
+	ErrorHandler func(error)
+	Config       *APIConfig
+	DialerConfig *WsDialerConfig // the proxy, tls and local address setting of the connection, not necessary
+	Settle       string          // usdt or btc
+
+	conn       *websocket.Conn
+	connId     string
+	subscribed []interface{}
+
+	restartSleepSec int
+	restartLimitNum int // In X(restartLimitSec) seconds, the limit times(restartLimitNum) of restart
+	restartLimitSec int // In X(restartLimitSec) seconds, the limit times(restartLimitNum) of restart
+
+	lastPingTS int64
+	restartTS  map[int64]string
+
+	stopChecSign chan bool
+	stopPingSign chan bool
+}
+
+func (this *WSSwapMarketGate) Start() error {
+	this.initDefaultValue()
+	var stopErr = this.startCheck()
+	if stopErr != nil {
+		return stopErr
+	}
+
+	var conn, err = this.getConn(fmt.Sprintf(SWAP_WEBSOCKET_ENDPOINT, this.Settle))
+	if err != nil {
+		if len(this.restartTS) != 0 {
+			this.Restart()
+		}
+		return err
+	}
+	this.conn = conn
+	this.connId = UUID()
+	this.lastPingTS = time.Now().Unix()
+
+	go this.recvRoutine()
+	go this.checkRoutine()
+	go this.pingRoutine()
+	return nil
+}
+
+func (this *WSSwapMarketGate) Subscribe(v interface{}) {
+	var err = this.conn.WriteJSON(v)
+	if err != nil {
+		this.ErrorHandler(err)
+	}
+	this.subscribed = append(this.subscribed, v)
+}
+
+func (this *WSSwapMarketGate) Unsubscribe(v interface{}) {
+	var err = this.conn.WriteJSON(v)
+	if err != nil {
+		this.ErrorHandler(err)
+	}
+}
+
+func (this *WSSwapMarketGate) Restart() {
+	this.Config.GetMetrics().IncWSReconnect(GATE, "WSSwapMarketGate")
+	this.ErrorHandler(
+		&WSRestartError{
+			Msg: fmt.Sprintf("gate market websocket will restart in next %d seconds...", this.restartSleepSec),
+		},
+	)
+	this.restartTS[time.Now().Unix()] = this.connId
+	this.Stop()
+
+	time.Sleep(time.Duration(this.restartSleepSec) * time.Second)
+	if err := this.Start(); err != nil {
+		this.ErrorHandler(err)
+		return
+	}
+
+	// subscribe unsubscribe the channel
+	for _, channel := range this.subscribed {
+		var err = this.conn.WriteJSON(channel)
+		if err != nil {
+			this.ErrorHandler(err)
+			var errMsg, _ = json.Marshal(channel)
+			this.ErrorHandler(fmt.Errorf("subscribe error: %s", string(errMsg)))
+		}
+	}
+}
+
+func (this *WSSwapMarketGate) checkRoutine() {
+	var stopChecChn = make(chan bool, 1)
+	this.stopChecSign = stopChecChn
+
+	var ticker = time.NewTicker(DEFAULT_WEBSOCKET_PENDING_SEC * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			// 超过x秒没有收到消息，重新连接，如果超出重连次数，ws将停止。
+			if time.Now().Unix()-this.lastPingTS > DEFAULT_WEBSOCKET_PENDING_SEC {
+				this.ErrorHandler(fmt.Errorf("ping timeout, last ping ts: %d", this.lastPingTS))
+				this.Restart()
+				continue
+			}
+		case _, opened := <-stopChecChn:
+			if opened {
+				close(stopChecChn)
+			}
+			this.stopChecSign = nil
+			return
+		}
+	}
+}
+
+// pingRoutine send the application ping, gate answer it with the futures.pong channel.
+func (this *WSSwapMarketGate) pingRoutine() {
+	var stopPingChn = make(chan bool, 1)
+	this.stopPingSign = stopPingChn
+	var ticker = time.NewTicker(DEFAULT_WEBSOCKET_PING_SEC * time.Second)
+	defer ticker.Stop()
+	var conn = this.conn
+
+	for {
+		select {
+		case <-ticker.C:
+			var err = conn.WriteJSON(WSSwapRequestGate{
+				Time:    time.Now().Unix(),
+				Channel: "futures.ping",
+			})
+			if err != nil {
+				this.logger().Warn("ping error", LogF(LOG_FIELD_ERROR, err))
+			}
+		case _, opened := <-stopPingChn:
+			if opened {
+				close(stopPingChn)
+			}
+			this.stopPingSign = nil
+			return
+		}
+	}
+}
+
+func (this *WSSwapMarketGate) recvRoutine() {
+	for {
+		var msgType, msg, readErr = this.conn.ReadMessage()
+		if readErr != nil {
+			// conn closed by user.
+			if strings.Index(readErr.Error(), "use of closed network connection") > 0 {
+				this.logger().Info("conn closed by user")
+				return
+			}
+
+			this.ErrorHandler(readErr)
+			this.Restart()
+			return
+		}
+
+		if msgType != websocket.TextMessage {
+			continue
+		}
+		var event = struct {
+			Channel string `json:"channel"`
+		}{}
+		_ = json.Unmarshal(msg, &event)
+		this.lastPingTS = time.Now().Unix()
+		this.Config.GetMetrics().IncWSMessage(GATE, "WSSwapMarketGate")
+		if event.Channel != "futures.pong" {
+			this.RecvHandler(string(msg))
+		}
+	}
+}
+
+func (this *WSSwapMarketGate) startCheck() error {
+	var restartNum, limitTS = 0, time.Now().Unix() - int64(this.restartLimitSec)
+	for ts := range this.restartTS {
+		if ts > limitTS {
+			restartNum++
+		}
+	}
+	if restartNum > this.restartLimitNum {
+		var wsErr = &WSStopError{
+			Msg: fmt.Sprintf(
+				"The ws restarted %d times in %d seconds, stop the ws",
+				restartNum, this.restartLimitSec,
+			),
+		}
+		this.Config.GetMetrics().IncWSRestartLimit(GATE, "WSSwapMarketGate")
+		return wsErr
+	}
+	return nil
+}
+
+func (this *WSSwapMarketGate) getConn(wss string) (*websocket.Conn, error) {
+	var dialer, dialErr = NewWsDialer(this.DialerConfig)
+	if dialErr != nil {
+		return nil, dialErr
+	}
+	var conn, _, err = dialer.Dial(
+		wss,
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return conn, nil
+}
+
+func (this *WSSwapMarketGate) initDefaultValue() {
+	if this.RecvHandler == nil {
+		this.RecvHandler = func(msg string) {
+			this.logger().Debug("receive message", LogF("msg", msg))
+		}
+	}
+	if this.ErrorHandler == nil {
+		this.ErrorHandler = func(err error) {
+			this.logger().Error("websocket error", LogF(LOG_FIELD_ERROR, err))
+		}
+	}
+	if this.Settle == "" {
+		this.Settle = "usdt"
+	}
+	if this.restartSleepSec == 0 {
+		this.restartSleepSec = DEFAULT_WEBSOCKET_RESTART_SLEEP_SEC
+	}
+
+	if this.restartLimitNum == 0 {
+		this.restartLimitNum = DERFAULT_WEBSOCKET_RESTART_LIMIT_NUM
+	}
+
+	if this.restartLimitSec == 0 {
+		this.restartLimitSec = DERFAULT_WEBSOCKET_RESTART_LIMIT_SEC
+	}
+
+	if this.restartTS == nil {
+		this.restartTS = make(map[int64]string, 0)
+	}
+}
+
+func (this *WSSwapMarketGate) Stop() {
+	if this.stopChecSign != nil {
+		this.stopChecSign <- true
+	}
+	if this.stopPingSign != nil {
+		this.stopPingSign <- true
+	}
+
+	if this.conn != nil {
+		_ = this.conn.Close()
+		this.conn = nil
+	}
+	this.connId = ""
+}
+
+func (this *WSSwapMarketGate) logger() Logger {
+	return this.Config.GetLogger().With(
+		LogF(LOG_FIELD_EXCHANGE, GATE),
+		LogF(LOG_FIELD_CONN_ID, this.connId),
+	)
+}
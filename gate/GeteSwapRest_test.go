@@ -167,3 +167,49 @@ func TestSwap_TradeAPI(t *testing.T) {
 	//	fmt.Println(err)
 	//}
 }
+
+// go test -v ./gate/... -count=1 -run=TestSwapOrderGate_Contract
+func TestSwapOrderGate_Contract(t *testing.T) {
+	var contract = &SwapContract{
+		Pair:            Pair{Basis: BTC, Counter: USDT},
+		SettleMode:      SETTLE_MODE_COUNTER,
+		UnitAmount:      0.0001,
+		TickSize:        0.1,
+		PricePrecision:  1,
+		AmountPrecision: 4,
+		LotSize:         0.0001,
+		MinAmount:       0.0001,
+	}
+
+	var sog = &SwapOrderGate{}
+	var err = sog.Merge(&SwapOrder{
+		Pair:      Pair{Basis: BTC, Counter: USDT},
+		Type:      OPEN_SHORT,
+		PlaceType: NORMAL,
+		Price:     40000.06,
+		Amount:    0.00123,
+	}, contract)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if sog.Contract != "BTC_USDT" || sog.Size != -12 || sog.Price != 40000 {
+		t.Error("the order request is wrong: ", *sog)
+		return
+	}
+
+	sog.Left, sog.FillPrice, sog.Status = -2, 40000, "open"
+	var order = sog.New(time.UTC, contract)
+	if order.Type != OPEN_SHORT || order.Amount != 0.0012 || order.DealAmount != 0.001 {
+		t.Error("the order is wrong: ", *order)
+		return
+	}
+
+	var position = (&swapPositionGate{
+		Contract: "BTC_USDT", Size: -30, Leverage: 0, CrossLeverageLimit: 20, EntryPrice: 40000, Mode: "single",
+	}).toPosition(Pair{Basis: BTC, Counter: USDT}, contract)
+	if position.Type != OPEN_SHORT || position.Amount != 0.003 || position.MarginType != CROSS ||
+		position.Leverage != 20 {
+		t.Error("the position is wrong: ", *position)
+	}
+}
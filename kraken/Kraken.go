@@ -34,7 +34,7 @@ var _INERNAL_KLINE_PERIOD_CONVERTER = map[int]string{
 
 func New(config *APIConfig) *Kraken {
 	var k = &Kraken{config: config}
-	k.Spot = &Spot{k}
+	k.Spot = &Spot{Kraken: k}
 	k.Swap = &Swap{
 		Kraken:        k,
 		Locker:        new(sync.Mutex),
@@ -56,7 +56,9 @@ func (k *Kraken) GetExchangeName() string {
 }
 
 func (k *Kraken) DoRequest(httpMethod, uri, reqBody string, response interface{}) ([]byte, error) {
-	resp, err := NewHttpRequest(
+	resp, err := NewHttpRequestWithMetrics(
+		k.config.GetMetrics(),
+		KRAKEN,
 		k.config.HttpClient,
 		httpMethod,
 		k.config.Endpoint+uri,
@@ -84,7 +86,9 @@ func (k *Kraken) DoSignRequest(httpMethod, uri string, data interface{}, respons
 	}
 
 	var postData, _ = json.Marshal(data)
-	resp, err := NewHttpRequest(
+	resp, err := NewHttpRequestWithMetrics(
+		k.config.GetMetrics(),
+		KRAKEN,
 		k.config.HttpClient,
 		httpMethod,
 		k.config.Endpoint+uri,
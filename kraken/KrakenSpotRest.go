@@ -4,9 +4,11 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 
 	. "github.com/deforceHK/goghostex"
@@ -25,6 +27,64 @@ var _INERNAL_ORDER_PLACE_TYPE_CONVERTER = map[PlaceType]string{
 
 type Spot struct {
 	*Kraken
+
+	rules sync.Map // pair.String() => *Rule, the cache of GetExchangeRule for PlaceOrder
+}
+
+func (s *Spot) GetExchangeRule(pair Pair) (*Rule, []byte, error) {
+	var pairStd = strings.ToUpper(pair.ToSymbol("", true))
+	if pairStd == "BTCUSD" {
+		pairStd = "XXBTZUSD"
+	} else if pairStd == "ETHUSD" {
+		pairStd = "XETHZUSD"
+	}
+
+	var result = struct {
+		Error  []string `json:"error"`
+		Result map[string]struct {
+			PairDecimals int     `json:"pair_decimals"`
+			LotDecimals  int     `json:"lot_decimals"`
+			OrderMin     float64 `json:"ordermin,string"`
+			CostMin      float64 `json:"costmin,string"`
+			TickSize     float64 `json:"tick_size,string"`
+		} `json:"result"`
+	}{}
+
+	resp, err := s.DoRequest(http.MethodGet, API_V1+"AssetPairs?pair="+pairStd, "", &result)
+	if err != nil {
+		return nil, resp, err
+	}
+	if len(result.Error) != 0 {
+		return nil, resp, errors.New(strings.Join(result.Error, ","))
+	}
+
+	for _, info := range result.Result {
+		return &Rule{
+			Pair:             pair,
+			Base:             pair.Basis,
+			Counter:          pair.Counter,
+			BaseMinSize:      info.OrderMin,
+			BasePrecision:    info.LotDecimals,
+			CounterPrecision: info.PairDecimals,
+			TickSize:         info.TickSize,
+			LotSize:          math.Pow10(-info.LotDecimals),
+			MinNotional:      info.CostMin,
+		}, resp, nil
+	}
+	return nil, resp, errors.New("Can not find the pair in exchange. ")
+}
+
+// getRule return the cached rule of the pair, the rule is queried at the first time.
+func (s *Spot) getRule(pair Pair) (*Rule, error) {
+	if rule, exist := s.rules.Load(pair.String()); exist {
+		return rule.(*Rule), nil
+	}
+	var rule, _, err = s.GetExchangeRule(pair)
+	if err != nil {
+		return nil, err
+	}
+	s.rules.Store(pair.String(), rule)
+	return rule, nil
 }
 
 func (s *Spot) GetTicker(pair Pair) (*Ticker, []byte, error) {
@@ -137,12 +197,22 @@ func (s *Spot) PlaceOrder(order *Order) ([]byte, error) {
 		return nil, errors.New("invalid order side")
 	}
 
+	rule, err := s.getRule(order.Pair)
+	if err != nil {
+		return nil, err
+	}
+	var normalizer = rule.GetNormalizer()
+	price, amount, err := normalizer.Normalize(order.Price, order.Amount, orderType == "market")
+	if err != nil {
+		return nil, err
+	}
+
 	var params = map[string]interface{}{
 		"pair":      pairStd,
 		"type":      side,
 		"ordertype": orderType,
-		"volume":    fmt.Sprintf("%f", order.Amount),
-		"price":     fmt.Sprintf("%f", order.Price),
+		"volume":    normalizer.FormatAmount(amount),
+		"price":     normalizer.FormatPrice(price),
 		"nonce":     fmt.Sprintf("%d", time.Now().UnixNano()),
 	}
 
@@ -206,6 +276,76 @@ func (s *Spot) CancelOrder(order *Order) ([]byte, error) {
 	return resp, nil
 }
 
+// AmendOrder modify the open order by the AmendOrder api, the order keep the txid and the queue position
+// when only the amount is reduced.
+func (s *Spot) AmendOrder(order *Order, newPrice, newAmount float64) ([]byte, error) {
+	if order.OrderId == "" {
+		return nil, errors.New("order id cannot be empty")
+	}
+	if newPrice <= 0 {
+		newPrice = order.Price
+	}
+	if newAmount <= 0 {
+		newAmount = order.Amount
+	}
+
+	rule, err := s.getRule(order.Pair)
+	if err != nil {
+		return nil, err
+	}
+	var normalizer = rule.GetNormalizer()
+	price, amount, err := normalizer.Normalize(newPrice, newAmount, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var params = map[string]interface{}{
+		"txid":        order.OrderId,
+		"order_qty":   normalizer.FormatAmount(amount),
+		"limit_price": normalizer.FormatPrice(price),
+		"nonce":       fmt.Sprintf("%d", time.Now().UnixNano()),
+	}
+	var result struct {
+		Error  []string `json:"error"`
+		Result struct {
+			AmendId string `json:"amend_id"`
+		} `json:"result"`
+	}
+
+	resp, err := s.DoSignRequest(http.MethodPost, API_PRIVATE+"/AmendOrder", params, &result)
+	if err != nil {
+		return resp, err
+	}
+	if len(result.Error) > 0 {
+		return resp, errors.New(strings.Join(result.Error, ","))
+	}
+	order.Price, order.Amount = price, amount
+	return resp, nil
+}
+
+// BatchPlaceOrders place the orders one by one.
+func (s *Spot) BatchPlaceOrders(orders []*Order) ([]error, []byte, error) {
+	return BatchSequential(len(orders), func(i int) ([]byte, error) {
+		return s.PlaceOrder(orders[i])
+	})
+}
+
+func (s *Spot) BatchCancelOrders(orders []*Order) ([]error, []byte, error) {
+	return BatchSequential(len(orders), func(i int) ([]byte, error) {
+		return s.CancelOrder(orders[i])
+	})
+}
+
+// CancelAllOrders cancel the unfinished orders of the pair one by one.
+func (s *Spot) CancelAllOrders(pair Pair) ([]byte, error) {
+	var orders, resp, err = s.GetUnFinishOrders(pair)
+	if err != nil {
+		return resp, err
+	}
+	_, resp, err = s.BatchCancelOrders(orders)
+	return resp, err
+}
+
 func (s *Spot) GetOrder(order *Order) ([]byte, error) {
 	if order.OrderId == "" {
 		return nil, errors.New("order id cannot be empty")
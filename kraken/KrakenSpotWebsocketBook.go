@@ -139,7 +139,7 @@ func (this *SpotOrderBooks) Receiver(msg string) {
 
 	_ = json.Unmarshal(rawData, &pre)
 	if pre.Channel != "book" {
-		fmt.Println("The feed must in book_snapshot book")
+		this.logger().Debug("The feed must in book_snapshot book", LogF("msg", msg))
 		return
 	}
 
@@ -231,6 +231,7 @@ func (this *SpotOrderBooks) Snapshot(pair Pair) (*Depth, error) {
 	defer mux.Unlock()
 
 	var lastTime = time.UnixMilli(this.TsData[productId]).In(this.WSSpotMarketKK.Config.Location)
+	this.Config.GetMetrics().SetBookStaleness(KRAKEN, productId, time.Since(lastTime))
 	var lastTS = lastTime.UnixMilli()
 	var depth = &Depth{
 		Pair:      pair,
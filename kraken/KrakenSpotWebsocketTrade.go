@@ -3,7 +3,6 @@ package kraken
 import (
 	"encoding/json"
 	"fmt"
-	"log"
 	"strings"
 	"time"
 
@@ -22,6 +21,7 @@ type WSSpotTradeKK struct {
 	RecvHandler  func(string)
 	ErrorHandler func(error)
 	Config       *APIConfig
+	DialerConfig *WsDialerConfig // the proxy, tls and local address setting of the connection, not necessary
 
 	conn       *websocket.Conn
 	connId     string
@@ -137,6 +137,7 @@ func (this *WSSpotTradeKK) Stop() {
 }
 
 func (this *WSSpotTradeKK) Restart() {
+	this.Config.GetMetrics().IncWSReconnect(KRAKEN, "WSSpotTradeKK")
 	this.ErrorHandler(
 		&WSRestartError{Msg: fmt.Sprintf("websocket will restart in next %d seconds...", this.restartSleepSec)},
 	)
@@ -164,6 +165,7 @@ func (this *WSSpotTradeKK) startCheck() error {
 				restartNum, this.restartLimitSec,
 			),
 		}
+		this.Config.GetMetrics().IncWSRestartLimit(KRAKEN, "WSSpotTradeKK")
 		return wsErr
 	}
 	return nil
@@ -178,7 +180,11 @@ func (this *WSSpotTradeKK) getLoginConn(wss string) (*websocket.Conn, error) {
 		this.connId = token
 	}
 
-	if conn, _, err := websocket.DefaultDialer.Dial(
+	var dialer, dialErr = NewWsDialer(this.DialerConfig)
+	if dialErr != nil {
+		return nil, dialErr
+	}
+	if conn, _, err := dialer.Dial(
 		wss,
 		nil,
 	); err != nil {
@@ -191,12 +197,12 @@ func (this *WSSpotTradeKK) getLoginConn(wss string) (*websocket.Conn, error) {
 func (this *WSSpotTradeKK) initDefaultValue() {
 	if this.RecvHandler == nil {
 		this.RecvHandler = func(msg string) {
-			log.Println(msg)
+			this.logger().Debug("receive message", LogF("msg", msg))
 		}
 	}
 	if this.ErrorHandler == nil {
 		this.ErrorHandler = func(err error) {
-			log.Println(err)
+			this.logger().Error("websocket error", LogF(LOG_FIELD_ERROR, err))
 		}
 	}
 	if this.restartSleepSec == 0 {
@@ -249,7 +255,7 @@ func (this *WSSpotTradeKK) recvRoutine() {
 		if readErr != nil {
 			// conn closed by user.
 			if strings.Index(readErr.Error(), "use of closed network connection") > 0 {
-				fmt.Println("conn closed by user. ")
+				this.logger().Info("conn closed by user")
 				return
 			}
 
@@ -266,6 +272,7 @@ func (this *WSSpotTradeKK) recvRoutine() {
 		//}{}
 		//_ = json.Unmarshal(msg, &event)
 		this.lastPingTS = time.Now().Unix()
+		this.Config.GetMetrics().IncWSMessage(KRAKEN, "WSSpotTradeKK")
 		this.RecvHandler(string(msg))
 	}
 }
@@ -292,7 +299,7 @@ func (this *WSSpotTradeKK) pingRoutine() {
 
 			var err = conn.WriteJSON(ping)
 			if err != nil {
-				fmt.Println(err)
+				this.logger().Warn("ping error", LogF(LOG_FIELD_ERROR, err))
 			}
 		case _, opened := <-stopPingChn:
 			if opened {
@@ -303,3 +310,10 @@ func (this *WSSpotTradeKK) pingRoutine() {
 		}
 	}
 }
+
+func (this *WSSpotTradeKK) logger() Logger {
+	return this.Config.GetLogger().With(
+		LogF(LOG_FIELD_EXCHANGE, KRAKEN),
+		LogF(LOG_FIELD_CONN_ID, this.connId),
+	)
+}
@@ -6,7 +6,9 @@ import (
 	"crypto/sha512"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
 	"sync"
 	"time"
 
@@ -17,8 +19,18 @@ const (
 	SWAP_KRAKEN_ENDPOINT = "https://futures.kraken.com/derivatives"
 
 	SWAP_BASE_MODE_CHART = "https://futures.kraken.com"
+
+	// the uri templates which have the contract name or the chart setting.
+	SWAP_TICKER_URI = "/api/v3/tickers/%s"
+	SWAP_CHART_URI  = "/api/charts/v1/%s/%s/%s"
 )
 
+// the route label of the rest metrics.
+var _INERNAL_SWAP_ROUTES = []string{
+	SWAP_TICKER_URI,
+	SWAP_CHART_URI,
+}
+
 type Swap struct {
 	*Kraken
 	sync.Locker
@@ -30,7 +42,10 @@ type Swap struct {
 }
 
 func (swap *Swap) DoRequest(baseUrl, httpMethod, uri, reqBody string, response interface{}) ([]byte, error) {
-	var resp, err = NewHttpRequest(
+	var resp, err = NewHttpRequestWithRoute(
+		swap.config.GetMetrics(),
+		KRAKEN,
+		RouteOf(uri, _INERNAL_SWAP_ROUTES),
 		swap.config.HttpClient,
 		httpMethod,
 		baseUrl+uri,
@@ -64,7 +79,10 @@ func (swap *Swap) DoAuthRequest(httpMethod, uri, reqBody string, response interf
 		aut = base64.StdEncoding.EncodeToString(hmacAUT)
 	}
 
-	resp, err := NewHttpRequest(
+	resp, err := NewHttpRequestWithRoute(
+		swap.config.GetMetrics(),
+		KRAKEN,
+		RouteOf(uri, _INERNAL_SWAP_ROUTES),
 		swap.config.HttpClient,
 		httpMethod,
 		SWAP_KRAKEN_ENDPOINT+uri,
@@ -87,19 +105,56 @@ func (swap *Swap) DoAuthRequest(httpMethod, uri, reqBody string, response interf
 
 }
 
+// GetOpenAmount return the open interest in the ticker, the flexible contract is in the basis currency.
 func (swap *Swap) GetOpenAmount(pair Pair) (float64, int64, []byte, error) {
-	//TODO implement me
-	panic("implement me")
+	var contract = swap.getContract(pair)
+	var response = struct {
+		Result     string `json:"result"`
+		ServerTime string `json:"serverTime"`
+		Ticker     struct {
+			OpenInterest float64 `json:"openInterest"`
+		} `json:"ticker"`
+	}{}
+	resp, err := swap.DoRequest(
+		SWAP_KRAKEN_ENDPOINT,
+		http.MethodGet,
+		fmt.Sprintf(SWAP_TICKER_URI, contract.ContractName),
+		"",
+		&response,
+	)
+	if err != nil {
+		return 0, 0, resp, err
+	}
+	if response.Result != "success" {
+		return 0, 0, resp, errors.New(string(resp))
+	}
+	var timestamp = time.Now().UnixMilli()
+	if serverTime, err := time.Parse(time.RFC3339, response.ServerTime); err == nil {
+		timestamp = serverTime.UnixMilli()
+	}
+	return response.Ticker.OpenInterest, timestamp, resp, nil
 }
 
+// GetFundingFees return the realized funding rates in the last week, the item is [rate, funding time].
 func (swap *Swap) GetFundingFees(pair Pair) ([][]interface{}, []byte, error) {
-	//TODO implement me
-	panic("implement me")
+	var now = time.Now()
+	var rates, resp, err = swap.GetFundingRateHistory(pair, now.AddDate(0, 0, -7), now)
+	if err != nil {
+		return nil, resp, err
+	}
+	var fees = make([][]interface{}, 0, len(rates))
+	for _, rate := range rates {
+		fees = append(fees, []interface{}{rate.Rate, rate.FundingTime})
+	}
+	return fees, resp, nil
 }
 
 func (swap *Swap) GetFundingFee(pair Pair) (float64, error) {
-	//TODO implement me
-	panic("implement me")
+	var rate, _, err = swap.GetNextFundingRate(pair)
+	if err != nil {
+		return 0, err
+	}
+	return rate.Rate, nil
 }
 
 func (swap *Swap) GetAccount() (*SwapAccount, []byte, error) {
@@ -113,6 +168,18 @@ func (swap *Swap) GetPosition(pair Pair, openType FutureType) (*SwapPosition, []
 	panic("implement me")
 }
 
+// GetPositionMode kraken futures has the net position only.
+func (swap *Swap) GetPositionMode(pair Pair) (PositionMode, []byte, error) {
+	return POSITION_MODE_ONE_WAY, nil, nil
+}
+
+func (swap *Swap) SetPositionMode(pair Pair, mode PositionMode) ([]byte, error) {
+	if mode != POSITION_MODE_ONE_WAY {
+		return nil, errors.New("kraken only support the one-way position mode. ")
+	}
+	return nil, nil
+}
+
 func (swap *Swap) AddMargin(pair Pair, openType FutureType, marginAmount float64) ([]byte, error) {
 	//TODO implement me
 	panic("implement me")
@@ -1,9 +1,18 @@
 package kraken
 
 import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
 	. "github.com/deforceHK/goghostex"
 )
 
+const (
+	SWAP_LEVERAGE_URI = "/api/v3/leveragepreferences"
+)
+
 func (swap *Swap) GetAccountFlow() ([]*SwapAccountItem, []byte, error) {
 	//TODO implement me
 	panic("implement me")
@@ -13,3 +22,115 @@ func (swap *Swap) GetPairFlow(pair Pair) ([]*SwapAccountItem, []byte, error) {
 	// todo sync fee
 	return make([]*SwapAccountItem, 0), []byte(""), nil
 }
+
+// SetLeverage set the max leverage of the symbol, the position with the max leverage is isolated in kraken.
+func (swap *Swap) SetLeverage(pair Pair, lever int64, side PositionSide) ([]byte, error) {
+	if lever <= 0 {
+		return nil, errors.New("the leverage must be positive. ")
+	}
+	var param = url.Values{}
+	param.Set("symbol", swap.getContract(pair).ContractName)
+	param.Set("maxLeverage", fmt.Sprintf("%d", lever))
+	return swap.putLeverage(param)
+}
+
+// GetLeverage return the max leverage of the symbol, 0 means the cross margin without the max leverage.
+func (swap *Swap) GetLeverage(pair Pair, side PositionSide) (int64, []byte, error) {
+	var response struct {
+		Result              string `json:"result"`
+		LeveragePreferences []struct {
+			Symbol      string  `json:"symbol"`
+			MaxLeverage float64 `json:"maxLeverage"`
+		} `json:"leveragePreferences"`
+	}
+	var resp, err = swap.DoAuthRequest(http.MethodGet, SWAP_LEVERAGE_URI, "", &response)
+	if err != nil {
+		return 0, resp, err
+	}
+	if response.Result != "success" {
+		return 0, resp, errors.New(string(resp))
+	}
+	var symbol = swap.getContract(pair).ContractName
+	for _, preference := range response.LeveragePreferences {
+		if preference.Symbol == symbol {
+			return int64(preference.MaxLeverage), resp, nil
+		}
+	}
+	return 0, resp, nil
+}
+
+// SetMarginMode remove the max leverage for the cross margin, the isolated margin is set by SetLeverage.
+func (swap *Swap) SetMarginMode(pair Pair, marginType string) ([]byte, error) {
+	if marginType == ISOLATED {
+		return nil, errors.New("kraken isolate the position by the max leverage, use SetLeverage. ")
+	}
+	if marginType != CROSS {
+		return nil, fmt.Errorf("the margin type %s is not supported. ", marginType)
+	}
+	var param = url.Values{}
+	param.Set("symbol", swap.getContract(pair).ContractName)
+	return swap.putLeverage(param)
+}
+
+func (swap *Swap) putLeverage(param url.Values) ([]byte, error) {
+	var response struct {
+		Result string `json:"result"`
+	}
+	var resp, err = swap.DoAuthRequest(http.MethodPut, SWAP_LEVERAGE_URI, param.Encode(), &response)
+	if err != nil {
+		return resp, err
+	}
+	if response.Result != "success" {
+		return resp, errors.New(string(resp))
+	}
+	return resp, nil
+}
+
+// GetLeverageBrackets return the margin levels of the instrument, the Floor is the position value in usd.
+func (swap *Swap) GetLeverageBrackets(pair Pair) ([]*LeverageBracket, []byte, error) {
+	var response = struct {
+		Result      string `json:"result"`
+		Instruments []struct {
+			Symbol       string `json:"symbol"`
+			MarginLevels []struct {
+				Contracts           float64 `json:"contracts"`
+				NumNonContractUnits float64 `json:"numNonContractUnits"`
+				InitialMargin       float64 `json:"initialMargin"`
+				MaintenanceMargin   float64 `json:"maintenanceMargin"`
+			} `json:"marginLevels"`
+		} `json:"instruments"`
+	}{}
+	var resp, err = swap.DoRequest(SWAP_KRAKEN_ENDPOINT, http.MethodGet, SWAP_CONTRACT_URI, "", &response)
+	if err != nil {
+		return nil, resp, err
+	}
+	if response.Result != "success" {
+		return nil, resp, errors.New(string(resp))
+	}
+
+	var symbol = swap.getContract(pair).ContractName
+	for _, inst := range response.Instruments {
+		if inst.Symbol != symbol {
+			continue
+		}
+		var brackets = make([]*LeverageBracket, 0, len(inst.MarginLevels))
+		for i, level := range inst.MarginLevels {
+			var bracket = &LeverageBracket{
+				Pair:             pair,
+				Tier:             int64(i + 1),
+				Floor:            level.NumNonContractUnits + level.Contracts,
+				MaintMarginRatio: level.MaintenanceMargin,
+			}
+			if level.InitialMargin > 0 {
+				bracket.MaxLeverage = 1 / level.InitialMargin
+			}
+			// the cap is the floor of the next level, 0 means no limit in the last level.
+			if i > 0 {
+				brackets[i-1].Cap = bracket.Floor
+			}
+			brackets = append(brackets, bracket)
+		}
+		return brackets, resp, nil
+	}
+	return nil, resp, fmt.Errorf("the instrument %s is not found. ", symbol)
+}
@@ -1,9 +1,11 @@
 package kraken
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
+	"sort"
 	"time"
 
 	. "github.com/deforceHK/goghostex"
@@ -35,7 +37,7 @@ var SWAP_KRAKEN_PERIOD_INTERVAL = map[int]int{
 
 func (swap *Swap) GetTicker(pair Pair) (*SwapTicker, []byte, error) {
 	var contract = swap.getContract(pair)
-	var uri = fmt.Sprintf("/api/v3/tickers/%s", contract.ContractName)
+	var uri = fmt.Sprintf(SWAP_TICKER_URI, contract.ContractName)
 	var response = struct {
 		ServerTime string `json:"serverTime"`
 		Result     string `json:"result"`
@@ -136,6 +138,25 @@ func (swap *Swap) GetLimit(pair Pair) (float64, float64, error) {
 }
 
 func (swap *Swap) GetKline(pair Pair, period, size, since int) ([]*SwapKline, []byte, error) {
+	return swap.getChartKline("trade", pair, period, size, since)
+}
+
+func (swap *Swap) GetMarkKline(pair Pair, period, size, since int) ([]*SwapKline, []byte, error) {
+	return swap.getChartKline("mark", pair, period, size, since)
+}
+
+// GetIndexKline return the spot tick type in the charts, it is the index price of the contract.
+func (swap *Swap) GetIndexKline(pair Pair, period, size, since int) ([]*SwapKline, []byte, error) {
+	return swap.getChartKline("spot", pair, period, size, since)
+}
+
+// GetPremiumKline kraken has no premium index kline, the premium is the mark kline minus the index kline.
+func (swap *Swap) GetPremiumKline(pair Pair, period, size, since int) ([]*SwapKline, []byte, error) {
+	return nil, nil, errors.New("kraken has no premium index kline. ")
+}
+
+// getChartKline request the charts api, the tickType is trade, mark or spot.
+func (swap *Swap) getChartKline(tickType string, pair Pair, period, size, since int) ([]*SwapKline, []byte, error) {
 	var symbol = pair.ToSymbol("", true)
 	if symbol == "BTCUSD" {
 		symbol = "XBTUSD"
@@ -167,7 +188,7 @@ func (swap *Swap) GetKline(pair Pair, period, size, since int) ([]*SwapKline, []
 	if resp, err := swap.DoRequest(
 		SWAP_BASE_MODE_CHART,
 		http.MethodGet,
-		fmt.Sprintf("/api/charts/v1/trade/%s/%s", symbol, SWAP_KRAKEN_PERIOD_TRANS[period])+reqBody,
+		fmt.Sprintf(SWAP_CHART_URI, tickType, symbol, SWAP_KRAKEN_PERIOD_TRANS[period])+reqBody,
 		"", &candles,
 	); err != nil {
 		return nil, resp, err
@@ -192,3 +213,144 @@ func (swap *Swap) GetKline(pair Pair, period, size, since int) ([]*SwapKline, []
 		return GetAscSwapKline(klines), resp, nil
 	}
 }
+
+const SWAP_FUNDING_INTERVAL = 60 * 60 * 1000 // unit: ms, kraken funding every hour
+
+// GetFundingRateHistory filter the whole history of the historicalfundingrates api, there is no page in it.
+func (swap *Swap) GetFundingRateHistory(pair Pair, start, end time.Time) ([]*FundingRate, []byte, error) {
+	var params = url.Values{}
+	params.Set("symbol", swap.getContract(pair).ContractName)
+	var response = struct {
+		Rates []struct {
+			Timestamp           string  `json:"timestamp"`
+			RelativeFundingRate float64 `json:"relativeFundingRate"`
+		} `json:"rates"`
+	}{}
+	resp, err := swap.DoRequest(
+		SWAP_KRAKEN_ENDPOINT,
+		http.MethodGet,
+		"/api/v4/historicalfundingrates?"+params.Encode(),
+		"",
+		&response,
+	)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	var rates = make([]*FundingRate, 0)
+	for _, item := range response.Rates {
+		var fundingTime, err = time.Parse(time.RFC3339, item.Timestamp)
+		if err != nil {
+			return nil, resp, err
+		}
+		if fundingTime.Before(start) || fundingTime.After(end) {
+			continue
+		}
+		rates = append(rates, &FundingRate{
+			Pair:        pair,
+			Exchange:    KRAKEN,
+			Rate:        item.RelativeFundingRate,
+			FundingTime: fundingTime.UnixMilli(),
+			Interval:    SWAP_FUNDING_INTERVAL,
+		})
+	}
+	SortFundingRates(rates, SWAP_FUNDING_INTERVAL)
+	return rates, resp, nil
+}
+
+// GetNextFundingRate return the predicted rate in the ticker, the absolute rate is divided by the mark price.
+func (swap *Swap) GetNextFundingRate(pair Pair) (*FundingRate, []byte, error) {
+	var contract = swap.getContract(pair)
+	var response = struct {
+		Result string `json:"result"`
+		Ticker struct {
+			MarkPrice             float64 `json:"markPrice"`
+			FundingRatePrediction float64 `json:"fundingRatePrediction"`
+		} `json:"ticker"`
+	}{}
+	resp, err := swap.DoRequest(
+		SWAP_KRAKEN_ENDPOINT,
+		http.MethodGet,
+		fmt.Sprintf(SWAP_TICKER_URI, contract.ContractName),
+		"",
+		&response,
+	)
+	if err != nil {
+		return nil, resp, err
+	}
+	if response.Result != "success" || response.Ticker.MarkPrice <= 0 {
+		return nil, resp, fmt.Errorf("the ticker of %s is wrong: %s", contract.ContractName, string(resp))
+	}
+
+	var nextHour = time.Now().Truncate(time.Hour).Add(time.Hour)
+	return &FundingRate{
+		Pair:        pair,
+		Exchange:    KRAKEN,
+		Rate:        response.Ticker.FundingRatePrediction / response.Ticker.MarkPrice,
+		IsPredicted: true,
+		FundingTime: nextHour.UnixMilli(),
+		Interval:    SWAP_FUNDING_INTERVAL,
+		MarkPrice:   response.Ticker.MarkPrice,
+	}, resp, nil
+}
+
+// GetLiquidations filter the liquidations in the recent trades of the history api.
+func (swap *Swap) GetLiquidations(pair Pair, size int) ([]*Liquidation, []byte, error) {
+	var contract = swap.getContract(pair)
+	var params = url.Values{}
+	params.Set("symbol", contract.ContractName)
+	var response = struct {
+		Result  string `json:"result"`
+		History []struct {
+			Time  string  `json:"time"`
+			Price float64 `json:"price"`
+			Size  float64 `json:"size"`
+			Side  string  `json:"side"`
+			Type  string  `json:"type"`
+		} `json:"history"`
+	}{}
+	resp, err := swap.DoRequest(
+		SWAP_KRAKEN_ENDPOINT,
+		http.MethodGet,
+		"/api/v3/history?"+params.Encode(),
+		"",
+		&response,
+	)
+	if err != nil {
+		return nil, resp, err
+	}
+	if response.Result != "success" {
+		return nil, resp, errors.New(string(resp))
+	}
+
+	var liquidations = make([]*Liquidation, 0)
+	for _, trade := range response.History {
+		if trade.Type != "liquidation" {
+			continue
+		}
+		var tradeTime, err = time.Parse(time.RFC3339, trade.Time)
+		if err != nil {
+			return nil, resp, err
+		}
+		var liquidation = &Liquidation{
+			Pair:      pair,
+			Exchange:  KRAKEN,
+			Symbol:    contract.ContractName,
+			Side:      SELL,
+			Price:     trade.Price,
+			Amount:    trade.Size * contract.UnitAmount,
+			Timestamp: tradeTime.UnixMilli(),
+		}
+		if trade.Side == "buy" {
+			liquidation.Side = BUY
+		}
+		liquidations = append(liquidations, liquidation)
+	}
+	sort.Slice(liquidations, func(i, j int) bool {
+		return liquidations[i].Timestamp < liquidations[j].Timestamp
+	})
+	if size > 0 && len(liquidations) > size {
+		liquidations = liquidations[len(liquidations)-size:]
+	}
+	return liquidations, resp, nil
+}
@@ -1,6 +1,7 @@
 package kraken
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
@@ -36,13 +37,56 @@ var getOrderStatusRelation = map[string]TradeStatus{
 	"FULLY_EXECUTED": ORDER_FINISH,
 	"REJECTED":       ORDER_FAIL,
 	"CANCELLED":      ORDER_CANCEL,
+	// the conditional order is waiting for the trigger
+	"TRIGGER_PLACED":     ORDER_UNFINISH,
+	"TRIGGER_ACTIVATING": ORDER_UNFINISH,
 }
 
-func (swap *Swap) PlaceOrder(order *SwapOrder) ([]byte, error) {
-	if order == nil {
-		return nil, errors.New("order param is nil")
+var conditionTypeRelation = map[ConditionType]string{
+	CONDITION_STOP:          "stp",
+	CONDITION_TAKE_PROFIT:   "take_profit",
+	CONDITION_TRAILING_STOP: "trailing_stop",
+}
+
+var triggerSignalRelation = map[TriggerType]string{
+	TRIGGER_LAST:  "last",
+	TRIGGER_MARK:  "mark",
+	TRIGGER_INDEX: "spot",
+}
+
+// setConditionParams change the order type to the conditional one,
+// the stop and the take profit is the stop limit order unless the place type is MARKET.
+func (swap *Swap) setConditionParams(param *url.Values, order *SwapOrder, normalizer *Normalizer) error {
+	var orderType, exist = conditionTypeRelation[order.ConditionType]
+	if !exist {
+		return errors.New("condition type not found. ")
+	}
+	triggerSignal, exist := triggerSignalRelation[order.TriggerType]
+	if !exist {
+		return errors.New("trigger type not found. ")
 	}
+	param.Set("orderType", orderType)
+	param.Set("triggerSignal", triggerSignal)
 
+	if order.ConditionType == CONDITION_TRAILING_STOP {
+		if order.CallbackRate <= 0 {
+			return errors.New("the callback rate of the trailing stop must be positive. ")
+		}
+		param.Set("trailingStopMaxDeviation", FloatToString(order.CallbackRate*100, 2))
+		param.Set("trailingStopDeviationUnit", "PERCENT")
+		param.Del("limitPrice")
+		return nil
+	}
+
+	if order.TriggerPrice <= 0 {
+		return errors.New("the trigger price must be positive. ")
+	}
+	param.Set("stopPrice", normalizer.FormatPrice(order.TriggerPrice))
+	return nil
+}
+
+// placeParams build the params of the order, the batch orders use it too.
+func (swap *Swap) placeParams(order *SwapOrder) (url.Values, error) {
 	var side, placeType = "", ""
 	var exist = false
 
@@ -53,21 +97,61 @@ func (swap *Swap) PlaceOrder(order *SwapOrder) ([]byte, error) {
 		return nil, errors.New("place type not found. ")
 	}
 
+	if order.ClosePosition {
+		return nil, errors.New("kraken not support close position, use reduce only with the amount. ")
+	}
+	// kraken has the net position only, the orders work in the one-way mode.
+	var positionSide = order.PositionSide
+	if positionSide == POSITION_SIDE_AUTO {
+		positionSide = POSITION_SIDE_BOTH
+	}
+	if positionSide != POSITION_SIDE_BOTH {
+		return nil, errors.New("kraken only support the one-way position mode. ")
+	}
+	var _, reduceOnly, err = ResolvePosition(order.Type, positionSide, order.ReduceOnly, false)
+	if err != nil {
+		return nil, err
+	}
+
 	var contract = swap.getContract(order.Pair)
 	var symbol = contract.ContractName
 
+	var normalizer = contract.GetNormalizer()
+	normalizer.ReduceOnly = reduceOnly
+	price, amount, err := normalizer.Normalize(order.Price, order.Amount, order.PlaceType == MARKET)
+	if err != nil {
+		return nil, err
+	}
+
 	var param = url.Values{}
 	param.Set("symbol", symbol)
 	param.Set("orderType", placeType)
 	param.Set("side", side)
-	param.Set("size", fmt.Sprintf("%v", order.Amount))
-	param.Set("reduceOnly", "false")
+	param.Set("size", normalizer.FormatAmount(amount))
+	param.Set("reduceOnly", fmt.Sprintf("%t", reduceOnly))
 	if order.PlaceType != MARKET {
-		param.Set("limitPrice", FloatToPrice(order.Price, contract.PricePrecision, contract.TickSize))
+		param.Set("limitPrice", normalizer.FormatPrice(price))
 	}
 	if order.Cid != "" {
 		param.Set("cliOrdId", order.Cid)
 	}
+	if order.ConditionType != CONDITION_NONE {
+		if err := swap.setConditionParams(&param, order, normalizer); err != nil {
+			return nil, err
+		}
+	}
+	return param, nil
+}
+
+func (swap *Swap) PlaceOrder(order *SwapOrder) ([]byte, error) {
+	if order == nil {
+		return nil, errors.New("order param is nil")
+	}
+
+	var param, err = swap.placeParams(order)
+	if err != nil {
+		return nil, err
+	}
 
 	var response struct {
 		ServerTime string `json:"serverTime"`
@@ -160,6 +244,249 @@ func (swap *Swap) CancelOrder(order *SwapOrder) ([]byte, error) {
 
 }
 
+// AmendOrder modify the open order by the editorder api, the size includes the filled part.
+func (swap *Swap) AmendOrder(order *SwapOrder, newPrice, newAmount float64) ([]byte, error) {
+	if order.OrderId == "" && order.Cid == "" {
+		return nil, errors.New("The orderid and cid is empty. ")
+	}
+	if newPrice <= 0 {
+		newPrice = order.Price
+	}
+	if newAmount <= 0 {
+		newAmount = order.Amount
+	}
+
+	var normalizer = swap.getContract(order.Pair).GetNormalizer()
+	normalizer.ReduceOnly = IsReduce(order.Type, order.ReduceOnly)
+	var price, amount, err = normalizer.Normalize(newPrice, newAmount, order.PlaceType == MARKET)
+	if err != nil {
+		return nil, err
+	}
+
+	var param = url.Values{}
+	if order.OrderId != "" {
+		param.Set("orderId", order.OrderId)
+	} else {
+		param.Set("cliOrdId", order.Cid)
+	}
+	param.Set("size", normalizer.FormatAmount(amount))
+	if order.PlaceType != MARKET {
+		param.Set("limitPrice", normalizer.FormatPrice(price))
+	}
+
+	var response struct {
+		Result     string `json:"result"`
+		EditStatus struct {
+			Status       string `json:"status"`
+			OrderId      string `json:"orderId"`
+			ReceivedTime string `json:"receivedTime"`
+		} `json:"editStatus"`
+	}
+	resp, err := swap.DoAuthRequest(http.MethodPost, "/api/v3/editorder", param.Encode(), &response)
+	if err != nil {
+		return resp, err
+	}
+	if response.Result != "success" || response.EditStatus.Status != "edited" {
+		return resp, errors.New(string(resp))
+	}
+	order.Price, order.Amount = price, amount
+	return resp, nil
+}
+
+func (swap *Swap) CancelAllOrders(pair Pair) ([]byte, error) {
+	var param = url.Values{}
+	param.Set("symbol", swap.getContract(pair).ContractName)
+	var response struct {
+		Result       string `json:"result"`
+		CancelStatus struct {
+			Status string `json:"status"`
+		} `json:"cancelStatus"`
+	}
+
+	var resp, err = swap.DoAuthRequest(http.MethodPost, "/api/v3/cancelallorders", param.Encode(), &response)
+	if err != nil {
+		return resp, err
+	}
+	if response.Result != "success" {
+		return resp, errors.New(string(resp))
+	}
+	return resp, nil
+}
+
+// CancelAllAfter set the cancelallordersafter of the whole account, the pair is not used.
+func (swap *Swap) CancelAllAfter(pair Pair, timeout time.Duration) ([]byte, error) {
+	var param = url.Values{}
+	param.Set("timeout", fmt.Sprintf("%d", int64(timeout.Seconds())))
+	var response struct {
+		Result string `json:"result"`
+		Status struct {
+			CurrentTime string `json:"currentTime"`
+			TriggerTime string `json:"triggerTime"`
+		} `json:"status"`
+	}
+
+	var resp, err = swap.DoAuthRequest(http.MethodPost, "/api/v3/cancelallordersafter", param.Encode(), &response)
+	if err != nil {
+		return resp, err
+	}
+	if response.Result != "success" {
+		return resp, errors.New(string(resp))
+	}
+	return resp, nil
+}
+
+// the numeric params of the order are the numbers in the batch order json.
+var batchNumberParams = map[string]bool{
+	"size":                     true,
+	"limitPrice":               true,
+	"stopPrice":                true,
+	"trailingStopMaxDeviation": true,
+}
+
+type batchStatus struct {
+	Status           string `json:"status"`
+	OrderTag         string `json:"order_tag"`
+	OrderId          string `json:"order_id"`
+	CliOrdId         string `json:"cliOrdId"`
+	DateTimeReceived string `json:"dateTimeReceived"`
+}
+
+// doBatchRequest send the instructions to the batchorder api, the results are in the same order of the instructions.
+// The send instruction is matched by the order_tag, and the cancel instruction is matched by the order id.
+func (swap *Swap) doBatchRequest(instructions []map[string]interface{}) ([]batchStatus, []error, []byte) {
+	var statuses = make([]batchStatus, len(instructions))
+	var errs = make([]error, len(instructions))
+	if len(instructions) == 0 {
+		return statuses, errs, nil
+	}
+
+	var raw, _ = json.Marshal(map[string]interface{}{"batchOrder": instructions})
+	var param = url.Values{}
+	param.Set("json", string(raw))
+
+	var response struct {
+		Result      string        `json:"result"`
+		Error       string        `json:"error"`
+		BatchStatus []batchStatus `json:"batchStatus"`
+	}
+	var resp, err = swap.DoAuthRequest(http.MethodPost, "/api/v3/batchorder", param.Encode(), &response)
+	if err == nil && response.Result != "success" {
+		err = errors.New(string(resp))
+	}
+	if err != nil {
+		for i := range errs {
+			errs[i] = err
+		}
+		return statuses, errs, resp
+	}
+
+	for i, instruction := range instructions {
+		var found = false
+		for _, status := range response.BatchStatus {
+			if instruction["order"] == "send" && status.OrderTag == instruction["order_tag"] ||
+				instruction["order"] == "cancel" && status.OrderTag == "" &&
+					(status.OrderId == instruction["order_id"] || status.CliOrdId == instruction["cliOrdId"]) {
+				statuses[i], found = status, true
+				break
+			}
+		}
+		if !found {
+			errs[i] = errors.New(string(resp))
+		}
+	}
+	return statuses, errs, resp
+}
+
+// BatchPlaceOrders place the orders by the batchorder api.
+func (swap *Swap) BatchPlaceOrders(orders []*SwapOrder) ([]error, []byte, error) {
+	var errs = make([]error, len(orders))
+	var indexes = make([]int, 0, len(orders))
+	var instructions = make([]map[string]interface{}, 0, len(orders))
+	for i, order := range orders {
+		if order == nil {
+			errs[i] = errors.New("order param is nil")
+			continue
+		}
+		var param, err = swap.placeParams(order)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		var instruction = map[string]interface{}{"order": "send", "order_tag": fmt.Sprintf("%d", i)}
+		for key := range param {
+			if batchNumberParams[key] {
+				instruction[key] = json.Number(param.Get(key))
+			} else if key == "reduceOnly" {
+				instruction[key] = param.Get(key) == "true"
+			} else {
+				instruction[key] = param.Get(key)
+			}
+		}
+		indexes = append(indexes, i)
+		instructions = append(instructions, instruction)
+	}
+
+	var statuses, batchErrs, resp = swap.doBatchRequest(instructions)
+	for j, i := range indexes {
+		if batchErrs[j] != nil {
+			errs[i] = batchErrs[j]
+			continue
+		}
+		var orderStatus, exist = statusRelation[statuses[j].Status]
+		if !exist {
+			orders[i].Status = ORDER_FAIL
+			errs[i] = errors.New(statuses[j].Status)
+			continue
+		}
+		orders[i].Status = orderStatus
+		orders[i].OrderId = statuses[j].OrderId
+		if orderTime, err := time.Parse(time.RFC3339, statuses[j].DateTimeReceived); err == nil {
+			orders[i].PlaceTimestamp = orderTime.UnixMilli()
+			orders[i].PlaceDatetime = orderTime.In(swap.config.Location).Format(GO_BIRTHDAY)
+		}
+	}
+	return errs, resp, BatchResult(errs)
+}
+
+// BatchCancelOrders cancel the orders by the batchorder api.
+func (swap *Swap) BatchCancelOrders(orders []*SwapOrder) ([]error, []byte, error) {
+	var errs = make([]error, len(orders))
+	var indexes = make([]int, 0, len(orders))
+	var instructions = make([]map[string]interface{}, 0, len(orders))
+	for i, order := range orders {
+		if order == nil {
+			errs[i] = errors.New("order param is nil")
+			continue
+		}
+		var instruction = map[string]interface{}{"order": "cancel"}
+		if order.OrderId != "" {
+			instruction["order_id"] = order.OrderId
+		} else if order.Cid != "" {
+			instruction["cliOrdId"] = order.Cid
+		} else {
+			errs[i] = errors.New("The orderid and cid is empty. ")
+			continue
+		}
+		indexes = append(indexes, i)
+		instructions = append(instructions, instruction)
+	}
+
+	var statuses, batchErrs, resp = swap.doBatchRequest(instructions)
+	for j, i := range indexes {
+		if batchErrs[j] != nil {
+			errs[i] = batchErrs[j]
+			continue
+		}
+		var orderStatus, exist = statusRelation[statuses[j].Status]
+		if !exist {
+			errs[i] = errors.New(statuses[j].Status)
+			continue
+		}
+		orders[i].Status = orderStatus
+	}
+	return errs, resp, BatchResult(errs)
+}
+
 func (swap *Swap) GetOrder(order *SwapOrder) ([]byte, error) {
 	var param = url.Values{}
 	param.Set("orderIds", order.OrderId)
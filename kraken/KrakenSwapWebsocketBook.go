@@ -135,7 +135,7 @@ func (this *LocalOrderBooks) Receiver(msg string) {
 		_ = json.Unmarshal(rawData, &snapshot)
 		this.recvSnapshot(snapshot)
 	} else {
-		fmt.Println("The feed must in book_snapshot book")
+		this.logger().Debug("The feed must in book_snapshot book", LogF("msg", msg))
 	}
 }
 
@@ -150,6 +150,7 @@ func (this *LocalOrderBooks) recvBook(book KKBook) {
 	if book.Seq != this.SeqData[book.ProductId]+1 {
 		mux.Unlock()
 		//这样restart也可以，但是重新订阅是不是更轻量？
+		this.Config.GetMetrics().IncBookResync(KRAKEN, book.ProductId)
 		this.Resubscribe(book.ProductId)
 		return
 	}
@@ -208,6 +209,7 @@ func (this *LocalOrderBooks) Snapshot(pair Pair) (*SwapDepth, error) {
 	defer mux.Unlock()
 
 	var lastTime = time.UnixMilli(this.TsData[productId]).In(this.WSSwapMarketKK.Config.Location)
+	this.Config.GetMetrics().SetBookStaleness(KRAKEN, productId, time.Since(lastTime))
 	var depth = &SwapDepth{
 		Pair:      pair,
 		Timestamp: lastTime.UnixMilli(),
@@ -0,0 +1,111 @@
+package kraken
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	. "github.com/deforceHK/goghostex"
+)
+
+// Liquidations is the typed liquidation stream of the flexible swap, it filter the liquidation type in the trade feed.
+type Liquidations struct {
+	*WSSwapMarketKK
+	LiquidationHandler func(liquidation *Liquidation)
+
+	pairs sync.Map // the product id to the pair of the subscribed
+}
+
+type tradeKK struct {
+	Side  string  `json:"side"`
+	Type  string  `json:"type"`
+	Time  int64   `json:"time"`
+	Qty   float64 `json:"qty"`
+	Price float64 `json:"price"`
+}
+
+func (this *Liquidations) Init() error {
+	if this.LiquidationHandler == nil {
+		this.LiquidationHandler = func(liquidation *Liquidation) {
+			this.logger().Debug("receive liquidation", LogF("liquidation", *liquidation))
+		}
+	}
+	this.RecvHandler = func(s string) {
+		this.Receiver(s)
+	}
+	return this.Start()
+}
+
+func (this *Liquidations) productId(pair Pair) string {
+	var symbol = pair.ToSymbol("", true)
+	if symbol == "BTCUSD" {
+		symbol = "XBTUSD"
+	}
+	return fmt.Sprintf("PF_%s", symbol)
+}
+
+// Subscribe the trade feed of the pair, the snapshot of the recent trades backfill the liquidations.
+func (this *Liquidations) Subscribe(pair Pair) {
+	var productId = this.productId(pair)
+	this.pairs.Store(productId, pair)
+	this.WSSwapMarketKK.Subscribe(map[string]interface{}{
+		"event":       "subscribe",
+		"feed":        "trade",
+		"product_ids": []string{productId},
+	})
+}
+
+func (this *Liquidations) Unsubscribe(pair Pair) {
+	this.WSSwapMarketKK.Unsubscribe(map[string]interface{}{
+		"event":       "unsubscribe",
+		"feed":        "trade",
+		"product_ids": []string{this.productId(pair)},
+	})
+}
+
+func (this *Liquidations) Receiver(msg string) {
+	var response = struct {
+		Feed      string     `json:"feed"`
+		ProductId string     `json:"product_id"`
+		Trades    []*tradeKK `json:"trades"`
+		tradeKK
+	}{}
+	if err := json.Unmarshal([]byte(msg), &response); err != nil {
+		this.ErrorHandler(err)
+		return
+	}
+
+	var trades []*tradeKK
+	switch response.Feed {
+	case "trade":
+		trades = []*tradeKK{&response.tradeKK}
+	case "trade_snapshot":
+		trades = response.Trades
+	default:
+		this.logger().Debug("receive message", LogF("msg", msg))
+		return
+	}
+
+	var pair, exist = this.pairs.Load(response.ProductId)
+	if !exist {
+		return
+	}
+	for _, trade := range trades {
+		if trade.Type != "liquidation" {
+			continue
+		}
+		var liquidation = &Liquidation{
+			Pair:      pair.(Pair),
+			Exchange:  KRAKEN,
+			Symbol:    response.ProductId,
+			Side:      SELL,
+			Price:     trade.Price,
+			Amount:    trade.Qty,
+			Timestamp: trade.Time,
+		}
+		if trade.Side == "buy" {
+			liquidation.Side = BUY
+		}
+		this.LiquidationHandler(liquidation)
+	}
+}
@@ -0,0 +1,32 @@
+package kraken
+
+import (
+	"testing"
+
+	. "github.com/deforceHK/goghostex"
+)
+
+// go test -v ./kraken/... -count=1 -run=TestLiquidations_Receiver
+func TestLiquidations_Receiver(t *testing.T) {
+	var received []*Liquidation
+	var liquidations = &Liquidations{
+		WSSwapMarketKK: &WSSwapMarketKK{Config: &APIConfig{}},
+		LiquidationHandler: func(liquidation *Liquidation) {
+			received = append(received, liquidation)
+		},
+	}
+	liquidations.pairs.Store("PF_XBTUSD", Pair{Basis: BTC, Counter: USD})
+
+	liquidations.Receiver(`{"feed":"trade_snapshot","product_id":"PF_XBTUSD","trades":[` +
+		`{"feed":"trade","product_id":"PF_XBTUSD","side":"sell","type":"fill","seq":1,"time":1612269825817,"qty":0.5,"price":34893},` +
+		`{"feed":"trade","product_id":"PF_XBTUSD","side":"sell","type":"liquidation","seq":2,"time":1612269825818,"qty":0.2,"price":34890}]}`)
+	liquidations.Receiver(`{"feed":"trade","product_id":"PF_XBTUSD","uid":"05af78ac","side":"buy","type":"liquidation",` +
+		`"seq":3,"time":1612269825819,"qty":0.1,"price":34900}`)
+	if len(received) != 2 {
+		t.Error("the liquidations are wrong: ", len(received))
+		return
+	}
+	if received[0].Side != SELL || received[0].Amount != 0.2 || received[1].Side != BUY || received[1].Price != 34900 {
+		t.Error("the liquidation is wrong: ", *received[0], *received[1])
+	}
+}
@@ -3,7 +3,6 @@ package kraken
 import (
 	"encoding/json"
 	"fmt"
-	"log"
 	"strings"
 	"time"
 
@@ -16,6 +15,7 @@ type WSSwapMarketKK struct {
 	RecvHandler  func(string)
 	ErrorHandler func(error)
 	Config       *APIConfig
+	DialerConfig *WsDialerConfig // the proxy, tls and local address setting of the connection, not necessary
 
 	conn       *websocket.Conn
 	connId     string
@@ -85,6 +85,7 @@ func (this *WSSwapMarketKK) Unsubscribe(v interface{}) {
 }
 
 func (this *WSSwapMarketKK) Restart() {
+	this.Config.GetMetrics().IncWSReconnect(KRAKEN, "WSSwapMarketKK")
 	this.ErrorHandler(
 		&WSRestartError{
 			Msg: fmt.Sprintf("kk market websocket will restart in next %d seconds...", this.restartSleepSec),
@@ -143,7 +144,7 @@ func (this *WSSwapMarketKK) recvRoutine() {
 		if readErr != nil {
 			// conn closed by user.
 			if strings.Index(readErr.Error(), "use of closed network connection") > 0 {
-				fmt.Println("conn closed by user. ")
+				this.logger().Info("conn closed by user")
 				return
 			}
 
@@ -160,6 +161,7 @@ func (this *WSSwapMarketKK) recvRoutine() {
 		}{}
 		_ = json.Unmarshal(msg, &event)
 		this.lastPingTS = time.Now().Unix()
+		this.Config.GetMetrics().IncWSMessage(KRAKEN, "WSSwapMarketKK")
 		if event.Feed != "heartbeat" {
 			this.RecvHandler(string(msg))
 		}
@@ -180,6 +182,7 @@ func (this *WSSwapMarketKK) startCheck() error {
 				restartNum, this.restartLimitSec,
 			),
 		}
+		this.Config.GetMetrics().IncWSRestartLimit(KRAKEN, "WSSwapMarketKK")
 		return wsErr
 	}
 	return nil
@@ -187,7 +190,11 @@ func (this *WSSwapMarketKK) startCheck() error {
 
 func (this *WSSwapMarketKK) getConn(wss string) (*websocket.Conn, error) {
 	this.initDefaultValue()
-	var conn, _, err = websocket.DefaultDialer.Dial(
+	var dialer, dialErr = NewWsDialer(this.DialerConfig)
+	if dialErr != nil {
+		return nil, dialErr
+	}
+	var conn, _, err = dialer.Dial(
 		wss,
 		nil,
 	)
@@ -200,12 +207,12 @@ func (this *WSSwapMarketKK) getConn(wss string) (*websocket.Conn, error) {
 func (this *WSSwapMarketKK) initDefaultValue() {
 	if this.RecvHandler == nil {
 		this.RecvHandler = func(msg string) {
-			log.Println(msg)
+			this.logger().Debug("receive message", LogF("msg", msg))
 		}
 	}
 	if this.ErrorHandler == nil {
 		this.ErrorHandler = func(err error) {
-			log.Println(err)
+			this.logger().Error("websocket error", LogF(LOG_FIELD_ERROR, err))
 		}
 	}
 	if this.restartSleepSec == 0 {
@@ -238,3 +245,10 @@ func (this *WSSwapMarketKK) Stop() {
 	}
 	this.connId = ""
 }
+
+func (this *WSSwapMarketKK) logger() Logger {
+	return this.Config.GetLogger().With(
+		LogF(LOG_FIELD_EXCHANGE, KRAKEN),
+		LogF(LOG_FIELD_CONN_ID, this.connId),
+	)
+}
@@ -7,7 +7,6 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
-	"log"
 	"strings"
 	"time"
 
@@ -28,6 +27,7 @@ type WSSwapTradeKK struct {
 	RecvHandler  func(string)
 	ErrorHandler func(error)
 	Config       *APIConfig
+	DialerConfig *WsDialerConfig // the proxy, tls and local address setting of the connection, not necessary
 
 	conn       *websocket.Conn
 	connId     string
@@ -191,6 +191,7 @@ func (this *WSSwapTradeKK) Stop() {
 }
 
 func (this *WSSwapTradeKK) Restart() {
+	this.Config.GetMetrics().IncWSReconnect(KRAKEN, "WSSwapTradeKK")
 	this.ErrorHandler(
 		&WSRestartError{Msg: fmt.Sprintf("websocket will restart in next %d seconds...", this.restartSleepSec)},
 	)
@@ -235,6 +236,7 @@ func (this *WSSwapTradeKK) startCheck() error {
 				restartNum, this.restartLimitSec,
 			),
 		}
+		this.Config.GetMetrics().IncWSRestartLimit(KRAKEN, "WSSwapTradeKK")
 		return wsErr
 	}
 	return nil
@@ -242,7 +244,11 @@ func (this *WSSwapTradeKK) startCheck() error {
 
 func (this *WSSwapTradeKK) getConn(wss string) (*websocket.Conn, error) {
 	this.initDefaultValue()
-	var conn, _, err = websocket.DefaultDialer.Dial(
+	var dialer, dialErr = NewWsDialer(this.DialerConfig)
+	if dialErr != nil {
+		return nil, dialErr
+	}
+	var conn, _, err = dialer.Dial(
 		wss,
 		nil,
 	)
@@ -255,12 +261,12 @@ func (this *WSSwapTradeKK) getConn(wss string) (*websocket.Conn, error) {
 func (this *WSSwapTradeKK) initDefaultValue() {
 	if this.RecvHandler == nil {
 		this.RecvHandler = func(msg string) {
-			log.Println(msg)
+			this.logger().Debug("receive message", LogF("msg", msg))
 		}
 	}
 	if this.ErrorHandler == nil {
 		this.ErrorHandler = func(err error) {
-			log.Println(err)
+			this.logger().Error("websocket error", LogF(LOG_FIELD_ERROR, err))
 		}
 	}
 	if this.restartSleepSec == 0 {
@@ -313,7 +319,7 @@ func (this *WSSwapTradeKK) recvRoutine() {
 		if readErr != nil {
 			// conn closed by user.
 			if strings.Index(readErr.Error(), "use of closed network connection") > 0 {
-				fmt.Println("conn closed by user. ")
+				this.logger().Info("conn closed by user")
 				return
 			}
 
@@ -330,6 +336,7 @@ func (this *WSSwapTradeKK) recvRoutine() {
 		}{}
 		_ = json.Unmarshal(msg, &event)
 		this.lastPingTS = time.Now().Unix()
+		this.Config.GetMetrics().IncWSMessage(KRAKEN, "WSSwapTradeKK")
 		if event.Feed != "heartbeat" {
 			this.RecvHandler(string(msg))
 		}
@@ -352,3 +359,10 @@ func hashChallenge(apiSecret, challenge string) string {
 	// 4. Base64-encode the result of step 3
 	return base64.StdEncoding.EncodeToString(signature)
 }
+
+func (this *WSSwapTradeKK) logger() Logger {
+	return this.Config.GetLogger().With(
+		LogF(LOG_FIELD_EXCHANGE, KRAKEN),
+		LogF(LOG_FIELD_CONN_ID, this.connId),
+	)
+}
@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 	"sync"
@@ -13,6 +15,11 @@ import (
 	. "github.com/deforceHK/goghostex"
 )
 
+const (
+	V5_MARK_KLINE_URI  = "/api/v5/market/mark-price-candles?"
+	V5_INDEX_KLINE_URI = "/api/v5/market/index-candles?"
+)
+
 const (
 	/*
 	  http headers
@@ -60,6 +67,8 @@ const (
 	ResultDataJsonString = "resultDataJsonString"
 	ResultPageJsonString = "resultPageJsonString"
 
+	V5_BATCH_LIMIT = 20 // the max order count in one v5 batch request
+
 	BTC_USD_SWAP = "BTC-USD-SWAP"
 	LTC_USD_SWAP = "LTC-USD-SWAP"
 	ETH_USD_SWAP = "ETH-USD-SWAP"
@@ -79,8 +88,27 @@ const (
 	GET_DEPTH             = "/api/swap/v3/instruments/%s/depth?size=%d"
 	GET_TICKER            = "/api/swap/v3/instruments/%s/ticker"
 	GET_UNFINISHED_ORDERS = "/api/swap/v3/orders/%s?status=%d&from=%d&limit=%d"
+
+	SPOT_CANCEL_ORDER = "/api/spot/v3/cancel_orders/%s"
+	SPOT_GET_ORDER    = "/api/spot/v3/orders/%s"
+	SPOT_GET_DEPTH    = "/api/spot/v3/instruments/%s/book"
+	SPOT_GET_KLINE    = "/api/spot/v3/instruments/%s/candles"
 )
 
+// the route label of the rest metrics, the v5 api has the instrument in the query, so it is not here.
+var _INERNAL_ROUTES = []string{
+	CANCEL_ORDER,
+	GET_ORDER,
+	GET_POSITION,
+	GET_DEPTH,
+	GET_TICKER,
+	GET_UNFINISHED_ORDERS,
+	SPOT_CANCEL_ORDER,
+	SPOT_GET_ORDER,
+	SPOT_GET_DEPTH,
+	SPOT_GET_KLINE,
+}
+
 var _INERNAL_KLINE_PERIOD_CONVERTER = map[int]int{
 	KLINE_PERIOD_1MIN:  60,
 	KLINE_PERIOD_3MIN:  180,
@@ -125,7 +153,10 @@ type OKEx struct {
 	Spot   *Spot
 	Swap   *Swap
 	Future *Future
+	Option *Option
 	Wallet *Wallet
+
+	marginModes sync.Map // the margin mode of the instId, it is the td mode of the orders
 }
 
 func New(config *APIConfig) *OKEx {
@@ -144,6 +175,11 @@ func New(config *APIConfig) *OKEx {
 		OKEx:   okex,
 		Locker: new(sync.Mutex),
 	}
+	okex.Option = &Option{
+		OKEx:      okex,
+		Locker:    new(sync.Mutex),
+		contracts: make(map[string]*OptionContract),
+	}
 	okex.Wallet = &Wallet{okex}
 	return okex
 }
@@ -160,7 +196,7 @@ func (ok *OKEx) DoRequest(
 ) ([]byte, error) {
 	url := ok.config.Endpoint + uri
 	sign, timestamp := ok.doParamSign(httpMethod, uri, reqBody)
-	resp, err := NewHttpRequest(ok.config.HttpClient, httpMethod, url, reqBody, map[string]string{
+	resp, err := NewHttpRequestWithRoute(ok.config.GetMetrics(), OKEX, RouteOf(uri, _INERNAL_ROUTES), ok.config.HttpClient, httpMethod, url, reqBody, map[string]string{
 		CONTENT_TYPE:         APPLICATION_JSON_UTF8,
 		ACCEPT:               APPLICATION_JSON,
 		OK_ACCESS_KEY:        ok.config.ApiKey,
@@ -186,7 +222,7 @@ func (ok *OKEx) DoRequestMarket(
 ) ([]byte, error) {
 	url := ok.config.Endpoint + uri
 	//sign, timestamp := ok.doParamSign(httpMethod, uri, reqBody)
-	resp, err := NewHttpRequest(ok.config.HttpClient, httpMethod, url, reqBody, map[string]string{
+	resp, err := NewHttpRequestWithRoute(ok.config.GetMetrics(), OKEX, RouteOf(uri, _INERNAL_ROUTES), ok.config.HttpClient, httpMethod, url, reqBody, map[string]string{
 		CONTENT_TYPE: APPLICATION_JSON_UTF8,
 		ACCEPT:       APPLICATION_JSON,
 	})
@@ -240,6 +276,406 @@ func (ok *OKEx) BuildRequestBody(params interface{}) (string, *bytes.Reader, err
 	return jsonBody, binBody, nil
 }
 
+// v5BatchResult is the result of each order in the v5 batch api.
+type v5BatchResult struct {
+	ClOrdId string `json:"clOrdId"`
+	OrdId   string `json:"ordId"`
+	SCode   string `json:"sCode"`
+	SMsg    string `json:"sMsg"`
+}
+
+// doBatchRequest post the requests to the v5 batch api, 20 orders in one request at most.
+// The results are in the same order of the requests, the error of the order is not nil when it fails.
+func (ok *OKEx) doBatchRequest(uri string, requests []interface{}) ([]v5BatchResult, []error, []byte) {
+	var results = make([]v5BatchResult, len(requests))
+	var errs = make([]error, len(requests))
+	var responses = make([][]byte, 0)
+	for _, r := range BatchSplit(len(requests), V5_BATCH_LIMIT) {
+		var response = struct {
+			Code string          `json:"code"`
+			Msg  string          `json:"msg"`
+			Data []v5BatchResult `json:"data"`
+		}{}
+		reqBody, _, _ := ok.BuildRequestBody(requests[r[0]:r[1]])
+		resp, err := ok.DoRequest(
+			http.MethodPost,
+			uri,
+			reqBody,
+			&response,
+		)
+		if len(resp) > 0 {
+			responses = append(responses, resp)
+		}
+		// the code is 1 when all fail and 2 when some fail, the sCode tell which.
+		for i := r[0]; i < r[1]; i++ {
+			if err != nil {
+				errs[i] = err
+				continue
+			}
+			if i-r[0] >= len(response.Data) {
+				errs[i] = errors.New(string(resp))
+				continue
+			}
+			results[i] = response.Data[i-r[0]]
+			if results[i].SCode != "0" {
+				errs[i] = fmt.Errorf("%s: %s", results[i].SCode, results[i].SMsg)
+			}
+		}
+	}
+	return results, errs, BatchResponse(responses)
+}
+
+// cancelAll cancel all the pending orders of the instrument by the cancel-batch-orders api,
+// okex has no cancel all api of the normal orders.
+func (ok *OKEx) cancelAll(instType, instId string) ([]byte, error) {
+	var requests = make([]interface{}, 0)
+	var responses = make([][]byte, 0)
+	var params = url.Values{}
+	params.Set("instType", instType)
+	params.Set("instId", instId)
+	for {
+		var response = struct {
+			Code string `json:"code"`
+			Msg  string `json:"msg"`
+			Data []struct {
+				OrdId string `json:"ordId"`
+			} `json:"data"`
+		}{}
+		resp, err := ok.DoRequest(
+			http.MethodGet,
+			"/api/v5/trade/orders-pending?"+params.Encode(),
+			"",
+			&response,
+		)
+		if err != nil {
+			return resp, err
+		}
+		if response.Code != "0" {
+			return resp, errors.New(response.Msg)
+		}
+		for _, data := range response.Data {
+			requests = append(requests, map[string]string{"instId": instId, "ordId": data.OrdId})
+		}
+		// 100 orders in one page at most, the next page is after the last order.
+		if len(response.Data) < 100 {
+			break
+		}
+		params.Set("after", response.Data[len(response.Data)-1].OrdId)
+	}
+
+	_, errs, resp := ok.doBatchRequest("/api/v5/trade/cancel-batch-orders", requests)
+	if len(resp) > 0 {
+		responses = append(responses, resp)
+	}
+	return BatchResponse(responses), BatchResult(errs)
+}
+
+// amendOrder modify the open order by the amend-order api, the order keep the OrderId.
+func (ok *OKEx) amendOrder(instId, ordId, clOrdId, newSz, newPx string) ([]byte, error) {
+	var request = struct {
+		InstId  string `json:"instId"`
+		OrdId   string `json:"ordId,omitempty"`
+		ClOrdId string `json:"clOrdId,omitempty"`
+		NewSz   string `json:"newSz,omitempty"`
+		NewPx   string `json:"newPx,omitempty"`
+	}{instId, ordId, clOrdId, newSz, newPx}
+	if ordId != "" {
+		request.ClOrdId = ""
+	}
+
+	var response = struct {
+		Code string          `json:"code"`
+		Msg  string          `json:"msg"`
+		Data []v5BatchResult `json:"data"`
+	}{}
+	reqBody, _, _ := ok.BuildRequestBody(request)
+	resp, err := ok.DoRequest(
+		http.MethodPost,
+		"/api/v5/trade/amend-order",
+		reqBody,
+		&response,
+	)
+	if err != nil {
+		return resp, err
+	}
+	if len(response.Data) > 0 && response.Data[0].SCode != "0" {
+		return resp, errors.New(string(resp))
+	}
+	if response.Code != "0" {
+		return resp, errors.New(string(resp))
+	}
+	return resp, nil
+}
+
+// CancelAllAfter set the cancel-all-after of the whole account, the timeout is 0 or in [10, 120] seconds.
+func (ok *OKEx) CancelAllAfter(pair Pair, timeout time.Duration) ([]byte, error) {
+	var seconds = int64(timeout.Seconds())
+	if seconds != 0 && (seconds < 10 || seconds > 120) {
+		return nil, errors.New("the timeout must be 0 or in [10, 120] seconds. ")
+	}
+	var request = struct {
+		TimeOut string `json:"timeOut"`
+	}{fmt.Sprintf("%d", seconds)}
+
+	var response = struct {
+		Code string `json:"code"`
+		Msg  string `json:"msg"`
+		Data []struct {
+			TriggerTime string `json:"triggerTime"`
+			Ts          string `json:"ts"`
+		} `json:"data"`
+	}{}
+	reqBody, _, _ := ok.BuildRequestBody(request)
+	resp, err := ok.DoRequest(
+		http.MethodPost,
+		"/api/v5/trade/cancel-all-after",
+		reqBody,
+		&response,
+	)
+	if err != nil {
+		return resp, err
+	}
+	if response.Code != "0" {
+		return resp, errors.New(string(resp))
+	}
+	return resp, nil
+}
+
+var _INERNAL_V5_POSITION_MODE_CONVERTER = map[PositionMode]string{
+	POSITION_MODE_ONE_WAY: "net_mode",
+	POSITION_MODE_HEDGE:   "long_short_mode",
+}
+
+// GetPositionMode return the position mode of the whole account, the pair is ignored.
+func (ok *OKEx) GetPositionMode(pair Pair) (PositionMode, []byte, error) {
+	var response = struct {
+		Code string `json:"code"`
+		Msg  string `json:"msg"`
+		Data []struct {
+			PosMode string `json:"posMode"`
+		} `json:"data"`
+	}{}
+	resp, err := ok.DoRequest(
+		http.MethodGet,
+		"/api/v5/account/config",
+		"",
+		&response,
+	)
+	if err != nil {
+		return POSITION_MODE_ONE_WAY, resp, err
+	}
+	if response.Code != "0" || len(response.Data) == 0 {
+		return POSITION_MODE_ONE_WAY, resp, errors.New(string(resp))
+	}
+	if response.Data[0].PosMode == _INERNAL_V5_POSITION_MODE_CONVERTER[POSITION_MODE_HEDGE] {
+		return POSITION_MODE_HEDGE, resp, nil
+	}
+	return POSITION_MODE_ONE_WAY, resp, nil
+}
+
+// SetPositionMode change the position mode of the whole account, it fails when there are open orders or positions.
+func (ok *OKEx) SetPositionMode(pair Pair, mode PositionMode) ([]byte, error) {
+	var posMode, exist = _INERNAL_V5_POSITION_MODE_CONVERTER[mode]
+	if !exist {
+		return nil, errors.New("position mode not found. ")
+	}
+	var request = struct {
+		PosMode string `json:"posMode"`
+	}{posMode}
+
+	var response = struct {
+		Code string `json:"code"`
+		Msg  string `json:"msg"`
+	}{}
+	reqBody, _, _ := ok.BuildRequestBody(request)
+	resp, err := ok.DoRequest(
+		http.MethodPost,
+		"/api/v5/account/set-position-mode",
+		reqBody,
+		&response,
+	)
+	if err != nil {
+		return resp, err
+	}
+	if response.Code != "0" {
+		return resp, errors.New(string(resp))
+	}
+	return resp, nil
+}
+
+// tdMode return the margin mode of the order, the order MarginType first, then the mode set by SetMarginMode.
+func (ok *OKEx) tdMode(instId, marginType string) string {
+	if marginType == CROSS || marginType == ISOLATED {
+		return marginType
+	}
+	if mode, exist := ok.marginModes.Load(instId); exist {
+		return mode.(string)
+	}
+	return CROSS
+}
+
+// setMarginMode keep the margin mode of the instId, okex has no margin mode setting, it is in every order.
+func (ok *OKEx) setMarginMode(instId, marginType string) error {
+	if marginType != CROSS && marginType != ISOLATED {
+		return fmt.Errorf("the margin type %s is not supported. ", marginType)
+	}
+	ok.marginModes.Store(instId, marginType)
+	return nil
+}
+
+// setLeverage set the leverage in the margin mode of the instId, the side works in the isolated hedge mode only.
+func (ok *OKEx) setLeverage(instId string, lever int64, side PositionSide) ([]byte, error) {
+	if lever <= 0 {
+		return nil, errors.New("the leverage must be positive. ")
+	}
+	var request = struct {
+		InstId  string `json:"instId"`
+		Lever   string `json:"lever"`
+		MgnMode string `json:"mgnMode"`
+		PosSide string `json:"posSide,omitempty"`
+	}{
+		InstId:  instId,
+		Lever:   fmt.Sprintf("%d", lever),
+		MgnMode: ok.tdMode(instId, ""),
+	}
+	if request.MgnMode == ISOLATED && (side == POSITION_SIDE_LONG || side == POSITION_SIDE_SHORT) {
+		request.PosSide = _INERNAL_V5_POSITION_SIDE_CONVERTER[side]
+	}
+
+	var response = struct {
+		Code string `json:"code"`
+		Msg  string `json:"msg"`
+	}{}
+	reqBody, _, _ := ok.BuildRequestBody(request)
+	resp, err := ok.DoRequest(
+		http.MethodPost,
+		"/api/v5/account/set-leverage",
+		reqBody,
+		&response,
+	)
+	if err != nil {
+		return resp, err
+	}
+	if response.Code != "0" {
+		return resp, errors.New(string(resp))
+	}
+	return resp, nil
+}
+
+func (ok *OKEx) getLeverage(instId string, side PositionSide) (int64, []byte, error) {
+	var params = url.Values{}
+	params.Set("instId", instId)
+	params.Set("mgnMode", ok.tdMode(instId, ""))
+
+	var response = struct {
+		Code string `json:"code"`
+		Msg  string `json:"msg"`
+		Data []struct {
+			InstId  string  `json:"instId"`
+			Lever   float64 `json:"lever,string"`
+			PosSide string  `json:"posSide"`
+		} `json:"data"`
+	}{}
+	resp, err := ok.DoRequest(
+		http.MethodGet,
+		"/api/v5/account/leverage-info?"+params.Encode(),
+		"",
+		&response,
+	)
+	if err != nil {
+		return 0, resp, err
+	}
+	if response.Code != "0" || len(response.Data) == 0 {
+		return 0, resp, errors.New(string(resp))
+	}
+	for _, item := range response.Data {
+		if item.PosSide == _INERNAL_V5_POSITION_SIDE_CONVERTER[side] {
+			return int64(item.Lever), resp, nil
+		}
+	}
+	return int64(response.Data[0].Lever), resp, nil
+}
+
+// getLeverageBrackets return the position tiers of the inst family in the margin mode of the instId.
+func (ok *OKEx) getLeverageBrackets(pair Pair, instType, instId string) ([]*LeverageBracket, []byte, error) {
+	var params = url.Values{}
+	params.Set("instType", instType)
+	params.Set("tdMode", ok.tdMode(instId, ""))
+	params.Set("instFamily", pair.ToSymbol("-", true))
+
+	var response = struct {
+		Code string `json:"code"`
+		Msg  string `json:"msg"`
+		Data []struct {
+			Tier     int64   `json:"tier,string"`
+			MinSz    float64 `json:"minSz,string"`
+			MaxSz    float64 `json:"maxSz,string"`
+			Mmr      float64 `json:"mmr,string"`
+			MaxLever float64 `json:"maxLever,string"`
+		} `json:"data"`
+	}{}
+	resp, err := ok.DoRequest(
+		http.MethodGet,
+		"/api/v5/public/position-tiers?"+params.Encode(),
+		"",
+		&response,
+	)
+	if err != nil {
+		return nil, resp, err
+	}
+	if response.Code != "0" {
+		return nil, resp, errors.New(string(resp))
+	}
+
+	var brackets = make([]*LeverageBracket, 0, len(response.Data))
+	for _, item := range response.Data {
+		brackets = append(brackets, &LeverageBracket{
+			Pair:             pair,
+			Tier:             item.Tier,
+			Floor:            item.MinSz,
+			Cap:              item.MaxSz,
+			MaxLeverage:      item.MaxLever,
+			MaintMarginRatio: item.Mmr,
+		})
+	}
+	return brackets, resp, nil
+}
+
+// getPriceKline request the mark-price-candles or the index-candles, the item is [ts, o, h, l, c, confirm].
+// The since works as the candles api, the result is in the desc sequence.
+func (ok *OKEx) getPriceKline(uri, instId string, period, size, since int) ([][]string, []byte, error) {
+	var bar, exist = _INERNAL_V5_CANDLE_PERIOD_CONVERTER[period]
+	if !exist {
+		return nil, nil, fmt.Errorf("the period %d is not supported. ", period)
+	}
+	if size <= 0 || size > 100 {
+		size = 100
+	}
+
+	var params = url.Values{}
+	params.Set("instId", instId)
+	params.Set("bar", bar)
+	params.Set("limit", strconv.Itoa(size))
+	if since > 0 {
+		params.Set("before", strconv.Itoa(since))
+		params.Set("after", strconv.Itoa(int(time.Now().UnixNano()/1000000)))
+	}
+
+	var response struct {
+		Code string     `json:"code"`
+		Msg  string     `json:"msg"`
+		Data [][]string `json:"data"`
+	}
+	resp, err := ok.DoRequestMarket(http.MethodGet, uri+params.Encode(), "", &response)
+	if err != nil {
+		return nil, resp, err
+	}
+	if response.Code != "0" {
+		return nil, resp, errors.New(response.Msg)
+	}
+	return response.Data, resp, nil
+}
+
 func (ok *OKEx) doParamSign(httpMethod, uri, requestBody string) (string, string) {
 	timestamp := ok.IsoTime()
 	preText := fmt.Sprintf("%s%s%s%s", timestamp, strings.ToUpper(httpMethod), uri, requestBody)
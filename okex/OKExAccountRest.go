@@ -150,8 +150,6 @@ func (ok *Wallet) GetFundingAccount() (map[string]*FundingAccountDetail, []byte,
 		return nil, resp, err
 	}
 
-	fmt.Println(string(resp))
-
 	var details = make(map[string]*FundingAccountDetail, 0)
 	for _, detail := range response.Data {
 		details[detail.Ccy] = detail
@@ -158,3 +158,20 @@ func (future *Future) GetPairFlow(pair Pair) ([]*FutureAccountItem, []byte, erro
 	}
 	return items, resp, nil
 }
+
+func (future *Future) SetLeverage(pair Pair, contractType string, lever int64, side PositionSide) ([]byte, error) {
+	return future.setLeverage(future.GetInstrumentId(pair, contractType), lever, side)
+}
+
+func (future *Future) GetLeverage(pair Pair, contractType string, side PositionSide) (int64, []byte, error) {
+	return future.getLeverage(future.GetInstrumentId(pair, contractType), side)
+}
+
+// SetMarginMode change the td mode of the later orders and the leverage api, there is no remote call.
+func (future *Future) SetMarginMode(pair Pair, contractType string, marginType string) ([]byte, error) {
+	return nil, future.setMarginMode(future.GetInstrumentId(pair, contractType), marginType)
+}
+
+func (future *Future) GetLeverageBrackets(pair Pair, contractType string) ([]*LeverageBracket, []byte, error) {
+	return future.getLeverageBrackets(pair, "FUTURES", future.GetInstrumentId(pair, contractType))
+}
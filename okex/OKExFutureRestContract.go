@@ -26,6 +26,8 @@ func (future *Future) GetContracts() ([]*FutureContract, []byte, error) {
 			SettleCcy string  `json:"settleCcy"`
 			TickSz    float64 `json:"tickSz,string"`
 			LotSz     float64 `json:"lotSz,string"`
+			MinSz     float64 `json:"minSz,string"`
+			MaxMktSz  float64 `json:"maxMktSz,string"`
 			Uly       string  `json:"uly"`
 			State     string  `json:"state"`
 			CtType    string  `json:"ctType"`
@@ -87,6 +89,9 @@ func (future *Future) GetContracts() ([]*FutureContract, []byte, error) {
 			TickSize:        ToFloat64(item.TickSz),
 			PricePrecision:  GetPrecisionInt64(item.TickSz),
 			AmountPrecision: GetPrecisionInt64(item.LotSz),
+			LotSize:         item.LotSz,
+			MinAmount:       item.MinSz,
+			MaxMarketAmount: item.MaxMktSz,
 			RawData:         string(rawData),
 		}
 
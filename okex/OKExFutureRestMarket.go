@@ -210,6 +210,72 @@ var nonListKlineKV = map[string]string{
 	NEXT_QUARTER_CONTRACT: NEXT_QUARTER_CONTRACT,
 }
 
+func (future *Future) GetMarkKline(
+	contractType string,
+	pair Pair,
+	period, size, since int,
+) ([]*FutureKline, []byte, error) {
+	return future.getFuturePriceKline(V5_MARK_KLINE_URI, contractType, pair, period, size, since)
+}
+
+func (future *Future) GetIndexKline(
+	contractType string,
+	pair Pair,
+	period, size, since int,
+) ([]*FutureKline, []byte, error) {
+	return future.getFuturePriceKline(V5_INDEX_KLINE_URI, contractType, pair, period, size, since)
+}
+
+// GetPremiumKline okex has no premium index kline, the basis is the mark kline minus the index kline.
+func (future *Future) GetPremiumKline(
+	contractType string,
+	pair Pair,
+	period, size, since int,
+) ([]*FutureKline, []byte, error) {
+	return nil, nil, errors.New("okex has no premium index kline. ")
+}
+
+// getFuturePriceKline use the due time of the current contract, the klines before the roll are in the previous one.
+func (future *Future) getFuturePriceKline(
+	uri, contractType string,
+	pair Pair,
+	period, size, since int,
+) ([]*FutureKline, []byte, error) {
+	contract, err := future.GetContract(pair, contractType)
+	if err != nil {
+		return nil, nil, err
+	}
+	var instId = contract.ContractName
+	if uri == V5_INDEX_KLINE_URI {
+		instId = pair.ToSymbol("-", true)
+	}
+	data, resp, err := future.getPriceKline(uri, instId, period, size, since)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	var klines = make([]*FutureKline, 0, len(data))
+	for _, itm := range data {
+		timestamp := ToInt64(itm[0])
+		klines = append(klines, &FutureKline{
+			Kline: Kline{
+				Timestamp: timestamp,
+				Date:      time.Unix(timestamp/1000, 0).In(future.config.Location).Format(GO_BIRTHDAY),
+				Pair:      pair,
+				Exchange:  OKEX,
+				Open:      ToFloat64(itm[1]),
+				High:      ToFloat64(itm[2]),
+				Low:       ToFloat64(itm[3]),
+				Close:     ToFloat64(itm[4]),
+			},
+			ContractType: contractType,
+			DueTimestamp: contract.DueTimestamp,
+			DueDate:      contract.DueDate,
+		})
+	}
+	return GetAscFutureKline(klines), resp, nil
+}
+
 /**
  * since : 单位毫秒,开始时间
 **/
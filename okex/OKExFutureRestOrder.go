@@ -23,6 +23,13 @@ func (future *Future) PlaceOrder(order *FutureOrder) ([]byte, error) {
 		order.ContractName = future.GetInstrumentId(order.Pair, order.ContractType)
 	}
 
+	var normalizer = contract.GetNormalizer()
+	normalizer.ReduceOnly = IsReduce(order.Type, false)
+	price, _, err := normalizer.Normalize(order.Price, float64(order.Amount), order.PlaceType == MARKET)
+	if err != nil {
+		return nil, err
+	}
+
 	var sideInfo, _ = _INERNAL_V5_FUTURE_TYPE_CONVERTER[order.Type]
 	var placeInfo, _ = _INERNAL_V5_FUTURE_PLACE_TYPE_CONVERTER[order.PlaceType]
 	var request = struct {
@@ -36,12 +43,12 @@ func (future *Future) PlaceOrder(order *FutureOrder) ([]byte, error) {
 		ClOrdId string `json:"clOrdId,omitempty"`
 	}{
 		order.ContractName,
-		"cross",
+		future.tdMode(order.ContractName, ""),
 		sideInfo[0],
 		sideInfo[1],
 		placeInfo,
 		strconv.FormatInt(order.Amount, 10),
-		FloatToPrice(order.Price, contract.PricePrecision, contract.TickSize),
+		normalizer.FormatPrice(price),
 		order.Cid,
 	}
 
@@ -3,7 +3,6 @@ package okex
 import (
 	"encoding/json"
 	"fmt"
-	"log"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -27,7 +26,7 @@ func (this *OKexFutureWebsocket) Init() {
 
 	if this.receive == nil {
 		this.receive = func(data []byte) error {
-			log.Println(string(data))
+			GetLogger().Debug("receive message", LogF(LOG_FIELD_EXCHANGE, OKEX), LogF("msg", string(data)))
 			return nil
 		}
 	}
@@ -88,7 +87,7 @@ func (this *OKexFutureWebsocket) Start() {
 	for {
 		data := <-this.msg
 		if err := this.receive(data); err != nil {
-			log.Fatal(err)
+			GetLogger().Error("receive message error", LogF(LOG_FIELD_EXCHANGE, OKEX), LogF(LOG_FIELD_ERROR, err))
 			this.Close()
 			return
 		}
@@ -23,6 +23,7 @@ type OKExOne struct {
 
 	LastTimestamp int64
 	Location      *time.Location
+	Metrics       Metrics // the metrics of the client, the global metrics is used if nil
 }
 
 type Instrument struct {
@@ -72,6 +73,13 @@ func (ok *OKExOne) Init() error {
 	return nil
 }
 
+func (ok *OKExOne) getMetrics() Metrics {
+	if ok.Metrics == nil {
+		return GetMetrics()
+	}
+	return ok.Metrics
+}
+
 func (ok *OKExOne) RequestDirect(
 	httpMethod,
 	uri,
@@ -80,7 +88,9 @@ func (ok *OKExOne) RequestDirect(
 ) ([]byte, error) {
 
 	var reqUrl = ok.Endpoint + uri
-	var resp, err = NewHttpRequest(
+	var resp, err = NewHttpRequestWithMetrics(
+		ok.getMetrics(),
+		OKEX,
 		ok.HttpClient,
 		httpMethod, reqUrl,
 		reqBody,
@@ -118,7 +128,9 @@ func (ok *OKExOne) RequestAuth(
 
 	var url = ok.Endpoint + uri
 	sign, timestamp := ok.doParamSign(httpMethod, uri, requestBody)
-	var resp, respErr = NewHttpRequest(
+	var resp, respErr = NewHttpRequestWithMetrics(
+		ok.getMetrics(),
+		OKEX,
 		ok.HttpClient,
 		httpMethod,
 		url, requestBody,
@@ -0,0 +1,330 @@
+package okex
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	. "github.com/deforceHK/goghostex"
+)
+
+// Option is the european option of okex, the instId is like BTC-USD-241227-60000-C, the price is in the settle currency.
+type Option struct {
+	*OKEx
+	sync.Locker
+	contracts map[string]*OptionContract // the instId to the contract
+}
+
+// okOptionSummary is the opt-summary item in the rest and the websocket api, the BS greeks are in usd.
+type okOptionSummary struct {
+	InstId  string  `json:"instId"`
+	Uly     string  `json:"uly"`
+	DeltaBS float64 `json:"deltaBS,string"`
+	GammaBS float64 `json:"gammaBS,string"`
+	VegaBS  float64 `json:"vegaBS,string"`
+	ThetaBS float64 `json:"thetaBS,string"`
+	MarkVol float64 `json:"markVol,string"`
+	BidVol  string  `json:"bidVol"` // empty when there is no bid
+	AskVol  string  `json:"askVol"`
+	FwdPx   float64 `json:"fwdPx,string"`
+	Ts      int64   `json:"ts,string"`
+}
+
+func (option *Option) GetExchangeName() string {
+	return OKEX
+}
+
+// instFamily return the instrument family of the pair, eg: BTC-USD.
+func (option *Option) instFamily(pair Pair) string {
+	return pair.ToSymbol("-", true)
+}
+
+// symbolPair return the underlying pair of the instId.
+func (option *Option) symbolPair(symbol string) Pair {
+	var parts = strings.Split(symbol, "-")
+	if len(parts) < 2 {
+		return NewPair(symbol, "-")
+	}
+	return NewPair(parts[0]+"-"+parts[1], "-")
+}
+
+func (option *Option) GetContracts(pair Pair, expiry time.Time) ([]*OptionContract, []byte, error) {
+	var params = url.Values{}
+	params.Set("instType", "OPTION")
+	params.Set("instFamily", option.instFamily(pair))
+
+	var response struct {
+		Code string        `json:"code"`
+		Msg  string        `json:"msg"`
+		Data []*Instrument `json:"data"`
+	}
+	resp, err := option.DoRequestMarket(
+		http.MethodGet,
+		"/api/v5/public/instruments?"+params.Encode(),
+		"",
+		&response,
+	)
+	if err != nil {
+		return nil, resp, err
+	}
+	if response.Code != "0" {
+		return nil, resp, errors.New(response.Msg)
+	}
+
+	var contracts = make([]*OptionContract, 0, len(response.Data))
+	for _, item := range response.Data {
+		var expiryTimestamp = ToInt64(item.ExpTime)
+		var contract = &OptionContract{
+			Pair:       pair,
+			Symbol:     item.InstId,
+			Exchange:   OKEX,
+			OptionType: OPTION_CALL,
+			Strike:     ToFloat64(item.Stk),
+
+			ExpiryTimestamp: expiryTimestamp,
+			ExpiryDate:      time.Unix(expiryTimestamp/1000, 0).In(option.config.Location).Format("2006-01-02"),
+
+			SettleCurrency: item.SettleCcy,
+			UnitAmount:     ToFloat64(item.CtVal) * ToFloat64(item.CtMult),
+			TickSize:       ToFloat64(item.TickSz),
+			LotSize:        ToFloat64(item.LotSz),
+			MinAmount:      ToFloat64(item.MinSz),
+		}
+		if item.OptType == "P" {
+			contract.OptionType = OPTION_PUT
+		}
+		contracts = append(contracts, contract)
+	}
+
+	option.Lock()
+	if option.contracts == nil {
+		option.contracts = make(map[string]*OptionContract)
+	}
+	for _, contract := range contracts {
+		option.contracts[contract.Symbol] = contract
+	}
+	option.Unlock()
+
+	return FilterOptionContracts(contracts, expiry), resp, nil
+}
+
+// GetContract return the contract of the instId, the contracts of the family are requested when it is not found.
+func (option *Option) GetContract(symbol string) *OptionContract {
+	option.Lock()
+	var contract, exist = option.contracts[symbol]
+	option.Unlock()
+	if exist {
+		return contract
+	}
+
+	if _, _, err := option.GetContracts(option.symbolPair(symbol), time.Time{}); err != nil {
+		return nil
+	}
+	option.Lock()
+	defer option.Unlock()
+	return option.contracts[symbol]
+}
+
+// getSummaries request the opt-summary of the family, the zero expiry means all the expiries.
+func (option *Option) getSummaries(pair Pair, expiry time.Time) ([]*okOptionSummary, []byte, error) {
+	var params = url.Values{}
+	params.Set("instFamily", option.instFamily(pair))
+	if !expiry.IsZero() {
+		params.Set("expTime", expiry.Format("060102"))
+	}
+
+	var response struct {
+		Code string             `json:"code"`
+		Msg  string             `json:"msg"`
+		Data []*okOptionSummary `json:"data"`
+	}
+	resp, err := option.DoRequestMarket(
+		http.MethodGet,
+		"/api/v5/public/opt-summary?"+params.Encode(),
+		"",
+		&response,
+	)
+	if err != nil {
+		return nil, resp, err
+	}
+	if response.Code != "0" {
+		return nil, resp, errors.New(response.Msg)
+	}
+	return response.Data, resp, nil
+}
+
+// GetTickers merge the opt-summary, the tickers and the mark price of the family.
+func (option *Option) GetTickers(pair Pair, expiry time.Time) ([]*OptionTicker, []byte, error) {
+	var summaries, resp, err = option.getSummaries(pair, expiry)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	var params = url.Values{}
+	params.Set("instType", "OPTION")
+	params.Set("instFamily", option.instFamily(pair))
+
+	var tickerResponse struct {
+		Code string `json:"code"`
+		Msg  string `json:"msg"`
+		Data []struct {
+			InstId string `json:"instId"`
+			Last   string `json:"last"`
+			AskPx  string `json:"askPx"`
+			BidPx  string `json:"bidPx"`
+		} `json:"data"`
+	}
+	resp, err = option.DoRequestMarket(
+		http.MethodGet,
+		"/api/v5/market/tickers?"+params.Encode(),
+		"",
+		&tickerResponse,
+	)
+	if err != nil {
+		return nil, resp, err
+	}
+	if tickerResponse.Code != "0" {
+		return nil, resp, errors.New(tickerResponse.Msg)
+	}
+
+	var markResponse struct {
+		Code string `json:"code"`
+		Msg  string `json:"msg"`
+		Data []struct {
+			InstId string  `json:"instId"`
+			MarkPx float64 `json:"markPx,string"`
+		} `json:"data"`
+	}
+	resp, err = option.DoRequestMarket(
+		http.MethodGet,
+		"/api/v5/public/mark-price?"+params.Encode(),
+		"",
+		&markResponse,
+	)
+	if err != nil {
+		return nil, resp, err
+	}
+	if markResponse.Code != "0" {
+		return nil, resp, errors.New(markResponse.Msg)
+	}
+
+	var tickerKV = make(map[string]*OptionTicker, len(summaries))
+	var tickers = make([]*OptionTicker, 0, len(summaries))
+	for _, summary := range summaries {
+		var ticker = option.summaryTicker(pair, summary)
+		tickerKV[summary.InstId] = ticker
+		tickers = append(tickers, ticker)
+	}
+	for _, item := range tickerResponse.Data {
+		if ticker, exist := tickerKV[item.InstId]; exist {
+			ticker.Last, ticker.Buy, ticker.Sell = ToFloat64(item.Last), ToFloat64(item.BidPx), ToFloat64(item.AskPx)
+		}
+	}
+	for _, item := range markResponse.Data {
+		if ticker, exist := tickerKV[item.InstId]; exist {
+			ticker.MarkPrice = item.MarkPx
+		}
+	}
+	return tickers, resp, nil
+}
+
+func (option *Option) GetTicker(symbol string) (*OptionTicker, []byte, error) {
+	var contract = option.GetContract(symbol)
+	if contract == nil {
+		return nil, nil, fmt.Errorf("the option %s is not found. ", symbol)
+	}
+	var expiry = time.Unix(contract.ExpiryTimestamp/1000, 0).In(time.UTC)
+	var tickers, resp, err = option.GetTickers(contract.Pair, expiry)
+	if err != nil {
+		return nil, resp, err
+	}
+	for _, ticker := range tickers {
+		if ticker.Symbol == symbol {
+			return ticker, resp, nil
+		}
+	}
+	return nil, resp, errors.New("lack response data. ")
+}
+
+func (option *Option) summaryTicker(pair Pair, summary *okOptionSummary) *OptionTicker {
+	return &OptionTicker{
+		Pair:            pair,
+		Symbol:          summary.InstId,
+		Exchange:        OKEX,
+		BuyIV:           ToFloat64(summary.BidVol),
+		SellIV:          ToFloat64(summary.AskVol),
+		MarkIV:          summary.MarkVol,
+		UnderlyingPrice: summary.FwdPx,
+		Greeks: OptionGreeks{
+			Delta: summary.DeltaBS,
+			Gamma: summary.GammaBS,
+			Vega:  summary.VegaBS,
+			Theta: summary.ThetaBS,
+		},
+		Timestamp: summary.Ts,
+		Date:      time.Unix(summary.Ts/1000, 0).In(option.config.Location).Format(GO_BIRTHDAY),
+	}
+}
+
+// GetDepth return the depth of the instId, the amount is in contracts.
+func (option *Option) GetDepth(symbol string, size int) (*OptionDepth, []byte, error) {
+	var params = url.Values{}
+	params.Set("instId", symbol)
+	params.Set("sz", fmt.Sprintf("%d", size))
+
+	var response struct {
+		Code string `json:"code"`
+		Msg  string `json:"msg"`
+		Data []*struct {
+			Asks      [][]string `json:"asks"`
+			Bids      [][]string `json:"bids"`
+			Timestamp int64      `json:"ts,string"`
+		} `json:"data"`
+	}
+	resp, err := option.DoRequestMarket(
+		http.MethodGet,
+		"/api/v5/market/books?"+params.Encode(),
+		"",
+		&response,
+	)
+	if err != nil {
+		return nil, resp, err
+	}
+	if response.Code != "0" {
+		return nil, resp, errors.New(response.Msg)
+	}
+	if len(response.Data) == 0 {
+		return nil, resp, errors.New("lack response data. ")
+	}
+
+	var depth = &OptionDepth{
+		Pair:      option.symbolPair(symbol),
+		Symbol:    symbol,
+		Exchange:  OKEX,
+		Timestamp: response.Data[0].Timestamp,
+		Date:      time.Unix(response.Data[0].Timestamp/1000, 0).In(option.config.Location).Format(GO_BIRTHDAY),
+	}
+	for _, bid := range response.Data[0].Bids {
+		depth.BidList = append(depth.BidList, DepthRecord{Price: ToFloat64(bid[0]), Amount: ToFloat64(bid[1])})
+	}
+	for _, ask := range response.Data[0].Asks {
+		depth.AskList = append(depth.AskList, DepthRecord{Price: ToFloat64(ask[0]), Amount: ToFloat64(ask[1])})
+	}
+	return depth, resp, nil
+}
+
+func (option *Option) KeepAlive() {
+	nowTimestamp := time.Now().Unix() * 1000
+	// last in 5s, no need to keep alive.
+	if (nowTimestamp - option.config.LastTimestamp) < 5*1000 {
+		return
+	}
+
+	var response interface{}
+	_, _ = option.DoRequestMarket(http.MethodGet, "/api/v5/public/time", "", &response)
+	option.config.LastTimestamp = nowTimestamp
+}
@@ -0,0 +1,305 @@
+package okex
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	. "github.com/deforceHK/goghostex"
+)
+
+// okOptionOrder is the order item of the option, the sz is in contracts.
+type okOptionOrder struct {
+	InstId    string  `json:"instId"`
+	ClOrdId   string  `json:"clOrdId"`
+	OrdId     string  `json:"ordId"`
+	Side      string  `json:"side"`
+	OrdType   string  `json:"ordType"`
+	Px        float64 `json:"px,string"`
+	Sz        float64 `json:"sz,string"`
+	AvgPx     string  `json:"avgPx"`
+	AccFillSz float64 `json:"accFillSz,string"`
+	State     string  `json:"state"`
+	Fee       float64 `json:"fee,string"`
+	UTime     int64   `json:"uTime,string"`
+	CTime     int64   `json:"cTime,string"`
+}
+
+func (option *Option) mergeOrder(order *OptionOrder, item *okOptionOrder) {
+	if status, exist := _INERNAL_V5_FUTURE_ORDER_STATUE_CONVERTER[item.State]; exist {
+		order.Status = status
+	}
+	order.Symbol = item.InstId
+	order.Pair = option.symbolPair(item.InstId)
+	order.OrderId = item.OrdId
+	order.Cid = item.ClOrdId
+	order.Side = SELL
+	if item.Side == "buy" {
+		order.Side = BUY
+	}
+	for placeType, ordType := range _INERNAL_V5_FUTURE_PLACE_TYPE_CONVERTER {
+		if ordType == item.OrdType {
+			order.PlaceType = placeType
+		}
+	}
+	order.Price = item.Px
+	order.Amount = item.Sz
+	order.AvgPrice = ToFloat64(item.AvgPx)
+	order.DealAmount = item.AccFillSz
+	order.Fee = item.Fee
+	order.Exchange = OKEX
+
+	order.DealTimestamp = item.UTime
+	order.DealDatetime = time.Unix(item.UTime/1000, 0).In(option.config.Location).Format(GO_BIRTHDAY)
+	order.PlaceTimestamp = item.CTime
+	order.PlaceDatetime = time.Unix(item.CTime/1000, 0).In(option.config.Location).Format(GO_BIRTHDAY)
+}
+
+// PlaceOrder place the limit order of the option, okex has no market order in the option.
+func (option *Option) PlaceOrder(order *OptionOrder) ([]byte, error) {
+	var contract = option.GetContract(order.Symbol)
+	if contract == nil {
+		return nil, fmt.Errorf("the option %s is not found. ", order.Symbol)
+	}
+	var ordType, exist = _INERNAL_V5_FUTURE_PLACE_TYPE_CONVERTER[order.PlaceType]
+	if !exist || order.PlaceType == MARKET {
+		return nil, fmt.Errorf("the place type %s is not supported in the option. ", order.PlaceType)
+	}
+	var side, sideExist = _INERNAL_V5_SPOT_TRADE_SIDE_CONVERTER[order.Side]
+	if !sideExist {
+		return nil, fmt.Errorf("the trade side %s is not supported in the option. ", order.Side)
+	}
+
+	var normalizer = contract.GetNormalizer()
+	price, amount, err := normalizer.Normalize(order.Price, order.Amount, false)
+	if err != nil {
+		return nil, err
+	}
+	var request = struct {
+		InstId     string `json:"instId"`
+		TdMode     string `json:"tdMode"`
+		Side       string `json:"side"`
+		OrdType    string `json:"ordType"`
+		Sz         string `json:"sz"`
+		Px         string `json:"px"`
+		ClOrdId    string `json:"clOrdId,omitempty"`
+		ReduceOnly bool   `json:"reduceOnly,omitempty"`
+	}{
+		InstId:     order.Symbol,
+		TdMode:     option.tdMode(order.Symbol, ""),
+		Side:       side,
+		OrdType:    ordType,
+		Sz:         normalizer.FormatAmount(amount),
+		Px:         normalizer.FormatPrice(price),
+		ClOrdId:    order.Cid,
+		ReduceOnly: order.ReduceOnly,
+	}
+
+	var response = struct {
+		Code string `json:"code"`
+		Msg  string `json:"msg"`
+		Data []struct {
+			ClOrdId string `json:"clOrdId"`
+			OrdId   string `json:"ordId"`
+			SCode   string `json:"sCode"`
+			SMsg    string `json:"sMsg"`
+		} `json:"data"`
+	}{}
+
+	now := time.Now()
+	order.PlaceTimestamp = now.UnixNano() / int64(time.Millisecond)
+	order.PlaceDatetime = now.In(option.config.Location).Format(GO_BIRTHDAY)
+	reqBody, _, _ := option.BuildRequestBody(request)
+	resp, err := option.DoRequest(
+		http.MethodPost,
+		"/api/v5/trade/order",
+		reqBody,
+		&response,
+	)
+	if err != nil {
+		return resp, err
+	}
+	if len(response.Data) > 0 && response.Data[0].SCode != "0" {
+		return resp, errors.New(string(resp))
+	}
+	if response.Code != "0" || len(response.Data) == 0 {
+		return resp, errors.New(string(resp))
+	}
+
+	order.Pair = contract.Pair
+	order.Exchange = OKEX
+	order.OrderId = response.Data[0].OrdId
+	order.Status = ORDER_UNFINISH
+	return resp, nil
+}
+
+func (option *Option) CancelOrder(order *OptionOrder) ([]byte, error) {
+	var request = struct {
+		InstId  string `json:"instId"`
+		OrdId   string `json:"ordId,omitempty"`
+		ClOrdId string `json:"clOrdId,omitempty"`
+	}{
+		order.Symbol,
+		order.OrderId,
+		order.Cid,
+	}
+
+	var response = struct {
+		Code string `json:"code"`
+		Msg  string `json:"msg"`
+		Data []struct {
+			OrdId string `json:"ordId"`
+			SCode string `json:"sCode"`
+			SMsg  string `json:"sMsg"`
+		} `json:"data"`
+	}{}
+
+	reqBody, _, _ := option.BuildRequestBody(request)
+	resp, err := option.DoRequest(
+		http.MethodPost,
+		"/api/v5/trade/cancel-order",
+		reqBody,
+		&response,
+	)
+	if err != nil {
+		return resp, err
+	}
+	if len(response.Data) == 0 {
+		return resp, errors.New("request lack the data. ")
+	}
+	if response.Data[0].SCode != "0" {
+		return resp, errors.New(response.Data[0].SMsg)
+	}
+	return resp, nil
+}
+
+func (option *Option) GetOrder(order *OptionOrder) ([]byte, error) {
+	var params = url.Values{}
+	params.Set("instId", order.Symbol)
+	if order.OrderId != "" {
+		params.Set("ordId", order.OrderId)
+	} else {
+		params.Set("clOrdId", order.Cid)
+	}
+
+	var response = struct {
+		Code string           `json:"code"`
+		Msg  string           `json:"msg"`
+		Data []*okOptionOrder `json:"data"`
+	}{}
+	resp, err := option.DoRequest(
+		http.MethodGet,
+		"/api/v5/trade/order?"+params.Encode(),
+		"",
+		&response,
+	)
+	if err != nil {
+		return resp, err
+	}
+	if response.Code != "0" {
+		return resp, errors.New(response.Msg)
+	}
+	if len(response.Data) == 0 {
+		return resp, errors.New("lack response data. ")
+	}
+	option.mergeOrder(order, response.Data[0])
+	return resp, nil
+}
+
+func (option *Option) GetUnFinishOrders(pair Pair) ([]*OptionOrder, []byte, error) {
+	var params = url.Values{}
+	params.Set("instType", "OPTION")
+	params.Set("instFamily", option.instFamily(pair))
+
+	var response = struct {
+		Code string           `json:"code"`
+		Msg  string           `json:"msg"`
+		Data []*okOptionOrder `json:"data"`
+	}{}
+	resp, err := option.DoRequest(
+		http.MethodGet,
+		"/api/v5/trade/orders-pending?"+params.Encode(),
+		"",
+		&response,
+	)
+	if err != nil {
+		return nil, resp, err
+	}
+	if response.Code != "0" {
+		return nil, resp, errors.New(response.Msg)
+	}
+
+	var orders = make([]*OptionOrder, 0, len(response.Data))
+	for _, item := range response.Data {
+		var order = &OptionOrder{}
+		option.mergeOrder(order, item)
+		orders = append(orders, order)
+	}
+	return orders, resp, nil
+}
+
+// GetPositions return the option positions of the family, the greeks are the BS greeks of the whole position.
+func (option *Option) GetPositions(pair Pair) ([]*OptionPosition, []byte, error) {
+	var params = url.Values{}
+	params.Set("instType", "OPTION")
+
+	var response = struct {
+		Code string `json:"code"`
+		Msg  string `json:"msg"`
+		Data []struct {
+			InstId  string  `json:"instId"`
+			PosSide string  `json:"posSide"`
+			Pos     float64 `json:"pos,string"`
+			AvgPx   float64 `json:"avgPx,string"`
+			MarkPx  float64 `json:"markPx,string"`
+			Upl     float64 `json:"upl,string"`
+			DeltaBS float64 `json:"deltaBS,string"`
+			GammaBS float64 `json:"gammaBS,string"`
+			VegaBS  float64 `json:"vegaBS,string"`
+			ThetaBS float64 `json:"thetaBS,string"`
+		} `json:"data"`
+	}{}
+	resp, err := option.DoRequest(
+		http.MethodGet,
+		"/api/v5/account/positions?"+params.Encode(),
+		"",
+		&response,
+	)
+	if err != nil {
+		return nil, resp, err
+	}
+	if response.Code != "0" {
+		return nil, resp, errors.New(response.Msg)
+	}
+
+	var prefix = option.instFamily(pair) + "-"
+	var positions = make([]*OptionPosition, 0, len(response.Data))
+	for _, item := range response.Data {
+		if !strings.HasPrefix(item.InstId, prefix) || item.Pos == 0 {
+			continue
+		}
+		var amount = item.Pos
+		if item.PosSide == "short" && amount > 0 {
+			amount = -amount
+		}
+		positions = append(positions, &OptionPosition{
+			Pair:             pair,
+			Symbol:           item.InstId,
+			Exchange:         OKEX,
+			Amount:           amount,
+			AvgPrice:         item.AvgPx,
+			MarkPrice:        item.MarkPx,
+			UnrealizedProfit: item.Upl,
+			Greeks: OptionGreeks{
+				Delta: item.DeltaBS,
+				Gamma: item.GammaBS,
+				Vega:  item.VegaBS,
+				Theta: item.ThetaBS,
+			},
+		})
+	}
+	return positions, resp, nil
+}
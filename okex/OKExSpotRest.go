@@ -115,7 +115,7 @@ func (spot *Spot) GetTicker(pair Pair) (*Ticker, []byte, error) {
 
 func (spot *Spot) GetDepth(pair Pair, size int) (*Depth, []byte, error) {
 	uri := fmt.Sprintf(
-		"/api/spot/v3/instruments/%s/book?size=%d",
+		SPOT_GET_DEPTH+"?size=%d",
 		pair.ToSymbol("-", true),
 		size,
 	)
@@ -157,7 +157,7 @@ func (spot *Spot) GetDepth(pair Pair, size int) (*Depth, []byte, error) {
 
 func (spot *Spot) GetKlineRecords(pair Pair, period, size, since int) ([]*Kline, []byte, error) {
 	uri := fmt.Sprintf(
-		"/api/spot/v3/instruments/%s/candles?",
+		SPOT_GET_KLINE+"?",
 		pair.ToSymbol("-", true),
 	)
 
@@ -249,6 +249,8 @@ func (spot *Spot) GetExchangeRule(pair Pair) (*Rule, []byte, error) {
 				BaseMinSize:      p.MinSize,
 				BasePrecision:    GetPrecision(p.SizeIncrement),
 				CounterPrecision: GetPrecision(p.TickSize),
+				TickSize:         p.TickSize,
+				LotSize:          p.SizeIncrement,
 			}
 			return &rule, raw, nil
 		}
@@ -332,6 +334,19 @@ func (spot *Spot) getInstruments(pair Pair) *Instrument {
 	return spot.Spot.Instruments[pair.ToSymbol("-", true)]
 }
 
+// getNormalizer return the normalizer of the spot instrument, the maxMktSz of spot is in USDT, so it is not used.
+func (spot *Spot) getNormalizer(instrument *Instrument) *Normalizer {
+	return &Normalizer{
+		TickSize:        ToFloat64(instrument.TickSz),
+		LotSize:         ToFloat64(instrument.LotSz),
+		PricePrecision:  instrument.PricePrecision,
+		AmountPrecision: instrument.AmountPrecision,
+		MinAmount:       ToFloat64(instrument.MinSz),
+		UnitAmount:      1,
+		SettleMode:      SETTLE_MODE_COUNTER,
+	}
+}
+
 func (spot *Spot) GetInstruments(pair Pair) *Instrument {
 	return spot.getInstruments(pair)
 }
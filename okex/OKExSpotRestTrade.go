@@ -25,28 +25,48 @@ var _INERNAL_V5_SPOT_PLACE_TYPE_CONVERTER = map[PlaceType]string{
 	MARKET:     "market",
 }
 
-func (spot *Spot) PlaceOrder(order *Order) ([]byte, error) {
+type spotOrderRequest struct {
+	InstId  string `json:"instId"`
+	TdMode  string `json:"tdMode"`
+	Side    string `json:"side"`
+	PosSide string `json:"posSide,omitempty"`
+	OrdType string `json:"ordType"`
+	Sz      string `json:"sz"`
+	Px      string `json:"px,omitempty"`
+	ClOrdId string `json:"clOrdId,omitempty"`
+	TgtCcy  string `json:"tgtCcy,omitempty"`
+}
+
+// placeRequest build the request of the order, the batch orders use it too.
+func (spot *Spot) placeRequest(order *Order) (*spotOrderRequest, error) {
 	var instrument = spot.getInstruments(order.Pair)
-	var request = struct {
-		InstId  string `json:"instId"`
-		TdMode  string `json:"tdMode"`
-		Side    string `json:"side"`
-		PosSide string `json:"posSide,omitempty"`
-		OrdType string `json:"ordType"`
-		Sz      string `json:"sz"`
-		Px      string `json:"px,omitempty"`
-		ClOrdId string `json:"clOrdId,omitempty"`
-		TgtCcy  string `json:"tgtCcy,omitempty"`
-	}{}
+	var request = &spotOrderRequest{}
 
 	request.InstId = instrument.InstId
 	request.TdMode = "cross"
 	request.Side = _INERNAL_V5_SPOT_TRADE_SIDE_CONVERTER[order.Side]
 	request.OrdType = _INERNAL_V5_SPOT_PLACE_TYPE_CONVERTER[order.OrderType]
-	request.Sz = FloatToString(order.Amount, instrument.AmountPrecision)
-	request.Px = FloatToString(order.Price, instrument.PricePrecision)
+
+	var normalizer = spot.getNormalizer(instrument)
+	var isMarket = order.OrderType == MARKET || order.Side == BUY_MARKET || order.Side == SELL_MARKET
+	price, amount, err := normalizer.Normalize(order.Price, order.Amount, isMarket)
+	if err != nil {
+		return nil, err
+	}
+	request.Sz = normalizer.FormatAmount(amount)
+	if !isMarket {
+		request.Px = normalizer.FormatPrice(price)
+	}
 	request.ClOrdId = order.Cid
 	request.TgtCcy = "base_ccy"
+	return request, nil
+}
+
+func (spot *Spot) PlaceOrder(order *Order) ([]byte, error) {
+	var request, err = spot.placeRequest(order)
+	if err != nil {
+		return nil, err
+	}
 
 	var response = struct {
 		Code string `json:"code"`
@@ -90,7 +110,7 @@ func (spot *Spot) PlaceOrder(order *Order) ([]byte, error) {
 
 // orderId can set client oid or orderId
 func (spot *Spot) CancelOrder(order *Order) ([]byte, error) {
-	urlPath := "/api/spot/v3/cancel_orders/" + order.OrderId
+	urlPath := fmt.Sprintf(SPOT_CANCEL_ORDER, order.OrderId)
 	param := struct {
 		InstrumentId string `json:"instrument_id"`
 	}{
@@ -118,6 +138,105 @@ func (spot *Spot) CancelOrder(order *Order) ([]byte, error) {
 	return resp, NewError(400, "cancel fail, unknown error")
 }
 
+// BatchPlaceOrders place the orders by the batch-orders api.
+func (spot *Spot) BatchPlaceOrders(orders []*Order) ([]error, []byte, error) {
+	var errs = make([]error, len(orders))
+	var indexes = make([]int, 0, len(orders))
+	var requests = make([]interface{}, 0, len(orders))
+	for i, order := range orders {
+		request, err := spot.placeRequest(order)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		indexes = append(indexes, i)
+		requests = append(requests, request)
+	}
+
+	var now = time.Now()
+	results, batchErrs, resp := spot.doBatchRequest("/api/v5/trade/batch-orders", requests)
+	var dealTime = time.Now()
+	for j, i := range indexes {
+		if batchErrs[j] != nil {
+			errs[i] = batchErrs[j]
+			continue
+		}
+		orders[i].OrderId = results[j].OrdId
+		orders[i].PlaceTimestamp = now.UnixNano() / int64(time.Millisecond)
+		orders[i].PlaceDatetime = now.In(spot.config.Location).Format(GO_BIRTHDAY)
+		orders[i].DealTimestamp = dealTime.UnixNano() / int64(time.Millisecond)
+		orders[i].DealDatetime = dealTime.In(spot.config.Location).Format(GO_BIRTHDAY)
+	}
+	return errs, resp, BatchResult(errs)
+}
+
+// BatchCancelOrders cancel the orders by the cancel-batch-orders api.
+func (spot *Spot) BatchCancelOrders(orders []*Order) ([]error, []byte, error) {
+	var errs = make([]error, len(orders))
+	var indexes = make([]int, 0, len(orders))
+	var requests = make([]interface{}, 0, len(orders))
+	for i, order := range orders {
+		if order.OrderId == "" && order.Cid == "" {
+			errs[i] = errors.New("The orderid and cid is empty. ")
+			continue
+		}
+		indexes = append(indexes, i)
+		requests = append(requests, &spotCancelRequest{
+			InstId:  order.Pair.ToSymbol("-", true),
+			OrdId:   order.OrderId,
+			ClOrdId: order.Cid,
+		})
+	}
+
+	_, batchErrs, resp := spot.doBatchRequest("/api/v5/trade/cancel-batch-orders", requests)
+	for j, i := range indexes {
+		errs[i] = batchErrs[j]
+	}
+	return errs, resp, BatchResult(errs)
+}
+
+// AmendOrder modify the price and the amount of the limit order, the amount includes the filled part.
+func (spot *Spot) AmendOrder(order *Order, newPrice, newAmount float64) ([]byte, error) {
+	if order.OrderId == "" && order.Cid == "" {
+		return nil, errors.New("The orderid and cid is empty. ")
+	}
+	if newPrice <= 0 {
+		newPrice = order.Price
+	}
+	if newAmount <= 0 {
+		newAmount = order.Amount
+	}
+
+	var instrument = spot.getInstruments(order.Pair)
+	var normalizer = spot.getNormalizer(instrument)
+	price, amount, err := normalizer.Normalize(newPrice, newAmount, false)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := spot.amendOrder(
+		instrument.InstId,
+		order.OrderId,
+		order.Cid,
+		normalizer.FormatAmount(amount),
+		normalizer.FormatPrice(price),
+	)
+	if err != nil {
+		return resp, err
+	}
+	order.Price, order.Amount = price, amount
+	return resp, nil
+}
+
+func (spot *Spot) CancelAllOrders(pair Pair) ([]byte, error) {
+	return spot.cancelAll("SPOT", pair.ToSymbol("-", true))
+}
+
+type spotCancelRequest struct {
+	InstId  string `json:"instId"`
+	OrdId   string `json:"ordId,omitempty"`
+	ClOrdId string `json:"clOrdId,omitempty"`
+}
+
 func (spot *Spot) adaptOrder(order *Order, response *OrderResponse) error {
 
 	order.Cid = response.ClientOid
@@ -169,7 +288,7 @@ func (spot *Spot) adaptOrder(order *Order, response *OrderResponse) error {
 
 // orderId can set client oid or orderId
 func (spot *Spot) GetOrder(order *Order) ([]byte, error) {
-	uri := "/api/spot/v3/orders/" + order.OrderId + "?instrument_id=" + order.Pair.ToSymbol("-", true)
+	uri := fmt.Sprintf(SPOT_GET_ORDER, order.OrderId) + "?instrument_id=" + order.Pair.ToSymbol("-", true)
 	var response OrderResponse
 	resp, err := spot.DoRequest(
 		"GET",
@@ -2,6 +2,7 @@ package okex
 
 import (
 	"errors"
+	"math"
 	"net/http"
 	"net/url"
 	"strings"
@@ -29,8 +30,151 @@ func (swap *Swap) getAmount(price, amountContract float64, contract *SwapContrac
 	return amount
 }
 
+// swapPositionOK is the item of the positions api, the pos is in contracts, it is negative in the net short position.
+type swapPositionOK struct {
+	InstId  string  `json:"instId"`
+	PosSide string  `json:"posSide"`
+	Pos     float64 `json:"pos,string"`
+	AvgPx   string  `json:"avgPx"`
+	MarkPx  string  `json:"markPx"`
+	LiqPx   string  `json:"liqPx"`
+	MgnMode string  `json:"mgnMode"`
+	Margin  string  `json:"margin"`
+	Imr     string  `json:"imr"`
+	Lever   string  `json:"lever"`
+}
+
+// toPosition convert the contracts to the basis amount, nil means the position is empty or the contract is unknown.
+func (swap *Swap) toPosition(item *swapPositionOK) *SwapPosition {
+	if item.Pos == 0 {
+		return nil
+	}
+	var pair = NewPair(strings.TrimSuffix(item.InstId, "-SWAP"), "-")
+	var contract = swap.getContract(pair)
+	if contract == nil {
+		return nil
+	}
+
+	var positionType = OPEN_LONG
+	if item.PosSide == "short" || (item.PosSide == "net" && item.Pos < 0) {
+		positionType = OPEN_SHORT
+	}
+	var contracts = math.Abs(item.Pos)
+	var price = ToFloat64(item.AvgPx)
+	var marginAmount = ToFloat64(item.Margin)
+	if item.MgnMode == CROSS {
+		marginAmount = ToFloat64(item.Imr)
+	}
+	return &SwapPosition{
+		Pair:           pair,
+		Type:           positionType,
+		Amount:         swap.getAmount(price, contracts, contract),
+		Price:          price,
+		MarkPrice:      ToFloat64(item.MarkPx),
+		LiquidatePrice: ToFloat64(item.LiqPx),
+		MarginType:     item.MgnMode,
+		MarginAmount:   marginAmount,
+		Leverage:       ToInt64(item.Lever),
+	}
+}
+
+// getPositions request the swap positions, the empty instId means all the swaps.
+func (swap *Swap) getPositions(instId string) ([]*SwapPosition, []byte, error) {
+	var params = url.Values{}
+	params.Set("instType", "SWAP")
+	if instId != "" {
+		params.Set("instId", instId)
+	}
+
+	var response = struct {
+		Code string            `json:"code"`
+		Msg  string            `json:"msg"`
+		Data []*swapPositionOK `json:"data"`
+	}{}
+	resp, err := swap.DoRequest(
+		http.MethodGet,
+		"/api/v5/account/positions?"+params.Encode(),
+		"",
+		&response,
+	)
+	if err != nil {
+		return nil, resp, err
+	}
+	if response.Code != "0" {
+		return nil, resp, errors.New(response.Msg)
+	}
+
+	var positions = make([]*SwapPosition, 0, len(response.Data))
+	for _, item := range response.Data {
+		if position := swap.toPosition(item); position != nil {
+			positions = append(positions, position)
+		}
+	}
+	return positions, resp, nil
+}
+
+// GetAccount return the usdt balance of the trading account and all the swap positions.
 func (swap *Swap) GetAccount() (*SwapAccount, []byte, error) {
-	panic("implement me")
+	var params = url.Values{}
+	params.Set("ccy", USDT.Symbol)
+
+	var response = struct {
+		Code string `json:"code"`
+		Msg  string `json:"msg"`
+		Data []struct {
+			Details []struct {
+				Ccy       string `json:"ccy"`
+				Eq        string `json:"eq"`
+				CashBal   string `json:"cashBal"`
+				AvailEq   string `json:"availEq"`
+				FrozenBal string `json:"frozenBal"`
+				OrdFrozen string `json:"ordFrozen"`
+				MgnRatio  string `json:"mgnRatio"`
+				Upl       string `json:"upl"`
+			} `json:"details"`
+		} `json:"data"`
+	}{}
+	resp, err := swap.DoRequest(
+		http.MethodGet,
+		"/api/v5/account/balance?"+params.Encode(),
+		"",
+		&response,
+	)
+	if err != nil {
+		return nil, resp, err
+	}
+	if response.Code != "0" {
+		return nil, resp, errors.New(response.Msg)
+	}
+	if len(response.Data) == 0 {
+		return nil, resp, errors.New("lack response data. ")
+	}
+
+	var account = &SwapAccount{
+		Exchange:  OKEX,
+		Currency:  USDT,
+		Positions: make([]*SwapPosition, 0),
+	}
+	for _, detail := range response.Data[0].Details {
+		if detail.Ccy != USDT.Symbol {
+			continue
+		}
+		account.Margin = ToFloat64(detail.FrozenBal)
+		account.MarginPosition = ToFloat64(detail.FrozenBal) - ToFloat64(detail.OrdFrozen)
+		account.MarginOpen = ToFloat64(detail.OrdFrozen)
+		account.MarginRate = ToFloat64(detail.MgnRatio)
+		account.BalanceTotal = ToFloat64(detail.CashBal)
+		account.BalanceNet = ToFloat64(detail.Eq)
+		account.BalanceAvail = ToFloat64(detail.AvailEq)
+		account.ProfitUnreal = ToFloat64(detail.Upl)
+	}
+
+	positions, positionResp, err := swap.getPositions("")
+	if err != nil {
+		return nil, positionResp, err
+	}
+	account.Positions = positions
+	return account, resp, nil
 }
 
 var _INERNAL_V5_FUTURE_TYPE_CONVERTER = map[FutureType][]string{
@@ -40,6 +184,12 @@ var _INERNAL_V5_FUTURE_TYPE_CONVERTER = map[FutureType][]string{
 	LIQUIDATE_SHORT: {"buy", "short"},
 }
 
+var _INERNAL_V5_POSITION_SIDE_CONVERTER = map[PositionSide]string{
+	POSITION_SIDE_BOTH:  "net",
+	POSITION_SIDE_LONG:  "long",
+	POSITION_SIDE_SHORT: "short",
+}
+
 var _INERNAL_V5_FUTURE_PLACE_TYPE_CONVERTER = map[PlaceType]string{
 	NORMAL:     "limit",
 	ONLY_MAKER: "post_only",
@@ -48,29 +198,63 @@ var _INERNAL_V5_FUTURE_PLACE_TYPE_CONVERTER = map[PlaceType]string{
 	MARKET:     "market",
 }
 
-func (swap *Swap) PlaceOrder(order *SwapOrder) ([]byte, error) {
+type swapOrderRequest struct {
+	InstId  string `json:"instId"`
+	TdMode  string `json:"tdMode"`
+	Side    string `json:"side"`
+	PosSide string `json:"posSide,omitempty"`
+	OrdType string `json:"ordType"`
+	Sz      string `json:"sz"`
+	Px      string `json:"px"`
+	ClOrdId string `json:"clOrdId,omitempty"`
+	// the reduce only works in the net mode only
+	ReduceOnly bool `json:"reduceOnly,omitempty"`
+}
+
+// placeRequest build the request of the normal order, the batch orders use it too.
+func (swap *Swap) placeRequest(order *SwapOrder) (*swapOrderRequest, error) {
 	var contract = swap.getContract(order.Pair)
-	var request = struct {
-		InstId  string `json:"instId"`
-		TdMode  string `json:"tdMode"`
-		Side    string `json:"side"`
-		PosSide string `json:"posSide,omitempty"`
-		OrdType string `json:"ordType"`
-		Sz      string `json:"sz"`
-		Px      string `json:"px"`
-		ClOrdId string `json:"clOrdId,omitempty"`
-	}{}
+	var request = &swapOrderRequest{}
 
 	request.InstId = order.Pair.ToSymbol("-", true) + "-SWAP"
-	request.TdMode = "cross" // todo 目前写死全仓，后续调整成可逐仓
+	request.TdMode = swap.tdMode(request.InstId, order.MarginType)
+	if order.ClosePosition {
+		return nil, errors.New("okex close position by the market order or the conditional order only. ")
+	}
+	positionSide, reduceOnly, err := order.ResolvePosition()
+	if err != nil {
+		return nil, err
+	}
 	sideInfo, _ := _INERNAL_V5_FUTURE_TYPE_CONVERTER[order.Type]
 	request.Side = sideInfo[0]
-	request.PosSide = sideInfo[1]
+	request.PosSide = _INERNAL_V5_POSITION_SIDE_CONVERTER[positionSide]
+	request.ReduceOnly = reduceOnly && positionSide == POSITION_SIDE_BOTH
 	placeInfo, _ := _INERNAL_V5_FUTURE_PLACE_TYPE_CONVERTER[order.PlaceType]
 	request.OrdType = placeInfo
-	request.Sz = FloatToString(order.Amount, contract.AmountPrecision)
-	request.Px = FloatToPrice(order.Price, contract.PricePrecision, contract.TickSize)
+
+	var normalizer = contract.GetNormalizer()
+	normalizer.ReduceOnly = reduceOnly
+	price, amount, err := normalizer.Normalize(order.Price, order.Amount, order.PlaceType == MARKET)
+	if err != nil {
+		return nil, err
+	}
+	request.Sz = normalizer.FormatAmount(amount)
+	request.Px = normalizer.FormatPrice(price)
 	request.ClOrdId = order.Cid
+	return request, nil
+}
+
+func (swap *Swap) PlaceOrder(order *SwapOrder) ([]byte, error) {
+	if order.ConditionType != CONDITION_NONE {
+		return swap.placeAlgoOrder(order)
+	}
+	if order.ClosePosition && order.PlaceType == MARKET {
+		return swap.closePosition(order)
+	}
+	var request, err = swap.placeRequest(order)
+	if err != nil {
+		return nil, err
+	}
 
 	var response = struct {
 		Code string `json:"code"`
@@ -113,6 +297,9 @@ func (swap *Swap) PlaceOrder(order *SwapOrder) ([]byte, error) {
 }
 
 func (swap *Swap) CancelOrder(order *SwapOrder) ([]byte, error) {
+	if order.ConditionType != CONDITION_NONE {
+		return swap.cancelAlgoOrder(order)
+	}
 
 	var request = struct {
 		InstId string `json:"instId"`
@@ -168,6 +355,9 @@ var _INERNAL_V5_FUTURE_ORDER_STATUE_CONVERTER = map[string]TradeStatus{
 }
 
 func (swap *Swap) GetOrder(order *SwapOrder) ([]byte, error) {
+	if order.ConditionType != CONDITION_NONE {
+		return swap.getAlgoOrder(order)
+	}
 
 	var params = url.Values{}
 	params.Set("instId", order.Pair.ToSymbol("-", true)+"-SWAP")
@@ -233,20 +423,160 @@ func (swap *Swap) GetOrder(order *SwapOrder) ([]byte, error) {
 
 }
 
+// GetUnFinishOrders return the live and the partially filled orders of the pair, the Amount is in contracts as PlaceOrder.
 func (swap *Swap) GetUnFinishOrders(pair Pair) ([]*SwapOrder, []byte, error) {
-	panic("implement me")
+	var params = url.Values{}
+	params.Set("instType", "SWAP")
+	params.Set("instId", pair.ToSymbol("-", true)+"-SWAP")
+
+	var response = struct {
+		Code string `json:"code"`
+		Msg  string `json:"msg"`
+		Data []struct {
+			ClOrdId    string  `json:"clOrdId"`
+			OrdId      string  `json:"ordId"`
+			Side       string  `json:"side"`
+			PosSide    string  `json:"posSide"`
+			OrdType    string  `json:"ordType"`
+			Px         string  `json:"px"`
+			Sz         float64 `json:"sz,string"`
+			AvgPx      string  `json:"avgPx"`
+			AccFillSz  float64 `json:"accFillSz,string"`
+			State      string  `json:"state"`
+			Lever      string  `json:"lever"`
+			Fee        string  `json:"fee"`
+			TdMode     string  `json:"tdMode"`
+			ReduceOnly string  `json:"reduceOnly"`
+			UTime      int64   `json:"uTime,string"`
+			CTime      int64   `json:"cTime,string"`
+		} `json:"data"`
+	}{}
+	resp, err := swap.DoRequest(
+		http.MethodGet,
+		"/api/v5/trade/orders-pending?"+params.Encode(),
+		"",
+		&response,
+	)
+	if err != nil {
+		return nil, resp, err
+	}
+	if response.Code != "0" {
+		return nil, resp, errors.New(response.Msg)
+	}
+
+	var orders = make([]*SwapOrder, 0, len(response.Data))
+	for _, item := range response.Data {
+		var order = &SwapOrder{
+			Cid:            item.ClOrdId,
+			OrderId:        item.OrdId,
+			Price:          ToFloat64(item.Px),
+			Amount:         item.Sz,
+			AvgPrice:       ToFloat64(item.AvgPx),
+			DealAmount:     item.AccFillSz,
+			PlaceTimestamp: item.CTime,
+			PlaceDatetime:  time.Unix(item.CTime/1000, 0).In(swap.config.Location).Format(GO_BIRTHDAY),
+			DealTimestamp:  item.UTime,
+			DealDatetime:   time.Unix(item.UTime/1000, 0).In(swap.config.Location).Format(GO_BIRTHDAY),
+			Status:         _INERNAL_V5_FUTURE_ORDER_STATUE_CONVERTER[item.State],
+			MarginType:     item.TdMode,
+			LeverRate:      ToInt64(item.Lever),
+			Fee:            ToFloat64(item.Fee),
+			Pair:           pair,
+			Exchange:       OKEX,
+			ReduceOnly:     item.ReduceOnly == "true",
+			PositionSide:   POSITION_SIDE_BOTH,
+		}
+		for placeType, ordType := range _INERNAL_V5_FUTURE_PLACE_TYPE_CONVERTER {
+			if ordType == item.OrdType {
+				order.PlaceType = placeType
+			}
+		}
+		// the net mode order is the open order when it is not reduce only.
+		for futureType, sideInfo := range _INERNAL_V5_FUTURE_TYPE_CONVERTER {
+			if sideInfo[0] != item.Side {
+				continue
+			}
+			var isLiquidate = futureType == LIQUIDATE_LONG || futureType == LIQUIDATE_SHORT
+			if (item.PosSide == "net" && isLiquidate == order.ReduceOnly) || sideInfo[1] == item.PosSide {
+				order.Type = futureType
+			}
+		}
+		if item.PosSide == "long" {
+			order.PositionSide = POSITION_SIDE_LONG
+		} else if item.PosSide == "short" {
+			order.PositionSide = POSITION_SIDE_SHORT
+		}
+		orders = append(orders, order)
+	}
+	return orders, resp, nil
 }
 
+// GetPosition return the position of the openType side, the Amount is in the basis currency.
 func (swap *Swap) GetPosition(pair Pair, openType FutureType) (*SwapPosition, []byte, error) {
-	panic("implement me")
+	var positions, resp, err = swap.getPositions(pair.ToSymbol("-", true) + "-SWAP")
+	if err != nil {
+		return nil, resp, err
+	}
+	for _, position := range positions {
+		if position.Type == openType {
+			return position, resp, nil
+		}
+	}
+	return nil, resp, errors.New("Can not find the position. ")
 }
 
 func (swap *Swap) AddMargin(pair Pair, openType FutureType, marginAmount float64) ([]byte, error) {
-	panic("implement me")
+	return swap.modifyMargin(pair, openType, marginAmount, "add")
 }
 
 func (swap *Swap) ReduceMargin(pair Pair, openType FutureType, marginAmount float64) ([]byte, error) {
-	panic("implement me")
+	return swap.modifyMargin(pair, openType, marginAmount, "reduce")
+}
+
+// modifyMargin change the margin of the isolated position, the posSide is net in the one-way mode.
+func (swap *Swap) modifyMargin(pair Pair, openType FutureType, marginAmount float64, opType string) ([]byte, error) {
+	var sideInfo, exist = _INERNAL_V5_FUTURE_TYPE_CONVERTER[openType]
+	if !exist {
+		return nil, errors.New("future type not found. ")
+	}
+	var mode, resp, err = swap.GetPositionMode(pair)
+	if err != nil {
+		return resp, err
+	}
+	var posSide = sideInfo[1]
+	if mode == POSITION_MODE_ONE_WAY {
+		posSide = _INERNAL_V5_POSITION_SIDE_CONVERTER[POSITION_SIDE_BOTH]
+	}
+
+	var request = struct {
+		InstId  string `json:"instId"`
+		PosSide string `json:"posSide"`
+		Type    string `json:"type"`
+		Amt     string `json:"amt"`
+	}{
+		pair.ToSymbol("-", true) + "-SWAP",
+		posSide,
+		opType,
+		FloatToString(marginAmount, 8),
+	}
+	var response = struct {
+		Code string `json:"code"`
+		Msg  string `json:"msg"`
+	}{}
+	reqBody, _, _ := swap.BuildRequestBody(request)
+	resp, err = swap.DoRequest(
+		http.MethodPost,
+		"/api/v5/account/position/margin-balance",
+		reqBody,
+		&response,
+	)
+	if err != nil {
+		return resp, err
+	}
+	if response.Code != "0" {
+		return resp, errors.New(string(resp))
+	}
+	return resp, nil
 }
 
 func (swap *Swap) getContract(pair Pair) *SwapContract {
@@ -286,6 +616,7 @@ func (swap *Swap) updateContracts() ([]byte, error) {
 			TickSz    string `json:"tickSz"`
 			LotSz     string `json:"lotSz"`
 			MinSz     string `json:"minSz"`
+			MaxMktSz  string `json:"maxMktSz"`
 		} `json:"data"`
 	}{}
 	resp, err := swap.DoRequestMarket(
@@ -328,6 +659,9 @@ func (swap *Swap) updateContracts() ([]byte, error) {
 			TickSize:        ToFloat64(item.TickSz),
 			PricePrecision:  GetPrecisionInt64(ToFloat64(item.TickSz)),
 			AmountPrecision: GetPrecisionInt64(ToFloat64(item.LotSz)),
+			LotSize:         ToFloat64(item.LotSz),
+			MinAmount:       ToFloat64(item.MinSz),
+			MaxMarketAmount: ToFloat64(item.MaxMktSz),
 		}
 
 		swapContracts.ContractNameKV[stdContractName] = &contract
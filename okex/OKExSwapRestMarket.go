@@ -180,6 +180,42 @@ func (swap *Swap) GetKline(pair Pair, period, size, since int) ([]*SwapKline, []
 	return GetAscSwapKline(klines), resp, nil
 }
 
+func (swap *Swap) GetMarkKline(pair Pair, period, size, since int) ([]*SwapKline, []byte, error) {
+	return swap.getSwapPriceKline(V5_MARK_KLINE_URI, pair.ToSymbol("-", true)+"-SWAP", pair, period, size, since)
+}
+
+func (swap *Swap) GetIndexKline(pair Pair, period, size, since int) ([]*SwapKline, []byte, error) {
+	return swap.getSwapPriceKline(V5_INDEX_KLINE_URI, pair.ToSymbol("-", true), pair, period, size, since)
+}
+
+// GetPremiumKline okex has no premium index kline, the premium is in the funding rate.
+func (swap *Swap) GetPremiumKline(pair Pair, period, size, since int) ([]*SwapKline, []byte, error) {
+	return nil, nil, errors.New("okex has no premium index kline. ")
+}
+
+func (swap *Swap) getSwapPriceKline(uri, instId string, pair Pair, period, size, since int) ([]*SwapKline, []byte, error) {
+	var data, resp, err = swap.getPriceKline(uri, instId, period, size, since)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	var klines = make([]*SwapKline, 0, len(data))
+	for _, itm := range data {
+		timestamp := ToInt64(itm[0])
+		klines = append(klines, &SwapKline{
+			Timestamp: timestamp,
+			Date:      time.Unix(timestamp/1000, 0).In(swap.config.Location).Format(GO_BIRTHDAY),
+			Pair:      pair,
+			Exchange:  OKEX,
+			Open:      ToFloat64(itm[1]),
+			High:      ToFloat64(itm[2]),
+			Low:       ToFloat64(itm[3]),
+			Close:     ToFloat64(itm[4]),
+		})
+	}
+	return GetAscSwapKline(klines), resp, nil
+}
+
 func (swap *Swap) GetContract(pair Pair) *SwapContract {
 	return swap.getContract(pair)
 }
@@ -225,13 +261,144 @@ func (swap *Swap) GetLimit(pair Pair) (float64, float64, error) {
 }
 
 func (swap *Swap) GetOpenAmount(pair Pair) (float64, int64, []byte, error) {
-	panic("implement me")
+	var openInterest, resp, err = swap.GetOpenInterest(pair)
+	if err != nil {
+		return 0, 0, resp, err
+	}
+	return openInterest.Amount, openInterest.Timestamp, resp, nil
 }
 
+const SWAP_FUNDING_LIMIT = 100
+
+// GetFundingFees return the latest page of the realized funding rates, the item is [rate, funding time].
 func (swap *Swap) GetFundingFees(pair Pair) ([][]interface{}, []byte, error) {
-	panic("implement me")
+	var rates, resp, err = swap.getFundingRates(pair, time.Now().UnixMilli())
+	if err != nil {
+		return nil, resp, err
+	}
+	var fees = make([][]interface{}, 0, len(rates))
+	for _, rate := range rates {
+		fees = append(fees, []interface{}{rate.Rate, rate.FundingTime})
+	}
+	return fees, resp, nil
 }
 
+// GetFundingFee return the funding rate of the current period.
 func (swap *Swap) GetFundingFee(pair Pair) (float64, error) {
-	panic("implement me")
+	var rate, _, err = swap.GetNextFundingRate(pair)
+	if err != nil {
+		return 0, err
+	}
+	return rate.Rate, nil
+}
+
+// GetFundingRateHistory request the funding-rate-history api from the end page by page.
+func (swap *Swap) GetFundingRateHistory(pair Pair, start, end time.Time) ([]*FundingRate, []byte, error) {
+	var rates = make([]*FundingRate, 0)
+	var responses = make([][]byte, 0)
+	var after = end.UnixMilli() + 1
+	for {
+		var page, resp, err = swap.getFundingRates(pair, after)
+		responses = append(responses, resp)
+		if err != nil {
+			return nil, BatchResponse(responses), err
+		}
+		for _, rate := range page {
+			if rate.FundingTime >= start.UnixMilli() {
+				rates = append(rates, rate)
+			}
+		}
+		// the page is in the descending order of the funding time.
+		if len(page) < SWAP_FUNDING_LIMIT || page[len(page)-1].FundingTime < start.UnixMilli() {
+			break
+		}
+		after = page[len(page)-1].FundingTime
+	}
+
+	SortFundingRates(rates, 8*60*60*1000)
+	return rates, BatchResponse(responses), nil
+}
+
+// getFundingRates return the realized funding rates before the after timestamp, the latest first.
+func (swap *Swap) getFundingRates(pair Pair, after int64) ([]*FundingRate, []byte, error) {
+	var params = url.Values{}
+	params.Set("instId", pair.ToSymbol("-", true)+"-SWAP")
+	params.Set("after", fmt.Sprintf("%d", after))
+	params.Set("limit", fmt.Sprintf("%d", SWAP_FUNDING_LIMIT))
+
+	var response struct {
+		Code string `json:"code"`
+		Msg  string `json:"msg"`
+		Data []struct {
+			FundingRate  float64 `json:"fundingRate,string"`
+			RealizedRate string  `json:"realizedRate"`
+			FundingTime  int64   `json:"fundingTime,string"`
+		} `json:"data"`
+	}
+	resp, err := swap.DoRequestMarket(
+		http.MethodGet,
+		"/api/v5/public/funding-rate-history?"+params.Encode(),
+		"",
+		&response,
+	)
+	if err != nil {
+		return nil, resp, err
+	}
+	if response.Code != "0" {
+		return nil, resp, errors.New(response.Msg)
+	}
+
+	var rates = make([]*FundingRate, 0, len(response.Data))
+	for _, item := range response.Data {
+		var rate = &FundingRate{
+			Pair:        pair,
+			Exchange:    OKEX,
+			Rate:        item.FundingRate,
+			FundingTime: item.FundingTime,
+		}
+		// the realized rate is the one really settled, it is empty in the old records.
+		if item.RealizedRate != "" {
+			rate.Rate = ToFloat64(item.RealizedRate)
+		}
+		rates = append(rates, rate)
+	}
+	return rates, resp, nil
+}
+
+// GetNextFundingRate return the rate of the current period, it is settled at the funding time.
+func (swap *Swap) GetNextFundingRate(pair Pair) (*FundingRate, []byte, error) {
+	var params = url.Values{}
+	params.Set("instId", pair.ToSymbol("-", true)+"-SWAP")
+
+	var response struct {
+		Code string `json:"code"`
+		Msg  string `json:"msg"`
+		Data []struct {
+			FundingRate     float64 `json:"fundingRate,string"`
+			FundingTime     int64   `json:"fundingTime,string"`
+			NextFundingTime int64   `json:"nextFundingTime,string"`
+		} `json:"data"`
+	}
+	resp, err := swap.DoRequestMarket(
+		http.MethodGet,
+		"/api/v5/public/funding-rate?"+params.Encode(),
+		"",
+		&response,
+	)
+	if err != nil {
+		return nil, resp, err
+	}
+	if response.Code != "0" || len(response.Data) == 0 {
+		return nil, resp, errors.New(string(resp))
+	}
+
+	var data = response.Data[0]
+	return &FundingRate{
+		Pair:        pair,
+		Exchange:    OKEX,
+		Rate:        data.FundingRate,
+		IsPredicted: true,
+		FundingTime: data.FundingTime,
+		Interval:    data.NextFundingTime - data.FundingTime,
+	}, resp, nil
 }
@@ -0,0 +1,186 @@
+package okex
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/deforceHK/goghostex"
+)
+
+// newSwapStandIn start the stand-in server of the v5 api, the handlers are keyed by the path.
+func newSwapStandIn(t *testing.T, handlers map[string]http.HandlerFunc) (*OKEx, *httptest.Server) {
+	var mux = http.NewServeMux()
+	mux.HandleFunc("/api/v5/public/instruments", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"code":"0","msg":"","data":[
+			{"instId":"BTC-USDT-SWAP","uly":"BTC-USDT","settleCcy":"USDT","ctVal":"0.01","tickSz":"0.1","lotSz":"0.01","minSz":"0.01","maxMktSz":"10000"}
+		]}`))
+	})
+	for path, handler := range handlers {
+		mux.HandleFunc(path, handler)
+	}
+	var server = httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	var ok = New(&APIConfig{
+		Endpoint:      server.URL,
+		HttpClient:    server.Client(),
+		ApiKey:        "key",
+		ApiSecretKey:  "secret",
+		ApiPassphrase: "passphrase",
+		Location:      time.UTC,
+	})
+	return ok, server
+}
+
+// go test -v ./okex/... -count=1 -run=TestSwap_GetAccount_StandIn
+func TestSwap_GetAccount_StandIn(t *testing.T) {
+	var ok, _ = newSwapStandIn(t, map[string]http.HandlerFunc{
+		"/api/v5/account/balance": func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("ccy") != "USDT" {
+				t.Error("the ccy is wrong: ", r.URL.RawQuery)
+			}
+			_, _ = w.Write([]byte(`{"code":"0","msg":"","data":[{"details":[
+				{"ccy":"USDT","eq":"1050","cashBal":"1000","availEq":"900","frozenBal":"150","ordFrozen":"50","mgnRatio":"12.5","upl":"50"}
+			]}]}`))
+		},
+		"/api/v5/account/positions": func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"code":"0","msg":"","data":[
+				{"instId":"BTC-USDT-SWAP","posSide":"net","pos":"-200","avgPx":"50000","markPx":"49000","liqPx":"60000","mgnMode":"isolated","margin":"100","imr":"","lever":"10"},
+				{"instId":"BTC-USDT-SWAP","posSide":"long","pos":"0","avgPx":"","markPx":"49000","liqPx":"","mgnMode":"cross","margin":"","imr":"0","lever":"5"}
+			]}`))
+		},
+	})
+
+	var account, _, err = ok.Swap.GetAccount()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if account.Currency != USDT || account.BalanceNet != 1050 || account.BalanceAvail != 900 ||
+		account.MarginPosition != 100 || account.MarginOpen != 50 || account.ProfitUnreal != 50 {
+		t.Error("the account is wrong: ", *account)
+		return
+	}
+	if len(account.Positions) != 1 {
+		t.Error("the empty position should be skipped: ", len(account.Positions))
+		return
+	}
+	var position = account.Positions[0]
+	if position.Type != OPEN_SHORT || position.Amount != 2 || position.Price != 50000 ||
+		position.MarginType != ISOLATED || position.MarginAmount != 100 || position.Leverage != 10 {
+		t.Error("the position is wrong: ", *position)
+	}
+}
+
+// go test -v ./okex/... -count=1 -run=TestSwap_GetPosition_StandIn
+func TestSwap_GetPosition_StandIn(t *testing.T) {
+	var ok, _ = newSwapStandIn(t, map[string]http.HandlerFunc{
+		"/api/v5/account/positions": func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("instId") != "BTC-USDT-SWAP" {
+				t.Error("the instId is wrong: ", r.URL.RawQuery)
+			}
+			_, _ = w.Write([]byte(`{"code":"0","msg":"","data":[
+				{"instId":"BTC-USDT-SWAP","posSide":"long","pos":"150","avgPx":"40000","markPx":"41000","liqPx":"30000","mgnMode":"cross","margin":"","imr":"60","lever":"10"}
+			]}`))
+		},
+	})
+
+	var position, _, err = ok.Swap.GetPosition(Pair{Basis: BTC, Counter: USDT}, OPEN_LONG)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if position.Amount != 1.5 || position.MarginType != CROSS || position.MarginAmount != 60 {
+		t.Error("the position is wrong: ", *position)
+		return
+	}
+	if _, _, err := ok.Swap.GetPosition(Pair{Basis: BTC, Counter: USDT}, OPEN_SHORT); err == nil {
+		t.Error("the short position should not be found. ")
+	}
+}
+
+// go test -v ./okex/... -count=1 -run=TestSwap_GetUnFinishOrders_StandIn
+func TestSwap_GetUnFinishOrders_StandIn(t *testing.T) {
+	var ok, _ = newSwapStandIn(t, map[string]http.HandlerFunc{
+		"/api/v5/trade/orders-pending": func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("instType") != "SWAP" || r.URL.Query().Get("instId") != "BTC-USDT-SWAP" {
+				t.Error("the query is wrong: ", r.URL.RawQuery)
+			}
+			_, _ = w.Write([]byte(`{"code":"0","msg":"","data":[
+				{"clOrdId":"c1","ordId":"1","side":"buy","posSide":"net","ordType":"limit","px":"40000","sz":"3","avgPx":"","accFillSz":"1","state":"partially_filled","lever":"10","fee":"-0.1","tdMode":"cross","reduceOnly":"false","cTime":"1700000000000","uTime":"1700000001000"},
+				{"clOrdId":"c2","ordId":"2","side":"buy","posSide":"net","ordType":"post_only","px":"39000","sz":"2","avgPx":"","accFillSz":"0","state":"live","lever":"10","fee":"0","tdMode":"cross","reduceOnly":"true","cTime":"1700000000000","uTime":"1700000000000"},
+				{"clOrdId":"c3","ordId":"3","side":"sell","posSide":"long","ordType":"limit","px":"45000","sz":"1","avgPx":"","accFillSz":"0","state":"live","lever":"10","fee":"0","tdMode":"isolated","reduceOnly":"false","cTime":"1700000000000","uTime":"1700000000000"}
+			]}`))
+		},
+	})
+
+	var orders, _, err = ok.Swap.GetUnFinishOrders(Pair{Basis: BTC, Counter: USDT})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(orders) != 3 {
+		t.Error("the orders are wrong: ", len(orders))
+		return
+	}
+	if orders[0].Type != OPEN_LONG || orders[0].Status != ORDER_PART_FINISH || orders[0].Amount != 3 ||
+		orders[0].DealAmount != 1 || orders[0].PositionSide != POSITION_SIDE_BOTH {
+		t.Error("the open order is wrong: ", *orders[0])
+		return
+	}
+	if orders[1].Type != LIQUIDATE_SHORT || orders[1].PlaceType != ONLY_MAKER || !orders[1].ReduceOnly {
+		t.Error("the reduce only order is wrong: ", *orders[1])
+		return
+	}
+	if orders[2].Type != LIQUIDATE_LONG || orders[2].PositionSide != POSITION_SIDE_LONG {
+		t.Error("the hedge order is wrong: ", *orders[2])
+	}
+}
+
+// go test -v ./okex/... -count=1 -run=TestSwap_ModifyMargin_StandIn
+func TestSwap_ModifyMargin_StandIn(t *testing.T) {
+	var posMode = "net_mode"
+	var requests = make([]map[string]string, 0)
+	var ok, _ = newSwapStandIn(t, map[string]http.HandlerFunc{
+		"/api/v5/account/config": func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"code":"0","msg":"","data":[{"posMode":"` + posMode + `"}]}`))
+		},
+		"/api/v5/account/position/margin-balance": func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPost {
+				t.Error("the method is wrong: ", r.Method)
+			}
+			var body, _ = io.ReadAll(r.Body)
+			var request = make(map[string]string)
+			_ = json.Unmarshal(body, &request)
+			requests = append(requests, request)
+			_, _ = w.Write([]byte(`{"code":"0","msg":"","data":[]}`))
+		},
+	})
+
+	if _, err := ok.Swap.AddMargin(Pair{Basis: BTC, Counter: USDT}, OPEN_SHORT, 12.5); err != nil {
+		t.Error(err)
+		return
+	}
+	posMode = "long_short_mode"
+	if _, err := ok.Swap.ReduceMargin(Pair{Basis: BTC, Counter: USDT}, OPEN_SHORT, 3); err != nil {
+		t.Error(err)
+		return
+	}
+
+	if len(requests) != 2 {
+		t.Error("the requests are wrong: ", requests)
+		return
+	}
+	if requests[0]["instId"] != "BTC-USDT-SWAP" || requests[0]["posSide"] != "net" ||
+		requests[0]["type"] != "add" || ToFloat64(requests[0]["amt"]) != 12.5 {
+		t.Error("the add margin request is wrong: ", requests[0])
+		return
+	}
+	if requests[1]["posSide"] != "short" || requests[1]["type"] != "reduce" || ToFloat64(requests[1]["amt"]) != 3 {
+		t.Error("the reduce margin request is wrong: ", requests[1])
+	}
+}
@@ -0,0 +1,271 @@
+package okex
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	. "github.com/deforceHK/goghostex"
+)
+
+const SWAP_STAT_LIMIT = 100
+
+var _INERNAL_V5_LONG_SHORT_URI_CONVERTER = map[LongShortType]string{
+	LONG_SHORT_TOP_ACCOUNT:    "/api/v5/rubik/stat/contracts/long-short-account-ratio-contract-top-trader?",
+	LONG_SHORT_TOP_POSITION:   "/api/v5/rubik/stat/contracts/long-short-position-ratio-contract-top-trader?",
+	LONG_SHORT_GLOBAL_ACCOUNT: "/api/v5/rubik/stat/contracts/long-short-account-ratio-contract?",
+}
+
+// statParams build the params of the rubik api, the begin is the since and the end is the size periods after it.
+func (swap *Swap) statParams(pair Pair, period, size, since int) (url.Values, error) {
+	var bar, exist = _INERNAL_V5_CANDLE_PERIOD_CONVERTER[period]
+	if !exist || period < KLINE_PERIOD_5MIN {
+		return nil, fmt.Errorf("the period %d is not supported in the rubik stat. ", period)
+	}
+	if size <= 0 || size > SWAP_STAT_LIMIT {
+		size = SWAP_STAT_LIMIT
+	}
+
+	var params = url.Values{}
+	params.Set("instId", pair.ToSymbol("-", true)+"-SWAP")
+	params.Set("period", bar)
+	params.Set("limit", strconv.Itoa(size))
+	if since > 0 {
+		params.Set("begin", strconv.Itoa(since))
+		params.Set("end", strconv.Itoa(since+size*_INERNAL_KLINE_PERIOD_CONVERTER[period]*1000))
+	}
+	return params, nil
+}
+
+// getStat request the rubik api, the item of the data is [ts, value...] in string.
+func (swap *Swap) getStat(uri string) ([][]string, []byte, error) {
+	var response struct {
+		Code string     `json:"code"`
+		Msg  string     `json:"msg"`
+		Data [][]string `json:"data"`
+	}
+	resp, err := swap.DoRequestMarket(http.MethodGet, uri, "", &response)
+	if err != nil {
+		return nil, resp, err
+	}
+	if response.Code != "0" {
+		return nil, resp, errors.New(response.Msg)
+	}
+	return response.Data, resp, nil
+}
+
+func (swap *Swap) GetOpenInterest(pair Pair) (*OpenInterest, []byte, error) {
+	var params = url.Values{}
+	params.Set("instType", "SWAP")
+	params.Set("instId", pair.ToSymbol("-", true)+"-SWAP")
+
+	var response struct {
+		Code string `json:"code"`
+		Msg  string `json:"msg"`
+		Data []struct {
+			OiCcy float64 `json:"oiCcy,string"`
+			OiUsd float64 `json:"oiUsd,string"`
+			Ts    int64   `json:"ts,string"`
+		} `json:"data"`
+	}
+	resp, err := swap.DoRequestMarket(
+		http.MethodGet,
+		"/api/v5/public/open-interest?"+params.Encode(),
+		"",
+		&response,
+	)
+	if err != nil {
+		return nil, resp, err
+	}
+	if response.Code != "0" {
+		return nil, resp, errors.New(response.Msg)
+	}
+	if len(response.Data) == 0 {
+		return nil, resp, errors.New("lack response data. ")
+	}
+
+	return &OpenInterest{
+		Pair:      pair,
+		Exchange:  OKEX,
+		Amount:    response.Data[0].OiCcy,
+		Value:     response.Data[0].OiUsd,
+		Timestamp: response.Data[0].Ts,
+	}, resp, nil
+}
+
+func (swap *Swap) GetOpenInterestHistory(pair Pair, period, size, since int) ([]*OpenInterest, []byte, error) {
+	var params, err = swap.statParams(pair, period, size, since)
+	if err != nil {
+		return nil, nil, err
+	}
+	// the item is [ts, oi, oiCcy, oiUsd]
+	data, resp, err := swap.getStat("/api/v5/rubik/stat/contracts/open-interest-history?" + params.Encode())
+	if err != nil {
+		return nil, resp, err
+	}
+
+	var openInterests = make([]*OpenInterest, 0, len(data))
+	for _, item := range data {
+		if len(item) < 4 {
+			return nil, resp, fmt.Errorf("the open interest item is wrong: %v", item)
+		}
+		openInterests = append(openInterests, &OpenInterest{
+			Pair:      pair,
+			Exchange:  OKEX,
+			Amount:    ToFloat64(item[2]),
+			Value:     ToFloat64(item[3]),
+			Timestamp: ToInt64(item[0]),
+		})
+	}
+	sort.Slice(openInterests, func(i, j int) bool {
+		return openInterests[i].Timestamp < openInterests[j].Timestamp
+	})
+	return openInterests, resp, nil
+}
+
+// GetLongShortRatio return the ratio only, okex has no long and short shares.
+func (swap *Swap) GetLongShortRatio(
+	pair Pair,
+	ratioType LongShortType,
+	period, size, since int,
+) ([]*LongShortRatio, []byte, error) {
+	var uri, exist = _INERNAL_V5_LONG_SHORT_URI_CONVERTER[ratioType]
+	if !exist {
+		return nil, nil, fmt.Errorf("the long short type %d is not supported. ", ratioType)
+	}
+	var params, err = swap.statParams(pair, period, size, since)
+	if err != nil {
+		return nil, nil, err
+	}
+	// the item is [ts, ratio]
+	data, resp, err := swap.getStat(uri + params.Encode())
+	if err != nil {
+		return nil, resp, err
+	}
+
+	var ratios = make([]*LongShortRatio, 0, len(data))
+	for _, item := range data {
+		if len(item) < 2 {
+			return nil, resp, fmt.Errorf("the long short ratio item is wrong: %v", item)
+		}
+		ratios = append(ratios, &LongShortRatio{
+			Pair:      pair,
+			Exchange:  OKEX,
+			Type:      ratioType,
+			Ratio:     ToFloat64(item[1]),
+			Timestamp: ToInt64(item[0]),
+		})
+	}
+	sort.Slice(ratios, func(i, j int) bool {
+		return ratios[i].Timestamp < ratios[j].Timestamp
+	})
+	return ratios, resp, nil
+}
+
+func (swap *Swap) GetTakerVolume(pair Pair, period, size, since int) ([]*TakerVolume, []byte, error) {
+	var params, err = swap.statParams(pair, period, size, since)
+	if err != nil {
+		return nil, nil, err
+	}
+	// unit 0 is the basis currency, the item is [ts, sellVol, buyVol]
+	params.Set("unit", "0")
+	data, resp, err := swap.getStat("/api/v5/rubik/stat/taker-volume-contract?" + params.Encode())
+	if err != nil {
+		return nil, resp, err
+	}
+
+	var volumes = make([]*TakerVolume, 0, len(data))
+	for _, item := range data {
+		if len(item) < 3 {
+			return nil, resp, fmt.Errorf("the taker volume item is wrong: %v", item)
+		}
+		volumes = append(volumes, &TakerVolume{
+			Pair:       pair,
+			Exchange:   OKEX,
+			BuyVolume:  ToFloat64(item[2]),
+			SellVolume: ToFloat64(item[1]),
+			Timestamp:  ToInt64(item[0]),
+		})
+	}
+	sort.Slice(volumes, func(i, j int) bool {
+		return volumes[i].Timestamp < volumes[j].Timestamp
+	})
+	return volumes, resp, nil
+}
+
+// okLiquidation is the liquidation-orders item in the rest and the websocket api, the sz is in contracts.
+type okLiquidation struct {
+	InstId  string `json:"instId"`
+	Details []struct {
+		Side string  `json:"side"`
+		BkPx float64 `json:"bkPx,string"`
+		Sz   float64 `json:"sz,string"`
+		Ts   int64   `json:"ts,string"`
+	} `json:"details"`
+}
+
+// toLiquidations convert the contracts to the basis amount, the unknown instrument is skipped.
+func (swap *Swap) toLiquidations(items []*okLiquidation) []*Liquidation {
+	var liquidations = make([]*Liquidation, 0)
+	for _, item := range items {
+		var pair = NewPair(strings.TrimSuffix(item.InstId, "-SWAP"), "-")
+		var contract = swap.getContract(pair)
+		if contract == nil {
+			continue
+		}
+		for _, detail := range item.Details {
+			var liquidation = &Liquidation{
+				Pair:      pair,
+				Exchange:  OKEX,
+				Symbol:    item.InstId,
+				Side:      SELL,
+				Price:     detail.BkPx,
+				Amount:    swap.getAmount(detail.BkPx, detail.Sz, contract),
+				Timestamp: detail.Ts,
+			}
+			if detail.Side == "buy" {
+				liquidation.Side = BUY
+			}
+			liquidations = append(liquidations, liquidation)
+		}
+	}
+	sort.Slice(liquidations, func(i, j int) bool {
+		return liquidations[i].Timestamp < liquidations[j].Timestamp
+	})
+	return liquidations
+}
+
+// GetLiquidations return the filled liquidations of the underlying, the price is the bankruptcy price.
+func (swap *Swap) GetLiquidations(pair Pair, size int) ([]*Liquidation, []byte, error) {
+	if size <= 0 || size > 100 {
+		size = 100
+	}
+	var params = url.Values{}
+	params.Set("instType", "SWAP")
+	params.Set("uly", pair.ToSymbol("-", true))
+	params.Set("state", "filled")
+	params.Set("limit", strconv.Itoa(size))
+
+	var response struct {
+		Code string           `json:"code"`
+		Msg  string           `json:"msg"`
+		Data []*okLiquidation `json:"data"`
+	}
+	resp, err := swap.DoRequestMarket(
+		http.MethodGet,
+		"/api/v5/public/liquidation-orders?"+params.Encode(),
+		"",
+		&response,
+	)
+	if err != nil {
+		return nil, resp, err
+	}
+	if response.Code != "0" {
+		return nil, resp, errors.New(response.Msg)
+	}
+	return swap.toLiquidations(response.Data), resp, nil
+}
@@ -1 +1,465 @@
 package okex
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	. "github.com/deforceHK/goghostex"
+)
+
+var _INERNAL_V5_TRIGGER_TYPE_CONVERTER = map[TriggerType]string{
+	TRIGGER_LAST:  "last",
+	TRIGGER_MARK:  "mark",
+	TRIGGER_INDEX: "index",
+}
+
+// the algo order is effective means it is triggered, and the order is placed.
+var _INERNAL_V5_ALGO_ORDER_STATUS_CONVERTER = map[string]TradeStatus{
+	"live":                ORDER_UNFINISH,
+	"pause":               ORDER_UNFINISH,
+	"partially_effective": ORDER_PART_FINISH,
+	"effective":           ORDER_FINISH,
+	"canceled":            ORDER_CANCEL,
+	"order_failed":        ORDER_FAIL,
+	"partially_failed":    ORDER_FAIL,
+}
+
+// placeAlgoOrder place the conditional order by the algo api, the OrderId of the order is the algoId.
+// The stop and the take profit of the open order are the trigger orders,
+// they are the stop loss and the take profit orders of the position when liquidate.
+func (swap *Swap) placeAlgoOrder(order *SwapOrder) ([]byte, error) {
+	var contract = swap.getContract(order.Pair)
+	var request = struct {
+		InstId      string `json:"instId"`
+		TdMode      string `json:"tdMode"`
+		Side        string `json:"side"`
+		PosSide     string `json:"posSide,omitempty"`
+		OrdType     string `json:"ordType"`
+		Sz          string `json:"sz,omitempty"`
+		AlgoClOrdId string `json:"algoClOrdId,omitempty"`
+
+		TriggerPx     string `json:"triggerPx,omitempty"`
+		OrderPx       string `json:"orderPx,omitempty"`
+		TriggerPxType string `json:"triggerPxType,omitempty"`
+
+		TpTriggerPx     string `json:"tpTriggerPx,omitempty"`
+		TpOrdPx         string `json:"tpOrdPx,omitempty"`
+		TpTriggerPxType string `json:"tpTriggerPxType,omitempty"`
+		SlTriggerPx     string `json:"slTriggerPx,omitempty"`
+		SlOrdPx         string `json:"slOrdPx,omitempty"`
+		SlTriggerPxType string `json:"slTriggerPxType,omitempty"`
+
+		CallbackRatio string `json:"callbackRatio,omitempty"`
+		ActivePx      string `json:"activePx,omitempty"`
+
+		ReduceOnly    bool   `json:"reduceOnly,omitempty"`
+		CloseFraction string `json:"closeFraction,omitempty"`
+	}{}
+
+	request.InstId = order.Pair.ToSymbol("-", true) + "-SWAP"
+	request.TdMode = swap.tdMode(request.InstId, order.MarginType)
+	request.AlgoClOrdId = order.Cid
+	sideInfo, exist := _INERNAL_V5_FUTURE_TYPE_CONVERTER[order.Type]
+	if !exist {
+		return nil, errors.New("future type not found. ")
+	}
+	positionSide, isLiquidate, err := order.ResolvePosition()
+	if err != nil {
+		return nil, err
+	}
+	request.Side = sideInfo[0]
+	request.PosSide = _INERNAL_V5_POSITION_SIDE_CONVERTER[positionSide]
+	request.ReduceOnly = isLiquidate && positionSide == POSITION_SIDE_BOTH
+	triggerType, exist := _INERNAL_V5_TRIGGER_TYPE_CONVERTER[order.TriggerType]
+	if !exist {
+		return nil, errors.New("trigger type not found. ")
+	}
+	// the close fraction works in the stop loss and the take profit order of the position only.
+	if order.ClosePosition && order.ConditionType == CONDITION_TRAILING_STOP {
+		return nil, errors.New("okex close position not support the trailing stop. ")
+	}
+
+	var normalizer = contract.GetNormalizer()
+	normalizer.ReduceOnly = isLiquidate
+	var isMarket = order.PlaceType == MARKET || order.ConditionType == CONDITION_TRAILING_STOP
+	var price, amount = order.Price, order.Amount
+	if order.ClosePosition {
+		price, err = normalizer.NormalizePrice(order.Price, isMarket)
+		request.CloseFraction = "1"
+	} else {
+		price, amount, err = normalizer.Normalize(order.Price, order.Amount, isMarket)
+		request.Sz = normalizer.FormatAmount(amount)
+	}
+	if err != nil {
+		return nil, err
+	}
+	// -1 means the market price when it is triggered.
+	var orderPx = "-1"
+	if !isMarket {
+		orderPx = normalizer.FormatPrice(price)
+	}
+
+	switch order.ConditionType {
+	case CONDITION_STOP, CONDITION_TAKE_PROFIT:
+		if order.TriggerPrice <= 0 {
+			return nil, errors.New("the trigger price must be positive. ")
+		}
+		var triggerPx = normalizer.FormatPrice(order.TriggerPrice)
+		if !isLiquidate {
+			request.OrdType = "trigger"
+			request.TriggerPx, request.OrderPx, request.TriggerPxType = triggerPx, orderPx, triggerType
+		} else if order.ConditionType == CONDITION_STOP {
+			request.OrdType = "conditional"
+			request.SlTriggerPx, request.SlOrdPx, request.SlTriggerPxType = triggerPx, orderPx, triggerType
+		} else {
+			request.OrdType = "conditional"
+			request.TpTriggerPx, request.TpOrdPx, request.TpTriggerPxType = triggerPx, orderPx, triggerType
+		}
+	case CONDITION_TRAILING_STOP:
+		if order.CallbackRate <= 0 {
+			return nil, errors.New("the callback rate of the trailing stop must be positive. ")
+		}
+		request.OrdType = "move_order_stop"
+		request.CallbackRatio = FloatToString(order.CallbackRate, 4)
+		if order.TriggerPrice > 0 {
+			request.ActivePx = normalizer.FormatPrice(order.TriggerPrice)
+		}
+	default:
+		return nil, fmt.Errorf("the condition type %s is not supported. ", order.ConditionType)
+	}
+
+	var response = struct {
+		Code string `json:"code"`
+		Msg  string `json:"msg"`
+		Data []struct {
+			AlgoClOrdId string `json:"algoClOrdId"`
+			AlgoId      string `json:"algoId"`
+			SCode       string `json:"sCode"`
+			SMsg        string `json:"sMsg"`
+		} `json:"data"`
+	}{}
+	var uri = "/api/v5/trade/order-algo"
+
+	now := time.Now()
+	order.PlaceTimestamp = now.UnixNano() / int64(time.Millisecond)
+	order.PlaceDatetime = now.In(swap.config.Location).Format(GO_BIRTHDAY)
+	reqBody, _, _ := swap.BuildRequestBody(request)
+	resp, err := swap.DoRequest(
+		http.MethodPost,
+		uri,
+		reqBody,
+		&response,
+	)
+	if err != nil {
+		return resp, err
+	}
+	if len(response.Data) > 0 && response.Data[0].SCode != "0" {
+		return resp, errors.New(string(resp))
+	}
+	if response.Code != "0" || len(response.Data) == 0 {
+		return resp, errors.New(string(resp))
+	}
+
+	order.OrderId = response.Data[0].AlgoId
+	order.Status = ORDER_UNFINISH
+	return resp, nil
+}
+
+func (swap *Swap) cancelAlgoOrder(order *SwapOrder) ([]byte, error) {
+	var request = []struct {
+		AlgoId string `json:"algoId"`
+		InstId string `json:"instId"`
+	}{{
+		order.OrderId,
+		order.Pair.ToSymbol("-", true) + "-SWAP",
+	}}
+
+	var response = struct {
+		Code string `json:"code"`
+		Msg  string `json:"msg"`
+		Data []struct {
+			AlgoId string `json:"algoId"`
+			SCode  string `json:"sCode"`
+			SMsg   string `json:"sMsg"`
+		} `json:"data"`
+	}{}
+
+	var uri = "/api/v5/trade/cancel-algos"
+	reqBody, _, _ := swap.BuildRequestBody(request)
+	resp, err := swap.DoRequest(
+		http.MethodPost,
+		uri,
+		reqBody,
+		&response,
+	)
+	if err != nil {
+		return resp, err
+	}
+	if len(response.Data) == 0 {
+		return resp, errors.New("request lack the data. ")
+	}
+	if response.Data[0].SCode != "0" {
+		return resp, errors.New(response.Data[0].SMsg)
+	}
+	return resp, nil
+}
+
+func (swap *Swap) getAlgoOrder(order *SwapOrder) ([]byte, error) {
+	var params = url.Values{}
+	if order.OrderId != "" {
+		params.Set("algoId", order.OrderId)
+	} else {
+		params.Set("algoClOrdId", order.Cid)
+	}
+
+	var response = struct {
+		Code string `json:"code"`
+		Msg  string `json:"msg"`
+		Data []struct {
+			AlgoId      string  `json:"algoId"`
+			AlgoClOrdId string  `json:"algoClOrdId"`
+			Sz          float64 `json:"sz,string"`
+			State       string  `json:"state"`
+			ActualPx    string  `json:"actualPx"`
+			ActualSz    string  `json:"actualSz"`
+			Lever       string  `json:"lever"`
+			UTime       int64   `json:"uTime,string"`
+			CTime       int64   `json:"cTime,string"`
+		} `json:"data"`
+	}{}
+	var uri = "/api/v5/trade/order-algo?"
+
+	resp, err := swap.DoRequest(
+		http.MethodGet,
+		uri+params.Encode(),
+		"",
+		&response,
+	)
+	if err != nil {
+		return resp, err
+	}
+	if response.Code != "0" {
+		return resp, errors.New(response.Msg)
+	}
+	if len(response.Data) == 0 {
+		return resp, errors.New("the algo order not found. ")
+	}
+
+	var data = response.Data[0]
+	if status, exist := _INERNAL_V5_ALGO_ORDER_STATUS_CONVERTER[data.State]; exist {
+		order.Status = status
+	}
+	order.OrderId = data.AlgoId
+	order.Cid = data.AlgoClOrdId
+	order.Amount = data.Sz
+	order.LeverRate = ToInt64(data.Lever)
+	// the price and the amount of the triggered order.
+	if data.ActualSz != "" {
+		order.AvgPrice = ToFloat64(data.ActualPx)
+		order.DealAmount = ToFloat64(data.ActualSz)
+	}
+
+	order.PlaceTimestamp = data.CTime
+	order.PlaceDatetime = time.Unix(
+		order.PlaceTimestamp/1000, 0,
+	).In(swap.config.Location).Format(GO_BIRTHDAY)
+	order.DealTimestamp = data.UTime
+	order.DealDatetime = time.Unix(
+		order.DealTimestamp/1000, 0,
+	).In(swap.config.Location).Format(GO_BIRTHDAY)
+	return resp, nil
+}
+
+// swapCancelRequest is the order in the cancel batch api, the ordId is first if both of them are set.
+type swapCancelRequest struct {
+	InstId  string `json:"instId"`
+	OrdId   string `json:"ordId,omitempty"`
+	ClOrdId string `json:"clOrdId,omitempty"`
+}
+
+// BatchPlaceOrders place the orders by the batch-orders api, the conditional orders are placed one by one.
+func (swap *Swap) BatchPlaceOrders(orders []*SwapOrder) ([]error, []byte, error) {
+	var errs = make([]error, len(orders))
+	var indexes = make([]int, 0, len(orders))
+	var requests = make([]interface{}, 0, len(orders))
+	var responses = make([][]byte, 0)
+	for i, order := range orders {
+		if order.ConditionType != CONDITION_NONE {
+			resp, err := swap.placeAlgoOrder(order)
+			if len(resp) > 0 {
+				responses = append(responses, resp)
+			}
+			errs[i] = err
+			continue
+		}
+		request, err := swap.placeRequest(order)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		indexes = append(indexes, i)
+		requests = append(requests, request)
+	}
+
+	var now = time.Now()
+	results, batchErrs, resp := swap.doBatchRequest("/api/v5/trade/batch-orders", requests)
+	if len(resp) > 0 {
+		responses = append(responses, resp)
+	}
+	var dealTime = time.Now()
+	for j, i := range indexes {
+		if batchErrs[j] != nil {
+			errs[i] = batchErrs[j]
+			continue
+		}
+		orders[i].OrderId = results[j].OrdId
+		orders[i].PlaceTimestamp = now.UnixNano() / int64(time.Millisecond)
+		orders[i].PlaceDatetime = now.In(swap.config.Location).Format(GO_BIRTHDAY)
+		orders[i].DealTimestamp = dealTime.UnixNano() / int64(time.Millisecond)
+		orders[i].DealDatetime = dealTime.In(swap.config.Location).Format(GO_BIRTHDAY)
+	}
+	return errs, BatchResponse(responses), BatchResult(errs)
+}
+
+// BatchCancelOrders cancel the orders by the cancel-batch-orders api, the conditional orders are canceled one by one.
+func (swap *Swap) BatchCancelOrders(orders []*SwapOrder) ([]error, []byte, error) {
+	var errs = make([]error, len(orders))
+	var indexes = make([]int, 0, len(orders))
+	var requests = make([]interface{}, 0, len(orders))
+	var responses = make([][]byte, 0)
+	for i, order := range orders {
+		if order.ConditionType != CONDITION_NONE {
+			resp, err := swap.cancelAlgoOrder(order)
+			if len(resp) > 0 {
+				responses = append(responses, resp)
+			}
+			errs[i] = err
+			continue
+		}
+		if order.OrderId == "" && order.Cid == "" {
+			errs[i] = errors.New("The orderid and cid is empty. ")
+			continue
+		}
+		indexes = append(indexes, i)
+		requests = append(requests, &swapCancelRequest{
+			InstId:  order.Pair.ToSymbol("-", true) + "-SWAP",
+			OrdId:   order.OrderId,
+			ClOrdId: order.Cid,
+		})
+	}
+
+	_, batchErrs, resp := swap.doBatchRequest("/api/v5/trade/cancel-batch-orders", requests)
+	if len(resp) > 0 {
+		responses = append(responses, resp)
+	}
+	for j, i := range indexes {
+		errs[i] = batchErrs[j]
+	}
+	return errs, BatchResponse(responses), BatchResult(errs)
+}
+
+// CancelAllOrders cancel all the normal orders of the pair, the conditional orders are not included.
+func (swap *Swap) CancelAllOrders(pair Pair) ([]byte, error) {
+	return swap.cancelAll("SWAP", pair.ToSymbol("-", true)+"-SWAP")
+}
+
+// AmendOrder modify the price and the amount of the normal order, the conditional order is not supported.
+func (swap *Swap) AmendOrder(order *SwapOrder, newPrice, newAmount float64) ([]byte, error) {
+	if order.ConditionType != CONDITION_NONE {
+		return nil, errors.New("the conditional order can not be amended. ")
+	}
+	if order.OrderId == "" && order.Cid == "" {
+		return nil, errors.New("The orderid and cid is empty. ")
+	}
+	if newPrice <= 0 {
+		newPrice = order.Price
+	}
+	if newAmount <= 0 {
+		newAmount = order.Amount
+	}
+
+	var normalizer = swap.getContract(order.Pair).GetNormalizer()
+	normalizer.ReduceOnly = IsReduce(order.Type, order.ReduceOnly)
+	price, amount, err := normalizer.Normalize(newPrice, newAmount, order.PlaceType == MARKET)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := swap.amendOrder(
+		order.Pair.ToSymbol("-", true)+"-SWAP",
+		order.OrderId,
+		order.Cid,
+		normalizer.FormatAmount(amount),
+		normalizer.FormatPrice(price),
+	)
+	if err != nil {
+		return resp, err
+	}
+	order.Price, order.Amount = price, amount
+	return resp, nil
+}
+
+// closePosition close the whole position at the market price by the close-position api,
+// there is no order id in the response, the order is found by the cid.
+func (swap *Swap) closePosition(order *SwapOrder) ([]byte, error) {
+	positionSide, _, err := order.ResolvePosition()
+	if err != nil {
+		return nil, err
+	}
+	var request = struct {
+		InstId  string `json:"instId"`
+		PosSide string `json:"posSide"`
+		MgnMode string `json:"mgnMode"`
+		ClOrdId string `json:"clOrdId,omitempty"`
+		AutoCxl bool   `json:"autoCxl"`
+	}{
+		InstId:  order.Pair.ToSymbol("-", true) + "-SWAP",
+		PosSide: _INERNAL_V5_POSITION_SIDE_CONVERTER[positionSide],
+		MgnMode: swap.tdMode(order.Pair.ToSymbol("-", true)+"-SWAP", order.MarginType),
+		ClOrdId: order.Cid,
+		// the pending close orders make the close fail, cancel them.
+		AutoCxl: true,
+	}
+
+	var response = struct {
+		Code string `json:"code"`
+		Msg  string `json:"msg"`
+	}{}
+
+	now := time.Now()
+	order.PlaceTimestamp = now.UnixNano() / int64(time.Millisecond)
+	order.PlaceDatetime = now.In(swap.config.Location).Format(GO_BIRTHDAY)
+	reqBody, _, _ := swap.BuildRequestBody(request)
+	resp, err := swap.DoRequest(
+		http.MethodPost,
+		"/api/v5/trade/close-position",
+		reqBody,
+		&response,
+	)
+	if err != nil {
+		return resp, err
+	}
+	if response.Code != "0" {
+		return resp, errors.New(string(resp))
+	}
+	return resp, nil
+}
+
+func (swap *Swap) SetLeverage(pair Pair, lever int64, side PositionSide) ([]byte, error) {
+	return swap.setLeverage(pair.ToSymbol("-", true)+"-SWAP", lever, side)
+}
+
+func (swap *Swap) GetLeverage(pair Pair, side PositionSide) (int64, []byte, error) {
+	return swap.getLeverage(pair.ToSymbol("-", true)+"-SWAP", side)
+}
+
+// SetMarginMode change the td mode of the later orders and the leverage api, there is no remote call.
+func (swap *Swap) SetMarginMode(pair Pair, marginType string) ([]byte, error) {
+	return nil, swap.setMarginMode(pair.ToSymbol("-", true)+"-SWAP", marginType)
+}
+
+func (swap *Swap) GetLeverageBrackets(pair Pair) ([]*LeverageBracket, []byte, error) {
+	return swap.getLeverageBrackets(pair, "SWAP", pair.ToSymbol("-", true)+"-SWAP")
+}
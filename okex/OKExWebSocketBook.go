@@ -3,7 +3,6 @@ package okex
 import (
 	"encoding/json"
 	"fmt"
-	"log"
 	"sort"
 	"strconv"
 	"sync"
@@ -100,11 +99,14 @@ func (this *LocalOrderBooks) Receiver(msg string) {
 			this.TsData[instId] = timestamp
 		} else {
 			if prevSeqId != this.SeqData[instId] {
-				log.Println(fmt.Sprintf(
-					"The prevSeqId %d is not equal to the last seqId %d, in product %s. ",
-					prevSeqId, this.SeqData[instId], instId,
-				))
+				this.logger().Warn(
+					"The prevSeqId is not equal to the last seqId, resubscribe the book. ",
+					LogF(LOG_FIELD_INSTRUMENT, instId),
+					LogF("prev_seq_id", prevSeqId),
+					LogF("last_seq_id", this.SeqData[instId]),
+				)
 				this.OrderBookMux.Unlock()
+				this.Config.GetMetrics().IncBookResync(OKEX, instId)
 				this.Resubscribe(instId)
 				return
 			}
@@ -136,8 +138,7 @@ func (this *LocalOrderBooks) Receiver(msg string) {
 			}
 		}
 	} else {
-		fmt.Println(msg)
-		fmt.Println("The action must in snapshot/update. ")
+		this.logger().Debug("The action must in snapshot/update. ", LogF("msg", msg))
 	}
 	this.OrderBookMux.Unlock()
 }
@@ -182,6 +183,7 @@ func (this *LocalOrderBooks) Snapshot(pair Pair) (*SwapDepth, error) {
 	var productId = fmt.Sprintf("%s-SWAP", symbol)
 
 	var lastTime = time.UnixMilli(this.TsData[productId]).In(this.WSMarketOKEx.Config.Location)
+	this.Config.GetMetrics().SetBookStaleness(OKEX, productId, time.Since(lastTime))
 	var depth = &SwapDepth{
 		Pair:      pair,
 		Timestamp: this.TsData[productId],
@@ -293,6 +295,7 @@ func (this *LocalOrderBooks) SnapshotById(productId string) (*Depth, error) {
 	defer this.OrderBookMux.RUnlock()
 
 	var lastTime = time.UnixMilli(this.TsData[productId]).In(this.WSMarketOKEx.Config.Location)
+	this.Config.GetMetrics().SetBookStaleness(OKEX, productId, time.Since(lastTime))
 	var depth = &Depth{
 		Timestamp: this.TsData[productId],
 		Sequence:  this.SeqData[productId],
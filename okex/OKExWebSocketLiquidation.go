@@ -0,0 +1,65 @@
+package okex
+
+import (
+	"encoding/json"
+
+	. "github.com/deforceHK/goghostex"
+)
+
+// Liquidations is the typed liquidation-orders stream of the swap, the Swap convert the contracts to the basis amount.
+type Liquidations struct {
+	*WSMarketOKEx
+	Swap               *Swap // not necessary, it is built from the Config if nil
+	LiquidationHandler func(liquidation *Liquidation)
+}
+
+func (this *Liquidations) Init() error {
+	if this.Swap == nil {
+		this.Swap = New(this.Config).Swap
+	}
+	if this.LiquidationHandler == nil {
+		this.LiquidationHandler = func(liquidation *Liquidation) {
+			this.logger().Debug("receive liquidation", LogF("liquidation", *liquidation))
+		}
+	}
+	this.WSMarketOKEx.RecvHandler = func(s string) {
+		this.Receiver(s)
+	}
+	return this.Start()
+}
+
+// Subscribe the liquidations of all the swaps, okex has no liquidation channel of one instrument.
+func (this *Liquidations) Subscribe() {
+	this.WSMarketOKEx.Subscribe(WSOpOKEx{
+		Op:   "subscribe",
+		Args: []map[string]string{{"channel": "liquidation-orders", "instType": "SWAP"}},
+	})
+}
+
+func (this *Liquidations) Unsubscribe() {
+	this.WSMarketOKEx.Unsubscribe(WSOpOKEx{
+		Op:   "unsubscribe",
+		Args: []map[string]string{{"channel": "liquidation-orders", "instType": "SWAP"}},
+	})
+}
+
+func (this *Liquidations) Receiver(msg string) {
+	var response = struct {
+		Arg struct {
+			Channel string `json:"channel"`
+		} `json:"arg"`
+		Data []*okLiquidation `json:"data"`
+	}{}
+	if err := json.Unmarshal([]byte(msg), &response); err != nil {
+		this.ErrorHandler(err)
+		return
+	}
+	if response.Arg.Channel != "liquidation-orders" || len(response.Data) == 0 {
+		this.logger().Debug("receive message", LogF("msg", msg))
+		return
+	}
+
+	for _, liquidation := range this.Swap.toLiquidations(response.Data) {
+		this.LiquidationHandler(liquidation)
+	}
+}
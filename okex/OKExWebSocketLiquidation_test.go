@@ -0,0 +1,40 @@
+package okex
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/deforceHK/goghostex"
+)
+
+// go test -v ./okex/... -count=1 -run=TestLiquidations_Receiver
+func TestLiquidations_Receiver(t *testing.T) {
+	var config = &APIConfig{Location: time.UTC}
+	var swap = New(config).Swap
+	swap.swapContracts = SwapContracts{ContractNameKV: map[string]*SwapContract{
+		"BTC-USD-SWAP": {Pair: Pair{Basis: BTC, Counter: USD}, SettleMode: SETTLE_MODE_BASIS, UnitAmount: 100},
+	}}
+	swap.nextUpdateContractTime = time.Now().Add(time.Hour)
+
+	var received []*Liquidation
+	var liquidations = &Liquidations{
+		WSMarketOKEx: &WSMarketOKEx{Config: config},
+		Swap:         swap,
+		LiquidationHandler: func(liquidation *Liquidation) {
+			received = append(received, liquidation)
+		},
+	}
+
+	liquidations.Receiver(`{"arg":{"channel":"liquidation-orders","instType":"SWAP"},"data":[` +
+		`{"instId":"BTC-USD-SWAP","details":[{"side":"buy","bkPx":"50000","sz":"20","ts":"1640000000002"},` +
+		`{"side":"sell","bkPx":"40000","sz":"4","ts":"1640000000001"}]},` +
+		`{"instId":"DOGE-USD-SWAP","details":[{"side":"sell","bkPx":"0.1","sz":"1","ts":"1640000000003"}]}]}`)
+	if len(received) != 2 {
+		t.Error("the liquidations are wrong: ", len(received))
+		return
+	}
+	if received[0].Side != SELL || received[0].Amount != 0.01 || received[0].Timestamp != 1640000000001 ||
+		received[1].Side != BUY || received[1].Price != 50000 || received[1].Amount != 0.04 {
+		t.Error("the liquidation is wrong: ", *received[0], *received[1])
+	}
+}
@@ -3,7 +3,6 @@ package okex
 import (
 	"encoding/json"
 	"fmt"
-	"log"
 	"strings"
 	"time"
 
@@ -16,6 +15,7 @@ type WSMarketOKEx struct {
 	RecvHandler  func(string)
 	ErrorHandler func(error)
 	Config       *APIConfig
+	DialerConfig *WsDialerConfig // the proxy, tls and local address setting of the connection, not necessary
 
 	conn   *websocket.Conn
 	connId string
@@ -67,6 +67,7 @@ func (this *WSMarketOKEx) Stop() {
 }
 
 func (this *WSMarketOKEx) Restart() {
+	this.Config.GetMetrics().IncWSReconnect(OKEX, "WSMarketOKEx")
 	this.ErrorHandler(
 		&WSRestartError{Msg: fmt.Sprintf("websocket will restart in next %d seconds...", this.restartSec)},
 	)
@@ -129,6 +130,7 @@ func (this *WSMarketOKEx) startCheck() error {
 				restartNum, this.restartLimitSec,
 			),
 		}
+		this.Config.GetMetrics().IncWSRestartLimit(OKEX, "WSMarketOKEx")
 		return wsErr
 	}
 	return nil
@@ -136,7 +138,11 @@ func (this *WSMarketOKEx) startCheck() error {
 
 func (this *WSMarketOKEx) getConn(wss string) (*websocket.Conn, error) {
 	this.initDefaultValue()
-	var conn, _, err = websocket.DefaultDialer.Dial(
+	var dialer, dialErr = NewWsDialer(this.DialerConfig)
+	if dialErr != nil {
+		return nil, dialErr
+	}
+	var conn, _, err = dialer.Dial(
 		wss,
 		nil,
 	)
@@ -155,12 +161,12 @@ func (this *WSMarketOKEx) getConn(wss string) (*websocket.Conn, error) {
 func (this *WSMarketOKEx) initDefaultValue() {
 	if this.RecvHandler == nil {
 		this.RecvHandler = func(msg string) {
-			log.Println(msg)
+			this.logger().Debug("receive message", LogF("msg", msg))
 		}
 	}
 	if this.ErrorHandler == nil {
 		this.ErrorHandler = func(err error) {
-			log.Println(err)
+			this.logger().Error("websocket error", LogF(LOG_FIELD_ERROR, err))
 		}
 	}
 	if this.restartSec == 0 {
@@ -194,7 +200,7 @@ func (this *WSMarketOKEx) pingRoutine() {
 				continue
 			}
 			if err := conn.WriteMessage(websocket.TextMessage, []byte("ping")); err != nil {
-				fmt.Println(err)
+				this.logger().Warn("ping error", LogF(LOG_FIELD_ERROR, err))
 			}
 		case _, opened := <-stopPingChn:
 			if opened {
@@ -239,7 +245,7 @@ func (this *WSMarketOKEx) recvRoutine() {
 		if readErr != nil {
 			// conn closed by user.
 			if strings.Index(readErr.Error(), "use of closed network connection") > 0 {
-				fmt.Println("conn closed by user. ")
+				this.logger().Info("conn closed by user")
 				return
 			}
 			this.ErrorHandler(readErr)
@@ -252,9 +258,17 @@ func (this *WSMarketOKEx) recvRoutine() {
 		}
 
 		this.lastPingTS = time.Now().Unix()
+		this.Config.GetMetrics().IncWSMessage(OKEX, "WSMarketOKEx")
 		var msgStr = string(msg)
 		if msgStr != "pong" {
 			this.RecvHandler(msgStr)
 		}
 	}
 }
+
+func (this *WSMarketOKEx) logger() Logger {
+	return this.Config.GetLogger().With(
+		LogF(LOG_FIELD_EXCHANGE, OKEX),
+		LogF(LOG_FIELD_CONN_ID, this.connId),
+	)
+}
@@ -0,0 +1,110 @@
+package okex
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	. "github.com/deforceHK/goghostex"
+)
+
+// OptionTickers is the typed opt-summary and mark-price stream of the option, the greeks and the mark price of
+// the same instId are merged, the handler receive the latest ticker of the instId in every push.
+type OptionTickers struct {
+	*WSMarketOKEx
+	Option        *Option
+	TickerHandler func(ticker *OptionTicker)
+
+	tickers sync.Map // the instId to the latest *OptionTicker
+}
+
+func (this *OptionTickers) Init() error {
+	if this.TickerHandler == nil {
+		this.TickerHandler = func(ticker *OptionTicker) {
+			this.logger().Debug("receive option ticker", LogF("ticker", *ticker))
+		}
+	}
+	this.WSMarketOKEx.RecvHandler = func(s string) {
+		this.Receiver(s)
+	}
+	return this.Start()
+}
+
+// SubscribeSummary subscribe the greeks and the implied volatility of all the options of the pair.
+func (this *OptionTickers) SubscribeSummary(pair Pair) {
+	this.WSMarketOKEx.Subscribe(WSOpOKEx{
+		Op:   "subscribe",
+		Args: []map[string]string{{"channel": "opt-summary", "instFamily": pair.ToSymbol("-", true)}},
+	})
+}
+
+// SubscribeMarkPrice subscribe the mark price of the instId, okex has no mark price channel of the family.
+func (this *OptionTickers) SubscribeMarkPrice(symbol string) {
+	this.WSMarketOKEx.Subscribe(WSOpOKEx{
+		Op:   "subscribe",
+		Args: []map[string]string{{"channel": "mark-price", "instId": symbol}},
+	})
+}
+
+func (this *OptionTickers) Receiver(msg string) {
+	var response = struct {
+		Arg struct {
+			Channel string `json:"channel"`
+		} `json:"arg"`
+		Data json.RawMessage `json:"data"`
+	}{}
+	if err := json.Unmarshal([]byte(msg), &response); err != nil {
+		this.ErrorHandler(err)
+		return
+	}
+
+	switch response.Arg.Channel {
+	case "opt-summary":
+		var summaries = make([]*okOptionSummary, 0)
+		if err := json.Unmarshal(response.Data, &summaries); err != nil {
+			this.ErrorHandler(err)
+			return
+		}
+		for _, summary := range summaries {
+			var ticker = this.getTicker(summary.InstId)
+			var markPrice = ticker.MarkPrice
+			*ticker = *this.Option.summaryTicker(ticker.Pair, summary)
+			ticker.MarkPrice = markPrice
+			this.emit(ticker)
+		}
+	case "mark-price":
+		var marks = make([]struct {
+			InstId string  `json:"instId"`
+			MarkPx float64 `json:"markPx,string"`
+			Ts     int64   `json:"ts,string"`
+		}, 0)
+		if err := json.Unmarshal(response.Data, &marks); err != nil {
+			this.ErrorHandler(err)
+			return
+		}
+		for _, mark := range marks {
+			var ticker = this.getTicker(mark.InstId)
+			ticker.MarkPrice = mark.MarkPx
+			ticker.Timestamp = mark.Ts
+			ticker.Date = time.Unix(mark.Ts/1000, 0).In(this.Option.config.Location).Format(GO_BIRTHDAY)
+			this.emit(ticker)
+		}
+	default:
+		this.logger().Debug("receive message", LogF("msg", msg))
+	}
+}
+
+// getTicker return the latest ticker of the instId, the handler receive the copy of it.
+func (this *OptionTickers) getTicker(symbol string) *OptionTicker {
+	var ticker, _ = this.tickers.LoadOrStore(symbol, &OptionTicker{
+		Pair:     this.Option.symbolPair(symbol),
+		Symbol:   symbol,
+		Exchange: OKEX,
+	})
+	return ticker.(*OptionTicker)
+}
+
+func (this *OptionTickers) emit(ticker *OptionTicker) {
+	var copied = *ticker
+	this.TickerHandler(&copied)
+}
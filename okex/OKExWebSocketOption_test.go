@@ -0,0 +1,41 @@
+package okex
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/deforceHK/goghostex"
+)
+
+// go test -v ./okex/... -count=1 -run=TestOptionTickers_Receiver
+func TestOptionTickers_Receiver(t *testing.T) {
+	var received []*OptionTicker
+	var tickers = &OptionTickers{
+		WSMarketOKEx: &WSMarketOKEx{Config: &APIConfig{}},
+		Option:       New(&APIConfig{Location: time.UTC}).Option,
+		TickerHandler: func(ticker *OptionTicker) {
+			received = append(received, ticker)
+		},
+	}
+
+	tickers.Receiver(`{"arg":{"channel":"mark-price","instId":"BTC-USD-241227-60000-C"},"data":[` +
+		`{"instType":"OPTION","instId":"BTC-USD-241227-60000-C","markPx":"0.0525","ts":"1700000000000"}]}`)
+	tickers.Receiver(`{"arg":{"channel":"opt-summary","instFamily":"BTC-USD"},"data":[` +
+		`{"instType":"OPTION","instId":"BTC-USD-241227-60000-C","uly":"BTC-USD","delta":"0.4","gamma":"1.2",` +
+		`"vega":"0.001","theta":"-0.0002","deltaBS":"0.55","gammaBS":"0.00003","vegaBS":"120.5","thetaBS":"-35.2",` +
+		`"lever":"18","markVol":"0.52","bidVol":"0.51","askVol":"","realVol":"","fwdPx":"61234.5","ts":"1700000001000"}]}`)
+	if len(received) != 2 {
+		t.Error("the tickers are wrong: ", len(received))
+		return
+	}
+	var ticker = received[1]
+	if ticker.MarkPrice != 0.0525 || ticker.MarkIV != 0.52 || ticker.BuyIV != 0.51 || ticker.SellIV != 0 {
+		t.Error("the ticker is wrong: ", *ticker)
+	}
+	if ticker.Greeks.Delta != 0.55 || ticker.Greeks.Vega != 120.5 || ticker.UnderlyingPrice != 61234.5 {
+		t.Error("the greeks are wrong: ", ticker.Greeks)
+	}
+	if ticker.Pair.ToSymbol("_", false) != "btc_usd" || received[0].Timestamp != 1700000000000 {
+		t.Error("the pair or the timestamp is wrong: ", *received[0])
+	}
+}
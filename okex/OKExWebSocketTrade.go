@@ -3,7 +3,6 @@ package okex
 import (
 	"encoding/json"
 	"fmt"
-	"log"
 	"strings"
 	"time"
 
@@ -43,6 +42,7 @@ type WSTradeOKEx struct {
 	RecvHandler  func(string)
 	ErrorHandler func(error)
 	Config       *APIConfig
+	DialerConfig *WsDialerConfig // the proxy, tls and local address setting of the connection, not necessary
 
 	conn   *websocket.Conn
 	connId string
@@ -172,7 +172,7 @@ func (this *WSTradeOKEx) pingRoutine() {
 				continue
 			}
 			if err := conn.WriteMessage(websocket.TextMessage, []byte("ping")); err != nil {
-				fmt.Println(err)
+				this.logger().Warn("ping error", LogF(LOG_FIELD_ERROR, err))
 			}
 		case _, opened := <-stopPingChn:
 			if opened {
@@ -217,7 +217,7 @@ func (this *WSTradeOKEx) recvRoutine() {
 		if readErr != nil {
 			// conn closed by user.
 			if strings.Index(readErr.Error(), "use of closed network connection") > 0 {
-				fmt.Println("conn closed by user. ")
+				this.logger().Info("conn closed by user")
 				return
 			}
 			this.ErrorHandler(readErr)
@@ -230,6 +230,7 @@ func (this *WSTradeOKEx) recvRoutine() {
 		}
 
 		this.lastPingTS = time.Now().Unix()
+		this.Config.GetMetrics().IncWSMessage(OKEX, "WSTradeOKEx")
 		var msgStr = string(msg)
 		if msgStr != "pong" {
 			this.RecvHandler(msgStr)
@@ -255,6 +256,7 @@ func (this *WSTradeOKEx) Stop() {
 }
 
 func (this *WSTradeOKEx) Restart() {
+	this.Config.GetMetrics().IncWSReconnect(OKEX, "WSTradeOKEx")
 	this.ErrorHandler(
 		&WSRestartError{Msg: fmt.Sprintf("websocket will restart in next %d seconds...", this.restartSec)},
 	)
@@ -283,12 +285,12 @@ func (this *WSTradeOKEx) Restart() {
 func (this *WSTradeOKEx) initDefaultValue() {
 	if this.RecvHandler == nil {
 		this.RecvHandler = func(msg string) {
-			log.Println(msg)
+			this.logger().Debug("receive message", LogF("msg", msg))
 		}
 	}
 	if this.ErrorHandler == nil {
 		this.ErrorHandler = func(err error) {
-			log.Println(err)
+			this.logger().Error("websocket error", LogF(LOG_FIELD_ERROR, err))
 		}
 	}
 	if this.restartSec == 0 {
@@ -323,6 +325,7 @@ func (this *WSTradeOKEx) startCheck() error {
 				restartNum, this.restartLimitSec,
 			),
 		}
+		this.Config.GetMetrics().IncWSRestartLimit(OKEX, "WSTradeOKEx")
 		return wsErr
 	}
 	return nil
@@ -330,7 +333,11 @@ func (this *WSTradeOKEx) startCheck() error {
 
 func (this *WSTradeOKEx) getConn(wss string) (*websocket.Conn, error) {
 	this.initDefaultValue()
-	var conn, _, err = websocket.DefaultDialer.Dial(
+	var dialer, dialErr = NewWsDialer(this.DialerConfig)
+	if dialErr != nil {
+		return nil, dialErr
+	}
+	var conn, _, err = dialer.Dial(
 		wss,
 		nil,
 	)
@@ -345,3 +352,10 @@ func (this *WSTradeOKEx) getConn(wss string) (*websocket.Conn, error) {
 	}
 	return conn, nil
 }
+
+func (this *WSTradeOKEx) logger() Logger {
+	return this.Config.GetLogger().With(
+		LogF(LOG_FIELD_EXCHANGE, OKEX),
+		LogF(LOG_FIELD_CONN_ID, this.connId),
+	)
+}
@@ -0,0 +1,506 @@
+package oms
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"reflect"
+	"sync"
+	"time"
+
+	. "github.com/deforceHK/goghostex"
+)
+
+const (
+	SOURCE_LOCAL     = "local"     // the order placed or canceled by the oms
+	SOURCE_REST      = "rest"      // the order queried from the rest api
+	SOURCE_WEBSOCKET = "websocket" // the order pushed by the private websocket stream
+
+	DEFAULT_RECONCILE_SEC  = 30
+	DEFAULT_MAX_QUERY_FAIL = 5
+)
+
+// TrackedOrder is the local state of the order, both the swap order and the spot order are kept in it.
+type TrackedOrder struct {
+	Cid        string
+	OrderId    string
+	Pair       Pair
+	TradeType  string // TRADE_TYPE_SWAP or TRADE_TYPE_SPOT
+	Status     TradeStatus
+	Price      float64
+	Amount     float64
+	AvgPrice   float64
+	DealAmount float64
+	Fee        float64
+
+	Source          string // the source of the last update
+	UpdateTimestamp int64  // unit: ms, the local time of the last update
+}
+
+// OrderDrift is the difference between the local order and the exchange order, found in the reconciliation.
+type OrderDrift struct {
+	Local  TrackedOrder
+	Remote TrackedOrder
+	Reason string
+}
+
+// book keep the tracked orders by cid and order id.
+type book struct {
+	mux       sync.RWMutex
+	byCid     map[string]*TrackedOrder
+	byOrderId map[string]*TrackedOrder
+	pairs     map[string]Pair
+	fails     map[*TrackedOrder]int // the continuous query failures of the open orders
+}
+
+func newBook() *book {
+	return &book{
+		byCid:     make(map[string]*TrackedOrder),
+		byOrderId: make(map[string]*TrackedOrder),
+		pairs:     make(map[string]Pair),
+		fails:     make(map[*TrackedOrder]int),
+	}
+}
+
+func (this *book) find(cid, orderId string) *TrackedOrder {
+	if cid != "" {
+		if order, exist := this.byCid[cid]; exist {
+			return order
+		}
+	}
+	if orderId != "" {
+		if order, exist := this.byOrderId[orderId]; exist {
+			return order
+		}
+	}
+	return nil
+}
+
+func (this *book) index(order *TrackedOrder) {
+	if order.Cid != "" {
+		this.byCid[order.Cid] = order
+	}
+	if order.OrderId != "" {
+		this.byOrderId[order.OrderId] = order
+	}
+	this.pairs[order.Pair.String()] = order.Pair
+}
+
+// merge the update into the tracked order, it returns the copy of the merged order,
+// whether the order is new to the book, whether it turns into the final status, and the drift reason if any.
+// The out-of-order update is handled by the status machine and the monotonic deal amount:
+// the stale status is ignored, but the bigger deal amount in it is still taken.
+func (this *book) merge(update TrackedOrder) (merged TrackedOrder, isNew, isFinal bool, drift string) {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+
+	update.UpdateTimestamp = time.Now().UnixMilli()
+	var order = this.find(update.Cid, update.OrderId)
+	if order == nil {
+		var added = update
+		this.index(&added)
+		return added, true, added.Status.IsFinal(), ""
+	}
+
+	var wasFinal = order.Status.IsFinal()
+	if order.OrderId == "" && update.OrderId != "" {
+		order.OrderId = update.OrderId
+	}
+	if order.Cid == "" && update.Cid != "" {
+		order.Cid = update.Cid
+	}
+	this.index(order)
+
+	if update.DealAmount > order.DealAmount {
+		order.DealAmount = update.DealAmount
+		if update.AvgPrice > 0 {
+			order.AvgPrice = update.AvgPrice
+		}
+		if update.Fee != 0 {
+			order.Fee = update.Fee
+		}
+	}
+	if order.Price == 0 {
+		order.Price = update.Price
+	}
+	if order.Amount == 0 {
+		order.Amount = update.Amount
+	}
+
+	if order.Status.CanTransitTo(update.Status) {
+		order.Status = update.Status
+		order.Source = update.Source
+		order.UpdateTimestamp = update.UpdateTimestamp
+	} else if wasFinal && order.Status != update.Status {
+		// the final status never change, the different final status means the local state is wrong.
+		drift = fmt.Sprintf(
+			"the order is %s in local, but the %s says %s", order.Status, update.Source, update.Status,
+		)
+		// the rest query is the truth of the final status.
+		if update.Source == SOURCE_REST && update.Status.IsFinal() {
+			order.Status = update.Status
+			order.Source = update.Source
+			order.UpdateTimestamp = update.UpdateTimestamp
+		}
+	}
+	// else: the stale update, such as the rest unfinish arrive after the websocket part_finish, ignore it.
+
+	return *order, false, !wasFinal && order.Status.IsFinal(), drift
+}
+
+func (this *book) get(cid, orderId string) (TrackedOrder, bool) {
+	this.mux.RLock()
+	defer this.mux.RUnlock()
+	var order = this.find(cid, orderId)
+	if order == nil {
+		return TrackedOrder{}, false
+	}
+	return *order, true
+}
+
+func (this *book) open(pair *Pair) []TrackedOrder {
+	this.mux.RLock()
+	defer this.mux.RUnlock()
+	var orders = make([]TrackedOrder, 0)
+	var seen = make(map[*TrackedOrder]bool)
+	for _, kv := range []map[string]*TrackedOrder{this.byCid, this.byOrderId} {
+		for _, order := range kv {
+			if seen[order] || order.Status.IsFinal() {
+				continue
+			}
+			if pair != nil && order.Pair.String() != pair.String() {
+				continue
+			}
+			seen[order] = true
+			orders = append(orders, *order)
+		}
+	}
+	return orders
+}
+
+func (this *book) trackedPairs() []Pair {
+	this.mux.RLock()
+	defer this.mux.RUnlock()
+	var pairs = make([]Pair, 0, len(this.pairs))
+	for _, pair := range this.pairs {
+		pairs = append(pairs, pair)
+	}
+	return pairs
+}
+
+// forget remove the final orders which updated before the timestamp, keep the book small.
+// The pair is removed too once no tracked order references it.
+func (this *book) forget(beforeTS int64) {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	for key, order := range this.byCid {
+		if order.Status.IsFinal() && order.UpdateTimestamp < beforeTS {
+			delete(this.byCid, key)
+			delete(this.fails, order)
+		}
+	}
+	for key, order := range this.byOrderId {
+		if order.Status.IsFinal() && order.UpdateTimestamp < beforeTS {
+			delete(this.byOrderId, key)
+			delete(this.fails, order)
+		}
+	}
+
+	var pairs = make(map[string]Pair)
+	for _, kv := range []map[string]*TrackedOrder{this.byCid, this.byOrderId} {
+		for _, order := range kv {
+			pairs[order.Pair.String()] = order.Pair
+		}
+	}
+	this.pairs = pairs
+}
+
+// queryFail count the query failure of the order, it returns the continuous failures.
+func (this *book) queryFail(cid, orderId string) int {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	var order = this.find(cid, orderId)
+	if order == nil {
+		return 0
+	}
+	this.fails[order] += 1
+	return this.fails[order]
+}
+
+func (this *book) querySucceed(cid, orderId string) {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	if order := this.find(cid, orderId); order != nil {
+		delete(this.fails, order)
+	}
+}
+
+// revert set the order back to the status if it is still in the from status, it is not a transition of the
+// status machine, but the rollback of the local guess, such as the canceling order which the api refused to cancel.
+func (this *book) revert(cid, orderId string, from, to TradeStatus) {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	var order = this.find(cid, orderId)
+	if order == nil || order.Status != from {
+		return
+	}
+	order.Status = to
+	order.Source = SOURCE_LOCAL
+	order.UpdateTimestamp = time.Now().UnixMilli()
+}
+
+// Tracker is the status machine and the book-keeping shared by SwapOMS and SpotOMS,
+// the market specific part is only the api call.
+type Tracker struct {
+	ReconcileInterval time.Duration // default DEFAULT_RECONCILE_SEC seconds
+	KeepFinal         time.Duration // how long the final orders are kept in the oms, 0 means forever
+	MaxQueryFail      int           // the open order turns into fail after the query fail so many times, default DEFAULT_MAX_QUERY_FAIL
+	// the pairs to find the orphans in the reconciliation besides the tracked pairs, not necessary
+	WatchPairs []Pair
+
+	// the order turns into finish/cancel/reject/fail, not necessary
+	TerminalHandler func(order TrackedOrder)
+	// the order is open in the exchange, but not tracked by the oms, not necessary
+	OrphanHandler func(order TrackedOrder)
+	// the order status in the oms is different from the exchange, not necessary
+	DriftHandler func(drift OrderDrift)
+	ErrorHandler func(err error)
+
+	book     *book
+	stopSign chan bool
+}
+
+func (this *Tracker) Init() {
+	if this.book == nil {
+		this.book = newBook()
+	}
+	if this.ReconcileInterval == 0 {
+		this.ReconcileInterval = DEFAULT_RECONCILE_SEC * time.Second
+	}
+	if this.MaxQueryFail == 0 {
+		this.MaxQueryFail = DEFAULT_MAX_QUERY_FAIL
+	}
+	if this.TerminalHandler == nil {
+		this.TerminalHandler = func(order TrackedOrder) {}
+	}
+	if this.OrphanHandler == nil {
+		this.OrphanHandler = func(order TrackedOrder) {}
+	}
+	if this.DriftHandler == nil {
+		this.DriftHandler = func(drift OrderDrift) {}
+	}
+	if this.ErrorHandler == nil {
+		this.ErrorHandler = func(err error) {
+			GetLogger().Error("oms error", LogF(LOG_FIELD_ERROR, err))
+		}
+	}
+}
+
+func (this *Tracker) Stop() {
+	if this.stopSign != nil {
+		close(this.stopSign)
+		this.stopSign = nil
+	}
+}
+
+func (this *Tracker) GetOrder(cid, orderId string) (TrackedOrder, bool) {
+	this.Init()
+	return this.book.get(cid, orderId)
+}
+
+func (this *Tracker) GetUnFinishOrders(pair Pair) []TrackedOrder {
+	this.Init()
+	return this.book.open(&pair)
+}
+
+// start run the reconcile func in every interval in the background, until the tracker is stopped.
+func (this *Tracker) start(reconcile func()) {
+	this.Init()
+	if this.stopSign != nil {
+		return
+	}
+	this.stopSign = make(chan bool)
+	go func(stop chan bool) {
+		var ticker = time.NewTicker(this.ReconcileInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				reconcile()
+			case <-stop:
+				return
+			}
+		}
+	}(this.stopSign)
+}
+
+// marketAPI is the part of SwapRestAPI and SpotRestAPI used by the oms, O is SwapOrder or Order.
+type marketAPI[O any] interface {
+	PlaceOrder(order *O) ([]byte, error)
+	CancelOrder(order *O) ([]byte, error)
+	GetOrder(order *O) ([]byte, error)
+	GetUnFinishOrders(pair Pair) ([]*O, []byte, error)
+}
+
+// market bind the tracker to the api of one market, SwapOMS and SpotOMS only wire the api into it.
+type market[O any] struct {
+	*Tracker
+	api       marketAPI[O]
+	tradeType string
+}
+
+// place track the local order before the api call. If the exchange refuse the order, it turns into fail.
+// If the outcome is unknown, such as the request timeout, the order is kept open,
+// the reconciliation will find out the real status.
+func (this *market[O]) place(order *O) ([]byte, error) {
+	var fields = reflect.ValueOf(order).Elem()
+	if fields.FieldByName("Cid").String() == "" {
+		fields.FieldByName("Cid").SetString(UUID())
+	}
+	fields.FieldByName("Status").Set(reflect.ValueOf(ORDER_UNFINISH))
+
+	var local = this.toTracked(order, SOURCE_LOCAL)
+	this.apply(local)
+	var resp, err = this.api.PlaceOrder(order)
+	if err != nil {
+		if !isUnknown(err) {
+			var failed = local
+			failed.Status = ORDER_FAIL
+			this.apply(failed)
+		}
+		return resp, err
+	}
+	this.apply(this.toTracked(order, SOURCE_REST))
+	return resp, nil
+}
+
+// cancel mark the tracked order canceling before the api call, and revert it if the api return error,
+// otherwise the order is stuck in canceling, the status machine never let it back to open.
+func (this *market[O]) cancel(order *O) ([]byte, error) {
+	var ref = this.toTracked(order, SOURCE_LOCAL)
+	var tracked, exist = this.book.get(ref.Cid, ref.OrderId)
+	var canceling = exist && !tracked.Status.IsFinal() && tracked.Status != ORDER_CANCEL_ING
+	if canceling {
+		var update = tracked
+		update.Status = ORDER_CANCEL_ING
+		update.Source = SOURCE_LOCAL
+		this.apply(update)
+	}
+
+	var resp, err = this.api.CancelOrder(order)
+	if err != nil {
+		if canceling {
+			this.book.revert(tracked.Cid, tracked.OrderId, ORDER_CANCEL_ING, tracked.Status)
+		}
+		return resp, err
+	}
+	this.apply(this.toTracked(order, SOURCE_REST))
+	return resp, nil
+}
+
+// track merge the order update from the private websocket stream or the rest api.
+func (this *market[O]) track(order *O, source string) TrackedOrder {
+	return this.apply(this.toTracked(order, source))
+}
+
+// reconcile compare the tracked orders with the exchange, find out the orphans and the drifts.
+// The open orders are listed in the tracked pairs and the watch pairs,
+// the tracked open order which is not listed is queried one by one,
+// it turns into fail if the query keep failing for MaxQueryFail times.
+func (this *market[O]) reconcile() {
+	var pairs = make(map[string]Pair)
+	for _, pair := range this.book.trackedPairs() {
+		pairs[pair.String()] = pair
+	}
+	for _, pair := range this.WatchPairs {
+		pairs[pair.String()] = pair
+	}
+
+	for _, pair := range pairs {
+		var remotes, _, err = this.api.GetUnFinishOrders(pair)
+		if err != nil {
+			this.ErrorHandler(err)
+			continue
+		}
+
+		var remoteOpen = make(map[string]bool)
+		for _, order := range remotes {
+			var remote = this.toTracked(order, SOURCE_REST)
+			remoteOpen[remote.Cid] = true
+			remoteOpen[remote.OrderId] = true
+			if _, exist := this.book.get(remote.Cid, remote.OrderId); !exist {
+				var orphan = this.apply(remote)
+				this.OrphanHandler(orphan)
+				continue
+			}
+			this.apply(remote)
+		}
+
+		for _, local := range this.book.open(&pair) {
+			if (local.Cid != "" && remoteOpen[local.Cid]) || (local.OrderId != "" && remoteOpen[local.OrderId]) {
+				continue
+			}
+
+			// the order is open in local, but not in the exchange, query the real status.
+			var query = new(O)
+			var fields = reflect.ValueOf(query).Elem()
+			fields.FieldByName("Cid").SetString(local.Cid)
+			fields.FieldByName("OrderId").SetString(local.OrderId)
+			fields.FieldByName("Pair").Set(reflect.ValueOf(local.Pair))
+			if _, err := this.api.GetOrder(query); err != nil {
+				this.ErrorHandler(fmt.Errorf("reconcile the order %s/%s error: %s", local.Cid, local.OrderId, err))
+				if this.book.queryFail(local.Cid, local.OrderId) >= this.MaxQueryFail &&
+					local.Status.CanTransitTo(ORDER_FAIL) {
+					var failed = local
+					failed.Status = ORDER_FAIL
+					failed.Source = SOURCE_LOCAL
+					this.apply(failed)
+				}
+				continue
+			}
+			this.book.querySucceed(local.Cid, local.OrderId)
+			this.apply(this.toTracked(query, SOURCE_REST))
+		}
+	}
+
+	if this.KeepFinal > 0 {
+		this.book.forget(time.Now().Add(-this.KeepFinal).UnixMilli())
+	}
+}
+
+// toTracked copy the tracked fields from the Order or the SwapOrder, they share the same field names.
+func (this *market[O]) toTracked(order *O, source string) TrackedOrder {
+	var fields = reflect.ValueOf(order).Elem()
+	return TrackedOrder{
+		Cid:        fields.FieldByName("Cid").String(),
+		OrderId:    fields.FieldByName("OrderId").String(),
+		Pair:       fields.FieldByName("Pair").Interface().(Pair),
+		TradeType:  this.tradeType,
+		Status:     fields.FieldByName("Status").Interface().(TradeStatus),
+		Price:      fields.FieldByName("Price").Float(),
+		Amount:     fields.FieldByName("Amount").Float(),
+		AvgPrice:   fields.FieldByName("AvgPrice").Float(),
+		DealAmount: fields.FieldByName("DealAmount").Float(),
+		Fee:        fields.FieldByName("Fee").Float(),
+		Source:     source,
+	}
+}
+
+// isUnknown tell whether the request may reach the exchange, such as the timeout or the broken connection.
+// The other errors are answered by the exchange or refused before sending, the order is not placed.
+func isUnknown(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+func (this *Tracker) apply(update TrackedOrder) TrackedOrder {
+	var before, _ = this.book.get(update.Cid, update.OrderId)
+	var merged, _, isFinal, drift = this.book.merge(update)
+	if drift != "" {
+		this.DriftHandler(OrderDrift{Local: before, Remote: update, Reason: drift})
+	}
+	if isFinal {
+		this.TerminalHandler(merged)
+	}
+	return merged
+}
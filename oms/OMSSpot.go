@@ -0,0 +1,45 @@
+package oms
+
+import (
+	. "github.com/deforceHK/goghostex"
+)
+
+// SpotOMS track the spot orders placed by it or pushed by the private websocket stream,
+// and reconcile them with the rest api periodically.
+type SpotOMS struct {
+	API SpotRestAPI
+	Tracker
+}
+
+func (this *SpotOMS) market() *market[Order] {
+	this.Init()
+	return &market[Order]{Tracker: &this.Tracker, api: this.API, tradeType: TRADE_TYPE_SPOT}
+}
+
+// Start the periodic reconciliation in the background.
+func (this *SpotOMS) Start() {
+	this.start(this.Reconcile)
+}
+
+// PlaceOrder place the order by the api and track it, the cid will be generated if it is empty.
+// The order turns into fail if the exchange refuse it, and is kept open if the outcome is unknown,
+// the reconciliation will find out the real status.
+func (this *SpotOMS) PlaceOrder(order *Order) ([]byte, error) {
+	return this.market().place(order)
+}
+
+// CancelOrder cancel the order by the api, the tracked order is canceling until the api return,
+// and back to the previous status if the api return error.
+func (this *SpotOMS) CancelOrder(order *Order) ([]byte, error) {
+	return this.market().cancel(order)
+}
+
+// OnOrder merge the order update from the private websocket stream or the rest api.
+func (this *SpotOMS) OnOrder(order *Order, source string) TrackedOrder {
+	return this.market().track(order, source)
+}
+
+// Reconcile compare the tracked orders with the exchange, find out the orphans and the drifts.
+func (this *SpotOMS) Reconcile() {
+	this.market().reconcile()
+}
@@ -0,0 +1,45 @@
+package oms
+
+import (
+	. "github.com/deforceHK/goghostex"
+)
+
+// SwapOMS track the swap orders placed by it or pushed by the private websocket stream,
+// and reconcile them with the rest api periodically.
+type SwapOMS struct {
+	API SwapRestAPI
+	Tracker
+}
+
+func (this *SwapOMS) market() *market[SwapOrder] {
+	this.Init()
+	return &market[SwapOrder]{Tracker: &this.Tracker, api: this.API, tradeType: TRADE_TYPE_SWAP}
+}
+
+// Start the periodic reconciliation in the background.
+func (this *SwapOMS) Start() {
+	this.start(this.Reconcile)
+}
+
+// PlaceOrder place the order by the api and track it, the cid will be generated if it is empty.
+// The order turns into fail if the exchange refuse it, and is kept open if the outcome is unknown,
+// the reconciliation will find out the real status.
+func (this *SwapOMS) PlaceOrder(order *SwapOrder) ([]byte, error) {
+	return this.market().place(order)
+}
+
+// CancelOrder cancel the order by the api, the tracked order is canceling until the api return,
+// and back to the previous status if the api return error.
+func (this *SwapOMS) CancelOrder(order *SwapOrder) ([]byte, error) {
+	return this.market().cancel(order)
+}
+
+// OnOrder merge the order update from the private websocket stream or the rest api.
+func (this *SwapOMS) OnOrder(order *SwapOrder, source string) TrackedOrder {
+	return this.market().track(order, source)
+}
+
+// Reconcile compare the tracked orders with the exchange, find out the orphans and the drifts.
+func (this *SwapOMS) Reconcile() {
+	this.market().reconcile()
+}
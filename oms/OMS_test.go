@@ -0,0 +1,206 @@
+package oms
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	. "github.com/deforceHK/goghostex"
+)
+
+type fakeSwap struct {
+	SwapRestAPI
+	open      []*SwapOrder
+	status    map[string]TradeStatus
+	placeErr  error
+	cancelErr error
+	queryErr  error
+}
+
+func (this *fakeSwap) PlaceOrder(order *SwapOrder) ([]byte, error) {
+	if this.placeErr != nil {
+		return nil, this.placeErr
+	}
+	order.OrderId = "oid-" + order.Cid
+	order.Status = ORDER_UNFINISH
+	return nil, nil
+}
+
+func (this *fakeSwap) CancelOrder(order *SwapOrder) ([]byte, error) {
+	if this.cancelErr != nil {
+		return nil, this.cancelErr
+	}
+	order.Status = ORDER_CANCEL
+	return nil, nil
+}
+
+func (this *fakeSwap) GetUnFinishOrders(pair Pair) ([]*SwapOrder, []byte, error) {
+	var open = make([]*SwapOrder, 0)
+	for _, order := range this.open {
+		if order.Pair.String() == pair.String() {
+			open = append(open, order)
+		}
+	}
+	return open, nil, nil
+}
+
+func (this *fakeSwap) GetOrder(order *SwapOrder) ([]byte, error) {
+	if this.queryErr != nil {
+		return nil, this.queryErr
+	}
+	order.Status = this.status[order.OrderId]
+	return nil, nil
+}
+
+// go test -v ./oms/... -count=1 -run=TestSwapOMS
+func TestSwapOMS(t *testing.T) {
+	var api = &fakeSwap{status: map[string]TradeStatus{}}
+	var terminals, orphans, drifts = 0, 0, 0
+	var oms = &SwapOMS{
+		API: api,
+		Tracker: Tracker{
+			TerminalHandler: func(order TrackedOrder) { terminals++ },
+			OrphanHandler:   func(order TrackedOrder) { orphans++ },
+			DriftHandler:    func(drift OrderDrift) { drifts++ },
+		},
+	}
+
+	var order = &SwapOrder{Cid: "c1", Pair: BTC_USDT, Price: 100, Amount: 2}
+	if _, err := oms.PlaceOrder(order); err != nil {
+		t.Error(err)
+		return
+	}
+
+	// the websocket part_finish arrive before the stale rest unfinish.
+	oms.OnOrder(&SwapOrder{OrderId: "oid-c1", Status: ORDER_PART_FINISH, DealAmount: 1}, SOURCE_WEBSOCKET)
+	oms.OnOrder(&SwapOrder{OrderId: "oid-c1", Status: ORDER_UNFINISH}, SOURCE_REST)
+	var tracked, _ = oms.GetOrder("c1", "")
+	if tracked.Status != ORDER_PART_FINISH || tracked.DealAmount != 1 {
+		t.Error("the stale update should be ignored: ", tracked.Status, tracked.DealAmount)
+		return
+	}
+
+	// the order is gone in the exchange, and an unknown order is open.
+	api.status["oid-c1"] = ORDER_FINISH
+	api.open = []*SwapOrder{{Cid: "c2", OrderId: "oid-c2", Pair: BTC_USDT, Status: ORDER_UNFINISH}}
+	oms.Reconcile()
+	tracked, _ = oms.GetOrder("c1", "")
+	if tracked.Status != ORDER_FINISH || terminals != 1 || orphans != 1 {
+		t.Error("the reconcile is wrong: ", tracked.Status, terminals, orphans)
+		return
+	}
+
+	// the final status conflict is the drift.
+	oms.OnOrder(&SwapOrder{OrderId: "oid-c1", Status: ORDER_CANCEL}, SOURCE_WEBSOCKET)
+	if drifts != 1 || terminals != 1 {
+		t.Error("the drift is not found: ", drifts, terminals)
+	}
+}
+
+// go test -v ./oms/... -count=1 -run=TestSwapOMS_CancelOrder
+func TestSwapOMS_CancelOrder(t *testing.T) {
+	var api = &fakeSwap{status: map[string]TradeStatus{}, cancelErr: errors.New("the order is busy")}
+	var oms = &SwapOMS{API: api}
+
+	var order = &SwapOrder{Cid: "c1", Pair: BTC_USDT, Price: 100, Amount: 2}
+	if _, err := oms.PlaceOrder(order); err != nil {
+		t.Error(err)
+		return
+	}
+	oms.OnOrder(&SwapOrder{OrderId: "oid-c1", Status: ORDER_PART_FINISH, DealAmount: 1}, SOURCE_WEBSOCKET)
+
+	// the failed cancel should not leave the order in canceling.
+	if _, err := oms.CancelOrder(&SwapOrder{Cid: "c1", OrderId: "oid-c1", Pair: BTC_USDT}); err == nil {
+		t.Error("the cancel error is lost")
+		return
+	}
+	var tracked, _ = oms.GetOrder("c1", "")
+	if tracked.Status != ORDER_PART_FINISH {
+		t.Error("the failed cancel should revert the status: ", tracked.Status)
+		return
+	}
+
+	api.cancelErr = nil
+	if _, err := oms.CancelOrder(&SwapOrder{Cid: "c1", OrderId: "oid-c1", Pair: BTC_USDT}); err != nil {
+		t.Error(err)
+		return
+	}
+	tracked, _ = oms.GetOrder("c1", "")
+	if tracked.Status != ORDER_CANCEL {
+		t.Error("the order should be canceled: ", tracked.Status)
+	}
+}
+
+// go test -v ./oms/... -count=1 -run=TestSwapOMS_WatchPairs
+func TestSwapOMS_WatchPairs(t *testing.T) {
+	var api = &fakeSwap{status: map[string]TradeStatus{}}
+	var orphans = make([]TrackedOrder, 0)
+	var oms = &SwapOMS{
+		API: api,
+		Tracker: Tracker{
+			WatchPairs:    []Pair{ETH_USDT},
+			OrphanHandler: func(order TrackedOrder) { orphans = append(orphans, order) },
+		},
+	}
+
+	// nothing is tracked, the orphan in the watch pair should be found.
+	api.open = []*SwapOrder{{Cid: "c9", OrderId: "oid-c9", Pair: ETH_USDT, Status: ORDER_UNFINISH}}
+	oms.Reconcile()
+	if len(orphans) != 1 || orphans[0].OrderId != "oid-c9" {
+		t.Error("the orphan in the watch pair is not found: ", orphans)
+	}
+}
+
+// go test -v ./oms/... -count=1 -run=TestSwapOMS_RefusedPlace
+func TestSwapOMS_RefusedPlace(t *testing.T) {
+	var api = &fakeSwap{status: map[string]TradeStatus{}, placeErr: errors.New("the balance is not enough")}
+	var terminals = make([]TrackedOrder, 0)
+	var oms = &SwapOMS{
+		API: api,
+		Tracker: Tracker{
+			MaxQueryFail:    2,
+			KeepFinal:       time.Millisecond,
+			TerminalHandler: func(order TrackedOrder) { terminals = append(terminals, order) },
+		},
+	}
+
+	// the exchange refuse the order, it is failed at once.
+	if _, err := oms.PlaceOrder(&SwapOrder{Cid: "c1", Pair: BTC_USDT, Price: 100, Amount: 2}); err == nil {
+		t.Error("the place error is lost")
+		return
+	}
+	var tracked, _ = oms.GetOrder("c1", "")
+	if tracked.Status != ORDER_FAIL || len(terminals) != 1 || terminals[0].Source != SOURCE_LOCAL {
+		t.Error("the refused order should fail: ", tracked.Status, terminals)
+		return
+	}
+
+	// the request timeout, the order is kept open until the query fail MaxQueryFail times.
+	api.placeErr = &url.Error{Op: "Post", URL: "https://api.test/order", Err: context.DeadlineExceeded}
+	api.queryErr = errors.New("the order does not exist")
+	if _, err := oms.PlaceOrder(&SwapOrder{Cid: "c2", Pair: ETH_USDT, Price: 100, Amount: 2}); err == nil {
+		t.Error("the place error is lost")
+		return
+	}
+	oms.Reconcile()
+	tracked, _ = oms.GetOrder("c2", "")
+	if tracked.Status != ORDER_UNFINISH {
+		t.Error("the unknown order should be kept open: ", tracked.Status)
+		return
+	}
+	oms.Reconcile()
+	tracked, _ = oms.GetOrder("c2", "")
+	if tracked.Status != ORDER_FAIL || len(terminals) != 2 {
+		t.Error("the unknown order should fail after the query failures: ", tracked.Status, len(terminals))
+		return
+	}
+
+	// the final orders are forgotten, so are their pairs.
+	time.Sleep(2 * time.Millisecond)
+	oms.Reconcile()
+	if pairs := oms.book.trackedPairs(); len(pairs) != 0 {
+		t.Error("the pairs should be pruned: ", pairs)
+	}
+}
@@ -0,0 +1,175 @@
+package pricing
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	. "github.com/deforceHK/goghostex"
+)
+
+const (
+	YEAR_DAYS = 365
+	YEAR_MS   = YEAR_DAYS * 24 * 60 * 60 * 1000
+)
+
+// Params is the input of the generalized black scholes model with the cost of carry, the Carry is 0 in the black 76
+// model of the futures and the forward, and it is the Rate in the black scholes model of the spot.
+type Params struct {
+	OptionType OptionType
+	Underlying float64 // the forward price in the black 76, the spot price in the black scholes
+	Strike     float64
+	Expiry     float64 // the time to the expiry in years, use YearFraction to get it
+	Vol        float64 // the annual volatility, eg: 0.5 means 50%
+	Rate       float64 // the risk free rate to discount, it is 0 in the crypto options usually
+	Carry      float64 // the cost of carry
+	// the coin margined option, eg: okex BTC-USD, the Price is in the basis currency, the Greeks are not changed.
+	Inverse bool
+}
+
+func NewBlack76(optionType OptionType, forward, strike, expiry, vol, rate float64) *Params {
+	return &Params{
+		OptionType: optionType,
+		Underlying: forward,
+		Strike:     strike,
+		Expiry:     expiry,
+		Vol:        vol,
+		Rate:       rate,
+	}
+}
+
+func NewBlackScholes(optionType OptionType, spot, strike, expiry, vol, rate float64) *Params {
+	return &Params{
+		OptionType: optionType,
+		Underlying: spot,
+		Strike:     strike,
+		Expiry:     expiry,
+		Vol:        vol,
+		Rate:       rate,
+		Carry:      rate,
+	}
+}
+
+// FromTicker build the black 76 params of the contract by the ticker, the underlying is the UnderlyingPrice and
+// the vol is the MarkIV. The settle currency same as the basis currency means the inverse option.
+func FromTicker(contract *OptionContract, ticker *OptionTicker, now time.Time) (*Params, error) {
+	if contract == nil || ticker == nil {
+		return nil, errors.New("the contract or the ticker is nil. ")
+	}
+	if ticker.UnderlyingPrice <= 0 {
+		return nil, fmt.Errorf("the underlying price of %s is wrong. ", contract.Symbol)
+	}
+	var params = NewBlack76(
+		contract.OptionType,
+		ticker.UnderlyingPrice,
+		contract.Strike,
+		YearFraction(now, contract.ExpiryTimestamp),
+		ticker.MarkIV,
+		0,
+	)
+	params.Inverse = IsInverse(contract)
+	return params, nil
+}
+
+// IsInverse return whether the option is settled in the basis currency.
+func IsInverse(contract *OptionContract) bool {
+	return strings.EqualFold(contract.SettleCurrency, contract.Pair.Basis.Symbol)
+}
+
+// YearFraction return the years from the now to the expiry timestamp in ms, it is 0 after the expiry.
+func YearFraction(now time.Time, expiryTimestamp int64) float64 {
+	var ms = expiryTimestamp - now.UnixMilli()
+	if ms <= 0 {
+		return 0
+	}
+	return float64(ms) / YEAR_MS
+}
+
+// Forward return the forward price of the underlying at the expiry.
+func (p *Params) Forward() float64 {
+	return p.Underlying * math.Exp(p.Carry*p.Expiry)
+}
+
+func (p *Params) d1d2() (float64, float64) {
+	var stdDev = p.Vol * math.Sqrt(p.Expiry)
+	var d1 = (math.Log(p.Underlying/p.Strike) + (p.Carry+p.Vol*p.Vol/2)*p.Expiry) / stdDev
+	return d1, d1 - stdDev
+}
+
+// isExpired means the value is the intrinsic value, the expired option and the zero volatility one.
+func (p *Params) isExpired() bool {
+	return p.Expiry <= 0 || p.Vol <= 0
+}
+
+// Value return the option value in the counter currency.
+func (p *Params) Value() float64 {
+	var carryDiscount = math.Exp((p.Carry - p.Rate) * p.Expiry)
+	var discount = math.Exp(-p.Rate * p.Expiry)
+	if p.isExpired() {
+		var forward = p.Underlying * carryDiscount
+		if p.OptionType == OPTION_CALL {
+			return math.Max(forward-p.Strike*discount, 0)
+		}
+		return math.Max(p.Strike*discount-forward, 0)
+	}
+
+	var d1, d2 = p.d1d2()
+	if p.OptionType == OPTION_CALL {
+		return p.Underlying*carryDiscount*normCdf(d1) - p.Strike*discount*normCdf(d2)
+	}
+	return p.Strike*discount*normCdf(-d2) - p.Underlying*carryDiscount*normCdf(-d1)
+}
+
+// Price return the option price in the quote of the exchange, the inverse option is in the basis currency.
+func (p *Params) Price() float64 {
+	if p.Inverse {
+		return p.Value() / p.Forward()
+	}
+	return p.Value()
+}
+
+// Greeks return the black scholes greeks in the counter currency, the Vega is for 1% volatility and the Theta is
+// for one day, as the greeks of the exchanges.
+func (p *Params) Greeks() OptionGreeks {
+	var carryDiscount = math.Exp((p.Carry - p.Rate) * p.Expiry)
+	if p.isExpired() {
+		var greeks = OptionGreeks{}
+		var forward = p.Underlying * math.Exp(p.Carry*p.Expiry)
+		if p.OptionType == OPTION_CALL && forward > p.Strike {
+			greeks.Delta = carryDiscount
+		} else if p.OptionType == OPTION_PUT && forward < p.Strike {
+			greeks.Delta = -carryDiscount
+		}
+		return greeks
+	}
+
+	var d1, d2 = p.d1d2()
+	var sqrtExpiry = math.Sqrt(p.Expiry)
+	var discount = math.Exp(-p.Rate * p.Expiry)
+	var density = p.Underlying * carryDiscount * normPdf(d1)
+
+	var greeks = OptionGreeks{
+		Gamma: carryDiscount * normPdf(d1) / (p.Underlying * p.Vol * sqrtExpiry),
+		Vega:  density * sqrtExpiry / 100,
+	}
+	var theta = -density * p.Vol / (2 * sqrtExpiry)
+	if p.OptionType == OPTION_CALL {
+		greeks.Delta = carryDiscount * normCdf(d1)
+		theta += -(p.Carry-p.Rate)*p.Underlying*carryDiscount*normCdf(d1) - p.Rate*p.Strike*discount*normCdf(d2)
+	} else {
+		greeks.Delta = carryDiscount * (normCdf(d1) - 1)
+		theta += (p.Carry-p.Rate)*p.Underlying*carryDiscount*normCdf(-d1) + p.Rate*p.Strike*discount*normCdf(-d2)
+	}
+	greeks.Theta = theta / YEAR_DAYS
+	return greeks
+}
+
+func normCdf(x float64) float64 {
+	return 0.5 * math.Erfc(-x/math.Sqrt2)
+}
+
+func normPdf(x float64) float64 {
+	return math.Exp(-x*x/2) / math.Sqrt(2*math.Pi)
+}
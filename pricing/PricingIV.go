@@ -0,0 +1,65 @@
+package pricing
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+const (
+	IV_MIN       = 1e-4
+	IV_MAX       = 10.0
+	IV_TOLERANCE = 1e-8
+	IV_MAX_ITER  = 100
+)
+
+// ImpliedVol solve the volatility of the price by the newton method, and fall back to the bisection when the vega is
+// too small or the newton step is out of the bounds. The price is in the quote of the exchange like the Price, the
+// Vol of the params is not changed.
+func ImpliedVol(params *Params, price float64) (float64, error) {
+	if params.Expiry <= 0 {
+		return 0, errors.New("the option is expired, there is no implied volatility. ")
+	}
+
+	var p = *params
+	var priceAt = func(vol float64) float64 {
+		p.Vol = vol
+		return p.Price()
+	}
+	var low, high = IV_MIN, IV_MAX
+	var lowPrice, highPrice = priceAt(low), priceAt(high)
+	if price < lowPrice-IV_TOLERANCE || price > highPrice+IV_TOLERANCE {
+		return 0, fmt.Errorf("the price %f is out of the bounds [%f, %f]. ", price, lowPrice, highPrice)
+	}
+
+	var vol = 0.5
+	if params.Vol > 0 {
+		vol = params.Vol
+	}
+	for i := 0; i < IV_MAX_ITER; i++ {
+		var diff = priceAt(vol) - price
+		if math.Abs(diff) < IV_TOLERANCE {
+			return vol, nil
+		}
+		if diff > 0 {
+			high = vol
+		} else {
+			low = vol
+		}
+
+		// the vega of the Greeks is for 1%, the price of the inverse option is divided by the forward.
+		var vega = p.Greeks().Vega * 100
+		if p.Inverse {
+			vega = vega / p.Forward()
+		}
+		var next = vol - diff/vega
+		if vega < IV_TOLERANCE || next <= low || next >= high {
+			next = (low + high) / 2
+		}
+		if math.Abs(next-vol) < IV_TOLERANCE {
+			return next, nil
+		}
+		vol = next
+	}
+	return vol, nil
+}
@@ -0,0 +1,80 @@
+package pricing
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/deforceHK/goghostex"
+)
+
+// PositionGreeks return the greeks of the whole position in the counter currency. The greeks provided by the
+// exchange in the position are used first, then the greeks of the ticker, and they are computed from the MarkIV
+// of the ticker at last. The greeks of one contract are multiplied by the Amount and the UnitAmount.
+func PositionGreeks(
+	position *OptionPosition,
+	contract *OptionContract,
+	ticker *OptionTicker,
+	now time.Time,
+) (OptionGreeks, error) {
+	if position.Greeks != (OptionGreeks{}) {
+		return position.Greeks, nil
+	}
+	if contract == nil || ticker == nil {
+		return OptionGreeks{}, fmt.Errorf("lack the contract or the ticker of %s. ", position.Symbol)
+	}
+
+	var greeks = ticker.Greeks
+	if greeks == (OptionGreeks{}) {
+		var params, err = FromTicker(contract, ticker, now)
+		if err != nil {
+			return OptionGreeks{}, err
+		}
+		if params.Vol <= 0 {
+			return OptionGreeks{}, fmt.Errorf("lack the mark iv of %s. ", position.Symbol)
+		}
+		greeks = params.Greeks()
+	}
+
+	var unitAmount = contract.UnitAmount
+	if unitAmount <= 0 {
+		unitAmount = 1
+	}
+	var multiplier = position.Amount * unitAmount
+	return OptionGreeks{
+		Delta: greeks.Delta * multiplier,
+		Gamma: greeks.Gamma * multiplier,
+		Vega:  greeks.Vega * multiplier,
+		Theta: greeks.Theta * multiplier,
+	}, nil
+}
+
+// PortfolioGreeks sum the greeks of the positions, the contracts and the tickers are found by the symbol.
+// The Delta is in the basis currency, sum the positions of the same underlying only.
+func PortfolioGreeks(
+	positions []*OptionPosition,
+	contracts []*OptionContract,
+	tickers []*OptionTicker,
+	now time.Time,
+) (OptionGreeks, error) {
+	var contractKV = make(map[string]*OptionContract, len(contracts))
+	for _, contract := range contracts {
+		contractKV[contract.Symbol] = contract
+	}
+	var tickerKV = make(map[string]*OptionTicker, len(tickers))
+	for _, ticker := range tickers {
+		tickerKV[ticker.Symbol] = ticker
+	}
+
+	var total = OptionGreeks{}
+	for _, position := range positions {
+		var greeks, err = PositionGreeks(position, contractKV[position.Symbol], tickerKV[position.Symbol], now)
+		if err != nil {
+			return OptionGreeks{}, err
+		}
+		total.Delta += greeks.Delta
+		total.Gamma += greeks.Gamma
+		total.Vega += greeks.Vega
+		total.Theta += greeks.Theta
+	}
+	return total, nil
+}
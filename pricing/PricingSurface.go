@@ -0,0 +1,191 @@
+package pricing
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"time"
+
+	. "github.com/deforceHK/goghostex"
+)
+
+// SmilePoint is the volatility of one strike, the out of the money option is used in the smile.
+type SmilePoint struct {
+	Symbol    string
+	Strike    float64
+	Moneyness float64 // the log moneyness ln(strike/forward)
+	Vol       float64
+}
+
+// Smile is the volatility of the strikes in one expiry, the points are sorted by the strike.
+type Smile struct {
+	ExpiryTimestamp int64   // unit:ms
+	Expiry          float64 // the time to the expiry in years when it is built
+	Forward         float64 // the average underlying price of the tickers
+	Points          []*SmilePoint
+}
+
+// Vol return the volatility of the strike, it is linear in the strike, and flat out of the strikes.
+func (smile *Smile) Vol(strike float64) float64 {
+	var points = smile.Points
+	if len(points) == 0 {
+		return 0
+	}
+	if strike <= points[0].Strike {
+		return points[0].Vol
+	}
+	if strike >= points[len(points)-1].Strike {
+		return points[len(points)-1].Vol
+	}
+	var i = sort.Search(len(points), func(i int) bool {
+		return points[i].Strike >= strike
+	})
+	var pre, next = points[i-1], points[i]
+	return pre.Vol + (next.Vol-pre.Vol)*(strike-pre.Strike)/(next.Strike-pre.Strike)
+}
+
+// ATMVol return the volatility at the forward.
+func (smile *Smile) ATMVol() float64 {
+	return smile.Vol(smile.Forward)
+}
+
+// Surface is the smiles of the expiries, the smiles are sorted by the expiry.
+type Surface struct {
+	Pair      Pair
+	Exchange  string
+	Timestamp int64 // unit:ms, the time when it is built
+	Smiles    []*Smile
+}
+
+// Vol return the volatility of the strike and the expiry timestamp in ms, the total variance is linear in the time
+// between the smiles, the volatility is flat before the first and after the last smile.
+func (surface *Surface) Vol(strike float64, expiryTimestamp int64) float64 {
+	var smiles = surface.Smiles
+	if len(smiles) == 0 {
+		return 0
+	}
+	if expiryTimestamp <= smiles[0].ExpiryTimestamp {
+		return smiles[0].Vol(strike)
+	}
+	if expiryTimestamp >= smiles[len(smiles)-1].ExpiryTimestamp {
+		return smiles[len(smiles)-1].Vol(strike)
+	}
+	var i = sort.Search(len(smiles), func(i int) bool {
+		return smiles[i].ExpiryTimestamp >= expiryTimestamp
+	})
+	var pre, next = smiles[i-1], smiles[i]
+	var expiry = YearFraction(time.UnixMilli(surface.Timestamp), expiryTimestamp)
+	if expiry <= 0 {
+		return pre.Vol(strike)
+	}
+
+	var preVariance = pre.Vol(strike) * pre.Vol(strike) * pre.Expiry
+	var nextVariance = next.Vol(strike) * next.Vol(strike) * next.Expiry
+	var weight = (expiry - pre.Expiry) / (next.Expiry - pre.Expiry)
+	var variance = preVariance + (nextVariance-preVariance)*weight
+	if variance <= 0 {
+		return 0
+	}
+	return math.Sqrt(variance / expiry)
+}
+
+// GetSmile return the smile of the expiry timestamp, nil means not found.
+func (surface *Surface) GetSmile(expiryTimestamp int64) *Smile {
+	for _, smile := range surface.Smiles {
+		if smile.ExpiryTimestamp == expiryTimestamp {
+			return smile
+		}
+	}
+	return nil
+}
+
+// BuildSurface build the surface from the chain of the exchange. The vol is the MarkIV of the ticker, it is solved
+// from the MarkPrice when the MarkIV is not provided. The call is used above the forward and the put below it, the
+// other one is used when one of them is not quoted. The expired contract and the ticker without vol are skipped.
+func BuildSurface(contracts []*OptionContract, tickers []*OptionTicker, now time.Time) (*Surface, error) {
+	var tickerKV = make(map[string]*OptionTicker, len(tickers))
+	for _, ticker := range tickers {
+		tickerKV[ticker.Symbol] = ticker
+	}
+
+	// the points of the expiry and the strike, [call, put]
+	type quote struct {
+		contract *OptionContract
+		vol      float64
+	}
+	var expiries = make(map[int64]map[float64]*[2]*quote)
+	var forwards = make(map[int64][]float64)
+	var surface = &Surface{Timestamp: now.UnixMilli()}
+
+	for _, contract := range contracts {
+		var ticker, exist = tickerKV[contract.Symbol]
+		if !exist || contract.ExpiryTimestamp <= now.UnixMilli() {
+			continue
+		}
+		var params, err = FromTicker(contract, ticker, now)
+		if err != nil {
+			continue
+		}
+		var vol = ticker.MarkIV
+		if vol <= 0 && ticker.MarkPrice > 0 {
+			if vol, err = ImpliedVol(params, ticker.MarkPrice); err != nil {
+				continue
+			}
+		}
+		if vol <= 0 {
+			continue
+		}
+
+		surface.Pair, surface.Exchange = contract.Pair, contract.Exchange
+		if _, exist := expiries[contract.ExpiryTimestamp]; !exist {
+			expiries[contract.ExpiryTimestamp] = make(map[float64]*[2]*quote)
+		}
+		var strikes = expiries[contract.ExpiryTimestamp]
+		if _, exist := strikes[contract.Strike]; !exist {
+			strikes[contract.Strike] = &[2]*quote{}
+		}
+		strikes[contract.Strike][contract.OptionType] = &quote{contract: contract, vol: vol}
+		forwards[contract.ExpiryTimestamp] = append(forwards[contract.ExpiryTimestamp], ticker.UnderlyingPrice)
+	}
+	if len(expiries) == 0 {
+		return nil, errors.New("there is no quote to build the surface. ")
+	}
+
+	for expiryTimestamp, strikes := range expiries {
+		var forward = 0.0
+		for _, price := range forwards[expiryTimestamp] {
+			forward += price
+		}
+		forward = forward / float64(len(forwards[expiryTimestamp]))
+
+		var smile = &Smile{
+			ExpiryTimestamp: expiryTimestamp,
+			Expiry:          YearFraction(now, expiryTimestamp),
+			Forward:         forward,
+			Points:          make([]*SmilePoint, 0, len(strikes)),
+		}
+		for strike, quotes := range strikes {
+			var chosen = quotes[OPTION_PUT]
+			if strike >= forward || chosen == nil {
+				chosen = quotes[OPTION_CALL]
+			}
+			if chosen == nil {
+				chosen = quotes[OPTION_PUT]
+			}
+			smile.Points = append(smile.Points, &SmilePoint{
+				Symbol:    chosen.contract.Symbol,
+				Strike:    strike,
+				Moneyness: math.Log(strike / forward),
+				Vol:       chosen.vol,
+			})
+		}
+		sort.Slice(smile.Points, func(i, j int) bool {
+			return smile.Points[i].Strike < smile.Points[j].Strike
+		})
+		surface.Smiles = append(surface.Smiles, smile)
+	}
+	sort.Slice(surface.Smiles, func(i, j int) bool {
+		return surface.Smiles[i].ExpiryTimestamp < surface.Smiles[j].ExpiryTimestamp
+	})
+	return surface, nil
+}
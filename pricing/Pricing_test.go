@@ -0,0 +1,165 @@
+package pricing
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	. "github.com/deforceHK/goghostex"
+)
+
+func near(a, b, tolerance float64) bool {
+	return math.Abs(a-b) <= tolerance
+}
+
+// go test -v ./pricing/... -count=1 -run=TestParams_Value
+func TestParams_Value(t *testing.T) {
+	var call = NewBlackScholes(OPTION_CALL, 100, 100, 1, 0.2, 0.05)
+	var put = NewBlackScholes(OPTION_PUT, 100, 100, 1, 0.2, 0.05)
+	if !near(call.Value(), 10.4506, 1e-4) || !near(put.Value(), 5.5735, 1e-4) {
+		t.Error("the black scholes value is wrong: ", call.Value(), put.Value())
+		return
+	}
+	var greeks = call.Greeks()
+	if !near(greeks.Delta, 0.6368, 1e-4) || !near(greeks.Gamma, 0.018762, 1e-6) ||
+		!near(greeks.Vega, 0.375240, 1e-6) || !near(greeks.Theta, -6.414028/365, 1e-6) {
+		t.Error("the black scholes greeks are wrong: ", greeks)
+		return
+	}
+
+	var b76 = NewBlack76(OPTION_CALL, 100, 100, 1, 0.2, 0)
+	if !near(b76.Value(), 7.9656, 1e-4) || !near(b76.Greeks().Delta, 0.5398, 1e-4) {
+		t.Error("the black 76 value is wrong: ", b76.Value(), b76.Greeks())
+		return
+	}
+	// the put call parity in the forward
+	var b76Put = NewBlack76(OPTION_PUT, 100, 90, 0.5, 0.6, 0.03)
+	var b76Call = NewBlack76(OPTION_CALL, 100, 90, 0.5, 0.6, 0.03)
+	if !near(b76Call.Value()-b76Put.Value(), (100-90)*math.Exp(-0.03*0.5), 1e-9) {
+		t.Error("the put call parity is wrong: ", b76Call.Value(), b76Put.Value())
+		return
+	}
+
+	var expired = NewBlack76(OPTION_PUT, 100, 120, 0, 0.5, 0)
+	if expired.Value() != 20 || expired.Greeks().Delta != -1 {
+		t.Error("the expired value is wrong: ", expired.Value(), expired.Greeks())
+	}
+}
+
+// go test -v ./pricing/... -count=1 -run=TestImpliedVol
+func TestImpliedVol(t *testing.T) {
+	var cases = []*Params{
+		NewBlack76(OPTION_CALL, 60000, 70000, 30.0/365, 0.55, 0),
+		NewBlack76(OPTION_PUT, 60000, 40000, 7.0/365, 1.2, 0),
+		NewBlackScholes(OPTION_CALL, 100, 100, 1, 0.2, 0.05),
+		{OptionType: OPTION_PUT, Underlying: 60000, Strike: 58000, Expiry: 90.0 / 365, Vol: 0.45, Inverse: true},
+	}
+	for i, params := range cases {
+		var price = params.Price()
+		var guess = *params
+		guess.Vol = 0
+		var vol, err = ImpliedVol(&guess, price)
+		if err != nil || !near(vol, params.Vol, 1e-6) {
+			t.Error(i, "the implied vol is wrong: ", vol, err)
+			return
+		}
+	}
+
+	if _, err := ImpliedVol(NewBlack76(OPTION_CALL, 100, 90, 1, 0, 0), 5); err == nil {
+		t.Error("the price below the intrinsic value should be rejected. ")
+	}
+}
+
+// go test -v ./pricing/... -count=1 -run=TestBuildSurface
+func TestBuildSurface(t *testing.T) {
+	var now = time.Date(2024, 12, 1, 8, 0, 0, 0, time.UTC)
+	var near30 = now.AddDate(0, 0, 30).UnixMilli()
+	var far90 = now.AddDate(0, 0, 90).UnixMilli()
+	var pair = Pair{Basis: BTC, Counter: USD}
+
+	var contracts = make([]*OptionContract, 0)
+	var tickers = make([]*OptionTicker, 0)
+	var add = func(symbol string, optionType OptionType, strike float64, expiry int64, vol float64) {
+		contracts = append(contracts, &OptionContract{
+			Pair: pair, Symbol: symbol, OptionType: optionType, Strike: strike,
+			ExpiryTimestamp: expiry, SettleCurrency: "BTC", UnitAmount: 0.01,
+		})
+		tickers = append(tickers, &OptionTicker{Symbol: symbol, MarkIV: vol, UnderlyingPrice: 60000})
+	}
+	add("N-50000-P", OPTION_PUT, 50000, near30, 0.70)
+	add("N-50000-C", OPTION_CALL, 50000, near30, 0.99)
+	add("N-60000-C", OPTION_CALL, 60000, near30, 0.50)
+	add("N-70000-C", OPTION_CALL, 70000, near30, 0.60)
+	add("F-60000-C", OPTION_CALL, 60000, far90, 0.60)
+
+	// the put without the MarkIV is solved from the MarkPrice.
+	var solved = NewBlack76(OPTION_PUT, 60000, 55000, YearFraction(now, far90), 0.65, 0)
+	solved.Inverse = true
+	contracts = append(contracts, &OptionContract{
+		Pair: pair, Symbol: "F-55000-P", OptionType: OPTION_PUT, Strike: 55000,
+		ExpiryTimestamp: far90, SettleCurrency: "BTC", UnitAmount: 0.01,
+	})
+	tickers = append(tickers, &OptionTicker{Symbol: "F-55000-P", MarkPrice: solved.Price(), UnderlyingPrice: 60000})
+
+	var surface, err = BuildSurface(contracts, tickers, now)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(surface.Smiles) != 2 || len(surface.Smiles[0].Points) != 3 {
+		t.Error("the smiles are wrong: ", len(surface.Smiles))
+		return
+	}
+	var smile = surface.Smiles[0]
+	if smile.Points[0].Vol != 0.70 || smile.ATMVol() != 0.50 || !near(smile.Vol(65000), 0.55, 1e-12) {
+		t.Error("the smile is wrong: ", smile.Points[0].Vol, smile.ATMVol(), smile.Vol(65000))
+		return
+	}
+	if !near(surface.Smiles[1].Points[0].Vol, 0.65, 1e-6) {
+		t.Error("the solved vol is wrong: ", surface.Smiles[1].Points[0].Vol)
+		return
+	}
+
+	// the total variance is linear in the time, 60 days is the middle of 30 and 90 days.
+	var vol = surface.Vol(60000, now.AddDate(0, 0, 60).UnixMilli())
+	var want = math.Sqrt((0.5*0.5*30 + 0.6*0.6*90) / 2 / 60)
+	if !near(vol, want, 1e-9) {
+		t.Error("the surface vol is wrong: ", vol, want)
+	}
+}
+
+// go test -v ./pricing/... -count=1 -run=TestPortfolioGreeks
+func TestPortfolioGreeks(t *testing.T) {
+	var now = time.Date(2024, 12, 1, 8, 0, 0, 0, time.UTC)
+	var expiry = now.AddDate(0, 0, 30).UnixMilli()
+	var pair = Pair{Basis: BTC, Counter: USDT}
+	var contracts = []*OptionContract{
+		{Pair: pair, Symbol: "C", OptionType: OPTION_CALL, Strike: 60000, ExpiryTimestamp: expiry, UnitAmount: 1},
+		{Pair: pair, Symbol: "P", OptionType: OPTION_PUT, Strike: 60000, ExpiryTimestamp: expiry, UnitAmount: 1},
+	}
+	var tickers = []*OptionTicker{
+		{Symbol: "C", MarkIV: 0.5, UnderlyingPrice: 60000, Greeks: OptionGreeks{Delta: 0.5, Gamma: 0.00005, Vega: 70, Theta: -90}},
+		{Symbol: "P", MarkIV: 0.5, UnderlyingPrice: 60000},
+	}
+	var positions = []*OptionPosition{
+		{Symbol: "C", Amount: 2},
+		{Symbol: "P", Amount: -1},
+		{Symbol: "X", Amount: 1, Greeks: OptionGreeks{Delta: 0.1}},
+	}
+
+	var greeks, err = PortfolioGreeks(positions, contracts, tickers, now)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	var put = NewBlack76(OPTION_PUT, 60000, 60000, YearFraction(now, expiry), 0.5, 0).Greeks()
+	if !near(greeks.Delta, 1+0.1-put.Delta, 1e-9) || !near(greeks.Vega, 140-put.Vega, 1e-9) {
+		t.Error("the portfolio greeks are wrong: ", greeks)
+		return
+	}
+
+	positions = append(positions, &OptionPosition{Symbol: "Y", Amount: 1})
+	if _, err := PortfolioGreeks(positions, contracts, tickers, now); err == nil {
+		t.Error("the position without the contract should be rejected. ")
+	}
+}
@@ -0,0 +1,168 @@
+package risk
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	. "github.com/deforceHK/goghostex"
+)
+
+const DEFAULT_DEAD_MAN_TIMEOUT_SEC = 60
+
+// DeadManSwitch keep the cancel all after of the exchange refreshed while the strategy is healthy.
+// The strategy call Heartbeat periodically, the switch stop refreshing when the heartbeat is lost,
+// then the exchange cancel all the open orders after the Timeout.
+type DeadManSwitch struct {
+	API SwapRestAPI
+	// binance and gate are per pair, one pair is enough for okex and kraken
+	Pairs []Pair
+	// the timeout in the exchange, default DEFAULT_DEAD_MAN_TIMEOUT_SEC seconds
+	Timeout time.Duration
+	// how often the timeout is refreshed, default a quarter of the Timeout
+	RefreshInterval time.Duration
+	// the heartbeat is lost when there is no heartbeat in it, default a half of the Timeout
+	HeartbeatTimeout time.Duration
+
+	// the heartbeat is lost and the refresh is stopped, not necessary
+	LostHandler  func(lastHeartbeat time.Time)
+	ErrorHandler func(err error)
+
+	mux           sync.Mutex
+	lastHeartbeat time.Time
+	lost          bool
+	stopSign      chan bool
+	done          chan bool
+}
+
+func (this *DeadManSwitch) initDefaultValue() error {
+	if this.API == nil || len(this.Pairs) == 0 {
+		return errors.New("the api and the pairs of the dead man switch are necessary")
+	}
+	if this.Timeout <= 0 {
+		this.Timeout = DEFAULT_DEAD_MAN_TIMEOUT_SEC * time.Second
+	}
+	if this.RefreshInterval <= 0 {
+		this.RefreshInterval = this.Timeout / 4
+	}
+	if this.HeartbeatTimeout <= 0 {
+		this.HeartbeatTimeout = this.Timeout / 2
+	}
+	if this.RefreshInterval >= this.Timeout {
+		return errors.New("the refresh interval must be less than the timeout")
+	}
+	if this.LostHandler == nil {
+		this.LostHandler = func(lastHeartbeat time.Time) {
+			GetLogger().Warn(
+				"dead man switch heartbeat lost",
+				LogF(LOG_FIELD_EXCHANGE, this.API.GetExchangeName()),
+				LogF("last_heartbeat", lastHeartbeat.UnixMilli()),
+			)
+		}
+	}
+	if this.ErrorHandler == nil {
+		this.ErrorHandler = func(err error) {
+			GetLogger().Error(
+				"dead man switch error",
+				LogF(LOG_FIELD_EXCHANGE, this.API.GetExchangeName()),
+				LogF(LOG_FIELD_ERROR, err),
+			)
+		}
+	}
+	return nil
+}
+
+// Start arm the switch in the exchange and refresh it in the background, the Start is a heartbeat too.
+func (this *DeadManSwitch) Start() error {
+	if this.stopSign != nil {
+		return errors.New("the dead man switch is started already")
+	}
+	if err := this.initDefaultValue(); err != nil {
+		return err
+	}
+
+	this.Heartbeat()
+	if err := this.refresh(this.Timeout); err != nil {
+		return err
+	}
+	this.stopSign = make(chan bool)
+	this.done = make(chan bool)
+	go this.run()
+	return nil
+}
+
+// Stop the refresh and disarm the switch in the exchange, the open orders are kept.
+func (this *DeadManSwitch) Stop() error {
+	if this.stopSign == nil {
+		return nil
+	}
+	close(this.stopSign)
+	<-this.done
+	this.stopSign = nil
+	return this.refresh(0)
+}
+
+// Heartbeat tell the switch the strategy is healthy.
+func (this *DeadManSwitch) Heartbeat() {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	this.lastHeartbeat = time.Now()
+}
+
+// IsLost return true when the heartbeat is lost, the exchange will cancel all the orders after the timeout.
+func (this *DeadManSwitch) IsLost() bool {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	return this.lost
+}
+
+func (this *DeadManSwitch) run() {
+	defer close(this.done)
+
+	var ticker = time.NewTicker(this.RefreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			this.tick()
+		case <-this.stopSign:
+			return
+		}
+	}
+}
+
+func (this *DeadManSwitch) tick() {
+	this.mux.Lock()
+	var lastHeartbeat = this.lastHeartbeat
+	var isLost = time.Since(lastHeartbeat) > this.HeartbeatTimeout
+	var justLost = isLost && !this.lost
+	this.lost = isLost
+	this.mux.Unlock()
+
+	if justLost {
+		this.LostHandler(lastHeartbeat)
+	}
+	// let the exchange timeout expire.
+	if isLost {
+		return
+	}
+	if err := this.refresh(this.Timeout); err != nil {
+		this.ErrorHandler(err)
+	}
+}
+
+// refresh set the cancel all after of every pair, one failed pair does not stop the others.
+func (this *DeadManSwitch) refresh(timeout time.Duration) error {
+	var msgs = make([]string, 0)
+	for _, pair := range this.Pairs {
+		if _, err := this.API.CancelAllAfter(pair, timeout); err != nil {
+			msgs = append(msgs, fmt.Sprintf("set the cancel all after of %s error: %s", pair.String(), err))
+		}
+	}
+	if len(msgs) > 0 {
+		return errors.New(strings.Join(msgs, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,335 @@
+package risk
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	. "github.com/deforceHK/goghostex"
+)
+
+const (
+	CHECK_KILL_SWITCH    = "kill_switch"
+	CHECK_ORDER_RATE     = "order_rate"
+	CHECK_PRICE_BAND     = "price_band"
+	CHECK_ORDER_NOTIONAL = "order_notional"
+	CHECK_POSITION       = "position"
+)
+
+// Limits is the pre-trade check setting, the zero value of each field means no check.
+type Limits struct {
+	MaxOrderNotional   float64 // the max notional of one order, in the counter currency
+	MaxPosition        float64 // the max absolute position of the pair, in the basis currency
+	MaxPriceDeviation  float64 // the max deviation ratio from the reference price (mark or last), eg: 0.05
+	UseExchangeLimit   bool    // reject the price out of the exchange limit bounds, swap and future only
+	MaxOrdersPerSecond int     // the max order count in one second of the guard
+}
+
+// RiskError is returned when the order is rejected by the pre-trade checks, the order is not sent.
+type RiskError struct {
+	Check  string // which check reject the order, CHECK_*
+	Pair   Pair
+	Reason string
+}
+
+func (e *RiskError) Error() string {
+	return fmt.Sprintf("risk check %s reject the %s order: %s", e.Check, e.Pair.String(), e.Reason)
+}
+
+// AuditRecord is the decision of the pre-trade checks, every order placed by the wrappers has one.
+type AuditRecord struct {
+	Timestamp int64 // unit: ms
+	Exchange  string
+	TradeType string // TRADE_TYPE_SPOT, TRADE_TYPE_SWAP or TRADE_TYPE_FUTURE
+	Cid       string
+	Pair      Pair
+	Side      string  // the order side or the future type
+	Price     float64 // the order price, or the reference price of the market order
+	Amount    float64 // in the basis currency
+	Notional  float64 // in the counter currency
+	Pass      bool
+	Check     string // the check which reject the order, empty when pass
+	Reason    string
+}
+
+// Guard run the pre-trade checks, one guard can be shared by the spot, swap and future wrappers,
+// so the kill switch and the order rate are global in them.
+type Guard struct {
+	Default      Limits
+	PairLimits   map[string]Limits // key is pair.String(), it override the Default
+	AuditHandler func(record AuditRecord)
+
+	mux        sync.Mutex
+	killed     bool
+	killReason string
+	positions  map[string]float64
+	second     int64
+	count      int
+}
+
+func (this *Guard) Init() {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	if this.positions == nil {
+		this.positions = make(map[string]float64)
+	}
+	if this.AuditHandler == nil {
+		this.AuditHandler = func(record AuditRecord) {
+			var fields = []LogField{
+				LogF(LOG_FIELD_EXCHANGE, record.Exchange),
+				LogF(LOG_FIELD_INSTRUMENT, record.Pair.String()),
+				LogF("cid", record.Cid),
+				LogF("side", record.Side),
+				LogF("price", record.Price),
+				LogF("amount", record.Amount),
+				LogF("notional", record.Notional),
+			}
+			if record.Pass {
+				GetLogger().Info("risk check pass", fields...)
+				return
+			}
+			fields = append(fields, LogF("check", record.Check), LogF("reason", record.Reason))
+			GetLogger().Warn("risk check reject", fields...)
+		}
+	}
+}
+
+// Kill turn on the kill switch, all the orders will be rejected until Resume.
+func (this *Guard) Kill(reason string) {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	this.killed = true
+	this.killReason = reason
+	GetLogger().Warn("risk kill switch on", LogF("reason", reason))
+}
+
+func (this *Guard) Resume() {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	this.killed = false
+	this.killReason = ""
+	GetLogger().Warn("risk kill switch off")
+}
+
+func (this *Guard) IsKilled() (bool, string) {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	return this.killed, this.killReason
+}
+
+// SetPosition sync the position of the pair, in the basis currency, long is positive and short is negative.
+// The guard add the accepted orders to the position as if they are all filled,
+// sync it from the exchange periodically to release the canceled orders.
+func (this *Guard) SetPosition(pair Pair, position float64) {
+	this.Init()
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	this.positions[pair.String()] = position
+}
+
+func (this *Guard) GetPosition(pair Pair) float64 {
+	this.Init()
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	return this.positions[pair.String()]
+}
+
+func (this *Guard) limits(pair Pair) Limits {
+	if limits, exist := this.PairLimits[pair.String()]; exist {
+		return limits
+	}
+	return this.Default
+}
+
+// intent is the order in the view of the checks, the wrappers convert their orders to it.
+type intent struct {
+	exchange  string
+	tradeType string
+	cid       string
+	pair      Pair
+	side      string
+	price     float64 // 0 means the market order
+	direction float64 // 1 increase the long position, -1 increase the short position
+
+	// toBasis convert the order amount to the basis currency amount in the price.
+	toBasis func(price float64) float64
+	// reference return the mark price or the last price.
+	reference func() (float64, error)
+	// bounds return the highest and the lowest price the exchange accept, nil means not support.
+	bounds func() (float64, float64, error)
+	// counted return the basis amount already in the position, the amended order only add the change.
+	counted func(price float64) float64
+}
+
+// check run the checks in order, the order is accepted only when all the checks pass.
+// The accepted order is counted in the order rate and the position, release the reservation if it is not sent.
+func (this *Guard) check(it *intent) (*reservation, error) {
+	this.Init()
+	var limits = this.limits(it.pair)
+	var record = AuditRecord{
+		Timestamp: time.Now().UnixMilli(),
+		Exchange:  it.exchange,
+		TradeType: it.tradeType,
+		Cid:       it.cid,
+		Pair:      it.pair,
+		Side:      it.side,
+		Price:     it.price,
+	}
+
+	var reject = func(check, reason string) error {
+		record.Check, record.Reason = check, reason
+		this.AuditHandler(record)
+		return &RiskError{Check: check, Pair: it.pair, Reason: reason}
+	}
+
+	if killed, reason := this.IsKilled(); killed {
+		return nil, reject(CHECK_KILL_SWITCH, fmt.Sprintf("the kill switch is on: %s", reason))
+	}
+
+	// the early rate check save the reference price query, it is checked again when the order is reserved.
+	if limits.MaxOrdersPerSecond > 0 {
+		this.mux.Lock()
+		var count = this.rate(time.Now().Unix())
+		this.mux.Unlock()
+		if count >= limits.MaxOrdersPerSecond {
+			return nil, reject(CHECK_ORDER_RATE, fmt.Sprintf("%d orders in this second already", count))
+		}
+	}
+
+	// the reference price is needed by the market order and the price band.
+	var refPrice float64
+	if it.price == 0 || limits.MaxPriceDeviation > 0 {
+		var err error
+		if refPrice, err = it.reference(); err != nil {
+			return nil, reject(CHECK_PRICE_BAND, fmt.Sprintf("can not get the reference price: %s", err))
+		}
+		if refPrice <= 0 {
+			return nil, reject(CHECK_PRICE_BAND, "the reference price is not positive")
+		}
+	}
+	if it.price == 0 {
+		record.Price = refPrice
+	} else {
+		if limits.MaxPriceDeviation > 0 {
+			var deviation = math.Abs(it.price-refPrice) / refPrice
+			if deviation > limits.MaxPriceDeviation {
+				return nil, reject(CHECK_PRICE_BAND, fmt.Sprintf(
+					"the price %v deviate %.4f from the reference %v, the max is %.4f",
+					it.price, deviation, refPrice, limits.MaxPriceDeviation,
+				))
+			}
+		}
+		if limits.UseExchangeLimit && it.bounds != nil {
+			var highest, lowest, err = it.bounds()
+			if err != nil {
+				return nil, reject(CHECK_PRICE_BAND, fmt.Sprintf("can not get the exchange limit: %s", err))
+			}
+			if it.price > highest || it.price < lowest {
+				return nil, reject(CHECK_PRICE_BAND, fmt.Sprintf(
+					"the price %v is out of the exchange limit [%v, %v]", it.price, lowest, highest,
+				))
+			}
+		}
+	}
+
+	record.Amount = it.toBasis(record.Price)
+	record.Notional = record.Amount * record.Price
+	if limits.MaxOrderNotional > 0 && record.Notional > limits.MaxOrderNotional {
+		return nil, reject(CHECK_ORDER_NOTIONAL, fmt.Sprintf(
+			"the notional %v is bigger than %v", record.Notional, limits.MaxOrderNotional,
+		))
+	}
+
+	var delta = record.Amount
+	if it.counted != nil {
+		delta -= it.counted(record.Price)
+	}
+
+	// check the order rate and the position and reserve them in one critical section,
+	// otherwise the concurrent orders all pass the check before any of them is counted.
+	this.mux.Lock()
+	var now = time.Now().Unix()
+	var count = this.rate(now)
+	var position = this.positions[it.pair.String()]
+	var after = position + it.direction*delta
+	var check, reason string
+	if limits.MaxOrdersPerSecond > 0 && count >= limits.MaxOrdersPerSecond {
+		check, reason = CHECK_ORDER_RATE, fmt.Sprintf("%d orders in this second already", count)
+	} else if limits.MaxPosition > 0 && math.Abs(after) > limits.MaxPosition && math.Abs(after) > math.Abs(position) {
+		// the order reduce the position is always allowed.
+		check, reason = CHECK_POSITION, fmt.Sprintf(
+			"the position will be %v after the order, the max is %v", after, limits.MaxPosition,
+		)
+	} else {
+		this.count++
+		this.positions[it.pair.String()] = after
+	}
+	this.mux.Unlock()
+	if check != "" {
+		return nil, reject(check, reason)
+	}
+
+	record.Pass = true
+	this.AuditHandler(record)
+	return &reservation{second: now, pair: it.pair.String(), position: it.direction * delta}, nil
+}
+
+// rate return the order count in the second, the caller must hold the lock.
+func (this *Guard) rate(now int64) int {
+	if this.second != now {
+		this.second, this.count = now, 0
+	}
+	return this.count
+}
+
+// reservation is the order rate and the position taken by the accepted order.
+type reservation struct {
+	second   int64
+	pair     string
+	position float64
+}
+
+// release give back the reservation of the order which the exchange refused.
+func (this *Guard) release(r *reservation) {
+	if r == nil {
+		return
+	}
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	if this.second == r.second && this.count > 0 {
+		this.count--
+	}
+	this.positions[r.pair] -= r.position
+}
+
+// checkBatch run the check of each order, the accepted orders are sent by the place function in one batch.
+// The errors of the rejected orders are the RiskError, the reservations of the failed orders are released.
+func (this *Guard) checkBatch(
+	count int, check func(i int) (*reservation, error), place func(indexes []int) ([]error, []byte, error),
+) ([]error, []byte, error) {
+	var errs = make([]error, count)
+	var reserved = make([]*reservation, count)
+	var indexes = make([]int, 0, count)
+	for i := 0; i < count; i++ {
+		if reserved[i], errs[i] = check(i); errs[i] == nil {
+			indexes = append(indexes, i)
+		}
+	}
+	if len(indexes) == 0 {
+		return errs, nil, BatchResult(errs)
+	}
+
+	var placeErrs, resp, err = place(indexes)
+	for j, i := range indexes {
+		if j < len(placeErrs) {
+			errs[i] = placeErrs[j]
+		} else if err != nil {
+			errs[i] = err
+		}
+		if errs[i] != nil {
+			this.release(reserved[i])
+		}
+	}
+	return errs, resp, BatchResult(errs)
+}
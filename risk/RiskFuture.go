@@ -0,0 +1,56 @@
+package risk
+
+import (
+	. "github.com/deforceHK/goghostex"
+)
+
+// Future wrap the FutureRestAPI, the order is checked by the guard before it is sent to the exchange.
+type Future struct {
+	FutureRestAPI
+	Guard *Guard
+}
+
+func NewFuture(api FutureRestAPI, guard *Guard) *Future {
+	return &Future{FutureRestAPI: api, Guard: guard}
+}
+
+func (future *Future) PlaceOrder(order *FutureOrder) ([]byte, error) {
+	var price = order.Price
+	if order.PlaceType == MARKET {
+		price = 0
+	}
+
+	var it = &intent{
+		exchange:  future.GetExchangeName(),
+		tradeType: TRADE_TYPE_FUTURE,
+		cid:       order.Cid,
+		pair:      order.Pair,
+		side:      order.Type.String(),
+		price:     price,
+		direction: futureDirection(order.Type),
+		toBasis: func(price float64) float64 {
+			var contract, err = future.GetContract(order.Pair, order.ContractType)
+			if err != nil || contract == nil {
+				return float64(order.Amount)
+			}
+			return contractToBasis(float64(order.Amount), contract.UnitAmount, contract.SettleMode, price)
+		},
+		reference: func() (float64, error) {
+			var mark, _, err = future.GetMark(order.Pair, order.ContractType)
+			return mark, err
+		},
+		bounds: func() (float64, float64, error) {
+			return future.GetLimit(order.Pair, order.ContractType)
+		},
+	}
+
+	var reserved, err = future.Guard.check(it)
+	if err != nil {
+		return nil, err
+	}
+	var resp, placeErr = future.FutureRestAPI.PlaceOrder(order)
+	if placeErr != nil {
+		future.Guard.release(reserved)
+	}
+	return resp, placeErr
+}
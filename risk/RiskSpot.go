@@ -0,0 +1,98 @@
+package risk
+
+import (
+	. "github.com/deforceHK/goghostex"
+)
+
+// Spot wrap the SpotRestAPI, the order is checked by the guard before it is sent to the exchange.
+type Spot struct {
+	SpotRestAPI
+	Guard *Guard
+}
+
+func NewSpot(api SpotRestAPI, guard *Guard) *Spot {
+	return &Spot{SpotRestAPI: api, Guard: guard}
+}
+
+func (spot *Spot) PlaceOrder(order *Order) ([]byte, error) {
+	var reserved, err = spot.Guard.check(spot.intent(order))
+	if err != nil {
+		return nil, err
+	}
+	var resp, placeErr = spot.SpotRestAPI.PlaceOrder(order)
+	if placeErr != nil {
+		spot.Guard.release(reserved)
+	}
+	return resp, placeErr
+}
+
+// BatchPlaceOrders check the orders one by one, only the accepted ones are sent in the batch.
+func (spot *Spot) BatchPlaceOrders(orders []*Order) ([]error, []byte, error) {
+	return spot.Guard.checkBatch(
+		len(orders),
+		func(i int) (*reservation, error) {
+			return spot.Guard.check(spot.intent(orders[i]))
+		},
+		func(indexes []int) ([]error, []byte, error) {
+			var accepted = make([]*Order, 0, len(indexes))
+			for _, i := range indexes {
+				accepted = append(accepted, orders[i])
+			}
+			return spot.SpotRestAPI.BatchPlaceOrders(accepted)
+		},
+	)
+}
+
+// AmendOrder check the amended order, only the change of the amount is added to the position.
+func (spot *Spot) AmendOrder(order *Order, newPrice, newAmount float64) ([]byte, error) {
+	var amended = *order
+	if newPrice > 0 {
+		amended.Price = newPrice
+	}
+	if newAmount > 0 {
+		amended.Amount = newAmount
+	}
+	var it = spot.intent(&amended)
+	it.counted = spot.intent(order).toBasis
+	var reserved, err = spot.Guard.check(it)
+	if err != nil {
+		return nil, err
+	}
+	var resp, amendErr = spot.SpotRestAPI.AmendOrder(order, newPrice, newAmount)
+	if amendErr != nil {
+		spot.Guard.release(reserved)
+	}
+	return resp, amendErr
+}
+
+func (spot *Spot) intent(order *Order) *intent {
+	var price = order.Price
+	if order.Side == BUY_MARKET || order.Side == SELL_MARKET || order.OrderType == MARKET {
+		price = 0
+	}
+	var direction float64 = 1
+	if order.Side == SELL || order.Side == SELL_MARKET {
+		direction = -1
+	}
+
+	var it = &intent{
+		exchange:  spot.GetExchangeName(),
+		tradeType: TRADE_TYPE_SPOT,
+		cid:       order.Cid,
+		pair:      order.Pair,
+		side:      order.Side.String(),
+		price:     price,
+		direction: direction,
+		toBasis: func(price float64) float64 {
+			return order.Amount
+		},
+		reference: func() (float64, error) {
+			var ticker, _, err = spot.GetTicker(order.Pair)
+			if err != nil {
+				return 0, err
+			}
+			return ticker.Last, nil
+		},
+	}
+	return it
+}
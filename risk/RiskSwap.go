@@ -0,0 +1,130 @@
+package risk
+
+import (
+	. "github.com/deforceHK/goghostex"
+)
+
+// Swap wrap the SwapRestAPI, the order is checked by the guard before it is sent to the exchange.
+type Swap struct {
+	SwapRestAPI
+	Guard *Guard
+}
+
+func NewSwap(api SwapRestAPI, guard *Guard) *Swap {
+	return &Swap{SwapRestAPI: api, Guard: guard}
+}
+
+func (swap *Swap) PlaceOrder(order *SwapOrder) ([]byte, error) {
+	var reserved, err = swap.Guard.check(swap.intent(order))
+	if err != nil {
+		return nil, err
+	}
+	var resp, placeErr = swap.SwapRestAPI.PlaceOrder(order)
+	if placeErr != nil {
+		swap.Guard.release(reserved)
+	}
+	return resp, placeErr
+}
+
+// BatchPlaceOrders check the orders one by one, only the accepted ones are sent in the batch.
+func (swap *Swap) BatchPlaceOrders(orders []*SwapOrder) ([]error, []byte, error) {
+	return swap.Guard.checkBatch(
+		len(orders),
+		func(i int) (*reservation, error) {
+			return swap.Guard.check(swap.intent(orders[i]))
+		},
+		func(indexes []int) ([]error, []byte, error) {
+			var accepted = make([]*SwapOrder, 0, len(indexes))
+			for _, i := range indexes {
+				accepted = append(accepted, orders[i])
+			}
+			return swap.SwapRestAPI.BatchPlaceOrders(accepted)
+		},
+	)
+}
+
+// AmendOrder check the amended order, only the change of the amount is added to the position.
+func (swap *Swap) AmendOrder(order *SwapOrder, newPrice, newAmount float64) ([]byte, error) {
+	var amended = *order
+	if newPrice > 0 {
+		amended.Price = newPrice
+	}
+	if newAmount > 0 {
+		amended.Amount = newAmount
+	}
+	var it = swap.intent(&amended)
+	it.counted = swap.intent(order).toBasis
+	var reserved, err = swap.Guard.check(it)
+	if err != nil {
+		return nil, err
+	}
+	var resp, amendErr = swap.SwapRestAPI.AmendOrder(order, newPrice, newAmount)
+	if amendErr != nil {
+		swap.Guard.release(reserved)
+	}
+	return resp, amendErr
+}
+
+func (swap *Swap) intent(order *SwapOrder) *intent {
+	var price = order.Price
+	if order.PlaceType == MARKET {
+		price = 0
+	}
+
+	var it = &intent{
+		exchange:  swap.GetExchangeName(),
+		tradeType: TRADE_TYPE_SWAP,
+		cid:       order.Cid,
+		pair:      order.Pair,
+		side:      order.Type.String(),
+		price:     price,
+		direction: futureDirection(order.Type),
+		toBasis: func(price float64) float64 {
+			var contract = swap.GetContract(order.Pair)
+			if contract == nil {
+				return order.Amount
+			}
+			return contractToBasis(order.Amount, contract.UnitAmount, contract.SettleMode, price)
+		},
+		reference: func() (float64, error) {
+			// binance swap has the mark price api, the others use the last price.
+			if marker, ok := swap.SwapRestAPI.(interface {
+				GetMark(pair Pair) (float64, error)
+			}); ok {
+				return marker.GetMark(order.Pair)
+			}
+			var ticker, _, err = swap.GetTicker(order.Pair)
+			if err != nil {
+				return 0, err
+			}
+			return ticker.Last, nil
+		},
+		bounds: func() (float64, float64, error) {
+			return swap.GetLimit(order.Pair)
+		},
+	}
+	return it
+}
+
+// futureDirection return 1 when the order increase the long position, otherwise -1.
+func futureDirection(futureType FutureType) float64 {
+	if futureType == OPEN_LONG || futureType == LIQUIDATE_SHORT {
+		return 1
+	}
+	return -1
+}
+
+// contractToBasis convert the contract amount to the basis currency amount,
+// the contract unit is the counter currency in the basis settle mode, otherwise is the basis currency.
+func contractToBasis(amount, unitAmount float64, settleMode int64, price float64) float64 {
+	if unitAmount == 0 {
+		unitAmount = 1
+	}
+	if settleMode == SETTLE_MODE_BASIS {
+		if price == 0 {
+			return 0
+		}
+		return amount * unitAmount / price
+	}
+	return amount * unitAmount
+}
@@ -0,0 +1,283 @@
+package risk
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/deforceHK/goghostex"
+)
+
+type fakeSwap struct {
+	SwapRestAPI
+	mux      sync.Mutex
+	placed   int
+	placeErr error
+}
+
+func (this *fakeSwap) GetExchangeName() string {
+	return OKEX
+}
+
+func (this *fakeSwap) GetContract(pair Pair) *SwapContract {
+	return &SwapContract{Pair: pair, SettleMode: SETTLE_MODE_COUNTER, UnitAmount: 0.01}
+}
+
+func (this *fakeSwap) GetTicker(pair Pair) (*SwapTicker, []byte, error) {
+	return &SwapTicker{Pair: pair, Last: 100}, nil, nil
+}
+
+func (this *fakeSwap) GetLimit(pair Pair) (float64, float64, error) {
+	return 110, 90, nil
+}
+
+func (this *fakeSwap) PlaceOrder(order *SwapOrder) ([]byte, error) {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	if this.placeErr != nil {
+		return nil, this.placeErr
+	}
+	this.placed++
+	return nil, nil
+}
+
+func (this *fakeSwap) BatchPlaceOrders(orders []*SwapOrder) ([]error, []byte, error) {
+	this.placed += len(orders)
+	return make([]error, len(orders)), nil, nil
+}
+
+func (this *fakeSwap) AmendOrder(order *SwapOrder, newPrice, newAmount float64) ([]byte, error) {
+	order.Price, order.Amount = newPrice, newAmount
+	return nil, nil
+}
+
+// go test -v ./risk/... -count=1 -run=TestGuard
+func TestGuard(t *testing.T) {
+	var records = make([]AuditRecord, 0)
+	var guard = &Guard{
+		Default: Limits{
+			MaxOrderNotional:   1000,
+			MaxPosition:        10,
+			MaxPriceDeviation:  0.05,
+			UseExchangeLimit:   true,
+			MaxOrdersPerSecond: 100,
+		},
+		AuditHandler: func(record AuditRecord) { records = append(records, record) },
+	}
+	var api = &fakeSwap{}
+	var swap = NewSwap(api, guard)
+
+	var cases = []struct {
+		order *SwapOrder
+		check string
+	}{
+		// 500 contracts is 5 BTC_USDT, the notional is 500.
+		{&SwapOrder{Pair: BTC_USDT, Type: OPEN_LONG, Price: 100, Amount: 500}, ""},
+		{&SwapOrder{Pair: BTC_USDT, Type: OPEN_LONG, Price: 120, Amount: 1}, CHECK_PRICE_BAND},
+		{&SwapOrder{Pair: BTC_USDT, Type: OPEN_LONG, Price: 100, Amount: 2000}, CHECK_ORDER_NOTIONAL},
+		{&SwapOrder{Pair: BTC_USDT, Type: OPEN_LONG, PlaceType: MARKET, Amount: 600}, CHECK_POSITION},
+		// reduce the position is allowed.
+		{&SwapOrder{Pair: BTC_USDT, Type: LIQUIDATE_LONG, Price: 100, Amount: 500}, ""},
+	}
+
+	for i, c := range cases {
+		var _, err = swap.PlaceOrder(c.order)
+		var riskErr *RiskError
+		if c.check == "" && err != nil {
+			t.Error(i, err)
+			return
+		}
+		if c.check != "" && (!errors.As(err, &riskErr) || riskErr.Check != c.check) {
+			t.Error(i, "the check should reject the order: ", c.check, err)
+			return
+		}
+	}
+	if api.placed != 2 || len(records) != len(cases) || guard.GetPosition(BTC_USDT) != 0 {
+		t.Error("the audit is wrong: ", api.placed, len(records), guard.GetPosition(BTC_USDT))
+		return
+	}
+
+	guard.Kill("test")
+	var _, err = swap.PlaceOrder(&SwapOrder{Pair: BTC_USDT, Type: OPEN_LONG, Price: 100, Amount: 1})
+	if riskErr, ok := err.(*RiskError); !ok || riskErr.Check != CHECK_KILL_SWITCH {
+		t.Error("the kill switch should reject the order: ", err)
+	}
+}
+
+// go test -v ./risk/... -count=1 -run=TestBatchGuard
+func TestBatchGuard(t *testing.T) {
+	var guard = &Guard{
+		Default:      Limits{MaxPriceDeviation: 0.05},
+		AuditHandler: func(record AuditRecord) {},
+	}
+	var api = &fakeSwap{}
+	var swap = NewSwap(api, guard)
+
+	var errs, _, err = swap.BatchPlaceOrders([]*SwapOrder{
+		{Pair: BTC_USDT, Type: OPEN_LONG, Price: 100, Amount: 1},
+		{Pair: BTC_USDT, Type: OPEN_LONG, Price: 120, Amount: 1},
+		{Pair: BTC_USDT, Type: OPEN_SHORT, Price: 101, Amount: 1},
+	})
+	var riskErr *RiskError
+	if errs[0] != nil || !errors.As(errs[1], &riskErr) || errs[2] != nil {
+		t.Error("the errors should be per order: ", errs)
+		return
+	}
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) || batchErr.Failed != 1 || api.placed != 2 {
+		t.Error("only the accepted orders should be sent: ", err, api.placed)
+	}
+}
+
+// go test -v ./risk/... -count=1 -run=TestAmendGuard
+func TestAmendGuard(t *testing.T) {
+	var guard = &Guard{
+		Default:      Limits{MaxPosition: 10},
+		AuditHandler: func(record AuditRecord) {},
+	}
+	var swap = NewSwap(&fakeSwap{}, guard)
+
+	// 500 contracts is 5 BTC_USDT.
+	var order = &SwapOrder{Pair: BTC_USDT, Type: OPEN_LONG, Price: 100, Amount: 500}
+	if _, err := swap.PlaceOrder(order); err != nil {
+		t.Error(err)
+		return
+	}
+	if _, err := swap.AmendOrder(order, 100, 900); err != nil || guard.GetPosition(BTC_USDT) != 9 {
+		t.Error("only the change should be counted: ", err, guard.GetPosition(BTC_USDT))
+		return
+	}
+	var riskErr *RiskError
+	if _, err := swap.AmendOrder(order, 100, 1200); !errors.As(err, &riskErr) || riskErr.Check != CHECK_POSITION {
+		t.Error("the amend over the max position should be rejected: ", err)
+	}
+}
+
+// go test -v ./risk/... -count=1 -run=TestGuardReserve
+func TestGuardReserve(t *testing.T) {
+	var guard = &Guard{
+		Default:      Limits{MaxPosition: 10, MaxOrdersPerSecond: 1000},
+		AuditHandler: func(record AuditRecord) {},
+	}
+	var api = &fakeSwap{}
+	var swap = NewSwap(api, guard)
+
+	// 100 contracts is 1 BTC_USDT, only 10 of the concurrent orders can pass.
+	var wg sync.WaitGroup
+	for i := 0; i < 30; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = swap.PlaceOrder(&SwapOrder{Pair: BTC_USDT, Type: OPEN_LONG, Price: 100, Amount: 100})
+		}()
+	}
+	wg.Wait()
+	if api.placed != 10 || guard.GetPosition(BTC_USDT) != 10 {
+		t.Error("the concurrent orders break the max position: ", api.placed, guard.GetPosition(BTC_USDT))
+		return
+	}
+
+	// the order refused by the exchange should release the reservation.
+	api.placeErr = errors.New("insufficient margin")
+	if _, err := swap.PlaceOrder(&SwapOrder{Pair: BTC_USDT, Type: LIQUIDATE_LONG, Price: 100, Amount: 300}); err == nil {
+		t.Error("the exchange error is lost")
+		return
+	}
+	if guard.GetPosition(BTC_USDT) != 10 {
+		t.Error("the reservation is not released: ", guard.GetPosition(BTC_USDT))
+	}
+}
+
+type fakeCountdown struct {
+	SwapRestAPI
+	mux      sync.Mutex
+	timeouts []time.Duration
+	failPair string // the cancel all after of the pair is refused
+}
+
+func (this *fakeCountdown) GetExchangeName() string {
+	return OKEX
+}
+
+func (this *fakeCountdown) CancelAllAfter(pair Pair, timeout time.Duration) ([]byte, error) {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	this.timeouts = append(this.timeouts, timeout)
+	if pair.String() == this.failPair {
+		return nil, errors.New("the pair is not supported")
+	}
+	return nil, nil
+}
+
+func (this *fakeCountdown) count() int {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	return len(this.timeouts)
+}
+
+// go test -v ./risk/... -count=1 -run=TestDeadManSwitch
+func TestDeadManSwitch(t *testing.T) {
+	var api = &fakeCountdown{}
+	var lost = make(chan bool, 1)
+	var dms = &DeadManSwitch{
+		API:              api,
+		Pairs:            []Pair{BTC_USDT},
+		Timeout:          time.Second,
+		RefreshInterval:  20 * time.Millisecond,
+		HeartbeatTimeout: 100 * time.Millisecond,
+		LostHandler:      func(lastHeartbeat time.Time) { lost <- true },
+	}
+	if err := dms.Start(); err != nil {
+		t.Error(err)
+		return
+	}
+
+	// the switch is refreshed while the heartbeat is alive.
+	for i := 0; i < 5; i++ {
+		time.Sleep(30 * time.Millisecond)
+		dms.Heartbeat()
+	}
+	if dms.IsLost() || api.count() < 3 {
+		t.Error("the switch should be refreshed: ", api.count())
+		return
+	}
+
+	select {
+	case <-lost:
+	case <-time.After(time.Second):
+		t.Error("the heartbeat lost is not found")
+		return
+	}
+	var refreshed = api.count()
+	time.Sleep(60 * time.Millisecond)
+	if api.count() != refreshed {
+		t.Error("the switch should not be refreshed after the heartbeat is lost")
+		return
+	}
+
+	if err := dms.Stop(); err != nil {
+		t.Error(err)
+		return
+	}
+	if api.timeouts[len(api.timeouts)-1] != 0 {
+		t.Error("the switch should be disarmed when stop")
+	}
+}
+
+// go test -v ./risk/... -count=1 -run=TestDeadManSwitch_Refresh
+func TestDeadManSwitch_Refresh(t *testing.T) {
+	var api = &fakeCountdown{failPair: BTC_USDT.String()}
+	var dms = &DeadManSwitch{API: api, Pairs: []Pair{BTC_USDT, ETH_USDT}}
+
+	// the failed pair should not stop the refresh of the others.
+	var err = dms.refresh(time.Second)
+	if err == nil || !strings.Contains(err.Error(), BTC_USDT.String()) {
+		t.Error("the refresh error is lost: ", err)
+		return
+	}
+	if api.count() != 2 {
+		t.Error("every pair should be refreshed: ", api.count())
+	}
+}